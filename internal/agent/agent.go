@@ -17,6 +17,7 @@ import (
 	"log/slog"
 	"os"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -71,6 +72,16 @@ type SessionAgentCall struct {
 	TopK             *int64
 	FrequencyPenalty *float64
 	PresencePenalty  *float64
+
+	// Model overrides the large model of the agent for this call.
+	Model *Model
+	// Tools overrides the tools of the agent for this call.
+	Tools []fantasy.AgentTool
+	// AllowedTools restricts the tools of the agent for this call.
+	AllowedTools []string
+	// Verification is stored with the user message when the prompt asks to
+	// fix its failures.
+	Verification *message.Verification
 }
 
 type SessionAgent interface {
@@ -168,6 +179,17 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 	largeModel := a.largeModel.Get()
 	systemPrompt := a.systemPrompt.Get()
 	promptPrefix := a.systemPromptPrefix.Get()
+	if call.Model != nil {
+		largeModel = *call.Model
+	}
+	if call.Tools != nil {
+		agentTools = slices.Clone(call.Tools)
+	}
+	if len(call.AllowedTools) > 0 {
+		agentTools = slices.DeleteFunc(agentTools, func(tool fantasy.AgentTool) bool {
+			return !slices.Contains(call.AllowedTools, tool.Info().Name)
+		})
+	}
 	var instructions strings.Builder
 
 	for _, server := range mcp.GetStates() {
@@ -230,6 +252,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 
 	defer cancel()
 	defer a.activeRequests.Del(call.SessionID)
+	defer tools.DeactivateSkill(call.SessionID)
 
 	history, files := a.preparePrompt(msgs, call.Attachments...)
 
@@ -267,6 +290,15 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 
 			prepared.Messages = a.workaroundProviderMediaLimitations(prepared.Messages, largeModel)
 
+			// Restrict the tools to the allowed-tools of the active skill. The
+			// skill tool stays available to switch to another skill.
+			if allowed, ok := tools.ActiveSkillTools(call.SessionID); ok {
+				prepared.Tools = slices.DeleteFunc(slices.Clone(agentTools), func(tool fantasy.AgentTool) bool {
+					name := tool.Info().Name
+					return name != tools.SkillToolName && !slices.Contains(allowed, name)
+				})
+			}
+
 			lastSystemRoleInx := 0
 			systemMessageUpdated := false
 			for i, msg := range prepared.Messages {
@@ -309,7 +341,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 		},
 		OnReasoningDelta: func(id string, text string) error {
 			currentAssistant.AppendReasoningContent(text)
-			return a.messages.Update(genCtx, *currentAssistant)
+			return a.messages.UpdateStreaming(genCtx, *currentAssistant)
 		},
 		OnReasoningEnd: func(id string, reasoning fantasy.ReasoningContent) error {
 			// handle anthropic signature
@@ -340,7 +372,7 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 			}
 
 			currentAssistant.AppendContent(text)
-			return a.messages.Update(genCtx, *currentAssistant)
+			return a.messages.UpdateStreaming(genCtx, *currentAssistant)
 		},
 		OnToolInputStart: func(id string, toolName string) error {
 			toolCall := message.ToolCall{
@@ -394,7 +426,8 @@ func (a *sessionAgent) Run(ctx context.Context, call SessionAgentCall) (*fantasy
 			if getSessionErr != nil {
 				return getSessionErr
 			}
-			a.updateSessionUsage(largeModel, &updatedSession, stepResult.Usage, a.openrouterCost(stepResult.ProviderMetadata))
+			cost := a.updateSessionUsage(largeModel, &updatedSession, stepResult.Usage, a.openrouterCost(stepResult.ProviderMetadata))
+			currentAssistant.Usage = messageUsage(stepResult.Usage, cost)
 			_, sessionErr := a.sessions.Save(ctx, updatedSession)
 			if sessionErr != nil {
 				return sessionErr
@@ -614,7 +647,7 @@ func (a *sessionAgent) Summarize(ctx context.Context, sessionID string, opts fan
 		},
 		OnReasoningDelta: func(id string, text string) error {
 			summaryMessage.AppendReasoningContent(text)
-			return a.messages.Update(genCtx, summaryMessage)
+			return a.messages.UpdateStreaming(genCtx, summaryMessage)
 		},
 		OnReasoningEnd: func(id string, reasoning fantasy.ReasoningContent) error {
 			// Handle anthropic signature.
@@ -628,7 +661,7 @@ func (a *sessionAgent) Summarize(ctx context.Context, sessionID string, opts fan
 		},
 		OnTextDelta: func(id, text string) error {
 			summaryMessage.AppendContent(text)
-			return a.messages.Update(genCtx, summaryMessage)
+			return a.messages.UpdateStreaming(genCtx, summaryMessage)
 		},
 	})
 	if err != nil {
@@ -641,12 +674,6 @@ func (a *sessionAgent) Summarize(ctx context.Context, sessionID string, opts fan
 		return err
 	}
 
-	summaryMessage.AddFinish(message.FinishReasonEndTurn, "", "")
-	err = a.messages.Update(genCtx, summaryMessage)
-	if err != nil {
-		return err
-	}
-
 	var openrouterCost *float64
 	for _, step := range resp.Steps {
 		stepCost := a.openrouterCost(step.ProviderMetadata)
@@ -659,15 +686,26 @@ func (a *sessionAgent) Summarize(ctx context.Context, sessionID string, opts fan
 		}
 	}
 
-	a.updateSessionUsage(largeModel, &currentSession, resp.TotalUsage, openrouterCost)
+	cost := a.updateSessionUsage(largeModel, &currentSession, resp.TotalUsage, openrouterCost)
+
+	summaryMessage.AddFinish(message.FinishReasonEndTurn, "", "")
+	summaryMessage.Usage = messageUsage(resp.TotalUsage, cost)
+	err = a.messages.Update(genCtx, summaryMessage)
+	if err != nil {
+		return err
+	}
 
 	// Just in case, get just the last usage info.
 	usage := resp.Response.Usage
 	currentSession.SummaryMessageID = summaryMessage.ID
 	currentSession.CompletionTokens = usage.OutputTokens
 	currentSession.PromptTokens = 0
-	_, err = a.sessions.Save(genCtx, currentSession)
-	return err
+	if _, err = a.sessions.Save(genCtx, currentSession); err != nil {
+		return err
+	}
+	// The summary replaces the nested context files given to the agent.
+	tools.ForgetContextFiles(sessionID)
+	return nil
 }
 
 func (a *sessionAgent) getCacheControlOptions() fantasy.ProviderOptions {
@@ -691,6 +729,9 @@ func (a *sessionAgent) createUserMessage(ctx context.Context, call SessionAgentC
 		attachmentParts = append(attachmentParts, message.BinaryContent{Path: attachment.FilePath, MIMEType: attachment.MimeType, Data: attachment.Content})
 	}
 	parts = append(parts, attachmentParts...)
+	if call.Verification != nil {
+		parts = append(parts, *call.Verification)
+	}
 	msg, err := a.messages.Create(ctx, call.SessionID, message.CreateMessageParams{
 		Role:  message.User,
 		Parts: parts,
@@ -896,7 +937,9 @@ func (a *sessionAgent) openrouterCost(metadata fantasy.ProviderMetadata) *float6
 	return &opts.Usage.Cost
 }
 
-func (a *sessionAgent) updateSessionUsage(model Model, session *session.Session, usage fantasy.Usage, overrideCost *float64) {
+// updateSessionUsage adds the usage of a generation to the session and
+// returns its cost.
+func (a *sessionAgent) updateSessionUsage(model Model, session *session.Session, usage fantasy.Usage, overrideCost *float64) float64 {
 	modelConfig := model.CatwalkCfg
 	cost := modelConfig.CostPer1MInCached/1e6*float64(usage.CacheCreationTokens) +
 		modelConfig.CostPer1MOutCached/1e6*float64(usage.CacheReadTokens) +
@@ -906,13 +949,25 @@ func (a *sessionAgent) updateSessionUsage(model Model, session *session.Session,
 	a.eventTokensUsed(session.ID, model, usage, cost)
 
 	if overrideCost != nil {
-		session.Cost += *overrideCost
-	} else {
-		session.Cost += cost
+		cost = *overrideCost
 	}
+	session.Cost += cost
 
 	session.CompletionTokens = usage.OutputTokens
 	session.PromptTokens = usage.InputTokens + usage.CacheReadTokens
+	return cost
+}
+
+// messageUsage returns the usage of an assistant message from the usage and
+// cost of its generation.
+func messageUsage(usage fantasy.Usage, cost float64) message.Usage {
+	return message.Usage{
+		InputTokens:         usage.InputTokens,
+		OutputTokens:        usage.OutputTokens,
+		CacheReadTokens:     usage.CacheReadTokens,
+		CacheCreationTokens: usage.CacheCreationTokens,
+		Cost:                cost,
+	}
 }
 
 func (a *sessionAgent) Cancel(sessionID string) {
@@ -52,15 +52,9 @@ var agenticFetchPromptTmpl []byte
 
 func (c *coordinator) agenticFetchTool(_ context.Context, client *http.Client) (fantasy.AgentTool, error) {
 	if client == nil {
-		client = &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:        100,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
-		}
+		client = tools.NewFetchClient(c.cfg.Tools.Fetch, c.fetchCacheDir(), 30*time.Second)
 	}
+	fetchCfg := c.cfg.Tools.Fetch
 
 	return fantasy.NewParallelAgentTool(
 		tools.AgenticFetchToolName,
@@ -79,22 +73,34 @@ func (c *coordinator) agenticFetchTool(_ context.Context, client *http.Client) (
 				description = "Search the web and analyze results"
 			}
 
-			p, err := c.permissions.Request(ctx,
-				permission.CreatePermissionRequest{
-					SessionID:   validationResult.SessionID,
-					Path:        c.cfg.WorkingDir(),
-					ToolCallID:  call.ID,
-					ToolName:    tools.AgenticFetchToolName,
-					Action:      "fetch",
-					Description: description,
-					Params:      tools.AgenticFetchPermissionsParams(params),
-				},
-			)
-			if err != nil {
-				return fantasy.ToolResponse{}, err
+			// Searching may lead anywhere, so only fetching a URL of an allowed
+			// domain skips the prompt.
+			var allowed bool
+			if params.URL != "" {
+				var denied bool
+				allowed, denied = tools.FetchDomainPolicy(fetchCfg, params.URL)
+				if denied {
+					return fantasy.NewTextErrorResponse(tools.DeniedDomainError(params.URL)), nil
+				}
 			}
-			if !p {
-				return fantasy.ToolResponse{}, permission.ErrorPermissionDenied
+			if !allowed {
+				p, err := c.permissions.Request(ctx,
+					permission.CreatePermissionRequest{
+						SessionID:   validationResult.SessionID,
+						Path:        c.cfg.WorkingDir(),
+						ToolCallID:  call.ID,
+						ToolName:    tools.AgenticFetchToolName,
+						Action:      "fetch",
+						Description: description,
+						Params:      tools.AgenticFetchPermissionsParams(params),
+					},
+				)
+				if err != nil {
+					return fantasy.ToolResponse{}, err
+				}
+				if !p {
+					return fantasy.ToolResponse{}, permission.ErrorPermissionDenied
+				}
 			}
 
 			tmpDir, err := os.MkdirTemp(c.cfg.Options.DataDirectory, "crush-fetch-*")
@@ -107,7 +113,7 @@ func (c *coordinator) agenticFetchTool(_ context.Context, client *http.Client) (
 
 			if params.URL != "" {
 				// URL mode: fetch the URL content first.
-				content, err := tools.FetchURLAndConvert(ctx, client, params.URL)
+				content, err := tools.FetchURLAndConvert(ctx, client, params.URL, fetchCfg.MaxSize())
 				if err != nil {
 					return fantasy.NewTextErrorResponse(fmt.Sprintf("Failed to fetch URL: %s", err)), nil
 				}
@@ -160,8 +166,12 @@ func (c *coordinator) agenticFetchTool(_ context.Context, client *http.Client) (
 				return fantasy.ToolResponse{}, errors.New("small model provider not configured")
 			}
 
-			webFetchTool := tools.NewWebFetchTool(tmpDir, client)
-			webSearchTool := tools.NewWebSearchTool(client)
+			webFetchTool := tools.NewWebFetchTool(tmpDir, client, fetchCfg)
+			searchProvider, err := tools.NewSearchProvider(c.cfg.Tools.WebSearch, c.cfg.Resolver(), client)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("Failed to set up web search: %s", err)), nil
+			}
+			webSearchTool := tools.NewWebSearchTool(searchProvider)
 			fetchTools := []fantasy.AgentTool{
 				webFetchTool,
 				webSearchTool,
@@ -12,14 +12,17 @@ import (
 	"maps"
 	"net/http"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"charm.land/catwalk/pkg/catwalk"
 	"charm.land/fantasy"
 	"github.com/charmbracelet/crush/internal/agent/hyper"
 	"github.com/charmbracelet/crush/internal/agent/prompt"
 	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/csync"
 	"github.com/charmbracelet/crush/internal/filetracker"
@@ -29,6 +32,8 @@ import (
 	"github.com/charmbracelet/crush/internal/message"
 	"github.com/charmbracelet/crush/internal/oauth/copilot"
 	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/charmbracelet/crush/internal/review"
 	"github.com/charmbracelet/crush/internal/session"
 	"golang.org/x/sync/errgroup"
 
@@ -47,6 +52,7 @@ type Coordinator interface {
 	// INFO: (kujtim) this is not used yet we will use this when we have multiple agents
 	// SetMainAgent(string)
 	Run(ctx context.Context, sessionID, prompt string, attachments ...message.Attachment) (*fantasy.AgentResult, error)
+	RunWithOptions(ctx context.Context, sessionID, prompt string, opts RunOptions, attachments ...message.Attachment) (*fantasy.AgentResult, error)
 	Cancel(sessionID string)
 	CancelAll()
 	IsSessionBusy(sessionID string) bool
@@ -55,10 +61,25 @@ type Coordinator interface {
 	QueuedPromptsList(sessionID string) []string
 	ClearQueue(sessionID string)
 	Summarize(context.Context, string) error
+	// Review runs the reviewer agent in a session on the changes of a git
+	// revision range, the uncommitted ones if empty, and returns its
+	// findings.
+	Review(ctx context.Context, sessionID, revRange string) (*review.Report, error)
 	Model() Model
 	UpdateModels(ctx context.Context) error
 }
 
+// RunOptions overrides the model and tools of the coordinator for a single
+// run, as custom commands do.
+type RunOptions struct {
+	// Model is "large", "small", a model ID or "provider/model".
+	Model string
+	// Agent is the ID of a configured agent whose model and tools to use.
+	Agent string
+	// AllowedTools restricts the tools the agent can use during the run.
+	AllowedTools []string
+}
+
 type coordinator struct {
 	cfg         *config.Config
 	sessions    session.Service
@@ -71,6 +92,10 @@ type coordinator struct {
 	currentAgent SessionAgent
 	agents       map[string]SessionAgent
 
+	// tasks holds the cancel functions of the work running in sessions
+	// besides their agent, such as verifications and reviews, by session.
+	tasks *csync.Map[string, context.CancelFunc]
+
 	readyWg errgroup.Group
 }
 
@@ -93,6 +118,7 @@ func NewCoordinator(
 		filetracker: filetracker,
 		lspClients:  lspClients,
 		agents:      make(map[string]SessionAgent),
+		tasks:       csync.NewMap[string, context.CancelFunc](),
 	}
 
 	agentCfg, ok := cfg.Agents[config.AgentCoder]
@@ -112,11 +138,35 @@ func NewCoordinator(
 	}
 	c.currentAgent = agent
 	c.agents[config.AgentCoder] = agent
+	mcp.SetSamplingHandler(c.sample)
 	return c, nil
 }
 
 // Run implements Coordinator.
 func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string, attachments ...message.Attachment) (*fantasy.AgentResult, error) {
+	return c.RunWithOptions(ctx, sessionID, prompt, RunOptions{}, attachments...)
+}
+
+// RunWithOptions implements Coordinator.
+func (c *coordinator) RunWithOptions(ctx context.Context, sessionID string, prompt string, opts RunOptions, attachments ...message.Attachment) (*fantasy.AgentResult, error) {
+	start := time.Now()
+	result, err := c.runWithOptions(ctx, sessionID, prompt, opts, nil, attachments...)
+	if result != nil && err == nil {
+		result, err = c.verify(ctx, sessionID, start, opts, result)
+	}
+	// Without result nor error the prompt was queued, and the run it was
+	// queued behind publishes the event once the queue is drained.
+	if result != nil || err != nil {
+		runBroker.Publish(pubsub.UpdatedEvent, RunEvent{
+			SessionID: sessionID,
+			Duration:  time.Since(start),
+			Err:       err,
+		})
+	}
+	return result, err
+}
+
+func (c *coordinator) runWithOptions(ctx context.Context, sessionID string, prompt string, opts RunOptions, verification *message.Verification, attachments ...message.Attachment) (*fantasy.AgentResult, error) {
 	if err := c.readyWg.Wait(); err != nil {
 		return nil, err
 	}
@@ -127,6 +177,38 @@ func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string,
 	}
 
 	model := c.currentAgent.Model()
+	var modelOverride *Model
+	var toolsOverride []fantasy.AgentTool
+	modelRef := opts.Model
+	if opts.Agent != "" {
+		agentCfg, ok := c.cfg.Agents[opts.Agent]
+		if !ok {
+			return nil, fmt.Errorf("agent %q not configured", opts.Agent)
+		}
+		if modelRef == "" {
+			modelRef = string(agentCfg.Model)
+		}
+		agentTools, err := c.buildTools(ctx, agentCfg)
+		if err != nil {
+			return nil, err
+		}
+		toolsOverride = agentTools
+	}
+	if modelRef != "" {
+		selected, err := c.resolveModel(modelRef)
+		if err != nil {
+			return nil, err
+		}
+		if selected.Provider != model.ModelCfg.Provider || selected.Model != model.ModelCfg.Model {
+			built, err := c.buildModel(ctx, selected)
+			if err != nil {
+				return nil, err
+			}
+			model = built
+			modelOverride = &built
+		}
+	}
+
 	maxTokens := model.CatwalkCfg.DefaultMaxTokens
 	if model.ModelCfg.MaxTokens != 0 {
 		maxTokens = model.ModelCfg.MaxTokens
@@ -169,6 +251,10 @@ func (c *coordinator) Run(ctx context.Context, sessionID string, prompt string,
 			TopK:             topK,
 			FrequencyPenalty: freqPenalty,
 			PresencePenalty:  presPenalty,
+			Model:            modelOverride,
+			Tools:            toolsOverride,
+			AllowedTools:     opts.AllowedTools,
+			Verification:     verification,
 		})
 	}
 	result, originalErr := run()
@@ -384,7 +470,7 @@ func (c *coordinator) buildTools(ctx context.Context, agent config.Agent) ([]fan
 		allTools = append(allTools, agenticFetchTool)
 	}
 
-	// Get the model name for the agent
+	// Get the model name for the commit attribution
 	modelName := ""
 	if modelCfg, ok := c.cfg.Models[agent.Model]; ok {
 		if model := c.cfg.GetModel(modelCfg.Provider, modelCfg.Model); model != nil {
@@ -393,16 +479,23 @@ func (c *coordinator) buildTools(ctx context.Context, agent config.Agent) ([]fan
 	}
 
 	allTools = append(allTools,
-		tools.NewBashTool(c.permissions, c.cfg.WorkingDir(), c.cfg.Options.Attribution, modelName),
+		tools.NewBashTool(c.permissions, c.cfg.WorkingDir(), c.cfg.Options.Attribution, modelName, slices.Contains(agent.AllowedTools, tools.GitCommitToolName)),
 		tools.NewJobOutputTool(),
+		tools.NewJobInputTool(c.permissions),
 		tools.NewJobKillTool(),
-		tools.NewDownloadTool(c.permissions, c.cfg.WorkingDir(), nil),
+		tools.NewDownloadTool(c.permissions, c.cfg.WorkingDir(), tools.NewFetchClient(c.cfg.Tools.Fetch, "", 5*time.Minute), c.cfg.Tools.Fetch),
 		tools.NewEditTool(c.lspClients, c.permissions, c.history, c.filetracker, c.cfg.WorkingDir()),
 		tools.NewMultiEditTool(c.lspClients, c.permissions, c.history, c.filetracker, c.cfg.WorkingDir()),
-		tools.NewFetchTool(c.permissions, c.cfg.WorkingDir(), nil),
+		tools.NewNotebookEditTool(c.permissions, c.history, c.filetracker, c.cfg.WorkingDir()),
+		tools.NewFetchTool(c.permissions, c.cfg.WorkingDir(), tools.NewFetchClient(c.cfg.Tools.Fetch, c.fetchCacheDir(), 30*time.Second), c.cfg.Tools.Fetch),
+		tools.NewGitStatusTool(c.cfg.WorkingDir()),
+		tools.NewGitDiffTool(c.cfg.WorkingDir()),
+		tools.NewGitLogTool(c.cfg.WorkingDir()),
+		tools.NewGitCommitTool(c.permissions, c.cfg.WorkingDir(), c.cfg.Options.Attribution, modelName),
 		tools.NewGlobTool(c.cfg.WorkingDir()),
 		tools.NewGrepTool(c.cfg.WorkingDir()),
 		tools.NewLsTool(c.permissions, c.cfg.WorkingDir(), c.cfg.Tools.Ls),
+		tools.NewSkillTool(c.cfg.WorkingDir(), c.cfg.Options.SkillsPaths...),
 		tools.NewSourcegraphTool(nil),
 		tools.NewTodosTool(c.sessions),
 		tools.NewViewTool(c.lspClients, c.permissions, c.filetracker, c.cfg.WorkingDir(), c.cfg.Options.SkillsPaths...),
@@ -413,6 +506,10 @@ func (c *coordinator) buildTools(ctx context.Context, agent config.Agent) ([]fan
 		allTools = append(allTools, tools.NewDiagnosticsTool(c.lspClients), tools.NewReferencesTool(c.lspClients), tools.NewLSPRestartTool(c.lspClients))
 	}
 
+	if len(c.cfg.MCP) > 0 {
+		allTools = append(allTools, tools.NewReadMCPResourceTool(agent.AllowedMCP))
+	}
+
 	var filteredTools []fantasy.AgentTool
 	for _, tool := range allTools {
 		if slices.Contains(agent.AllowedTools, tool.Info().Name) {
@@ -448,6 +545,11 @@ func (c *coordinator) buildTools(ctx context.Context, agent config.Agent) ([]fan
 	return filteredTools, nil
 }
 
+// fetchCacheDir is where the fetch tools cache the pages they fetch.
+func (c *coordinator) fetchCacheDir() string {
+	return filepath.Join(c.cfg.Options.DataDirectory, "fetch-cache")
+}
+
 // TODO: when we support multiple agents we need to change this so that we pass in the agent specific model config
 func (c *coordinator) buildAgentModels(ctx context.Context, isSubAgent bool) (Model, Model, error) {
 	largeModelCfg, ok := c.cfg.Models[config.SelectedModelTypeLarge]
@@ -532,6 +634,84 @@ func (c *coordinator) buildAgentModels(ctx context.Context, isSubAgent bool) (Mo
 		}, nil
 }
 
+// resolveModel resolves a model reference, "large", "small", a model ID or
+// "provider/model", to a selected model.
+func (c *coordinator) resolveModel(ref string) (config.SelectedModel, error) {
+	switch config.SelectedModelType(ref) {
+	case config.SelectedModelTypeLarge, config.SelectedModelTypeSmall:
+		selected, ok := c.cfg.Models[config.SelectedModelType(ref)]
+		if !ok {
+			return config.SelectedModel{}, fmt.Errorf("%s model not selected", ref)
+		}
+		return selected, nil
+	}
+
+	providerID, modelID := "", ref
+	if before, after, ok := strings.Cut(ref, "/"); ok {
+		if _, ok := c.cfg.Providers.Get(before); ok {
+			providerID, modelID = before, after
+		}
+	}
+
+	var matches []config.SelectedModel
+	for id, providerCfg := range c.cfg.Providers.Seq2() {
+		if providerCfg.Disable || (providerID != "" && id != providerID) {
+			continue
+		}
+		for _, m := range providerCfg.Models {
+			if m.ID == modelID {
+				matches = append(matches, config.SelectedModel{Provider: id, Model: m.ID})
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return config.SelectedModel{}, fmt.Errorf("model %q not found", ref)
+	case 1:
+	default:
+		return config.SelectedModel{}, fmt.Errorf("model %q found in multiple providers, use the provider/model format", ref)
+	}
+
+	// Keep the options of the selected models.
+	for _, selected := range c.cfg.Models {
+		if selected.Provider == matches[0].Provider && selected.Model == matches[0].Model {
+			return selected, nil
+		}
+	}
+	return matches[0], nil
+}
+
+// buildModel builds the language model of a selected model.
+func (c *coordinator) buildModel(ctx context.Context, selected config.SelectedModel) (Model, error) {
+	providerCfg, ok := c.cfg.Providers.Get(selected.Provider)
+	if !ok {
+		return Model{}, fmt.Errorf("provider %q not configured", selected.Provider)
+	}
+	catwalkModel := c.cfg.GetModel(selected.Provider, selected.Model)
+	if catwalkModel == nil {
+		return Model{}, fmt.Errorf("model %q not found in provider config", selected.Model)
+	}
+
+	provider, err := c.buildProvider(providerCfg, selected, false)
+	if err != nil {
+		return Model{}, err
+	}
+
+	modelID := selected.Model
+	if selected.Provider == openrouter.Name && isExactoSupported(modelID) {
+		modelID += ":exacto"
+	}
+	languageModel, err := provider.LanguageModel(ctx, modelID)
+	if err != nil {
+		return Model{}, err
+	}
+	return Model{
+		Model:      languageModel,
+		CatwalkCfg: *catwalkModel,
+		ModelCfg:   selected,
+	}, nil
+}
+
 func (c *coordinator) buildAnthropicProvider(baseURL, apiKey string, headers map[string]string) (fantasy.Provider, error) {
 	var opts []anthropic.Option
 
@@ -783,10 +963,16 @@ func isExactoSupported(modelID string) bool {
 }
 
 func (c *coordinator) Cancel(sessionID string) {
+	if cancel, ok := c.tasks.Get(sessionID); ok {
+		cancel()
+	}
 	c.currentAgent.Cancel(sessionID)
 }
 
 func (c *coordinator) CancelAll() {
+	for cancel := range c.tasks.Seq() {
+		cancel()
+	}
 	c.currentAgent.CancelAll()
 }
 
@@ -795,13 +981,28 @@ func (c *coordinator) ClearQueue(sessionID string) {
 }
 
 func (c *coordinator) IsBusy() bool {
-	return c.currentAgent.IsBusy()
+	return c.tasks.Len() > 0 || c.currentAgent.IsBusy()
 }
 
 func (c *coordinator) IsSessionBusy(sessionID string) bool {
+	if _, ok := c.tasks.Get(sessionID); ok {
+		return true
+	}
 	return c.currentAgent.IsSessionBusy(sessionID)
 }
 
+// track registers work running in a session besides its agent, so the
+// session is reported busy and the work can be cancelled. The returned
+// function must be called once the work is done.
+func (c *coordinator) track(ctx context.Context, sessionID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	c.tasks.Set(sessionID, cancel)
+	return ctx, func() {
+		c.tasks.Del(sessionID)
+		cancel()
+	}
+}
+
 func (c *coordinator) Model() Model {
 	return c.currentAgent.Model()
 }
@@ -0,0 +1,103 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/home"
+)
+
+// maxImportDepth limits how deep @path imports of context files nest.
+const maxImportDepth = 5
+
+// nestedContextFileNames are the context files loaded from the directories
+// of the files the agent works with.
+var nestedContextFileNames = []string{
+	"AGENTS.md",
+	"CRUSH.md",
+	"CRUSH.local.md",
+	"CLAUDE.md",
+	"CLAUDE.local.md",
+	"GEMINI.md",
+}
+
+// importPattern matches @path references preceded by a space or at the
+// start of a line.
+var importPattern = regexp.MustCompile(`(^|\s)@(\S+)`)
+
+// DirContextFiles returns the context files found directly in dir, with
+// their imports expanded.
+func DirContextFiles(dir string) []ContextFile {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []ContextFile
+	for _, name := range nestedContextFileNames {
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(entry.Name(), name) {
+				continue
+			}
+			if result := processFile(filepath.Join(dir, entry.Name())); result != nil {
+				files = append(files, *result)
+			}
+		}
+	}
+	return files
+}
+
+// expandImports replaces the @path references to markdown files in the
+// content of a context file with the content of those files. Paths are
+// relative to the file importing them, and references in code blocks are
+// left as is.
+func expandImports(filePath, content string, chain []string) string {
+	if len(chain) >= maxImportDepth {
+		return content
+	}
+	chain = append(chain, filePath)
+
+	lines := strings.SplitAfter(content, "\n")
+	var inCodeBlock bool
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock || !strings.Contains(line, "@") {
+			continue
+		}
+		lines[i] = importPattern.ReplaceAllStringFunc(line, func(match string) string {
+			prefix, ref, _ := strings.Cut(match, "@")
+			path := strings.TrimRight(ref, ".,;:!?)'\"")
+			rest := ref[len(path):]
+			imported, ok := importFile(filepath.Dir(filePath), path, chain)
+			if !ok {
+				return match
+			}
+			return prefix + imported + rest
+		})
+	}
+	return strings.Join(lines, "")
+}
+
+func importFile(dir, path string, chain []string) (string, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".md" && ext != ".markdown" {
+		return "", false
+	}
+	path = home.Long(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if slices.Contains(chain, path) {
+		return "", false
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimRight(expandImports(path, string(content), chain), "\n"), true
+}
@@ -100,7 +100,7 @@ func processFile(filePath string) *ContextFile {
 	}
 	return &ContextFile{
 		Path:    filePath,
-		Content: string(content),
+		Content: expandImports(filePath, string(content), nil),
 	}
 }
 
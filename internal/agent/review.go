@@ -0,0 +1,146 @@
+package agent
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"charm.land/fantasy"
+
+	"github.com/charmbracelet/crush/internal/agent/prompt"
+	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/review"
+)
+
+//go:embed templates/review.md.tpl
+var reviewPromptTmpl []byte
+
+// Review implements Coordinator.
+func (c *coordinator) Review(ctx context.Context, sessionID, revRange string) (*review.Report, error) {
+	if err := c.readyWg.Wait(); err != nil {
+		return nil, err
+	}
+	ctx, done := c.track(ctx, sessionID)
+	defer done()
+
+	diff, err := review.CollectDiff(ctx, c.cfg.WorkingDir(), revRange)
+	if err != nil {
+		return nil, err
+	}
+	report := &review.Report{Range: revRange}
+	if diff.Empty() {
+		report.Summary = "No changes to review."
+		return report, nil
+	}
+
+	promptTemplate, err := prompt.NewPrompt("review", string(reviewPromptTmpl), prompt.WithWorkingDir(c.cfg.WorkingDir()))
+	if err != nil {
+		return nil, fmt.Errorf("error creating prompt: %w", err)
+	}
+	large, small, err := c.buildAgentModels(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("error building models: %w", err)
+	}
+	systemPrompt, err := promptTemplate.Build(ctx, large.Model.Provider(), large.Model.Model(), *c.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error building system prompt: %w", err)
+	}
+	providerCfg, ok := c.cfg.Providers.Get(large.ModelCfg.Provider)
+	if !ok {
+		return nil, errors.New("model provider not configured")
+	}
+
+	// The reviewer only gets read-only tools, and the tool to report its
+	// findings with.
+	var mu sync.Mutex
+	reviewTools := []fantasy.AgentTool{
+		tools.NewViewTool(c.lspClients, c.permissions, c.filetracker, c.cfg.WorkingDir()),
+		tools.NewGrepTool(c.cfg.WorkingDir()),
+		tools.NewReportFindingTool(c.cfg.WorkingDir(), func(finding review.Finding) {
+			mu.Lock()
+			defer mu.Unlock()
+			report.Findings = append(report.Findings, finding)
+		}),
+	}
+	if len(c.cfg.LSP) > 0 {
+		reviewTools = append(reviewTools, tools.NewDiagnosticsTool(c.lspClients), tools.NewReferencesTool(c.lspClients))
+	}
+
+	agent := NewSessionAgent(SessionAgentOptions{
+		LargeModel:           large,
+		SmallModel:           small,
+		SystemPromptPrefix:   providerCfg.SystemPromptPrefix,
+		SystemPrompt:         systemPrompt,
+		IsSubAgent:           true,
+		DisableAutoSummarize: c.cfg.Options.DisableAutoSummarize,
+		IsYolo:               c.permissions.SkipRequests(),
+		Sessions:             c.sessions,
+		Messages:             c.messages,
+		Tools:                reviewTools,
+	})
+
+	reviewPrompt, cleanup, err := c.reviewPrompt(diff)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	maxTokens := large.CatwalkCfg.DefaultMaxTokens
+	if large.ModelCfg.MaxTokens != 0 {
+		maxTokens = large.ModelCfg.MaxTokens
+	}
+	mergedOptions, temp, topP, topK, freqPenalty, presPenalty := mergeCallOptions(large, providerCfg)
+	result, err := agent.Run(ctx, SessionAgentCall{
+		SessionID:        sessionID,
+		Prompt:           reviewPrompt,
+		MaxOutputTokens:  maxTokens,
+		ProviderOptions:  mergedOptions,
+		Temperature:      temp,
+		TopP:             topP,
+		TopK:             topK,
+		FrequencyPenalty: freqPenalty,
+		PresencePenalty:  presPenalty,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Summary = result.Response.Content.Text()
+	report.Sort()
+	return report, nil
+}
+
+// reviewPrompt builds the prompt asking to review a diff. Large diffs are
+// saved to a file the reviewer reads with its tools, which is removed by the
+// returned cleanup function.
+func (c *coordinator) reviewPrompt(diff *review.Diff) (string, func(), error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Review the %s.\n\nChanged files:\n", review.RangeTitle(diff.Range))
+	for _, file := range diff.Files {
+		fmt.Fprintf(&b, "- %s\n", file)
+	}
+
+	if len(diff.Patch) <= tools.LargeContentThreshold {
+		fmt.Fprintf(&b, "\n<diff>\n%s</diff>", diff.Patch)
+		return b.String(), func() {}, nil
+	}
+
+	if err := os.MkdirAll(c.cfg.Options.DataDirectory, 0o755); err != nil {
+		return "", nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	f, err := os.CreateTemp(c.cfg.Options.DataDirectory, "crush-review-*.diff")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create diff file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(diff.Patch); err != nil {
+		os.Remove(f.Name())
+		return "", nil, fmt.Errorf("failed to write diff file: %w", err)
+	}
+	fmt.Fprintf(&b, "\nThe diff is too large to include, it has been saved to: %s\n\nUse the view and grep tools to read it.", f.Name())
+	return b.String(), func() { os.Remove(f.Name()) }, nil
+}
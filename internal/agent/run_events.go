@@ -0,0 +1,30 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/pubsub"
+)
+
+// RunEvent is published when the coordinator finishes running the prompts
+// of a session, including the prompts queued while it was busy.
+type RunEvent struct {
+	SessionID string
+	Duration  time.Duration
+	// Err is the error the run ended with, if any, e.g. [context.Canceled]
+	// when cancelled.
+	Err error
+}
+
+var runBroker = pubsub.NewBroker[RunEvent]()
+
+// SubscribeRunEvents returns a channel for the events of finished runs.
+func SubscribeRunEvents(ctx context.Context, opts ...pubsub.SubscribeOption) <-chan pubsub.Event[RunEvent] {
+	return runBroker.Subscribe(ctx, opts...)
+}
+
+// RunEventStats returns the event counters of the run events broker.
+func RunEventStats() pubsub.Stats {
+	return runBroker.Stats()
+}
@@ -0,0 +1,92 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
+)
+
+// sample serves the sampling requests of MCP servers with the small model.
+func (c *coordinator) sample(ctx context.Context, req mcp.SamplingRequest) (mcp.SamplingResponse, error) {
+	_, small, err := c.buildAgentModels(ctx, true)
+	if err != nil {
+		return mcp.SamplingResponse{}, fmt.Errorf("failed to build small model: %w", err)
+	}
+
+	var prompt fantasy.Prompt
+	if req.SystemPrompt != "" {
+		prompt = append(prompt, fantasy.NewSystemMessage(req.SystemPrompt))
+	}
+	for _, m := range req.Messages {
+		msg := fantasy.Message{Role: fantasy.MessageRoleUser}
+		if m.Role == "assistant" {
+			msg.Role = fantasy.MessageRoleAssistant
+		}
+		if len(m.Data) > 0 {
+			if !small.CatwalkCfg.SupportsImages {
+				return mcp.SamplingResponse{}, errors.New("the small model does not support media content")
+			}
+			msg.Content = append(msg.Content, fantasy.FilePart{Data: m.Data, MediaType: m.MediaType})
+		} else {
+			msg.Content = append(msg.Content, fantasy.TextPart{Text: m.Text})
+		}
+		prompt = append(prompt, msg)
+	}
+
+	call := fantasy.Call{Prompt: prompt}
+	maxTokens := small.CatwalkCfg.DefaultMaxTokens
+	if req.MaxTokens > 0 && (maxTokens == 0 || req.MaxTokens < maxTokens) {
+		maxTokens = req.MaxTokens
+	}
+	if maxTokens > 0 {
+		call.MaxOutputTokens = &maxTokens
+	}
+	if req.Temperature > 0 {
+		call.Temperature = &req.Temperature
+	}
+
+	resp, err := small.Model.Generate(ctx, call)
+	if err != nil {
+		return mcp.SamplingResponse{}, err
+	}
+
+	stopReason := string(resp.FinishReason)
+	switch resp.FinishReason {
+	case fantasy.FinishReasonStop:
+		stopReason = "endTurn"
+	case fantasy.FinishReasonLength:
+		stopReason = "maxTokens"
+	}
+	text, stopped := cutStopSequences(resp.Content.Text(), req.StopSequences)
+	if stopped {
+		stopReason = "stopSequence"
+	}
+	return mcp.SamplingResponse{
+		Text:       text,
+		Model:      small.ModelCfg.Model,
+		StopReason: stopReason,
+	}, nil
+}
+
+// cutStopSequences truncates the text at the first of the stop sequences it
+// contains. Providers don't take stop sequences through fantasy, so they are
+// applied to the generated text instead.
+func cutStopSequences(text string, stopSequences []string) (string, bool) {
+	end := -1
+	for _, seq := range stopSequences {
+		if seq == "" {
+			continue
+		}
+		if i := strings.Index(text, seq); i >= 0 && (end < 0 || i < end) {
+			end = i
+		}
+	}
+	if end < 0 {
+		return text, false
+	}
+	return text[:end], true
+}
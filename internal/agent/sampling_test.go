@@ -0,0 +1,19 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCutStopSequences(t *testing.T) {
+	t.Parallel()
+
+	text, stopped := cutStopSequences("one\ntwo\nEND three", []string{"", "END", "\n"})
+	require.True(t, stopped)
+	require.Equal(t, "one", text)
+
+	text, stopped = cutStopSequences("one two", []string{"END"})
+	require.False(t, stopped)
+	require.Equal(t, "one two", text)
+}
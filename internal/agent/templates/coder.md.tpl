@@ -375,9 +375,10 @@ Diagnostics (lint/typecheck) included in tool output.
 {{.AvailSkillXML}}
 
 <skills_usage>
-When a user task matches a skill's description, read the skill's SKILL.md file to get full instructions.
-Skills are activated by reading their location path. Follow the skill's instructions to complete the task.
+When a user task matches a skill's description, activate it with the `skill` tool to get its full instructions and the list of files bundled with it.
+If the `skill` tool is not available, read the skill's SKILL.md file at its location instead. Follow the skill's instructions to complete the task.
 If a skill mentions scripts, references, or assets, they are placed in the same folder as the skill itself (e.g., scripts/, references/, assets/ subdirectories within the skill's folder).
+Some skills restrict the tools available while they are active.
 </skills_usage>
 {{end}}
 
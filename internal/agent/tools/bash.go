@@ -24,6 +24,7 @@ type BashParams struct {
 	Command         string `json:"command" description:"The command to execute"`
 	WorkingDir      string `json:"working_dir,omitempty" description:"The working directory to execute the command in (defaults to current directory)"`
 	RunInBackground bool   `json:"run_in_background,omitempty" description:"Set to true (boolean) to run this command in the background. Use job_output to read the output later."`
+	PTY             bool   `json:"pty,omitempty" description:"Set to true (boolean) to run the command in a pseudo-terminal for programs that need a TTY. Implies run_in_background. Use job_input to send input."`
 }
 
 type BashPermissionsParams struct {
@@ -31,6 +32,7 @@ type BashPermissionsParams struct {
 	Command         string `json:"command"`
 	WorkingDir      string `json:"working_dir"`
 	RunInBackground bool   `json:"run_in_background"`
+	PTY             bool   `json:"pty"`
 }
 
 type BashResponseMetadata struct {
@@ -40,6 +42,7 @@ type BashResponseMetadata struct {
 	Description      string `json:"description"`
 	WorkingDirectory string `json:"working_directory"`
 	Background       bool   `json:"background,omitempty"`
+	PTY              bool   `json:"pty,omitempty"`
 	ShellID          string `json:"shell_id,omitempty"`
 }
 
@@ -64,6 +67,9 @@ type bashDescriptionData struct {
 	MaxOutputLength int
 	Attribution     config.Attribution
 	ModelName       string
+	// GitTools tells whether commits are made with the git_commit tool
+	// rather than by running git.
+	GitTools bool
 }
 
 var bannedCommands = []string{
@@ -139,7 +145,7 @@ var bannedCommands = []string{
 	"ufw",
 }
 
-func bashDescription(attribution *config.Attribution, modelName string) string {
+func bashDescription(attribution *config.Attribution, modelName string, gitTools bool) string {
 	bannedCommandsStr := strings.Join(bannedCommands, ", ")
 	var out bytes.Buffer
 	if err := bashDescriptionTpl.Execute(&out, bashDescriptionData{
@@ -147,6 +153,7 @@ func bashDescription(attribution *config.Attribution, modelName string) string {
 		MaxOutputLength: MaxOutputLength,
 		Attribution:     *attribution,
 		ModelName:       modelName,
+		GitTools:        gitTools,
 	}); err != nil {
 		// this should never happen.
 		panic("failed to execute bash description template: " + err.Error())
@@ -154,7 +161,9 @@ func bashDescription(attribution *config.Attribution, modelName string) string {
 	return out.String()
 }
 
-func blockFuncs() []shell.BlockFunc {
+// BlockFuncs returns the functions blocking the commands the agent isn't
+// allowed to run.
+func BlockFuncs() []shell.BlockFunc {
 	return []shell.BlockFunc{
 		shell.CommandsBlocker(bannedCommands),
 
@@ -186,10 +195,13 @@ func blockFuncs() []shell.BlockFunc {
 	}
 }
 
-func NewBashTool(permissions permission.Service, workingDir string, attribution *config.Attribution, modelName string) fantasy.AgentTool {
+// NewBashTool creates the bash tool. When gitTools is set, the agent is told
+// to commit with the git_commit tool; otherwise it commits by running git,
+// adding the attribution itself.
+func NewBashTool(permissions permission.Service, workingDir string, attribution *config.Attribution, modelName string, gitTools bool) fantasy.AgentTool {
 	return fantasy.NewAgentTool(
 		BashToolName,
-		string(bashDescription(attribution, modelName)),
+		string(bashDescription(attribution, modelName, gitTools)),
 		func(ctx context.Context, params BashParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
 			if params.Command == "" {
 				return fantasy.NewTextErrorResponse("missing command"), nil
@@ -234,13 +246,22 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 				}
 			}
 
-			// If explicitly requested as background, start immediately with detached context
-			if params.RunInBackground {
+			// If explicitly requested as background, start immediately with detached context.
+			// Interactive commands always run in the background, as they may
+			// wait for input.
+			if params.RunInBackground || params.PTY {
 				startTime := time.Now()
 				bgManager := shell.GetBackgroundShellManager()
 				bgManager.Cleanup()
 				// Use background context so it continues after tool returns
-				bgShell, err := bgManager.Start(context.Background(), execWorkingDir, blockFuncs(), params.Command, params.Description)
+				bgShell, err := bgManager.StartWithOptions(context.Background(), shell.BackgroundOptions{
+					SessionID:   sessionID,
+					WorkingDir:  execWorkingDir,
+					BlockFuncs:  BlockFuncs(),
+					Command:     params.Command,
+					Description: params.Description,
+					PTY:         params.PTY,
+				})
 				if err != nil {
 					return fantasy.ToolResponse{}, fmt.Errorf("error starting background shell: %w", err)
 				}
@@ -277,15 +298,20 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 				}
 
 				// Still running after fast-failure check - return as background job
+				bgShell.MoveToBackground()
 				metadata := BashResponseMetadata{
 					StartTime:        startTime.UnixMilli(),
 					EndTime:          time.Now().UnixMilli(),
 					Description:      params.Description,
 					WorkingDirectory: bgShell.WorkingDir,
 					Background:       true,
+					PTY:              params.PTY,
 					ShellID:          bgShell.ID,
 				}
 				response := fmt.Sprintf("Background shell started with ID: %s\n\nUse job_output tool to view output or job_kill to terminate.", bgShell.ID)
+				if params.PTY {
+					response = fmt.Sprintf("Interactive background shell started with ID: %s\n\nUse job_input tool to send input, job_output tool to view output or job_kill to terminate.", bgShell.ID)
+				}
 				return fantasy.WithResponseMetadata(fantasy.NewTextResponse(response), metadata), nil
 			}
 
@@ -295,7 +321,7 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 			// Start with detached context so it can survive if moved to background
 			bgManager := shell.GetBackgroundShellManager()
 			bgManager.Cleanup()
-			bgShell, err := bgManager.Start(context.Background(), execWorkingDir, blockFuncs(), params.Command, params.Description)
+			bgShell, err := bgManager.StartInSession(context.Background(), sessionID, execWorkingDir, BlockFuncs(), params.Command, params.Description)
 			if err != nil {
 				return fantasy.ToolResponse{}, fmt.Errorf("error starting shell: %w", err)
 			}
@@ -358,6 +384,7 @@ func NewBashTool(permissions permission.Service, workingDir string, attribution
 			}
 
 			// Still running - keep as background job
+			bgShell.MoveToBackground()
 			metadata := BashResponseMetadata{
 				StartTime:        startTime.UnixMilli(),
 				EndTime:          time.Now().UnixMilli(),
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/agent/prompt"
+	"github.com/charmbracelet/crush/internal/csync"
+)
+
+// loadedContextFiles holds the paths of the nested context files already
+// given to the agent, by session ID.
+var loadedContextFiles = csync.NewMap[string, *csync.Map[string, bool]]()
+
+// ForgetContextFiles forgets the nested context files given to the agent in
+// a session, once they are no longer part of its conversation: when the
+// session is summarized, or deleted.
+func ForgetContextFiles(sessionID string) {
+	loadedContextFiles.Del(sessionID)
+}
+
+// getNestedContext returns the context files, such as AGENTS.md, of the
+// directories between the working directory and the file the agent works
+// with, that were not given to the agent yet in the session. The context
+// files of the working directory itself are part of the system prompt.
+func getNestedContext(ctx context.Context, workingDir, filePath string) string {
+	sessionID := GetSessionFromContext(ctx)
+	if sessionID == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(workingDir, filepath.Dir(filePath))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+
+	loaded := loadedContextFiles.GetOrSet(sessionID, csync.NewMap[string, bool])
+	var sb strings.Builder
+	dir := workingDir
+	for part := range strings.SplitSeq(rel, string(filepath.Separator)) {
+		dir = filepath.Join(dir, part)
+		for _, file := range prompt.DirContextFiles(dir) {
+			if _, ok := loaded.Get(file.Path); ok {
+				continue
+			}
+			loaded.Set(file.Path, true)
+			if file.Path == filePath {
+				// The agent is already reading it.
+				continue
+			}
+			relPath, _ := filepath.Rel(workingDir, file.Path)
+			fmt.Fprintf(&sb, "<file path=%q>\n%s\n</file>\n", filepath.ToSlash(relPath), strings.TrimSpace(file.Content))
+		}
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "\n<project_instructions>\nThe directories of this file have instructions for working in them. Follow them, they take precedence over the general project instructions:\n" +
+		sb.String() + "</project_instructions>\n"
+}
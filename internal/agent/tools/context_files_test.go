@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNestedContext(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	api := filepath.Join(workingDir, "services", "api")
+	require.NoError(t, os.MkdirAll(filepath.Join(api, "handlers"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(workingDir, "docs"), 0o755))
+
+	writeFile := func(path, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	writeFile(filepath.Join(workingDir, "AGENTS.md"), "root instructions")
+	writeFile(filepath.Join(workingDir, "docs", "style.md"), "Use tabs.\n")
+	writeFile(filepath.Join(workingDir, "services", "CRUSH.md"), "services instructions")
+	writeFile(filepath.Join(api, "AGENTS.md"), "api instructions\nSee @../../docs/style.md.\n```\n@../../docs/style.md\n```\n")
+	writeFile(filepath.Join(api, "handlers", "users.go"), "package handlers")
+
+	ctx := context.WithValue(t.Context(), SessionIDContextKey, "session-1")
+	file := filepath.Join(api, "handlers", "users.go")
+
+	got := getNestedContext(ctx, workingDir, file)
+	require.Contains(t, got, `<file path="services/CRUSH.md">`+"\nservices instructions\n</file>")
+	require.Contains(t, got, `<file path="services/api/AGENTS.md">`+"\napi instructions\nSee Use tabs..\n```\n@../../docs/style.md\n```\n</file>")
+	require.NotContains(t, got, "root instructions")
+
+	// Context files are only given once per session.
+	require.Empty(t, getNestedContext(ctx, workingDir, file))
+	otherSession := context.WithValue(t.Context(), SessionIDContextKey, "session-2")
+	require.NotEmpty(t, getNestedContext(otherSession, workingDir, file))
+
+	// Once forgotten, e.g. after a summary, they are given again.
+	ForgetContextFiles("session-1")
+	require.Equal(t, got, getNestedContext(ctx, workingDir, file))
+
+	// Files outside the working directory have no nested context.
+	require.Empty(t, getNestedContext(ctx, api, filepath.Join(workingDir, "AGENTS.md")))
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/filepathext"
 	"github.com/charmbracelet/crush/internal/permission"
 )
@@ -34,7 +35,7 @@ const DownloadToolName = "download"
 //go:embed download.md
 var downloadDescription []byte
 
-func NewDownloadTool(permissions permission.Service, workingDir string, client *http.Client) fantasy.AgentTool {
+func NewDownloadTool(permissions permission.Service, workingDir string, client *http.Client, fetchCfg config.ToolFetch) fantasy.AgentTool {
 	if client == nil {
 		client = &http.Client{
 			Timeout: 5 * time.Minute, // Default 5 minute timeout for downloads
@@ -70,21 +71,30 @@ func NewDownloadTool(permissions permission.Service, workingDir string, client *
 				return fantasy.ToolResponse{}, fmt.Errorf("session ID is required for downloading files")
 			}
 
-			p, err := permissions.Request(ctx,
-				permission.CreatePermissionRequest{
-					SessionID:   sessionID,
-					Path:        filePath,
-					ToolName:    DownloadToolName,
-					Action:      "download",
-					Description: fmt.Sprintf("Download file from URL: %s to %s", params.URL, filePath),
-					Params:      DownloadPermissionsParams(params),
-				},
-			)
-			if err != nil {
-				return fantasy.ToolResponse{}, err
+			allowed, denied := FetchDomainPolicy(fetchCfg, params.URL)
+			if denied {
+				return fantasy.NewTextErrorResponse(DeniedDomainError(params.URL)), nil
 			}
-			if !p {
-				return fantasy.ToolResponse{}, permission.ErrorPermissionDenied
+			// An allowed domain only skips the prompt for files inside the
+			// working directory, as the download writes to disk.
+			insideWorkingDir := !filepath.IsAbs(relPath) && relPath != ".." && !strings.HasPrefix(relPath, "../")
+			if !allowed || !insideWorkingDir {
+				p, err := permissions.Request(ctx,
+					permission.CreatePermissionRequest{
+						SessionID:   sessionID,
+						Path:        filePath,
+						ToolName:    DownloadToolName,
+						Action:      "download",
+						Description: fmt.Sprintf("Download file from URL: %s to %s", params.URL, filePath),
+						Params:      DownloadPermissionsParams(params),
+					},
+				)
+				if err != nil {
+					return fantasy.ToolResponse{}, err
+				}
+				if !p {
+					return fantasy.ToolResponse{}, permission.ErrorPermissionDenied
+				}
 			}
 
 			// Handle timeout with context
@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/httpcache"
+)
+
+const (
+	// robotsUserAgent is the user agent matched against robots.txt groups.
+	robotsUserAgent = "crush"
+	// maxRobotsSize is the maximum size of a robots.txt file that is read.
+	maxRobotsSize = 512 * 1024
+	robotsTimeout = 10 * time.Second
+)
+
+// NewFetchClient returns an HTTP client for the fetch tools. It refuses
+// denied domains and, if enabled, pages disallowed by robots.txt. Responses
+// are cached in cacheDir unless it's empty or caching is disabled.
+func NewFetchClient(cfg config.ToolFetch, cacheDir string, timeout time.Duration) *http.Client {
+	var transport http.RoundTripper = &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	if cacheDir != "" && cfg.CacheEnabled() {
+		transport = httpcache.New(cacheDir, cfg.MaxSize(), transport)
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &fetchGuard{
+			cfg:    cfg,
+			next:   transport,
+			robots: make(map[string]*robotsEntry),
+		},
+	}
+}
+
+// FetchDomainPolicy reports whether a URL may be fetched without asking for
+// permission, or must not be fetched at all, according to the allowed and
+// denied domains of the fetch configuration.
+func FetchDomainPolicy(cfg config.ToolFetch, rawURL string) (allowed, denied bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false, false
+	}
+	host := strings.ToLower(u.Hostname())
+	if matchesDomain(cfg.DeniedDomains, host) {
+		return false, true
+	}
+	return matchesDomain(cfg.AllowedDomains, host), false
+}
+
+// DeniedDomainError is the tool error for URLs of denied domains.
+func DeniedDomainError(rawURL string) string {
+	return fmt.Sprintf("Fetching %s is not allowed: its domain is in tools.fetch.denied_domains", rawURL)
+}
+
+func matchesDomain(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if ok, _ := path.Match(pattern, host); ok || strings.HasSuffix(host, "."+pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchGuard is an [http.RoundTripper] that enforces the denied domains and
+// robots.txt rules of the fetch configuration, including on redirects.
+type fetchGuard struct {
+	cfg  config.ToolFetch
+	next http.RoundTripper
+
+	mu     sync.Mutex
+	robots map[string]*robotsEntry
+}
+
+// robotsEntry holds the robots.txt rules of a site. done is closed once they
+// are fetched.
+type robotsEntry struct {
+	done  chan struct{}
+	rules *robotsRules
+}
+
+func (g *fetchGuard) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, denied := FetchDomainPolicy(g.cfg, req.URL.String()); denied {
+		return nil, fmt.Errorf("%s is in tools.fetch.denied_domains", req.URL.Hostname())
+	}
+	if g.cfg.RobotsEnabled() && req.URL.Path != "/robots.txt" {
+		rules := g.robotsRules(req.Context(), req.URL)
+		if !rules.allowed(req.URL) {
+			return nil, fmt.Errorf("%s is disallowed by the robots.txt of %s", req.URL.Path, req.URL.Host)
+		}
+	}
+	return g.next.RoundTrip(req)
+}
+
+// robotsRules returns the robots.txt rules of the site of a URL, fetching
+// them the first time the site is seen. Requests to the same site wait for
+// that fetch, requests to other sites don't.
+func (g *fetchGuard) robotsRules(ctx context.Context, u *url.URL) *robotsRules {
+	site := u.Scheme + "://" + u.Host
+	g.mu.Lock()
+	entry, ok := g.robots[site]
+	if !ok {
+		entry = &robotsEntry{done: make(chan struct{})}
+		g.robots[site] = entry
+	}
+	g.mu.Unlock()
+
+	if ok {
+		select {
+		case <-entry.done:
+			return entry.rules
+		case <-ctx.Done():
+			return &robotsRules{}
+		}
+	}
+
+	entry.rules = g.fetchRobots(ctx, site)
+	if ctx.Err() != nil {
+		// The request was cancelled, not the site: fetch them again next
+		// time.
+		g.mu.Lock()
+		delete(g.robots, site)
+		g.mu.Unlock()
+	}
+	close(entry.done)
+	return entry.rules
+}
+
+func (g *fetchGuard) fetchRobots(ctx context.Context, site string) *robotsRules {
+	ctx, cancel := context.WithTimeout(ctx, robotsTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, site+"/robots.txt", nil)
+	if err != nil {
+		return &robotsRules{}
+	}
+	req.Header.Set("User-Agent", "crush/1.0")
+	resp, err := g.next.RoundTrip(req)
+	if err != nil {
+		return &robotsRules{}
+	}
+	defer resp.Body.Close()
+	// A missing or broken robots.txt allows everything.
+	if resp.StatusCode != http.StatusOK {
+		return &robotsRules{}
+	}
+	return parseRobots(io.LimitReader(resp.Body, maxRobotsSize), robotsUserAgent)
+}
+
+type robotsRule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+// robotsRules are the rules of a robots.txt file that apply to a user
+// agent.
+type robotsRules struct {
+	rules []robotsRule
+}
+
+// allowed reports whether a URL may be fetched. The longest matching rule
+// wins, and Allow wins ties.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	p := u.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	if u.RawQuery != "" {
+		p += "?" + u.RawQuery
+	}
+	allow, length := true, -1
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(p) {
+			continue
+		}
+		if rule.length > length || (rule.length == length && rule.allow) {
+			allow, length = rule.allow, rule.length
+		}
+	}
+	return allow
+}
+
+// parseRobots parses the rules of a robots.txt file for a user agent. The
+// groups naming the agent are used if there are any, otherwise the groups
+// for all agents (*).
+func parseRobots(r io.Reader, userAgent string) *robotsRules {
+	var specific, wildcard []robotsRule
+	var hasSpecific, groupSpecific, groupWildcard bool
+	inRules := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// A user-agent line after rules starts a new group.
+			if inRules {
+				groupSpecific, groupWildcard, inRules = false, false, false
+			}
+			agent := strings.ToLower(value)
+			if agent == "*" {
+				groupWildcard = true
+			} else if agent != "" && strings.Contains(userAgent, agent) {
+				groupSpecific, hasSpecific = true, true
+			}
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				// An empty Disallow allows everything.
+				continue
+			}
+			rule := robotsRule{
+				allow:   key == "allow",
+				length:  len(value),
+				pattern: robotsPattern(value),
+			}
+			if groupSpecific {
+				specific = append(specific, rule)
+			}
+			if groupWildcard {
+				wildcard = append(wildcard, rule)
+			}
+		}
+	}
+	if hasSpecific {
+		return &robotsRules{rules: specific}
+	}
+	return &robotsRules{rules: wildcard}
+}
+
+// robotsPattern compiles a robots.txt path pattern, where * matches any
+// sequence of characters and a trailing $ anchors the end of the path.
+func robotsPattern(value string) *regexp.Regexp {
+	anchored := strings.HasSuffix(value, "$")
+	value = strings.TrimSuffix(value, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/httpcache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchDomainPolicy(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.ToolFetch{
+		AllowedDomains: []string{"go.dev", "*.github.io", "docs.*"},
+		DeniedDomains:  []string{"secret.go.dev"},
+	}
+	for rawURL, want := range map[string][2]bool{
+		"https://go.dev/doc":            {true, false},
+		"https://pkg.go.dev/net/http":   {true, false},
+		"https://GO.DEV/":               {true, false},
+		"https://user.github.io/page":   {true, false},
+		"https://github.io/":            {false, false},
+		"https://docs.example.com/":     {true, false},
+		"https://secret.go.dev/":        {false, true},
+		"https://api.secret.go.dev/x":   {false, true},
+		"https://example.com/go.dev":    {false, false},
+		"https://notgo.dev/":            {false, false},
+		"https://go.dev.example.com/":   {false, false},
+		"https://docs.example.com:8080": {true, false},
+	} {
+		allowed, denied := FetchDomainPolicy(cfg, rawURL)
+		require.Equal(t, want, [2]bool{allowed, denied}, rawURL)
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	t.Parallel()
+
+	const robots = `
+# Everyone.
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+
+User-agent: Googlebot
+Disallow: /
+`
+	rules := parseRobots(strings.NewReader(robots), robotsUserAgent)
+	for path, want := range map[string]bool{
+		"/":                     true,
+		"/docs":                 true,
+		"/private/":             false,
+		"/private/secret":       false,
+		"/private/public/page":  true,
+		"/paper.pdf":            false,
+		"/paper.pdf?download=1": true,
+	} {
+		u, err := url.Parse("https://example.com" + path)
+		require.NoError(t, err)
+		require.Equal(t, want, rules.allowed(u), path)
+	}
+
+	t.Run("specific group wins", func(t *testing.T) {
+		t.Parallel()
+		rules := parseRobots(strings.NewReader(`
+User-agent: *
+Disallow: /
+
+User-agent: Crush
+Disallow:
+`), robotsUserAgent)
+		u, _ := url.Parse("https://example.com/docs")
+		require.True(t, rules.allowed(u))
+	})
+}
+
+func TestNewFetchClient(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			_, _ = io.WriteString(w, "User-agent: *\nDisallow: /private\n")
+			return
+		}
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = io.WriteString(w, "hello")
+	}))
+	t.Cleanup(srv.Close)
+
+	respectRobots := true
+	client := NewFetchClient(config.ToolFetch{RespectRobots: &respectRobots}, t.TempDir(), 5*time.Second)
+
+	get := func(path string) (*http.Response, error) {
+		resp, err := client.Get(srv.URL + path)
+		if err == nil {
+			_, _ = io.ReadAll(resp.Body)
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	resp, err := get("/docs")
+	require.NoError(t, err)
+	require.Empty(t, resp.Header.Get(httpcache.CacheHeader))
+	resp, err = get("/docs")
+	require.NoError(t, err)
+	require.Equal(t, "hit", resp.Header.Get(httpcache.CacheHeader))
+	require.Equal(t, int64(1), hits.Load())
+
+	_, err = get("/private/page")
+	require.ErrorContains(t, err, "disallowed by the robots.txt")
+	require.Equal(t, int64(1), hits.Load())
+
+	denied := NewFetchClient(config.ToolFetch{DeniedDomains: []string{"127.0.0.1"}}, "", 5*time.Second)
+	_, err = denied.Get(srv.URL)
+	require.ErrorContains(t, err, "denied_domains")
+}
+
+func TestFetchClientSlowRobots(t *testing.T) {
+	t.Parallel()
+
+	requested := make(chan struct{})
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			close(requested)
+			<-release
+			return
+		}
+		_, _ = io.WriteString(w, "slow")
+	}))
+	t.Cleanup(slow.Close)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "fast")
+	}))
+	t.Cleanup(fast.Close)
+
+	respectRobots := true
+	client := NewFetchClient(config.ToolFetch{RespectRobots: &respectRobots}, "", 5*time.Second)
+
+	slowDone := make(chan error, 1)
+	go func() {
+		resp, err := client.Get(slow.URL + "/page")
+		if err == nil {
+			resp.Body.Close()
+		}
+		slowDone <- err
+	}()
+	<-requested
+
+	// Another site isn't held up by the robots.txt of the slow one.
+	resp, err := client.Get(fast.URL + "/page")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	close(release)
+	require.NoError(t, <-slowDone)
+}
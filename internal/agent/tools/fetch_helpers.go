@@ -21,8 +21,9 @@ const BrowserUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/
 
 var multipleNewlinesRe = regexp.MustCompile(`\n{3,}`)
 
-// FetchURLAndConvert fetches a URL and converts HTML content to markdown.
-func FetchURLAndConvert(ctx context.Context, client *http.Client, url string) (string, error) {
+// FetchURLAndConvert fetches a URL and converts HTML content to markdown,
+// reading at most maxSize bytes of it.
+func FetchURLAndConvert(ctx context.Context, client *http.Client, url string, maxSize int64) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
@@ -43,7 +44,6 @@ func FetchURLAndConvert(ctx context.Context, client *http.Client, url string) (s
 		return "", fmt.Errorf("request failed with status code: %d", resp.StatusCode)
 	}
 
-	maxSize := int64(5 * 1024 * 1024) // 5MB
 	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
 	if err != nil {
 		return "", fmt.Errorf("failed to read response body: %w", err)
@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// MaxGitOutputLength bounds the output of the git tools.
+	MaxGitOutputLength = 30000
+
+	// maxGitListEntries bounds the number of files listed per section of the
+	// git_status output.
+	maxGitListEntries = 200
+)
+
+// validateGitRef rejects refs that git would take as options.
+func validateGitRef(ref string) error {
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid ref %q", ref)
+	}
+	return nil
+}
+
+// truncateGitOutput truncates an output to MaxGitOutputLength, noting how much
+// was left out.
+func truncateGitOutput(s string) string {
+	if len(s) <= MaxGitOutputLength {
+		return s
+	}
+	cut := strings.LastIndexByte(s[:MaxGitOutputLength], '\n')
+	if cut < 0 {
+		cut = MaxGitOutputLength
+	}
+	return fmt.Sprintf("%s\n\n... [%d characters truncated]", s[:cut], len(s)-cut)
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/filepathext"
+	"github.com/charmbracelet/crush/internal/git"
+	"github.com/charmbracelet/crush/internal/permission"
+)
+
+type GitCommitParams struct {
+	Message string   `json:"message" description:"The commit message"`
+	Files   []string `json:"files,omitempty" description:"Files to stage before committing"`
+	All     bool     `json:"all,omitempty" description:"Stage all the changes of tracked files before committing"`
+}
+
+type GitCommitPermissionsParams struct {
+	Message string   `json:"message"`
+	Files   []string `json:"files,omitempty"`
+	All     bool     `json:"all,omitempty"`
+}
+
+type GitCommitResponseMetadata struct {
+	Hash    string `json:"hash"`
+	Message string `json:"message"`
+}
+
+const (
+	GitCommitToolName = "git_commit"
+
+	gitGeneratedWith = "💘 Generated with Crush"
+)
+
+//go:embed git_commit.md
+var gitCommitDescription []byte
+
+func NewGitCommitTool(permissions permission.Service, workingDir string, attribution *config.Attribution, modelName string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		GitCommitToolName,
+		string(gitCommitDescription),
+		func(ctx context.Context, params GitCommitParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			message := strings.TrimSpace(params.Message)
+			if message == "" {
+				return fantasy.NewTextErrorResponse("message is required"), nil
+			}
+
+			sessionID := GetSessionFromContext(ctx)
+			if sessionID == "" {
+				return fantasy.ToolResponse{}, fmt.Errorf("session ID is required for creating a commit")
+			}
+
+			p, err := permissions.Request(ctx,
+				permission.CreatePermissionRequest{
+					SessionID:   sessionID,
+					Path:        workingDir,
+					ToolCallID:  call.ID,
+					ToolName:    GitCommitToolName,
+					Action:      "commit",
+					Description: fmt.Sprintf("Create a commit: %s", firstLine(message)),
+					Params:      GitCommitPermissionsParams(params),
+				},
+			)
+			if err != nil {
+				return fantasy.ToolResponse{}, err
+			}
+			if !p {
+				return fantasy.ToolResponse{}, permission.ErrorPermissionDenied
+			}
+
+			if len(params.Files) > 0 {
+				args := []string{"add", "--"}
+				for _, file := range params.Files {
+					args = append(args, filepathext.SmartJoin(workingDir, file))
+				}
+				if _, err := git.Run(ctx, workingDir, nil, args...); err != nil {
+					return fantasy.NewTextErrorResponse(gitCommitError(workingDir, err)), nil
+				}
+			}
+
+			args := []string{"commit", "--file=-", "--cleanup=whitespace"}
+			if params.All {
+				args = append(args, "--all")
+			}
+			if trailer := gitAttributionTrailer(attribution, modelName); trailer != "" {
+				args = append(args, "--trailer", trailer)
+			}
+			if _, err := git.Run(ctx, workingDir, []byte(gitCommitMessage(message, attribution)), args...); err != nil {
+				return fantasy.NewTextErrorResponse(gitCommitError(workingDir, err)), nil
+			}
+
+			out, err := git.Run(ctx, workingDir, nil, "log", "-1", "--shortstat", "--format=%h%x1f%B%x1e")
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			commit, shortstat, _ := strings.Cut(string(out), "\x1e")
+			hash, body, _ := strings.Cut(commit, "\x1f")
+			body = strings.TrimSpace(body)
+			return fantasy.WithResponseMetadata(
+				fantasy.NewTextResponse(fmt.Sprintf("Created commit %s: %s\n%s", hash, firstLine(body), strings.TrimSpace(shortstat))),
+				GitCommitResponseMetadata{Hash: hash, Message: body},
+			), nil
+		})
+}
+
+// gitCommitMessage adds the "Generated with Crush" line to a commit message
+// when the attribution asks for it.
+func gitCommitMessage(message string, attribution *config.Attribution) string {
+	if attribution != nil && attribution.GeneratedWith && !strings.Contains(message, gitGeneratedWith) {
+		message += "\n\n" + gitGeneratedWith
+	}
+	return message + "\n"
+}
+
+// gitAttributionTrailer returns the trailer to add to commits for the
+// attribution's trailer style, or an empty string for none.
+func gitAttributionTrailer(attribution *config.Attribution, modelName string) string {
+	if attribution == nil {
+		return ""
+	}
+	switch attribution.TrailerStyle {
+	case config.TrailerStyleAssistedBy:
+		if modelName == "" {
+			return "Assisted-by: Crush <crush@charm.land>"
+		}
+		return fmt.Sprintf("Assisted-by: %s via Crush <crush@charm.land>", modelName)
+	case config.TrailerStyleCoAuthoredBy:
+		return "Co-Authored-By: Crush <crush@charm.land>"
+	default:
+		return ""
+	}
+}
+
+func gitCommitError(workingDir string, err error) string {
+	if errors.Is(err, git.ErrNotRepository) {
+		return fmt.Sprintf("%s is not in a git repository", workingDir)
+	}
+	return err.Error()
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/filepathext"
+	"github.com/charmbracelet/crush/internal/git"
+)
+
+type GitDiffParams struct {
+	Staged bool     `json:"staged,omitempty" description:"Show the staged changes instead of the unstaged ones"`
+	Ref    string   `json:"ref,omitempty" description:"Compare against a commit, branch or tag (for example HEAD~1 or main)"`
+	Paths  []string `json:"paths,omitempty" description:"Limit the diff to these files or directories"`
+}
+
+type GitDiffResponseMetadata struct {
+	Files     int  `json:"files"`
+	Additions int  `json:"additions"`
+	Deletions int  `json:"deletions"`
+	Truncated bool `json:"truncated"`
+}
+
+// gitFileStat is the number of lines added and deleted in a file.
+type gitFileStat struct {
+	path      string
+	additions int
+	deletions int
+	binary    bool
+}
+
+const GitDiffToolName = "git_diff"
+
+//go:embed git_diff.md
+var gitDiffDescription []byte
+
+func NewGitDiffTool(workingDir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		GitDiffToolName,
+		string(gitDiffDescription),
+		func(ctx context.Context, params GitDiffParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			args := []string{"diff", "--no-ext-diff", "--find-renames"}
+			if params.Staged {
+				args = append(args, "--cached")
+			}
+			if params.Ref != "" {
+				if err := validateGitRef(params.Ref); err != nil {
+					return fantasy.NewTextErrorResponse(err.Error()), nil
+				}
+				args = append(args, params.Ref)
+			}
+			var paths []string
+			if len(params.Paths) > 0 {
+				paths = append(paths, "--")
+				for _, path := range params.Paths {
+					paths = append(paths, filepathext.SmartJoin(workingDir, path))
+				}
+			}
+
+			numstat, err := git.Run(ctx, workingDir, nil, append(append(args, "--numstat"), paths...)...)
+			if errors.Is(err, git.ErrNotRepository) {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("%s is not in a git repository", workingDir)), nil
+			}
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			stats := parseGitNumstat(string(numstat))
+			if len(stats) == 0 {
+				return fantasy.NewTextResponse("No changes"), nil
+			}
+
+			patch, err := git.Run(ctx, workingDir, nil, append(args, paths...)...)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+
+			metadata := GitDiffResponseMetadata{Files: len(stats)}
+			var b strings.Builder
+			for _, stat := range stats {
+				metadata.Additions += stat.additions
+				metadata.Deletions += stat.deletions
+			}
+			fmt.Fprintf(&b, "%d files changed, %d insertions(+), %d deletions(-)\n", len(stats), metadata.Additions, metadata.Deletions)
+			for i, stat := range stats {
+				if i == maxGitListEntries {
+					fmt.Fprintf(&b, "  ... and %d more\n", len(stats)-i)
+					break
+				}
+				if stat.binary {
+					fmt.Fprintf(&b, "  %s (binary)\n", stat.path)
+				} else {
+					fmt.Fprintf(&b, "  %s +%d -%d\n", stat.path, stat.additions, stat.deletions)
+				}
+			}
+			b.WriteByte('\n')
+
+			shown, omitted := boundGitPatch(string(patch), MaxGitOutputLength-b.Len())
+			b.WriteString(shown)
+			if omitted > 0 {
+				metadata.Truncated = true
+				fmt.Fprintf(&b, "\n... [diff of %d more files omitted, pass their paths to see them]", omitted)
+			}
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(b.String()), metadata), nil
+		})
+}
+
+// parseGitNumstat parses the output of git diff --numstat.
+func parseGitNumstat(out string) []gitFileStat {
+	var stats []gitFileStat
+	for line := range strings.Lines(out) {
+		fields := strings.SplitN(strings.TrimSuffix(line, "\n"), "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		stat := gitFileStat{path: fields[2]}
+		if fields[0] == "-" && fields[1] == "-" {
+			stat.binary = true
+		} else {
+			stat.additions, _ = strconv.Atoi(fields[0])
+			stat.deletions, _ = strconv.Atoi(fields[1])
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// boundGitPatch keeps the diffs of as many files of a patch as fit in limit,
+// and returns the number of files left out. The diff of the first file is
+// truncated if it doesn't fit on its own.
+func boundGitPatch(patch string, limit int) (string, int) {
+	if patch == "" {
+		return "", 0
+	}
+	var files []string
+	for {
+		next := strings.Index(patch[1:], "\ndiff --git ")
+		if next < 0 {
+			files = append(files, patch)
+			break
+		}
+		files = append(files, patch[:next+2])
+		patch = patch[next+2:]
+	}
+
+	var b strings.Builder
+	for i, file := range files {
+		if b.Len()+len(file) > limit {
+			if i == 0 {
+				cut := strings.LastIndexByte(file[:max(limit, 0)], '\n')
+				b.WriteString(file[:max(cut, 0)])
+				fmt.Fprintf(&b, "\n... [%d characters truncated]", len(file)-max(cut, 0))
+				return b.String(), len(files) - 1
+			}
+			return b.String(), len(files) - i
+		}
+		b.WriteString(file)
+	}
+	return b.String(), 0
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/filepathext"
+	"github.com/charmbracelet/crush/internal/git"
+)
+
+type GitLogParams struct {
+	Ref   string `json:"ref,omitempty" description:"The commit, branch, tag or range to list (defaults to HEAD)"`
+	Path  string `json:"path,omitempty" description:"Only list the commits that changed this file or directory"`
+	Limit int    `json:"limit,omitempty" description:"The maximum number of commits to list (defaults to 20, at most 100)"`
+}
+
+type GitLogResponseMetadata struct {
+	Commits int `json:"commits"`
+}
+
+const (
+	GitLogToolName = "git_log"
+
+	defaultGitLogLimit = 20
+	maxGitLogLimit     = 100
+
+	// maxGitLogBodyLength bounds the body shown for each commit.
+	maxGitLogBodyLength = 1000
+)
+
+//go:embed git_log.md
+var gitLogDescription []byte
+
+func NewGitLogTool(workingDir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		GitLogToolName,
+		string(gitLogDescription),
+		func(ctx context.Context, params GitLogParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			limit := params.Limit
+			if limit <= 0 {
+				limit = defaultGitLogLimit
+			}
+			limit = min(limit, maxGitLogLimit)
+
+			// Fields are separated by the unit separator and commits by the
+			// record separator, which don't appear in commit messages.
+			args := []string{
+				"log",
+				"--max-count=" + strconv.Itoa(limit),
+				"--date=iso-strict",
+				"--format=%h%x1f%an <%ae>%x1f%ad%x1f%s%x1f%b%x1e",
+			}
+			if params.Ref != "" {
+				if err := validateGitRef(params.Ref); err != nil {
+					return fantasy.NewTextErrorResponse(err.Error()), nil
+				}
+				args = append(args, params.Ref)
+			}
+			if params.Path != "" {
+				args = append(args, "--", filepathext.SmartJoin(workingDir, params.Path))
+			}
+
+			out, err := git.Run(ctx, workingDir, nil, args...)
+			if errors.Is(err, git.ErrNotRepository) {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("%s is not in a git repository", workingDir)), nil
+			}
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+
+			var b strings.Builder
+			commits := 0
+			for record := range strings.SplitSeq(string(out), "\x1e") {
+				fields := strings.Split(strings.TrimPrefix(record, "\n"), "\x1f")
+				if len(fields) != 5 {
+					continue
+				}
+				if commits > 0 {
+					b.WriteByte('\n')
+				}
+				commits++
+				hash, author, date, subject, body := fields[0], fields[1], fields[2], fields[3], strings.TrimSpace(fields[4])
+				fmt.Fprintf(&b, "%s %s %s\n    %s\n", hash, date, author, subject)
+				if body == "" {
+					continue
+				}
+				if len(body) > maxGitLogBodyLength {
+					body = body[:maxGitLogBodyLength] + "..."
+				}
+				b.WriteByte('\n')
+				for line := range strings.Lines(body) {
+					fmt.Fprintf(&b, "    %s\n", strings.TrimSuffix(line, "\n"))
+				}
+			}
+			if commits == 0 {
+				return fantasy.NewTextResponse("No commits found"), nil
+			}
+			return fantasy.WithResponseMetadata(
+				fantasy.NewTextResponse(truncateGitOutput(b.String())),
+				GitLogResponseMetadata{Commits: commits},
+			), nil
+		})
+}
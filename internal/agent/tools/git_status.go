@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/filepathext"
+	"github.com/charmbracelet/crush/internal/git"
+)
+
+type GitStatusParams struct {
+	Path string `json:"path,omitempty" description:"Limit the status to a file or directory (defaults to the whole repository)"`
+}
+
+type GitStatusResponseMetadata struct {
+	Branch     string `json:"branch"`
+	Upstream   string `json:"upstream,omitempty"`
+	Ahead      int    `json:"ahead"`
+	Behind     int    `json:"behind"`
+	Staged     int    `json:"staged"`
+	Unstaged   int    `json:"unstaged"`
+	Untracked  int    `json:"untracked"`
+	Conflicted int    `json:"conflicted"`
+}
+
+// GitFileStatus is the status of a file in the index or the working tree.
+type GitFileStatus struct {
+	Path     string
+	OrigPath string
+	Status   string
+}
+
+type gitStatus struct {
+	branch     string
+	upstream   string
+	ahead      int
+	behind     int
+	staged     []GitFileStatus
+	unstaged   []GitFileStatus
+	untracked  []GitFileStatus
+	conflicted []GitFileStatus
+}
+
+const GitStatusToolName = "git_status"
+
+//go:embed git_status.md
+var gitStatusDescription []byte
+
+func NewGitStatusTool(workingDir string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		GitStatusToolName,
+		string(gitStatusDescription),
+		func(ctx context.Context, params GitStatusParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			args := []string{"status", "--porcelain=v2", "--branch", "--untracked-files=all", "-z"}
+			if params.Path != "" {
+				args = append(args, "--", filepathext.SmartJoin(workingDir, params.Path))
+			}
+			out, err := git.Run(ctx, workingDir, nil, args...)
+			if errors.Is(err, git.ErrNotRepository) {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("%s is not in a git repository", workingDir)), nil
+			}
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+
+			status := parseGitStatus(out)
+			return fantasy.WithResponseMetadata(
+				fantasy.NewTextResponse(status.String()),
+				GitStatusResponseMetadata{
+					Branch:     status.branch,
+					Upstream:   status.upstream,
+					Ahead:      status.ahead,
+					Behind:     status.behind,
+					Staged:     len(status.staged),
+					Unstaged:   len(status.unstaged),
+					Untracked:  len(status.untracked),
+					Conflicted: len(status.conflicted),
+				},
+			), nil
+		})
+}
+
+// parseGitStatus parses the output of git status --porcelain=v2 --branch -z.
+func parseGitStatus(out []byte) gitStatus {
+	var status gitStatus
+	records := bytes.Split(out, []byte{0})
+	for i := 0; i < len(records); i++ {
+		record := string(records[i])
+		switch {
+		case strings.HasPrefix(record, "# branch.head "):
+			status.branch = strings.TrimPrefix(record, "# branch.head ")
+		case strings.HasPrefix(record, "# branch.upstream "):
+			status.upstream = strings.TrimPrefix(record, "# branch.upstream ")
+		case strings.HasPrefix(record, "# branch.ab "):
+			for field := range strings.FieldsSeq(strings.TrimPrefix(record, "# branch.ab ")) {
+				n, _ := strconv.Atoi(field[1:])
+				if field[0] == '+' {
+					status.ahead = n
+				} else {
+					status.behind = n
+				}
+			}
+		case strings.HasPrefix(record, "1 "):
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) == 9 {
+				status.add(fields[1], fields[8], "")
+			}
+		case strings.HasPrefix(record, "2 "):
+			// The original path of a rename is the next record.
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) == 10 && i+1 < len(records) {
+				i++
+				status.add(fields[1], fields[9], string(records[i]))
+			}
+		case strings.HasPrefix(record, "u "):
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) == 11 {
+				status.conflicted = append(status.conflicted, GitFileStatus{Path: fields[10], Status: conflictStatus(fields[1])})
+			}
+		case strings.HasPrefix(record, "? "):
+			status.untracked = append(status.untracked, GitFileStatus{Path: record[2:], Status: "untracked"})
+		}
+	}
+	return status
+}
+
+// add adds a changed file, to the staged and/or unstaged files depending on
+// its XY status code.
+func (s *gitStatus) add(xy, path, origPath string) {
+	if len(xy) != 2 {
+		return
+	}
+	if xy[0] != '.' {
+		s.staged = append(s.staged, GitFileStatus{Path: path, OrigPath: origPath, Status: gitStatusName(xy[0])})
+	}
+	if xy[1] != '.' {
+		s.unstaged = append(s.unstaged, GitFileStatus{Path: path, Status: gitStatusName(xy[1])})
+	}
+}
+
+func gitStatusName(code byte) string {
+	switch code {
+	case 'M':
+		return "modified"
+	case 'A':
+		return "added"
+	case 'D':
+		return "deleted"
+	case 'R':
+		return "renamed"
+	case 'C':
+		return "copied"
+	case 'T':
+		return "typechange"
+	default:
+		return string(code)
+	}
+}
+
+func conflictStatus(xy string) string {
+	switch xy {
+	case "DD":
+		return "both deleted"
+	case "AU":
+		return "added by us"
+	case "UD":
+		return "deleted by them"
+	case "UA":
+		return "added by them"
+	case "DU":
+		return "deleted by us"
+	case "AA":
+		return "both added"
+	default:
+		return "both modified"
+	}
+}
+
+func (s gitStatus) String() string {
+	var b strings.Builder
+	switch s.branch {
+	case "", "(detached)":
+		b.WriteString("HEAD detached")
+	default:
+		fmt.Fprintf(&b, "On branch %s", s.branch)
+	}
+	if s.upstream != "" {
+		fmt.Fprintf(&b, ", tracking %s (ahead %d, behind %d)", s.upstream, s.ahead, s.behind)
+	}
+	b.WriteByte('\n')
+
+	if len(s.staged)+len(s.unstaged)+len(s.untracked)+len(s.conflicted) == 0 {
+		b.WriteString("\nNothing to commit, working tree clean\n")
+		return b.String()
+	}
+	writeGitFiles(&b, "Conflicts", s.conflicted)
+	writeGitFiles(&b, "Staged changes", s.staged)
+	writeGitFiles(&b, "Unstaged changes", s.unstaged)
+	writeGitFiles(&b, "Untracked files", s.untracked)
+	return b.String()
+}
+
+func writeGitFiles(b *strings.Builder, title string, files []GitFileStatus) {
+	if len(files) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s (%d):\n", title, len(files))
+	for i, f := range files {
+		if i == maxGitListEntries {
+			fmt.Fprintf(b, "  ... and %d more\n", len(files)-i)
+			break
+		}
+		switch {
+		case f.Status == "untracked":
+			fmt.Fprintf(b, "  %s\n", f.Path)
+		case f.OrigPath != "":
+			fmt.Fprintf(b, "  %s: %s -> %s\n", f.Status, f.OrigPath, f.Path)
+		default:
+			fmt.Fprintf(b, "  %s: %s\n", f.Status, f.Path)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/git"
+	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/stretchr/testify/require"
+)
+
+// newGitTestRepo creates a repository with a committed file.
+func newGitTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--initial-branch=main"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		_, err := git.Run(t.Context(), dir, nil, args...)
+		require.NoError(t, err)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644))
+	_, err := git.Run(t.Context(), dir, nil, "add", "main.go")
+	require.NoError(t, err)
+	_, err = git.Run(t.Context(), dir, nil, "commit", "-m", "Initial commit")
+	require.NoError(t, err)
+	return dir
+}
+
+func runGitTool(t *testing.T, tool fantasy.AgentTool, params any) fantasy.ToolResponse {
+	t.Helper()
+	input, err := json.Marshal(params)
+	require.NoError(t, err)
+	ctx := context.WithValue(t.Context(), SessionIDContextKey, "session-1")
+	resp, err := tool.Run(ctx, fantasy.ToolCall{ID: "call-1", Name: tool.Info().Name, Input: string(input)})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestParseGitStatus(t *testing.T) {
+	t.Parallel()
+
+	out := strings.Join([]string{
+		"# branch.oid 1234567890abcdef1234567890abcdef12345678",
+		"# branch.head main",
+		"# branch.upstream origin/main",
+		"# branch.ab +2 -1",
+		"1 M. N... 100644 100644 100644 aaaa bbbb staged.go",
+		"1 .M N... 100644 100644 100644 aaaa aaaa unstaged file.go",
+		"1 MM N... 100644 100644 100644 aaaa bbbb both.go",
+		"2 R. N... 100644 100644 100644 aaaa aaaa R100 new.go",
+		"old.go",
+		"u UU N... 100644 100644 100644 100644 aaaa bbbb cccc conflict.go",
+		"? notes.txt",
+		"",
+	}, "\x00")
+
+	status := parseGitStatus([]byte(out))
+	require.Equal(t, "main", status.branch)
+	require.Equal(t, "origin/main", status.upstream)
+	require.Equal(t, 2, status.ahead)
+	require.Equal(t, 1, status.behind)
+	require.Equal(t, []GitFileStatus{
+		{Path: "staged.go", Status: "modified"},
+		{Path: "both.go", Status: "modified"},
+		{Path: "new.go", OrigPath: "old.go", Status: "renamed"},
+	}, status.staged)
+	require.Equal(t, []GitFileStatus{
+		{Path: "unstaged file.go", Status: "modified"},
+		{Path: "both.go", Status: "modified"},
+	}, status.unstaged)
+	require.Equal(t, []GitFileStatus{{Path: "conflict.go", Status: "both modified"}}, status.conflicted)
+	require.Equal(t, []GitFileStatus{{Path: "notes.txt", Status: "untracked"}}, status.untracked)
+
+	rendered := status.String()
+	require.Contains(t, rendered, "On branch main, tracking origin/main (ahead 2, behind 1)")
+	require.Contains(t, rendered, "renamed: old.go -> new.go")
+	require.Contains(t, rendered, "Untracked files (1):\n  notes.txt")
+}
+
+func TestBoundGitPatch(t *testing.T) {
+	t.Parallel()
+
+	file := func(name string, lines int) string {
+		return "diff --git a/" + name + " b/" + name + "\n" + strings.Repeat("+line\n", lines)
+	}
+	patch := file("a.go", 10) + file("b.go", 10) + file("c.go", 10)
+
+	shown, omitted := boundGitPatch(patch, len(patch))
+	require.Equal(t, patch, shown)
+	require.Zero(t, omitted)
+
+	// Diffs are cut at file boundaries.
+	shown, omitted = boundGitPatch(patch, len(patch)-1)
+	require.Equal(t, file("a.go", 10)+file("b.go", 10), shown)
+	require.Equal(t, 1, omitted)
+
+	// The first diff is truncated if it doesn't fit on its own.
+	shown, omitted = boundGitPatch(patch, 40)
+	require.True(t, strings.HasPrefix(shown, "diff --git a/a.go b/a.go\n"))
+	require.Contains(t, shown, "characters truncated]")
+	require.Equal(t, 2, omitted)
+}
+
+func TestGitTools(t *testing.T) {
+	t.Parallel()
+
+	dir := newGitTestRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test\n"), 0o644))
+
+	resp := runGitTool(t, NewGitStatusTool(dir), GitStatusParams{})
+	require.False(t, resp.IsError, resp.Content)
+	require.Contains(t, resp.Content, "On branch main")
+	require.Contains(t, resp.Content, "Unstaged changes (1):\n  modified: main.go")
+	require.Contains(t, resp.Content, "Untracked files (1):\n  README.md")
+
+	resp = runGitTool(t, NewGitDiffTool(dir), GitDiffParams{})
+	require.False(t, resp.IsError, resp.Content)
+	require.Contains(t, resp.Content, "1 files changed, 2 insertions(+), 0 deletions(-)")
+	require.Contains(t, resp.Content, "+func main() {}")
+
+	resp = runGitTool(t, NewGitDiffTool(dir), GitDiffParams{Staged: true})
+	require.Equal(t, "No changes", resp.Content)
+
+	resp = runGitTool(t, NewGitDiffTool(dir), GitDiffParams{Ref: "--output=/tmp/x"})
+	require.True(t, resp.IsError)
+
+	permissions := &mockPermissionService{Broker: pubsub.NewBroker[permission.PermissionRequest]()}
+	attribution := &config.Attribution{TrailerStyle: config.TrailerStyleAssistedBy, GeneratedWith: true}
+	resp = runGitTool(t, NewGitCommitTool(permissions, dir, attribution, "Test Model"), GitCommitParams{
+		Message: "Add main function\n\nThe program now has an entry point.",
+		Files:   []string{"main.go"},
+	})
+	require.False(t, resp.IsError, resp.Content)
+	require.Contains(t, resp.Content, "Add main function")
+	require.Contains(t, resp.Content, "1 file changed, 2 insertions(+)")
+
+	out, err := git.Run(t.Context(), dir, nil, "log", "-1", "--format=%B")
+	require.NoError(t, err)
+	require.Equal(t, "Add main function\n\nThe program now has an entry point.\n\n💘 Generated with Crush\n\nAssisted-by: Test Model via Crush <crush@charm.land>\n", strings.TrimSuffix(string(out), "\n"))
+
+	resp = runGitTool(t, NewGitLogTool(dir), GitLogParams{Limit: 1})
+	require.False(t, resp.IsError, resp.Content)
+	require.Contains(t, resp.Content, "Test <test@example.com>\n    Add main function\n\n    The program now has an entry point.")
+	require.NotContains(t, resp.Content, "Initial commit")
+
+	// README.md is still untracked, so there is nothing to commit.
+	resp = runGitTool(t, NewGitCommitTool(permissions, dir, attribution, "Test Model"), GitCommitParams{Message: "Empty", All: true})
+	require.True(t, resp.IsError)
+}
+
+func TestGitAttributionTrailer(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, gitAttributionTrailer(nil, "Model"))
+	require.Empty(t, gitAttributionTrailer(&config.Attribution{TrailerStyle: config.TrailerStyleNone}, "Model"))
+	require.Equal(t, "Co-Authored-By: Crush <crush@charm.land>", gitAttributionTrailer(&config.Attribution{TrailerStyle: config.TrailerStyleCoAuthoredBy}, "Model"))
+	require.Equal(t, "Assisted-by: Model via Crush <crush@charm.land>", gitAttributionTrailer(&config.Attribution{TrailerStyle: config.TrailerStyleAssistedBy}, "Model"))
+
+	require.Equal(t, "Fix\n", gitCommitMessage("Fix", &config.Attribution{}))
+	require.Equal(t, "Fix\n\n💘 Generated with Crush\n", gitCommitMessage("Fix", &config.Attribution{GeneratedWith: true}))
+}
+
+func TestBashDescriptionGitCommits(t *testing.T) {
+	t.Parallel()
+
+	attribution := &config.Attribution{TrailerStyle: config.TrailerStyleAssistedBy}
+
+	description := bashDescription(attribution, "Model", true)
+	require.Contains(t, description, GitCommitToolName)
+	require.NotContains(t, description, "git commit -m")
+
+	description = bashDescription(attribution, "Model", false)
+	require.Contains(t, description, "git commit -m")
+	require.Contains(t, description, "Assisted-by: Model via Crush")
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/charmbracelet/crush/internal/shell"
+)
+
+const (
+	JobInputToolName = "job_input"
+
+	// jobInputSettleTime is how long to wait for the shell to react to the
+	// input before returning its output.
+	jobInputSettleTime = 500 * time.Millisecond
+	// jobInputTailLines is the number of output lines returned after sending
+	// input.
+	jobInputTailLines = 50
+)
+
+//go:embed job_input.md
+var jobInputDescription []byte
+
+type JobInputParams struct {
+	ShellID string `json:"shell_id" description:"The ID of the background shell to send input to"`
+	Input   string `json:"input" description:"The input to send. Include a trailing newline to press Enter"`
+}
+
+type JobInputPermissionsParams struct {
+	ShellID string `json:"shell_id"`
+	Command string `json:"command"`
+	Input   string `json:"input"`
+}
+
+type JobInputResponseMetadata struct {
+	ShellID     string `json:"shell_id"`
+	Command     string `json:"command"`
+	Description string `json:"description"`
+	Done        bool   `json:"done"`
+}
+
+func NewJobInputTool(permissions permission.Service) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		JobInputToolName,
+		string(jobInputDescription),
+		func(ctx context.Context, params JobInputParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			if params.ShellID == "" {
+				return fantasy.NewTextErrorResponse("missing shell_id"), nil
+			}
+			if params.Input == "" {
+				return fantasy.NewTextErrorResponse("missing input"), nil
+			}
+
+			bgManager := shell.GetBackgroundShellManager()
+			bgShell, ok := bgManager.Get(params.ShellID)
+			if !ok {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("background shell not found: %s", params.ShellID)), nil
+			}
+			if !bgShell.IsPTY() {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("background shell %s was not started with pty=true and does not accept input", params.ShellID)), nil
+			}
+
+			sessionID := GetSessionFromContext(ctx)
+			if sessionID == "" {
+				return fantasy.ToolResponse{}, fmt.Errorf("session ID is required for sending input to a shell")
+			}
+			p, err := permissions.Request(ctx,
+				permission.CreatePermissionRequest{
+					SessionID:   sessionID,
+					Path:        bgShell.WorkingDir,
+					ToolCallID:  call.ID,
+					ToolName:    JobInputToolName,
+					Action:      "execute",
+					Description: fmt.Sprintf("Send input to background shell %s: %q", params.ShellID, params.Input),
+					Params: JobInputPermissionsParams{
+						ShellID: params.ShellID,
+						Command: bgShell.Command,
+						Input:   params.Input,
+					},
+				},
+			)
+			if err != nil {
+				return fantasy.ToolResponse{}, err
+			}
+			if !p {
+				return fantasy.ToolResponse{}, permission.ErrorPermissionDenied
+			}
+
+			if err := bgShell.WriteInput([]byte(params.Input)); err != nil {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("failed to send input: %s", err)), nil
+			}
+
+			select {
+			case <-time.After(jobInputSettleTime):
+			case <-ctx.Done():
+				return fantasy.ToolResponse{}, ctx.Err()
+			}
+
+			status := "running"
+			done := bgShell.IsDone()
+			if done {
+				status = "completed"
+			}
+			output := bgShell.Tail(jobInputTailLines)
+			if output == "" {
+				output = BashNoOutput
+			}
+
+			metadata := JobInputResponseMetadata{
+				ShellID:     params.ShellID,
+				Command:     bgShell.Command,
+				Description: bgShell.Description,
+				Done:        done,
+			}
+			result := fmt.Sprintf("Input sent to background shell %s.\n\nStatus: %s\n\nRecent output:\n%s", params.ShellID, status, output)
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(result), metadata), nil
+		})
+}
@@ -0,0 +1,258 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ElicitationAction is the answer of the user to an elicitation.
+type ElicitationAction string
+
+const (
+	ElicitationAccept  ElicitationAction = "accept"
+	ElicitationDecline ElicitationAction = "decline"
+	ElicitationCancel  ElicitationAction = "cancel"
+)
+
+// ElicitationOption is an allowed value of an [ElicitationField].
+type ElicitationOption struct {
+	Value string
+	Label string
+}
+
+// ElicitationField is a field of the form requested by an MCP server.
+type ElicitationField struct {
+	Name        string
+	Title       string
+	Description string
+	Type        string // "string", "number", "integer" or "boolean"
+	Options     []ElicitationOption
+	Required    bool
+	Default     string
+}
+
+// ElicitationRequest is a request of an MCP server for input from the user.
+// Either Fields or URL is set: the user fills a form, or is sent to a web
+// page to complete the interaction out of band.
+type ElicitationRequest struct {
+	ID      string
+	MCP     string
+	Message string
+	Fields  []ElicitationField
+	URL     string
+}
+
+var (
+	elicitations        = pubsub.NewBroker[ElicitationRequest]()
+	pendingElicitations = csync.NewMap[string, chan *mcp.ElicitResult]()
+)
+
+// SubscribeElicitations returns a channel for elicitation requests. A
+// [pubsub.DeletedEvent] is published when a request is withdrawn before it
+// was answered.
+func SubscribeElicitations(ctx context.Context, opts ...pubsub.SubscribeOption) <-chan pubsub.Event[ElicitationRequest] {
+	return elicitations.Subscribe(ctx, opts...)
+}
+
+// ElicitationStats returns the event counters of the elicitation requests broker.
+func ElicitationStats() pubsub.Stats {
+	return elicitations.Stats()
+}
+
+// RespondElicitation answers a pending elicitation request. The content is
+// only sent when the request is accepted.
+func RespondElicitation(id string, action ElicitationAction, content map[string]any) {
+	respCh, ok := pendingElicitations.Get(id)
+	if !ok {
+		return
+	}
+	result := &mcp.ElicitResult{Action: string(action)}
+	if action == ElicitationAccept {
+		result.Content = content
+	}
+	select {
+	case respCh <- result:
+	default:
+	}
+}
+
+// Values converts the values entered by the user to the types of the fields,
+// checking that required fields are set and options are respected.
+func (r ElicitationRequest) Values(input map[string]string) (map[string]any, error) {
+	values := make(map[string]any, len(r.Fields))
+	for _, f := range r.Fields {
+		v := strings.TrimSpace(input[f.Name])
+		if v == "" {
+			if f.Required {
+				return nil, fmt.Errorf("%s is required", f.Label())
+			}
+			continue
+		}
+		switch f.Type {
+		case "boolean":
+			switch strings.ToLower(v) {
+			case "true", "yes", "y":
+				values[f.Name] = true
+			case "false", "no", "n":
+				values[f.Name] = false
+			default:
+				return nil, fmt.Errorf("%s must be yes or no", f.Label())
+			}
+		case "integer":
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be an integer", f.Label())
+			}
+			values[f.Name] = n
+		case "number":
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a number", f.Label())
+			}
+			values[f.Name] = n
+		default:
+			if len(f.Options) > 0 {
+				i := slices.IndexFunc(f.Options, func(o ElicitationOption) bool {
+					return o.Value == v || strings.EqualFold(o.Label, v)
+				})
+				if i < 0 {
+					return nil, fmt.Errorf("%s must be one of %s", f.Label(), f.optionLabels())
+				}
+				v = f.Options[i].Value
+			}
+			values[f.Name] = v
+		}
+	}
+	return values, nil
+}
+
+// Label returns the name of the field to show to the user.
+func (f ElicitationField) Label() string {
+	return cmp.Or(f.Title, f.Name)
+}
+
+// Hint describes the expected value of the field.
+func (f ElicitationField) Hint() string {
+	switch {
+	case len(f.Options) > 0:
+		return f.optionLabels()
+	case f.Type == "boolean":
+		return "yes, no"
+	default:
+		return f.Type
+	}
+}
+
+func (f ElicitationField) optionLabels() string {
+	labels := make([]string, 0, len(f.Options))
+	for _, o := range f.Options {
+		labels = append(labels, o.Label)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// elicitationSchema is the restricted JSON schema of elicitation forms: an
+// object with primitive properties.
+type elicitationSchema struct {
+	Properties map[string]struct {
+		Type        string   `json:"type"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Enum        []string `json:"enum"`
+		EnumNames   []string `json:"enumNames"`
+		OneOf       []struct {
+			Const string `json:"const"`
+			Title string `json:"title"`
+		} `json:"oneOf"`
+		Default any `json:"default"`
+	} `json:"properties"`
+	Required []string `json:"required"`
+}
+
+func newElicitationRequest(name string, params *mcp.ElicitParams) (ElicitationRequest, error) {
+	req := ElicitationRequest{
+		ID:      uuid.New().String(),
+		MCP:     name,
+		Message: params.Message,
+		URL:     params.URL,
+	}
+	if params.RequestedSchema == nil {
+		return req, nil
+	}
+
+	data, err := json.Marshal(params.RequestedSchema)
+	if err != nil {
+		return req, fmt.Errorf("invalid requested schema: %w", err)
+	}
+	var schema elicitationSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return req, fmt.Errorf("invalid requested schema: %w", err)
+	}
+	for name, prop := range schema.Properties {
+		field := ElicitationField{
+			Name:        name,
+			Title:       prop.Title,
+			Description: prop.Description,
+			Type:        cmp.Or(prop.Type, "string"),
+			Required:    slices.Contains(schema.Required, name),
+		}
+		if prop.Default != nil {
+			field.Default = fmt.Sprint(prop.Default)
+		}
+		for i, v := range prop.Enum {
+			label := v
+			if i < len(prop.EnumNames) {
+				label = prop.EnumNames[i]
+			}
+			field.Options = append(field.Options, ElicitationOption{Value: v, Label: label})
+		}
+		for _, o := range prop.OneOf {
+			field.Options = append(field.Options, ElicitationOption{Value: o.Const, Label: cmp.Or(o.Title, o.Const)})
+		}
+		req.Fields = append(req.Fields, field)
+	}
+	// Properties are unordered: show required fields first.
+	slices.SortFunc(req.Fields, func(a, b ElicitationField) int {
+		if a.Required != b.Required {
+			if a.Required {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return req, nil
+}
+
+func elicitationHandler(name string) func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	return func(ctx context.Context, r *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+		req, err := newElicitationRequest(name, r.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		respCh := make(chan *mcp.ElicitResult, 1)
+		pendingElicitations.Set(req.ID, respCh)
+		defer pendingElicitations.Del(req.ID)
+
+		elicitations.Publish(pubsub.CreatedEvent, req)
+
+		select {
+		case <-ctx.Done():
+			elicitations.Publish(pubsub.DeletedEvent, req)
+			return nil, ctx.Err()
+		case result := <-respCh:
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElicitationRequest_Values(t *testing.T) {
+	t.Parallel()
+
+	req, err := newElicitationRequest("test", &mcp.ElicitParams{
+		Message: "Configure the deployment",
+		RequestedSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":     map[string]any{"type": "string", "title": "Name"},
+				"replicas": map[string]any{"type": "integer", "default": 2},
+				"confirm":  map[string]any{"type": "boolean"},
+				"env": map[string]any{
+					"type":      "string",
+					"enum":      []string{"prod", "dev"},
+					"enumNames": []string{"Production", "Development"},
+				},
+			},
+			"required": []string{"name", "env"},
+		},
+	})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(req.Fields))
+	for _, f := range req.Fields {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"env", "name", "confirm", "replicas"}, names)
+	require.Equal(t, "2", req.Fields[3].Default)
+
+	values, err := req.Values(map[string]string{
+		"name":     "crush",
+		"env":      "production",
+		"replicas": "3",
+		"confirm":  "yes",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"name":     "crush",
+		"env":      "prod",
+		"replicas": int64(3),
+		"confirm":  true,
+	}, values)
+
+	_, err = req.Values(map[string]string{"env": "prod"})
+	require.EqualError(t, err, "Name is required")
+
+	_, err = req.Values(map[string]string{"name": "crush", "env": "staging"})
+	require.EqualError(t, err, "env must be one of Production, Development")
+
+	_, err = req.Values(map[string]string{"name": "crush", "env": "dev", "replicas": "many"})
+	require.EqualError(t, err, "replicas must be an integer")
+}
@@ -65,6 +65,8 @@ const (
 	EventStateChanged EventType = iota
 	EventToolsListChanged
 	EventPromptsListChanged
+	EventResourcesListChanged
+	EventResourceUpdated
 )
 
 // Event represents an event in the MCP system
@@ -74,12 +76,15 @@ type Event struct {
 	State  State
 	Error  error
 	Counts Counts
+	// URI is the URI of the updated resource, for [EventResourceUpdated].
+	URI string
 }
 
 // Counts number of available tools, prompts, etc.
 type Counts struct {
-	Tools   int
-	Prompts int
+	Tools     int
+	Prompts   int
+	Resources int
 }
 
 // ClientInfo holds information about an MCP client's state
@@ -93,8 +98,13 @@ type ClientInfo struct {
 }
 
 // SubscribeEvents returns a channel for MCP events
-func SubscribeEvents(ctx context.Context) <-chan pubsub.Event[Event] {
-	return broker.Subscribe(ctx)
+func SubscribeEvents(ctx context.Context, opts ...pubsub.SubscribeOption) <-chan pubsub.Event[Event] {
+	return broker.Subscribe(ctx, opts...)
+}
+
+// EventStats returns the event counters of the MCP events broker.
+func EventStats() pubsub.Stats {
+	return broker.Stats()
 }
 
 // GetStates returns the current state of all MCP clients
@@ -130,11 +140,13 @@ func Close() error {
 	case <-time.After(5 * time.Second):
 	}
 	broker.Shutdown()
+	elicitations.Shutdown()
 	return nil
 }
 
 // Initialize initializes MCP clients based on the provided configuration.
-func Initialize(ctx context.Context, permissions permission.Service, cfg *config.Config) {
+func Initialize(ctx context.Context, perms permission.Service, cfg *config.Config) {
+	permissions = perms
 	var wg sync.WaitGroup
 	// Initialize states for all configured MCPs
 	for name, m := range cfg.MCP {
@@ -188,13 +200,16 @@ func Initialize(ctx context.Context, permissions permission.Service, cfg *config
 				return
 			}
 
+			resources, templates := getResources(ctx, session)
 			toolCount := updateTools(name, tools)
 			updatePrompts(name, prompts)
+			updateResources(name, resources, templates)
 			sessions.Set(name, session)
 
 			updateState(name, StateConnected, nil, session, Counts{
-				Tools:   toolCount,
-				Prompts: len(prompts),
+				Tools:     toolCount,
+				Prompts:   len(prompts),
+				Resources: len(resources) + len(templates),
 			})
 		}(name, m)
 	}
@@ -232,6 +247,14 @@ func getOrRenewClient(ctx context.Context, name string) (*mcp.ClientSession, err
 	}
 	updateState(name, StateError, maybeTimeoutErr(err, timeout), nil, state.Counts)
 
+	// The server rejected our token: get a new one before reconnecting.
+	if a, ok := authenticators.Get(name); ok && a.rejected.Load() {
+		if err := a.forceRefresh(ctx); err != nil {
+			slog.Warn("Failed to refresh MCP OAuth token", "name", name, "error", err)
+			return nil, a.unauthorizedErr()
+		}
+	}
+
 	sess, err = createSession(ctx, name, m, cfg.Resolver())
 	if err != nil {
 		return nil, err
@@ -274,7 +297,7 @@ func createSession(ctx context.Context, name string, m config.MCPConfig, resolve
 	mcpCtx, cancel := context.WithCancel(ctx)
 	cancelTimer := time.AfterFunc(timeout, cancel)
 
-	transport, err := createTransport(mcpCtx, m, resolver)
+	transport, err := createTransport(mcpCtx, name, m, resolver)
 	if err != nil {
 		updateState(name, StateError, err, nil, Counts{})
 		slog.Error("Error creating MCP client", "error", err, "name", name)
@@ -302,15 +325,41 @@ func createSession(ctx context.Context, name string, m config.MCPConfig, resolve
 					Name: name,
 				})
 			},
+			ResourceListChangedHandler: func(context.Context, *mcp.ResourceListChangedRequest) {
+				broker.Publish(pubsub.UpdatedEvent, Event{
+					Type: EventResourcesListChanged,
+					Name: name,
+				})
+			},
+			ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+				broker.Publish(pubsub.UpdatedEvent, Event{
+					Type: EventResourceUpdated,
+					Name: name,
+					URI:  req.Params.URI,
+				})
+			},
 			LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
 				slog.Info("MCP log", "name", name, "data", req.Params.Data)
 			},
+			CreateMessageHandler: createMessageHandler(name),
+			ElicitationHandler:   elicitationHandler(name),
+			Capabilities: &mcp.ClientCapabilities{
+				RootsV2: &mcp.RootCapabilities{},
+				Elicitation: &mcp.ElicitationCapabilities{
+					Form: &mcp.FormElicitationCapabilities{},
+					URL:  &mcp.URLElicitationCapabilities{},
+				},
+			},
 		},
 	)
+	client.AddRoots(roots(config.Get())...)
 
 	session, err := client.Connect(mcpCtx, transport, nil)
 	if err != nil {
 		err = maybeStdioErr(err, transport)
+		if a, ok := authenticators.Get(name); ok && a.rejected.Load() {
+			err = a.unauthorizedErr()
+		}
 		updateState(name, StateError, maybeTimeoutErr(err, timeout), nil, Counts{})
 		slog.Error("MCP client failed to initialize", "error", err, "name", name)
 		cancel()
@@ -351,7 +400,7 @@ func maybeTimeoutErr(err error, timeout time.Duration) error {
 	return err
 }
 
-func createTransport(ctx context.Context, m config.MCPConfig, resolver config.VariableResolver) (mcp.Transport, error) {
+func createTransport(ctx context.Context, name string, m config.MCPConfig, resolver config.VariableResolver) (mcp.Transport, error) {
 	switch m.Type {
 	case config.MCPStdio:
 		command, err := resolver.ResolveValue(m.Command)
@@ -373,6 +422,7 @@ func createTransport(ctx context.Context, m config.MCPConfig, resolver config.Va
 		client := &http.Client{
 			Transport: &headerRoundTripper{
 				headers: m.ResolvedHeaders(),
+				auth:    getAuthenticator(name, m),
 			},
 		}
 		return &mcp.StreamableClientTransport{
@@ -386,6 +436,7 @@ func createTransport(ctx context.Context, m config.MCPConfig, resolver config.Va
 		client := &http.Client{
 			Transport: &headerRoundTripper{
 				headers: m.ResolvedHeaders(),
+				auth:    getAuthenticator(name, m),
 			},
 		}
 		return &mcp.SSEClientTransport{
@@ -397,17 +448,6 @@ func createTransport(ctx context.Context, m config.MCPConfig, resolver config.Va
 	}
 }
 
-type headerRoundTripper struct {
-	headers map[string]string
-}
-
-func (rt headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	for k, v := range rt.headers {
-		req.Header.Set(k, v)
-	}
-	return http.DefaultTransport.RoundTrip(req)
-}
-
 func mcpTimeout(m config.MCPConfig) time.Duration {
 	return time.Duration(cmp.Or(m.Timeout, 15)) * time.Second
 }
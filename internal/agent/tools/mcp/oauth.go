@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/oauth"
+	mcpoauth "github.com/charmbracelet/crush/internal/oauth/mcp"
+)
+
+// ErrUnauthorized is returned when an MCP server rejects our credentials.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// authenticators holds the authenticator of each HTTP/SSE MCP, so tokens
+// refreshed by one session are used by the next.
+var authenticators = csync.NewMap[string, *authenticator]()
+
+// authenticator adds the OAuth token of an MCP to its requests, refreshing
+// it when it expires or the server rejects it.
+type authenticator struct {
+	name   string
+	client *mcpoauth.Client
+
+	mu    sync.Mutex
+	token *oauth.Token
+
+	// rejected is set when the server answered with 401 Unauthorized and
+	// the token could not be refreshed.
+	rejected atomic.Bool
+}
+
+// getAuthenticator returns the authenticator of the given MCP, creating it
+// from its configuration if needed.
+func getAuthenticator(name string, m config.MCPConfig) *authenticator {
+	a := authenticators.GetOrSet(name, func() *authenticator {
+		return &authenticator{
+			name:   name,
+			client: m.OAuthClient,
+			token:  m.OAuthToken,
+		}
+	})
+	// Pick up a token obtained after the authenticator was created.
+	a.mu.Lock()
+	if a.token == nil && m.OAuthToken != nil {
+		a.client = m.OAuthClient
+		a.token = m.OAuthToken
+	}
+	a.mu.Unlock()
+	return a
+}
+
+// accessToken returns the current access token, refreshing it first if it
+// expired. It returns an empty string if the MCP has no token.
+func (a *authenticator) accessToken(ctx context.Context) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == nil {
+		return ""
+	}
+	if a.token.ExpiresAt != 0 && a.token.IsExpired() {
+		if err := a.refreshLocked(ctx); err != nil {
+			slog.Warn("Failed to refresh MCP OAuth token", "name", a.name, "error", err)
+		}
+	}
+	return a.token.AccessToken
+}
+
+// refresh gets a new access token, unless another request already did so
+// since stale was used.
+func (a *authenticator) refresh(ctx context.Context, stale string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != nil && a.token.AccessToken != stale {
+		return nil
+	}
+	return a.refreshLocked(ctx)
+}
+
+// forceRefresh gets a new access token regardless of the current one.
+func (a *authenticator) forceRefresh(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.refreshLocked(ctx)
+}
+
+func (a *authenticator) refreshLocked(ctx context.Context) error {
+	if a.token == nil || a.token.RefreshToken == "" || a.client == nil {
+		return fmt.Errorf("%w: no refresh token for mcp %s", ErrUnauthorized, a.name)
+	}
+	token, err := mcpoauth.RefreshToken(ctx, a.client, a.token.RefreshToken)
+	if err != nil {
+		return err
+	}
+	a.token = token
+	a.rejected.Store(false)
+	slog.Info("Successfully refreshed MCP OAuth token", "name", a.name)
+	if err := config.Get().SetMCPOAuth(a.name, a.client, token); err != nil {
+		slog.Warn("Failed to persist MCP OAuth token", "name", a.name, "error", err)
+	}
+	return nil
+}
+
+// unauthorizedErr explains how to fix an MCP rejecting our credentials.
+func (a *authenticator) unauthorizedErr() error {
+	return fmt.Errorf("%w: run 'crush login mcp %s' to authorize", ErrUnauthorized, a.name)
+}
+
+type headerRoundTripper struct {
+	headers map[string]string
+	auth    *authenticator
+}
+
+func (rt headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range rt.headers {
+		req.Header.Set(k, v)
+	}
+	if rt.auth == nil || req.Header.Get("Authorization") != "" {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+
+	token := rt.auth.accessToken(req.Context())
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The token was rejected: refresh it and retry once, if the request can
+	// be replayed.
+	if token == "" || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		rt.auth.rejected.Store(true)
+		return resp, nil
+	}
+	if err := rt.auth.refresh(req.Context(), token); err != nil {
+		slog.Warn("Failed to refresh MCP OAuth token", "name", rt.auth.name, "error", err)
+		rt.auth.rejected.Store(true)
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	retry.Header.Set("Authorization", "Bearer "+rt.auth.accessToken(req.Context()))
+	resp.Body.Close()
+	resp, err = http.DefaultTransport.RoundTrip(retry)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		rt.auth.rejected.Store(true)
+	}
+	return resp, err
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"log/slog"
+
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type (
+	Resource         = mcp.Resource
+	ResourceTemplate = mcp.ResourceTemplate
+	ResourceContents = mcp.ResourceContents
+)
+
+var (
+	allResources         = csync.NewMap[string, []*Resource]()
+	allResourceTemplates = csync.NewMap[string, []*ResourceTemplate]()
+)
+
+// Resources returns all available MCP resources.
+func Resources() iter.Seq2[string, []*Resource] {
+	return allResources.Seq2()
+}
+
+// ResourceTemplates returns all available MCP resource templates.
+func ResourceTemplates() iter.Seq2[string, []*ResourceTemplate] {
+	return allResourceTemplates.Seq2()
+}
+
+// ReadResource reads the contents of the resource with the given URI from
+// the given MCP.
+func ReadResource(ctx context.Context, clientName, uri string) ([]*ResourceContents, error) {
+	c, err := getOrRenewClient(ctx, clientName)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.ReadResource(ctx, &mcp.ReadResourceParams{
+		URI: uri,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// SubscribeResource asks the MCP to notify us when the resource with the
+// given URI changes. Updates are published as [EventResourceUpdated] events.
+// It is a no-op if the MCP does not support subscriptions.
+func SubscribeResource(ctx context.Context, clientName, uri string) error {
+	c, err := getOrRenewClient(ctx, clientName)
+	if err != nil {
+		return err
+	}
+	if !canSubscribe(c) {
+		return nil
+	}
+	if err := c.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+		return fmt.Errorf("could not subscribe to resource %s: %w", uri, err)
+	}
+	return nil
+}
+
+// UnsubscribeResource cancels a subscription made with [SubscribeResource].
+func UnsubscribeResource(ctx context.Context, clientName, uri string) error {
+	c, ok := sessions.Get(clientName)
+	if !ok || !canSubscribe(c) {
+		return nil
+	}
+	if err := c.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: uri}); err != nil {
+		return fmt.Errorf("could not unsubscribe from resource %s: %w", uri, err)
+	}
+	return nil
+}
+
+// RefreshResources gets the updated list of resources from the MCP and
+// updates the global state.
+func RefreshResources(ctx context.Context, name string) {
+	session, ok := sessions.Get(name)
+	if !ok {
+		slog.Warn("Refresh resources: no session", "name", name)
+		return
+	}
+
+	resources, templates := getResources(ctx, session)
+	updateResources(name, resources, templates)
+
+	prev, _ := states.Get(name)
+	prev.Counts.Resources = len(resources) + len(templates)
+	updateState(name, StateConnected, nil, session, prev.Counts)
+}
+
+func canSubscribe(c *mcp.ClientSession) bool {
+	caps := c.InitializeResult().Capabilities.Resources
+	return caps != nil && caps.Subscribe
+}
+
+// getResources lists the resources and resource templates of an MCP. They are
+// optional, so the MCP is still used for its tools and prompts when they
+// can't be listed.
+func getResources(ctx context.Context, c *mcp.ClientSession) ([]*Resource, []*ResourceTemplate) {
+	if c.InitializeResult().Capabilities.Resources == nil {
+		return nil, nil
+	}
+	var resources []*Resource
+	for resource, err := range c.Resources(ctx, &mcp.ListResourcesParams{}) {
+		if err != nil {
+			slog.Warn("Error listing resources", "error", err)
+			resources = nil
+			break
+		}
+		resources = append(resources, resource)
+	}
+	var templates []*ResourceTemplate
+	for template, err := range c.ResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{}) {
+		if err != nil {
+			slog.Warn("Error listing resource templates", "error", err)
+			templates = nil
+			break
+		}
+		templates = append(templates, template)
+	}
+	return resources, templates
+}
+
+// updateResources updates the global resource and resource template maps.
+func updateResources(mcpName string, resources []*Resource, templates []*ResourceTemplate) {
+	if len(resources) == 0 {
+		allResources.Del(mcpName)
+	} else {
+		allResources.Set(mcpName, resources)
+	}
+	if len(templates) == 0 {
+		allResourceTemplates.Del(mcpName)
+	} else {
+		allResourceTemplates.Set(mcpName, templates)
+	}
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+// newResourceServer creates an MCP server with a resource and a resource
+// template.
+func newResourceServer() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "docs"}, nil)
+	server.AddResource(&mcp.Resource{URI: "file:///readme", Name: "readme", MIMEType: "text/markdown"}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+			{URI: req.Params.URI, MIMEType: "text/markdown", Text: "# Readme"},
+		}}, nil
+	})
+	server.AddResourceTemplate(&mcp.ResourceTemplate{URITemplate: "file:///pages/{name}", Name: "page"}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	})
+	return server
+}
+
+// connect connects a client to the server in memory.
+func connect(t *testing.T, server *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { ss.Close() })
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "crush"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+func TestGetResources(t *testing.T) {
+	t.Parallel()
+
+	resources, templates := getResources(t.Context(), connect(t, newResourceServer()))
+	require.Len(t, resources, 1)
+	require.Equal(t, "file:///readme", resources[0].URI)
+	require.Len(t, templates, 1)
+	require.Equal(t, "file:///pages/{name}", templates[0].URITemplate)
+}
+
+func TestGetResourcesListError(t *testing.T) {
+	t.Parallel()
+
+	// Failing to list resources leaves the MCP without resources, rather
+	// than failing it.
+	server := newResourceServer()
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "resources/list" {
+				return nil, errors.New("resources unavailable")
+			}
+			return next(ctx, method, req)
+		}
+	})
+
+	resources, templates := getResources(t.Context(), connect(t, server))
+	require.Empty(t, resources)
+	require.Len(t, templates, 1)
+}
+
+func TestReadResource(t *testing.T) {
+	_, err := config.Init(t.TempDir(), t.TempDir(), false)
+	require.NoError(t, err)
+
+	sessions.Set("docs", connect(t, newResourceServer()))
+	t.Cleanup(func() { sessions.Del("docs") })
+
+	contents, err := ReadResource(t.Context(), "docs", "file:///readme")
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	require.Equal(t, "# Readme", contents[0].Text)
+
+	_, err = ReadResource(t.Context(), "docs", "file:///pages/missing")
+	require.Error(t, err)
+
+	_, err = ReadResource(t.Context(), "other", "file:///readme")
+	require.EqualError(t, err, "mcp 'other' not available")
+}
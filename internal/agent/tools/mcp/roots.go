@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/home"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// roots returns the directories MCP servers may operate on: the working
+// directory and the additional directories of the options.
+func roots(cfg *config.Config) []*mcp.Root {
+	dirs := []string{cfg.WorkingDir()}
+	for _, dir := range cfg.Options.AdditionalDirs {
+		dir = home.Long(dir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(cfg.WorkingDir(), dir)
+		}
+		dirs = append(dirs, filepath.Clean(dir))
+	}
+
+	roots := make([]*mcp.Root, 0, len(dirs))
+	for _, dir := range dirs {
+		path := filepath.ToSlash(dir)
+		if !strings.HasPrefix(path, "/") {
+			// Windows drive letters.
+			path = "/" + path
+		}
+		roots = append(roots, &mcp.Root{
+			Name: filepath.Base(dir),
+			URI:  (&url.URL{Scheme: "file", Path: path}).String(),
+		})
+	}
+	return roots
+}
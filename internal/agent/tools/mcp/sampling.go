@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SamplingMessage is a message of the conversation an MCP server asks the
+// model to continue.
+type SamplingMessage struct {
+	Role      string // "user" or "assistant"
+	Text      string
+	Data      []byte // Image or audio data
+	MediaType string
+}
+
+// SamplingRequest is a request of an MCP server to sample the model.
+type SamplingRequest struct {
+	MCP           string
+	SystemPrompt  string
+	Messages      []SamplingMessage
+	MaxTokens     int64
+	Temperature   float64
+	StopSequences []string
+}
+
+// SamplingResponse is the message generated for a [SamplingRequest].
+type SamplingResponse struct {
+	Text       string
+	Model      string
+	StopReason string
+}
+
+// SamplingHandler generates the response to a sampling request.
+type SamplingHandler func(ctx context.Context, req SamplingRequest) (SamplingResponse, error)
+
+var (
+	samplingHandler = csync.NewValue[SamplingHandler](nil)
+	toolSessions    = &toolCalls{sessions: make(map[string][]string)}
+	permissions     permission.Service
+)
+
+// toolCalls tracks the sessions running tools of each MCP, so requests made
+// by a server during a call are attributed to a session. Calls may overlap,
+// so a stack is kept per MCP and the latest call still running wins.
+type toolCalls struct {
+	mu       sync.Mutex
+	sessions map[string][]string
+}
+
+// start records a call of an MCP tool by the given session. The returned
+// function must be called when the call ends.
+func (t *toolCalls) start(name, sessionID string) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sessions[name] = append(t.sessions[name], sessionID)
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		sessions := t.sessions[name]
+		if i := slices.Index(sessions, sessionID); i >= 0 {
+			sessions = slices.Delete(sessions, i, i+1)
+		}
+		if len(sessions) == 0 {
+			delete(t.sessions, name)
+		} else {
+			t.sessions[name] = sessions
+		}
+	}
+}
+
+// session returns the session of the latest running call of an MCP tool.
+func (t *toolCalls) session(name string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sessions := t.sessions[name]
+	if len(sessions) == 0 {
+		return ""
+	}
+	return sessions[len(sessions)-1]
+}
+
+// SetSamplingHandler sets the handler serving the sampling requests of MCP
+// servers. Until it is set, sampling requests fail.
+func SetSamplingHandler(h SamplingHandler) {
+	samplingHandler.Set(h)
+}
+
+func createMessageHandler(name string) func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		handler := samplingHandler.Get()
+		if handler == nil {
+			return nil, errors.New("sampling is not available")
+		}
+
+		request := SamplingRequest{
+			MCP:           name,
+			SystemPrompt:  req.Params.SystemPrompt,
+			MaxTokens:     req.Params.MaxTokens,
+			Temperature:   req.Params.Temperature,
+			StopSequences: req.Params.StopSequences,
+		}
+		for _, msg := range req.Params.Messages {
+			m := SamplingMessage{Role: string(msg.Role)}
+			switch content := msg.Content.(type) {
+			case *mcp.TextContent:
+				m.Text = content.Text
+			case *mcp.ImageContent:
+				m.Data, m.MediaType = content.Data, content.MIMEType
+			case *mcp.AudioContent:
+				m.Data, m.MediaType = content.Data, content.MIMEType
+			default:
+				return nil, fmt.Errorf("unsupported sampling content %T", msg.Content)
+			}
+			request.Messages = append(request.Messages, m)
+		}
+
+		if permissions != nil {
+			granted, err := permissions.Request(ctx, permission.CreatePermissionRequest{
+				SessionID:   toolSessions.session(name),
+				Path:        config.Get().WorkingDir(),
+				ToolName:    fmt.Sprintf("mcp_%s_sampling", name),
+				Action:      "sample",
+				Description: fmt.Sprintf("MCP server %s wants the model to complete the following conversation:", name),
+				Params:      request.prompt(),
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !granted {
+				return nil, permission.ErrorPermissionDenied
+			}
+		}
+
+		resp, err := handler(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.CreateMessageResult{
+			Content:    &mcp.TextContent{Text: resp.Text},
+			Model:      resp.Model,
+			Role:       "assistant",
+			StopReason: resp.StopReason,
+		}, nil
+	}
+}
+
+// prompt renders the request for the user to review.
+func (r SamplingRequest) prompt() string {
+	var sb strings.Builder
+	if r.SystemPrompt != "" {
+		fmt.Fprintf(&sb, "system: %s\n\n", r.SystemPrompt)
+	}
+	for _, m := range r.Messages {
+		if m.Text != "" {
+			fmt.Fprintf(&sb, "%s: %s\n\n", m.Role, m.Text)
+		} else {
+			fmt.Fprintf(&sb, "%s: [%s, %d bytes]\n\n", m.Role, m.MediaType, len(m.Data))
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
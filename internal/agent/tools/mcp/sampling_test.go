@@ -0,0 +1,27 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolCalls(t *testing.T) {
+	t.Parallel()
+
+	calls := &toolCalls{sessions: make(map[string][]string)}
+	require.Empty(t, calls.session("test"))
+
+	endFirst := calls.start("test", "first")
+	endSecond := calls.start("test", "second")
+	calls.start("other", "third")
+	require.Equal(t, "second", calls.session("test"))
+
+	// The first call ending must not forget the second one.
+	endFirst()
+	require.Equal(t, "second", calls.session("test"))
+
+	endSecond()
+	require.Empty(t, calls.session("test"))
+	require.Equal(t, "third", calls.session("other"))
+}
@@ -31,8 +31,9 @@ func Tools() iter.Seq2[string, []*Tool] {
 	return allTools.Seq2()
 }
 
-// RunTool runs an MCP tool with the given input parameters.
-func RunTool(ctx context.Context, name, toolName string, input string) (ToolResult, error) {
+// RunTool runs an MCP tool with the given input parameters on behalf of the
+// given session.
+func RunTool(ctx context.Context, sessionID, name, toolName string, input string) (ToolResult, error) {
 	var args map[string]any
 	if err := json.Unmarshal([]byte(input), &args); err != nil {
 		return ToolResult{}, fmt.Errorf("error parsing parameters: %s", err)
@@ -42,6 +43,7 @@ func RunTool(ctx context.Context, name, toolName string, input string) (ToolResu
 	if err != nil {
 		return ToolResult{}, err
 	}
+	defer toolSessions.start(name, sessionID)()
 	result, err := c.CallTool(ctx, &mcp.CallToolParams{
 		Name:      toolName,
 		Arguments: args,
@@ -109,6 +109,7 @@ func NewMultiEditTool(
 			// Wait for LSP diagnostics and add them to the response
 			text := fmt.Sprintf("<result>\n%s\n</result>\n", response.Content)
 			text += getDiagnostics(params.FilePath, lspClients)
+			text += getNestedContext(ctx, workingDir, params.FilePath)
 			response.Content = text
 			return response, nil
 		})
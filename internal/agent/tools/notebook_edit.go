@@ -0,0 +1,457 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/diff"
+	"github.com/charmbracelet/crush/internal/extract"
+	"github.com/charmbracelet/crush/internal/filepathext"
+	"github.com/charmbracelet/crush/internal/filetracker"
+	"github.com/charmbracelet/crush/internal/fsext"
+	"github.com/charmbracelet/crush/internal/history"
+	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/google/uuid"
+)
+
+type NotebookEditParams struct {
+	NotebookPath string `json:"notebook_path" description:"The path to the Jupyter notebook (.ipynb) to modify"`
+	CellID       string `json:"cell_id,omitempty" description:"The ID of the cell to edit. For insert the new cell is added after it"`
+	CellIndex    *int   `json:"cell_index,omitempty" description:"The 0-based index of the cell to edit (alternative to cell_id). For insert the new cell is added at this index"`
+	NewSource    string `json:"new_source,omitempty" description:"The new source of the cell (for replace and insert)"`
+	CellType     string `json:"cell_type,omitempty" description:"The type of the cell: code or markdown. Required for insert; for replace it changes the type of the cell"`
+	EditMode     string `json:"edit_mode,omitempty" description:"The kind of edit: replace (default), insert or delete"`
+}
+
+type NotebookEditPermissionsParams struct {
+	NotebookPath string `json:"notebook_path"`
+	CellIndex    int    `json:"cell_index"`
+	CellID       string `json:"cell_id,omitempty"`
+	EditMode     string `json:"edit_mode"`
+	OldContent   string `json:"old_content,omitempty"`
+	NewContent   string `json:"new_content,omitempty"`
+}
+
+type NotebookEditResponseMetadata struct {
+	Additions  int    `json:"additions"`
+	Removals   int    `json:"removals"`
+	CellIndex  int    `json:"cell_index"`
+	CellID     string `json:"cell_id,omitempty"`
+	EditMode   string `json:"edit_mode"`
+	OldContent string `json:"old_content,omitempty"`
+	NewContent string `json:"new_content,omitempty"`
+}
+
+const (
+	NotebookEditToolName = "notebook_edit"
+
+	NotebookEditReplace = "replace"
+	NotebookEditInsert  = "insert"
+	NotebookEditDelete  = "delete"
+)
+
+//go:embed notebook_edit.md
+var notebookEditDescription []byte
+
+func NewNotebookEditTool(
+	permissions permission.Service,
+	files history.Service,
+	filetracker filetracker.Service,
+	workingDir string,
+) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		NotebookEditToolName,
+		string(notebookEditDescription),
+		func(ctx context.Context, params NotebookEditParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			if params.NotebookPath == "" {
+				return fantasy.NewTextErrorResponse("notebook_path is required"), nil
+			}
+			if !strings.EqualFold(filepath.Ext(params.NotebookPath), ".ipynb") {
+				return fantasy.NewTextErrorResponse("notebook_path must be a Jupyter notebook (.ipynb). Use the edit tool for other files"), nil
+			}
+			if params.EditMode == "" {
+				params.EditMode = NotebookEditReplace
+			}
+			switch params.EditMode {
+			case NotebookEditReplace, NotebookEditInsert, NotebookEditDelete:
+			default:
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("invalid edit_mode %q: must be replace, insert or delete", params.EditMode)), nil
+			}
+			if params.CellType != "" && params.CellType != "code" && params.CellType != "markdown" {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("invalid cell_type %q: must be code or markdown", params.CellType)), nil
+			}
+			if params.EditMode == NotebookEditInsert && params.CellType == "" {
+				return fantasy.NewTextErrorResponse("cell_type is required to insert a cell"), nil
+			}
+
+			notebookPath := filepathext.SmartJoin(workingDir, params.NotebookPath)
+			fileInfo, err := os.Stat(notebookPath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return fantasy.NewTextErrorResponse(fmt.Sprintf("file not found: %s", notebookPath)), nil
+				}
+				return fantasy.ToolResponse{}, fmt.Errorf("failed to access file: %w", err)
+			}
+			if fileInfo.IsDir() {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("path is a directory, not a file: %s", notebookPath)), nil
+			}
+
+			sessionID := GetSessionFromContext(ctx)
+			if sessionID == "" {
+				return fantasy.ToolResponse{}, fmt.Errorf("session ID is required for editing a notebook")
+			}
+
+			lastRead := filetracker.LastReadTime(ctx, sessionID, notebookPath)
+			if lastRead.IsZero() {
+				return fantasy.NewTextErrorResponse("you must read the notebook before editing it. Use the View tool first"), nil
+			}
+			modTime := fileInfo.ModTime().Truncate(time.Second)
+			if modTime.After(lastRead) {
+				return fantasy.NewTextErrorResponse(
+					fmt.Sprintf("file %s has been modified since it was last read (mod time: %s, last read: %s)",
+						notebookPath, modTime.Format(time.RFC3339), lastRead.Format(time.RFC3339),
+					)), nil
+			}
+
+			content, err := os.ReadFile(notebookPath)
+			if err != nil {
+				return fantasy.ToolResponse{}, fmt.Errorf("failed to read file: %w", err)
+			}
+			nb, err := parseNotebookFile(content)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			result, err := nb.apply(params)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			newContent, err := nb.marshal()
+			if err != nil {
+				return fantasy.ToolResponse{}, fmt.Errorf("failed to encode notebook: %w", err)
+			}
+
+			cellLabel := notebookCellLabel(result.index, result.id)
+			_, additions, removals := diff.GenerateDiff(
+				result.oldSource,
+				result.newSource,
+				strings.TrimPrefix(notebookPath, workingDir)+" ("+cellLabel+")",
+			)
+
+			p, err := permissions.Request(ctx,
+				permission.CreatePermissionRequest{
+					SessionID:   sessionID,
+					Path:        fsext.PathOrPrefix(notebookPath, workingDir),
+					ToolCallID:  call.ID,
+					ToolName:    NotebookEditToolName,
+					Action:      "write",
+					Description: fmt.Sprintf("%s %s in notebook %s", notebookEditVerb(params.EditMode), cellLabel, notebookPath),
+					Params: NotebookEditPermissionsParams{
+						NotebookPath: notebookPath,
+						CellIndex:    result.index,
+						CellID:       result.id,
+						EditMode:     params.EditMode,
+						OldContent:   result.oldSource,
+						NewContent:   result.newSource,
+					},
+				},
+			)
+			if err != nil {
+				return fantasy.ToolResponse{}, err
+			}
+			if !p {
+				return fantasy.ToolResponse{}, permission.ErrorPermissionDenied
+			}
+
+			if err := os.WriteFile(notebookPath, newContent, 0o644); err != nil {
+				return fantasy.ToolResponse{}, fmt.Errorf("failed to write file: %w", err)
+			}
+			recordNotebookVersion(ctx, files, sessionID, notebookPath, string(content), string(newContent))
+			filetracker.RecordRead(ctx, sessionID, notebookPath)
+
+			text := fmt.Sprintf("%s %s in notebook %s", notebookEditPastVerb(params.EditMode), cellLabel, notebookPath)
+			if params.EditMode != NotebookEditDelete {
+				if rendered, err := extract.ParseNotebook(newContent); err == nil && result.index < len(rendered.Cells) {
+					text += "\n\n" + rendered.RenderCell(result.index)
+				}
+			}
+			return fantasy.WithResponseMetadata(
+				fantasy.NewTextResponse(fmt.Sprintf("<result>\n%s\n</result>\n", text)),
+				NotebookEditResponseMetadata{
+					Additions:  additions,
+					Removals:   removals,
+					CellIndex:  result.index,
+					CellID:     result.id,
+					EditMode:   params.EditMode,
+					OldContent: result.oldSource,
+					NewContent: result.newSource,
+				},
+			), nil
+		})
+}
+
+// recordNotebookVersion records the new content of a notebook in the file
+// history, with an intermediate version if it was changed outside of Crush.
+func recordNotebookVersion(ctx context.Context, files history.Service, sessionID, path, oldContent, newContent string) {
+	file, err := files.GetByPathAndSession(ctx, path, sessionID)
+	if err != nil {
+		if _, err = files.Create(ctx, sessionID, path, oldContent); err != nil {
+			slog.Error("Error creating file history", "error", err)
+			return
+		}
+	} else if file.Content != oldContent {
+		if _, err = files.CreateVersion(ctx, sessionID, path, oldContent); err != nil {
+			slog.Error("Error creating file history version", "error", err)
+		}
+	}
+	if _, err = files.CreateVersion(ctx, sessionID, path, newContent); err != nil {
+		slog.Error("Error creating file history version", "error", err)
+	}
+}
+
+func notebookCellLabel(index int, id string) string {
+	if id != "" {
+		return fmt.Sprintf("cell %d (id %s)", index, id)
+	}
+	return fmt.Sprintf("cell %d", index)
+}
+
+func notebookEditVerb(mode string) string {
+	switch mode {
+	case NotebookEditInsert:
+		return "Insert"
+	case NotebookEditDelete:
+		return "Delete"
+	default:
+		return "Replace"
+	}
+}
+
+func notebookEditPastVerb(mode string) string {
+	switch mode {
+	case NotebookEditInsert:
+		return "Inserted"
+	case NotebookEditDelete:
+		return "Deleted"
+	default:
+		return "Replaced"
+	}
+}
+
+// notebookFile is a notebook decoded as generic JSON, so that the fields of
+// the notebook and its cells that aren't edited are written back unchanged.
+// Keys are written sorted, as Jupyter does.
+type notebookFile struct {
+	doc             map[string]any
+	cells           []any
+	indent          string
+	trailingNewline bool
+}
+
+// notebookEdit describes an applied edit.
+type notebookEdit struct {
+	index                int
+	id                   string
+	oldSource, newSource string
+}
+
+func parseNotebookFile(data []byte) (*notebookFile, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid notebook: %w", err)
+	}
+	cells, ok := doc["cells"].([]any)
+	if !ok {
+		return nil, errors.New("invalid notebook: missing cells")
+	}
+	nb := &notebookFile{
+		doc:             doc,
+		cells:           cells,
+		trailingNewline: bytes.HasSuffix(data, []byte("\n")),
+	}
+	// Keep the indentation of the file, which is one space for notebooks
+	// written by Jupyter.
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		rest := data[i+1:]
+		nb.indent = string(rest[:len(rest)-len(bytes.TrimLeft(rest, " \t"))])
+	}
+	return nb, nil
+}
+
+func (nb *notebookFile) marshal() ([]byte, error) {
+	nb.doc["cells"] = nb.cells
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if nb.indent != "" {
+		enc.SetIndent("", nb.indent)
+	}
+	if err := enc.Encode(nb.doc); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	if !nb.trailingNewline {
+		data = bytes.TrimSuffix(data, []byte("\n"))
+	}
+	return data, nil
+}
+
+func (nb *notebookFile) apply(params NotebookEditParams) (notebookEdit, error) {
+	if params.EditMode == NotebookEditInsert {
+		index := len(nb.cells)
+		if params.CellID != "" || params.CellIndex != nil {
+			i, err := nb.find(params.CellID, params.CellIndex, true)
+			if err != nil {
+				return notebookEdit{}, err
+			}
+			index = i
+			if params.CellID != "" && params.CellIndex == nil {
+				index++
+			}
+		}
+		cell := nb.newCell(params.CellType, params.NewSource)
+		nb.cells = slices.Insert(nb.cells, index, any(cell))
+		return notebookEdit{index: index, id: cellString(cell, "id"), newSource: params.NewSource}, nil
+	}
+
+	if params.CellID == "" && params.CellIndex == nil {
+		return notebookEdit{}, errors.New("cell_id or cell_index is required")
+	}
+	index, err := nb.find(params.CellID, params.CellIndex, false)
+	if err != nil {
+		return notebookEdit{}, err
+	}
+	cell, ok := nb.cells[index].(map[string]any)
+	if !ok {
+		return notebookEdit{}, fmt.Errorf("invalid notebook: cell %d is not an object", index)
+	}
+	edit := notebookEdit{index: index, id: cellString(cell, "id"), oldSource: cellSource(cell)}
+
+	if params.EditMode == NotebookEditDelete {
+		nb.cells = slices.Delete(nb.cells, index, index+1)
+		return edit, nil
+	}
+
+	if params.NewSource == edit.oldSource && (params.CellType == "" || params.CellType == cellString(cell, "cell_type")) {
+		return notebookEdit{}, errors.New("new source is the same as the cell's source. No changes made")
+	}
+	cell["source"] = sourceLines(params.NewSource)
+	if params.CellType != "" && params.CellType != cellString(cell, "cell_type") {
+		setCellType(cell, params.CellType)
+	}
+	edit.newSource = params.NewSource
+	return edit, nil
+}
+
+// find returns the index of the cell with an ID or index. If both are given
+// they must refer to the same cell. For inserts, the index may be one past
+// the last cell.
+func (nb *notebookFile) find(id string, index *int, insert bool) (int, error) {
+	found := -1
+	if id != "" {
+		for i, c := range nb.cells {
+			if cell, ok := c.(map[string]any); ok && cellString(cell, "id") == id {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return 0, fmt.Errorf("cell with id %q not found", id)
+		}
+	}
+	if index != nil {
+		last := len(nb.cells) - 1
+		if insert {
+			last++
+		}
+		if *index < 0 || *index > last {
+			return 0, fmt.Errorf("cell_index %d is out of range: the notebook has %d cells", *index, len(nb.cells))
+		}
+		if found >= 0 && found != *index {
+			return 0, fmt.Errorf("cell_id %q is cell %d, not cell %d", id, found, *index)
+		}
+		found = *index
+	}
+	return found, nil
+}
+
+func (nb *notebookFile) newCell(cellType, source string) map[string]any {
+	cell := map[string]any{
+		"cell_type": cellType,
+		"metadata":  map[string]any{},
+		"source":    sourceLines(source),
+	}
+	// Cell IDs were added in nbformat 4.5.
+	major, _ := nb.doc["nbformat"].(json.Number)
+	minor, _ := nb.doc["nbformat_minor"].(json.Number)
+	majorVersion, _ := major.Int64()
+	minorVersion, _ := minor.Int64()
+	if majorVersion > 4 || (majorVersion == 4 && minorVersion >= 5) {
+		cell["id"] = strings.ReplaceAll(uuid.NewString(), "-", "")[:8]
+	}
+	if cellType == "code" {
+		cell["execution_count"] = nil
+		cell["outputs"] = []any{}
+	}
+	return cell
+}
+
+// setCellType changes the type of a cell, adding or dropping the fields only
+// code cells have.
+func setCellType(cell map[string]any, cellType string) {
+	cell["cell_type"] = cellType
+	if cellType == "code" {
+		cell["execution_count"] = nil
+		cell["outputs"] = []any{}
+		return
+	}
+	delete(cell, "execution_count")
+	delete(cell, "outputs")
+}
+
+func cellString(cell map[string]any, key string) string {
+	s, _ := cell[key].(string)
+	return s
+}
+
+// cellSource returns the source of a cell, which is stored either as a
+// string or as a list of lines.
+func cellSource(cell map[string]any) string {
+	switch source := cell["source"].(type) {
+	case string:
+		return source
+	case []any:
+		var b strings.Builder
+		for _, line := range source {
+			if s, ok := line.(string); ok {
+				b.WriteString(s)
+			}
+		}
+		return b.String()
+	}
+	return ""
+}
+
+// sourceLines splits a source into lines that keep their line endings, as
+// Jupyter stores them.
+func sourceLines(source string) []any {
+	lines := []any{}
+	for _, line := range strings.SplitAfter(source, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/extract"
+	"github.com/stretchr/testify/require"
+)
+
+func cellIndex(i int) *int {
+	return &i
+}
+
+func loadTestNotebook(t *testing.T) *notebookFile {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "view", "analysis.ipynb"))
+	require.NoError(t, err)
+	nb, err := parseNotebookFile(data)
+	require.NoError(t, err)
+	return nb
+}
+
+func renderedNotebook(t *testing.T, nb *notebookFile) *extract.Notebook {
+	t.Helper()
+	data, err := nb.marshal()
+	require.NoError(t, err)
+	rendered, err := extract.ParseNotebook(data)
+	require.NoError(t, err)
+	return rendered
+}
+
+func TestNotebookEditReplace(t *testing.T) {
+	t.Parallel()
+
+	nb := loadTestNotebook(t)
+	edit, err := nb.apply(NotebookEditParams{
+		CellID:    "sum",
+		NewSource: "total = sum(rows)\ntotal",
+		EditMode:  NotebookEditReplace,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, edit.index)
+	require.Equal(t, "sum", edit.id)
+	require.Equal(t, "sum(rows)", edit.oldSource)
+
+	rendered := renderedNotebook(t, nb)
+	require.Len(t, rendered.Cells, 3)
+	require.Equal(t, "total = sum(rows)\ntotal", string(rendered.Cells[2].Source))
+	// Outputs and execution counts are left alone.
+	require.Len(t, rendered.Cells[2].Outputs, 1)
+	require.Equal(t, 2, *rendered.Cells[2].ExecutionCount)
+
+	_, err = nb.apply(NotebookEditParams{CellIndex: cellIndex(0), NewSource: "# Analysis", EditMode: NotebookEditReplace})
+	require.ErrorContains(t, err, "No changes made")
+}
+
+func TestNotebookEditChangeCellType(t *testing.T) {
+	t.Parallel()
+
+	nb := loadTestNotebook(t)
+	_, err := nb.apply(NotebookEditParams{
+		CellIndex: cellIndex(1),
+		NewSource: "Loads the rows.",
+		CellType:  "markdown",
+		EditMode:  NotebookEditReplace,
+	})
+	require.NoError(t, err)
+
+	cell := nb.cells[1].(map[string]any)
+	require.Equal(t, "markdown", cell["cell_type"])
+	require.NotContains(t, cell, "outputs")
+	require.NotContains(t, cell, "execution_count")
+}
+
+func TestNotebookEditInsert(t *testing.T) {
+	t.Parallel()
+
+	t.Run("after cell id", func(t *testing.T) {
+		t.Parallel()
+		nb := loadTestNotebook(t)
+		edit, err := nb.apply(NotebookEditParams{
+			CellID:    "title",
+			NewSource: "import math\n",
+			CellType:  "code",
+			EditMode:  NotebookEditInsert,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, edit.index)
+		require.Len(t, edit.id, 8)
+
+		rendered := renderedNotebook(t, nb)
+		require.Len(t, rendered.Cells, 4)
+		require.Equal(t, "code", rendered.Cells[1].CellType)
+		require.Equal(t, "import math\n", string(rendered.Cells[1].Source))
+		require.Nil(t, rendered.Cells[1].ExecutionCount)
+		require.Equal(t, "load", rendered.Cells[2].ID)
+	})
+
+	t.Run("at index", func(t *testing.T) {
+		t.Parallel()
+		nb := loadTestNotebook(t)
+		edit, err := nb.apply(NotebookEditParams{
+			CellIndex: cellIndex(0),
+			NewSource: "Intro",
+			CellType:  "markdown",
+			EditMode:  NotebookEditInsert,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, edit.index)
+		require.Equal(t, "title", renderedNotebook(t, nb).Cells[1].ID)
+	})
+
+	t.Run("at end", func(t *testing.T) {
+		t.Parallel()
+		nb := loadTestNotebook(t)
+		edit, err := nb.apply(NotebookEditParams{
+			NewSource: "Done",
+			CellType:  "markdown",
+			EditMode:  NotebookEditInsert,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, edit.index)
+
+		_, err = nb.apply(NotebookEditParams{
+			CellIndex: cellIndex(10),
+			CellType:  "markdown",
+			EditMode:  NotebookEditInsert,
+		})
+		require.ErrorContains(t, err, "out of range")
+	})
+}
+
+func TestNotebookEditDelete(t *testing.T) {
+	t.Parallel()
+
+	nb := loadTestNotebook(t)
+	edit, err := nb.apply(NotebookEditParams{CellID: "load", EditMode: NotebookEditDelete})
+	require.NoError(t, err)
+	require.Equal(t, 1, edit.index)
+	require.Contains(t, edit.oldSource, "rows = [1, 2, 3]")
+
+	rendered := renderedNotebook(t, nb)
+	require.Len(t, rendered.Cells, 2)
+	require.Equal(t, "sum", rendered.Cells[1].ID)
+}
+
+func TestNotebookEditFindErrors(t *testing.T) {
+	t.Parallel()
+
+	nb := loadTestNotebook(t)
+
+	_, err := nb.apply(NotebookEditParams{EditMode: NotebookEditReplace})
+	require.ErrorContains(t, err, "cell_id or cell_index is required")
+
+	_, err = nb.apply(NotebookEditParams{CellID: "missing", EditMode: NotebookEditReplace})
+	require.ErrorContains(t, err, `cell with id "missing" not found`)
+
+	_, err = nb.apply(NotebookEditParams{CellIndex: cellIndex(3), EditMode: NotebookEditDelete})
+	require.ErrorContains(t, err, "out of range")
+
+	_, err = nb.apply(NotebookEditParams{CellID: "sum", CellIndex: cellIndex(1), EditMode: NotebookEditDelete})
+	require.ErrorContains(t, err, `cell_id "sum" is cell 2, not cell 1`)
+}
+
+func TestNotebookFileRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("{\n \"cells\": [],\n \"metadata\": {\"widgets\": \"<b>&</b>\"},\n \"nbformat\": 4,\n \"nbformat_minor\": 2\n}\n")
+	nb, err := parseNotebookFile(data)
+	require.NoError(t, err)
+	require.Equal(t, " ", nb.indent)
+
+	edit, err := nb.apply(NotebookEditParams{NewSource: "x", CellType: "markdown", EditMode: NotebookEditInsert})
+	require.NoError(t, err)
+	// Notebooks older than nbformat 4.5 have no cell IDs.
+	require.Empty(t, edit.id)
+
+	out, err := nb.marshal()
+	require.NoError(t, err)
+	require.Equal(t, `{
+ "cells": [
+  {
+   "cell_type": "markdown",
+   "metadata": {},
+   "source": [
+    "x"
+   ]
+  }
+ ],
+ "metadata": {
+  "widgets": "<b>&</b>"
+ },
+ "nbformat": 4,
+ "nbformat_minor": 2
+}
+`, string(out))
+
+	_, err = parseNotebookFile([]byte(`{"metadata": {}}`))
+	require.ErrorContains(t, err, "missing cells")
+}
@@ -0,0 +1,156 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
+)
+
+const ReadMCPResourceToolName = "read_mcp_resource"
+
+//go:embed read_mcp_resource.md
+var readMCPResourceDescription []byte
+
+type ReadMCPResourceParams struct {
+	Server string `json:"server,omitempty" description:"The name of the MCP server providing the resource"`
+	URI    string `json:"uri,omitempty" description:"The URI of the resource to read. Leave empty to list the available resources"`
+}
+
+type ReadMCPResourceResponseMetadata struct {
+	Server   string `json:"server"`
+	URI      string `json:"uri"`
+	MimeType string `json:"mime_type,omitempty"`
+}
+
+// NewReadMCPResourceTool creates a tool to list and read MCP resources. Only
+// the resources of MCPs in allowedMCP are available; a nil map allows all of
+// them.
+func NewReadMCPResourceTool(allowedMCP map[string][]string) fantasy.AgentTool {
+	allowed := func(name string) bool {
+		if allowedMCP == nil {
+			return true
+		}
+		_, ok := allowedMCP[name]
+		return ok
+	}
+
+	return fantasy.NewAgentTool(
+		ReadMCPResourceToolName,
+		string(readMCPResourceDescription),
+		func(ctx context.Context, params ReadMCPResourceParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			if params.Server != "" && !allowed(params.Server) {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("MCP server not available: %s", params.Server)), nil
+			}
+			if params.URI == "" {
+				return fantasy.NewTextResponse(listMCPResources(params.Server, allowed, mcp.Resources(), mcp.ResourceTemplates())), nil
+			}
+			if params.Server == "" {
+				return fantasy.NewTextErrorResponse("missing server"), nil
+			}
+
+			contents, err := mcp.ReadResource(ctx, params.Server, params.URI)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+
+			return mcpResourceResponse(ctx, ReadMCPResourceResponseMetadata{
+				Server: params.Server,
+				URI:    params.URI,
+			}, contents), nil
+		})
+}
+
+// mcpResourceResponse turns the contents of an MCP resource into the response
+// of the tool. Binary contents are only used when the resource has no text,
+// and only images can be shown to the model.
+func mcpResourceResponse(ctx context.Context, metadata ReadMCPResourceResponseMetadata, contents []*mcp.ResourceContents) fantasy.ToolResponse {
+	var texts []string
+	var blob *mcp.ResourceContents
+	for _, c := range contents {
+		switch {
+		case c.Text != "":
+			texts = append(texts, c.Text)
+		case len(c.Blob) > 0 && blob == nil:
+			blob = c
+		}
+	}
+
+	if len(texts) == 0 && blob != nil {
+		mimeType := cmp.Or(blob.MIMEType, "application/octet-stream")
+		metadata.MimeType = mimeType
+		if !strings.HasPrefix(mimeType, "image/") {
+			return fantasy.NewTextErrorResponse(fmt.Sprintf("Resource has binary contents of type %s which cannot be displayed.", mimeType))
+		}
+		if !GetSupportsImagesFromContext(ctx) {
+			modelName := GetModelNameFromContext(ctx)
+			return fantasy.NewTextErrorResponse(fmt.Sprintf("This model (%s) does not support image data.", modelName))
+		}
+		encoded := base64.StdEncoding.EncodeToString(blob.Blob)
+		return fantasy.WithResponseMetadata(fantasy.NewImageResponse([]byte(encoded), mimeType), metadata)
+	}
+
+	if len(contents) > 0 {
+		metadata.MimeType = contents[0].MIMEType
+	}
+	output := strings.Join(texts, "\n")
+	if output == "" {
+		output = "Resource is empty."
+	}
+	return fantasy.WithResponseMetadata(fantasy.NewTextResponse(output), metadata)
+}
+
+// listMCPResources describes the resources and resource templates of the
+// given MCP, or of all allowed MCPs when server is empty.
+func listMCPResources(server string, allowed func(string) bool, allResources iter.Seq2[string, []*mcp.Resource], allTemplates iter.Seq2[string, []*mcp.ResourceTemplate]) string {
+	include := func(name string) bool {
+		return allowed(name) && (server == "" || name == server)
+	}
+
+	lines := map[string][]string{}
+	for name, resources := range allResources {
+		if !include(name) {
+			continue
+		}
+		for _, r := range resources {
+			line := fmt.Sprintf("- %s (%s)", r.URI, cmp.Or(r.Title, r.Name))
+			if r.Description != "" {
+				line += ": " + r.Description
+			}
+			lines[name] = append(lines[name], line)
+		}
+	}
+	for name, templates := range allTemplates {
+		if !include(name) {
+			continue
+		}
+		for _, t := range templates {
+			line := fmt.Sprintf("- %s (template, %s)", t.URITemplate, cmp.Or(t.Title, t.Name))
+			if t.Description != "" {
+				line += ": " + t.Description
+			}
+			lines[name] = append(lines[name], line)
+		}
+	}
+
+	if len(lines) == 0 {
+		if server != "" {
+			return fmt.Sprintf("MCP server %s has no resources.", server)
+		}
+		return "No MCP resources available."
+	}
+
+	var sb strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(lines)) {
+		fmt.Fprintf(&sb, "Server %s:\n%s\n\n", name, strings.Join(lines[name], "\n"))
+	}
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMCPResourceParams(t *testing.T) {
+	t.Parallel()
+
+	tool := NewReadMCPResourceTool(map[string][]string{"docs": nil})
+	run := func(params ReadMCPResourceParams) fantasy.ToolResponse {
+		input, err := json.Marshal(params)
+		require.NoError(t, err)
+		resp, err := tool.Run(t.Context(), fantasy.ToolCall{ID: "call-1", Name: ReadMCPResourceToolName, Input: string(input)})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := run(ReadMCPResourceParams{Server: "secrets", URI: "file:///etc/passwd"})
+	require.True(t, resp.IsError)
+	require.Equal(t, "MCP server not available: secrets", resp.Content)
+
+	resp = run(ReadMCPResourceParams{URI: "file:///readme"})
+	require.True(t, resp.IsError)
+	require.Equal(t, "missing server", resp.Content)
+}
+
+func TestListMCPResources(t *testing.T) {
+	t.Parallel()
+
+	resources := map[string][]*mcp.Resource{
+		"docs": {
+			{URI: "file:///readme", Name: "readme", Title: "README", Description: "The readme"},
+			{URI: "file:///changelog", Name: "changelog"},
+		},
+		"secrets": {{URI: "file:///key", Name: "key"}},
+	}
+	templates := map[string][]*mcp.ResourceTemplate{
+		"docs": {{URITemplate: "file:///pages/{name}", Name: "page"}},
+	}
+	allowed := func(name string) bool { return name != "secrets" }
+
+	list := listMCPResources("", allowed, maps.All(resources), maps.All(templates))
+	require.Equal(t, "Server docs:\n- file:///readme (README): The readme\n- file:///changelog (changelog)\n- file:///pages/{name} (template, page)", list)
+
+	list = listMCPResources("other", func(string) bool { return true }, maps.All(resources), maps.All(templates))
+	require.Equal(t, "MCP server other has no resources.", list)
+
+	list = listMCPResources("", func(string) bool { return false }, maps.All(resources), maps.All(templates))
+	require.Equal(t, "No MCP resources available.", list)
+}
+
+func TestMCPResourceResponse(t *testing.T) {
+	t.Parallel()
+
+	metadata := ReadMCPResourceResponseMetadata{Server: "docs", URI: "file:///readme"}
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+		resp := mcpResourceResponse(t.Context(), metadata, []*mcp.ResourceContents{
+			{URI: "file:///readme", MIMEType: "text/markdown", Text: "# Readme"},
+			{URI: "file:///readme", Blob: []byte{0x1}},
+			{URI: "file:///readme", Text: "More"},
+		})
+		require.False(t, resp.IsError)
+		require.Equal(t, "# Readme\nMore", resp.Content)
+		var got ReadMCPResourceResponseMetadata
+		require.NoError(t, json.Unmarshal([]byte(resp.Metadata), &got))
+		require.Equal(t, "text/markdown", got.MimeType)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		resp := mcpResourceResponse(t.Context(), metadata, nil)
+		require.False(t, resp.IsError)
+		require.Equal(t, "Resource is empty.", resp.Content)
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		t.Parallel()
+		resp := mcpResourceResponse(t.Context(), metadata, []*mcp.ResourceContents{
+			{URI: "file:///archive", MIMEType: "application/zip", Blob: []byte("PK")},
+		})
+		require.True(t, resp.IsError)
+		require.Contains(t, resp.Content, "application/zip")
+	})
+
+	t.Run("image", func(t *testing.T) {
+		t.Parallel()
+		contents := []*mcp.ResourceContents{{URI: "file:///logo", MIMEType: "image/png", Blob: []byte("png")}}
+
+		ctx := context.WithValue(t.Context(), ModelNameContextKey, "Text Model")
+		resp := mcpResourceResponse(ctx, metadata, contents)
+		require.True(t, resp.IsError)
+		require.Contains(t, resp.Content, "Text Model")
+
+		ctx = context.WithValue(t.Context(), SupportsImagesContextKey, true)
+		resp = mcpResourceResponse(ctx, metadata, contents)
+		require.False(t, resp.IsError)
+		require.Equal(t, "image/png", resp.MediaType)
+		require.Equal(t, []byte("cG5n"), resp.Data)
+	})
+}
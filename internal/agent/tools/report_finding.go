@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/diff"
+	"github.com/charmbracelet/crush/internal/filepathext"
+	"github.com/charmbracelet/crush/internal/review"
+)
+
+type ReportFindingParams struct {
+	FilePath  string `json:"file_path" description:"The path of the file the finding is about"`
+	StartLine int    `json:"start_line" description:"The first line of the code the finding is about"`
+	EndLine   int    `json:"end_line,omitempty" description:"The last line of the code the finding is about (defaults to start_line)"`
+	Severity  string `json:"severity" description:"How important the finding is: error, warning or info"`
+	Message   string `json:"message" description:"What the problem is and why it matters"`
+	OldString string `json:"old_string,omitempty" description:"For a suggested fix, the text to replace, which must appear exactly once in the file"`
+	NewString string `json:"new_string,omitempty" description:"For a suggested fix, the text to replace old_string with"`
+}
+
+const ReportFindingToolName = "report_finding"
+
+//go:embed report_finding.md
+var reportFindingDescription []byte
+
+// NewReportFindingTool creates the tool the reviewer agent reports its
+// findings with. Each valid finding is passed to report.
+func NewReportFindingTool(workingDir string, report func(review.Finding)) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		ReportFindingToolName,
+		string(reportFindingDescription),
+		func(ctx context.Context, params ReportFindingParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			finding, err := newFinding(workingDir, params)
+			if err != nil {
+				return fantasy.NewTextErrorResponse(err.Error()), nil
+			}
+			report(finding)
+			return fantasy.NewTextResponse(fmt.Sprintf("Recorded %s finding at %s", finding.Severity, finding.Location())), nil
+		})
+}
+
+// newFinding validates the parameters of a finding and builds it.
+func newFinding(workingDir string, params ReportFindingParams) (review.Finding, error) {
+	if params.FilePath == "" {
+		return review.Finding{}, fmt.Errorf("file_path is required")
+	}
+	if strings.TrimSpace(params.Message) == "" {
+		return review.Finding{}, fmt.Errorf("message is required")
+	}
+	severity := review.Severity(params.Severity)
+	if !severity.Valid() {
+		return review.Finding{}, fmt.Errorf("severity must be one of error, warning or info")
+	}
+	if params.StartLine < 1 {
+		return review.Finding{}, fmt.Errorf("start_line must be at least 1")
+	}
+	endLine := max(params.EndLine, params.StartLine)
+
+	filePath := filepathext.SmartJoin(workingDir, params.FilePath)
+	relPath, err := filepath.Rel(workingDir, filePath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return review.Finding{}, fmt.Errorf("file %s is outside the working directory", params.FilePath)
+	}
+	finding := review.Finding{
+		File:      filepath.ToSlash(relPath),
+		StartLine: params.StartLine,
+		EndLine:   endLine,
+		Severity:  severity,
+		Message:   strings.TrimSpace(params.Message),
+	}
+	if params.OldString == "" && params.NewString == "" {
+		return finding, nil
+	}
+
+	if params.OldString == "" {
+		return review.Finding{}, fmt.Errorf("old_string is required to suggest a fix")
+	}
+	if params.OldString == params.NewString {
+		return review.Finding{}, fmt.Errorf("new_string must be different from old_string")
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return review.Finding{}, fmt.Errorf("failed to read %s: %w", params.FilePath, err)
+	}
+	switch strings.Count(string(content), params.OldString) {
+	case 0:
+		return review.Finding{}, fmt.Errorf("old_string not found in %s, it must match the file exactly", params.FilePath)
+	case 1:
+	default:
+		return review.Finding{}, fmt.Errorf("old_string appears more than once in %s, include more context to make it unique", params.FilePath)
+	}
+	newContent := strings.Replace(string(content), params.OldString, params.NewString, 1)
+	patch, _, _ := diff.GenerateDiff(string(content), newContent, finding.File)
+	finding.Suggestion = &review.Suggestion{
+		OldString: params.OldString,
+		NewString: params.NewString,
+		Patch:     patch,
+	}
+	return finding, nil
+}
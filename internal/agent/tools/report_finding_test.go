@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/review"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportFindingTool(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "main.go"), []byte("package main\n\nfunc main() {\n\tf()\n\tf()\n\tg()\n}\n"), 0o644))
+
+	var findings []review.Finding
+	tool := NewReportFindingTool(dir, func(f review.Finding) {
+		findings = append(findings, f)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, params := range map[string]ReportFindingParams{
+			"missing message":   {FilePath: "pkg/main.go", StartLine: 4, Severity: "error"},
+			"unknown severity":  {FilePath: "pkg/main.go", StartLine: 4, Severity: "critical", Message: "x"},
+			"missing line":      {FilePath: "pkg/main.go", Severity: "error", Message: "x"},
+			"outside":           {FilePath: "../main.go", StartLine: 1, Severity: "error", Message: "x"},
+			"missing old":       {FilePath: "pkg/main.go", StartLine: 4, Severity: "error", Message: "x", NewString: "h()"},
+			"old not found":     {FilePath: "pkg/main.go", StartLine: 4, Severity: "error", Message: "x", OldString: "h()", NewString: "i()"},
+			"old not unique":    {FilePath: "pkg/main.go", StartLine: 4, Severity: "error", Message: "x", OldString: "f()", NewString: "h()"},
+			"same replacement":  {FilePath: "pkg/main.go", StartLine: 4, Severity: "error", Message: "x", OldString: "g()", NewString: "g()"},
+			"missing file path": {StartLine: 4, Severity: "error", Message: "x"},
+		} {
+			resp := runGitTool(t, tool, params)
+			require.True(t, resp.IsError, name)
+		}
+		require.Empty(t, findings)
+	})
+
+	t.Run("without fix", func(t *testing.T) {
+		resp := runGitTool(t, tool, ReportFindingParams{
+			FilePath:  filepath.Join(dir, "pkg", "main.go"),
+			StartLine: 4,
+			EndLine:   5,
+			Severity:  "warning",
+			Message:   "  f is called twice.  ",
+		})
+		require.False(t, resp.IsError, resp.Content)
+		require.Equal(t, review.Finding{
+			File:      "pkg/main.go",
+			StartLine: 4,
+			EndLine:   5,
+			Severity:  review.SeverityWarning,
+			Message:   "f is called twice.",
+		}, findings[0])
+	})
+
+	t.Run("with fix", func(t *testing.T) {
+		resp := runGitTool(t, tool, ReportFindingParams{
+			FilePath:  "pkg/main.go",
+			StartLine: 6,
+			Severity:  "error",
+			Message:   "The error of g is ignored.",
+			OldString: "\tg()\n",
+			NewString: "\tif err := g(); err != nil {\n\t\tpanic(err)\n\t}\n",
+		})
+		require.False(t, resp.IsError, resp.Content)
+		finding := findings[1]
+		require.Equal(t, "pkg/main.go:6", finding.Location())
+		require.NotNil(t, finding.Suggestion)
+		require.Contains(t, finding.Suggestion.Patch, "-\tg()")
+		require.Contains(t, finding.Suggestion.Patch, "+\tif err := g(); err != nil {")
+	})
+}
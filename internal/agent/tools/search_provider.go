@@ -0,0 +1,303 @@
+package tools
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/config"
+)
+
+const (
+	braveSearchURL  = "https://api.search.brave.com/res/v1/web/search"
+	tavilySearchURL = "https://api.tavily.com/search"
+)
+
+// SearchProvider is a web search backend of the web_search tool.
+type SearchProvider interface {
+	Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error)
+}
+
+// NewSearchProvider returns the search provider configured under
+// tools.web_search, defaulting to DuckDuckGo.
+func NewSearchProvider(cfg config.ToolWebSearch, resolver config.VariableResolver, client *http.Client) (SearchProvider, error) {
+	apiKey, err := resolver.ResolveValue(cfg.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve web search API key: %w", err)
+	}
+	baseURL, err := resolver.ResolveValue(cfg.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve web search base URL: %w", err)
+	}
+	headers := make(http.Header, len(cfg.Headers))
+	for k, v := range cfg.Headers {
+		resolved, err := resolver.ResolveValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve web search header %s: %w", k, err)
+		}
+		headers.Set(k, resolved)
+	}
+	api := searchAPI{client: client, headers: headers}
+
+	switch cfg.Provider {
+	case "", config.WebSearchProviderDuckDuckGo:
+		return &duckDuckGoSearch{client: client}, nil
+	case config.WebSearchProviderSearXNG:
+		if baseURL == "" {
+			return nil, fmt.Errorf("web search provider %s requires base_url", cfg.Provider)
+		}
+		return &searXNGSearch{api: api, baseURL: baseURL}, nil
+	case config.WebSearchProviderBrave:
+		if apiKey == "" {
+			return nil, fmt.Errorf("web search provider %s requires api_key", cfg.Provider)
+		}
+		return &braveSearch{api: api, baseURL: cmp.Or(baseURL, braveSearchURL), apiKey: apiKey}, nil
+	case config.WebSearchProviderTavily:
+		if apiKey == "" {
+			return nil, fmt.Errorf("web search provider %s requires api_key", cfg.Provider)
+		}
+		return &tavilySearch{api: api, baseURL: cmp.Or(baseURL, tavilySearchURL), apiKey: apiKey}, nil
+	case config.WebSearchProviderJSON:
+		if baseURL == "" {
+			return nil, fmt.Errorf("web search provider %s requires base_url", cfg.Provider)
+		}
+		return &jsonSearch{api: api, baseURL: baseURL, mapping: cfg.JSON}, nil
+	default:
+		return nil, fmt.Errorf("unknown web search provider: %s", cfg.Provider)
+	}
+}
+
+// duckDuckGoSearch scrapes the results of DuckDuckGo Lite.
+type duckDuckGoSearch struct {
+	client *http.Client
+}
+
+func (s *duckDuckGoSearch) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	maybeDelaySearch()
+	return searchDuckDuckGo(ctx, s.client, query, maxResults)
+}
+
+// searXNGSearch queries the JSON API of a SearXNG instance.
+type searXNGSearch struct {
+	api     searchAPI
+	baseURL string
+}
+
+func (s *searXNGSearch) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	u, err := url.Parse(strings.TrimSuffix(s.baseURL, "/") + "/search")
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	u.RawQuery = url.Values{"q": {query}, "format": {"json"}}.Encode()
+
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := s.api.do(ctx, http.MethodGet, u.String(), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	for _, r := range resp.Results {
+		results = appendSearchResult(results, r.Title, r.URL, r.Content)
+	}
+	return limitSearchResults(results, maxResults), nil
+}
+
+// braveSearch queries the Brave Search API.
+type braveSearch struct {
+	api     searchAPI
+	baseURL string
+	apiKey  string
+}
+
+func (s *braveSearch) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	u, err := url.Parse(s.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	u.RawQuery = url.Values{"q": {query}, "count": {strconv.Itoa(maxResults)}}.Encode()
+
+	var resp struct {
+		Web struct {
+			Results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	headers := http.Header{"X-Subscription-Token": {s.apiKey}}
+	if err := s.api.do(ctx, http.MethodGet, u.String(), headers, nil, &resp); err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	for _, r := range resp.Web.Results {
+		results = appendSearchResult(results, r.Title, r.URL, r.Description)
+	}
+	return limitSearchResults(results, maxResults), nil
+}
+
+// tavilySearch queries the Tavily search API.
+type tavilySearch struct {
+	api     searchAPI
+	baseURL string
+	apiKey  string
+}
+
+func (s *tavilySearch) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	body, err := json.Marshal(map[string]any{
+		"query":       query,
+		"max_results": maxResults,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	var resp struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	headers := http.Header{
+		"Authorization": {"Bearer " + s.apiKey},
+		"Content-Type":  {"application/json"},
+	}
+	if err := s.api.do(ctx, http.MethodPost, s.baseURL, headers, body, &resp); err != nil {
+		return nil, err
+	}
+	var results []SearchResult
+	for _, r := range resp.Results {
+		results = appendSearchResult(results, r.Title, r.URL, r.Content)
+	}
+	return limitSearchResults(results, maxResults), nil
+}
+
+// jsonSearch queries a generic JSON search endpoint, mapping its response
+// to search results as configured.
+type jsonSearch struct {
+	api     searchAPI
+	baseURL string
+	mapping config.WebSearchJSON
+}
+
+func (s *jsonSearch) Search(ctx context.Context, query string, maxResults int) ([]SearchResult, error) {
+	u, err := url.Parse(s.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	q := u.Query()
+	q.Set(cmp.Or(s.mapping.QueryParam, "q"), query)
+	if s.mapping.LimitParam != "" {
+		q.Set(s.mapping.LimitParam, strconv.Itoa(maxResults))
+	}
+	u.RawQuery = q.Encode()
+
+	var resp any
+	if err := s.api.do(ctx, http.MethodGet, u.String(), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	resultsPath := cmp.Or(s.mapping.ResultsPath, "results")
+	items, ok := jsonPath(resp, resultsPath).([]any)
+	if !ok {
+		return nil, fmt.Errorf("no results array at %q in the response", resultsPath)
+	}
+	var results []SearchResult
+	for _, item := range items {
+		results = appendSearchResult(
+			results,
+			jsonString(item, cmp.Or(s.mapping.TitleField, "title")),
+			jsonString(item, cmp.Or(s.mapping.URLField, "url")),
+			jsonString(item, cmp.Or(s.mapping.SnippetField, "snippet")),
+		)
+	}
+	return limitSearchResults(results, maxResults), nil
+}
+
+// searchAPI sends requests to JSON search APIs.
+type searchAPI struct {
+	client  *http.Client
+	headers http.Header
+}
+
+func (a searchAPI) do(ctx context.Context, method, target string, headers http.Header, body []byte, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "crush/1.0")
+	for k, vs := range a.headers {
+		req.Header[k] = vs
+	}
+	for k, vs := range headers {
+		req.Header[k] = vs
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute search: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("search failed with status code: %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func appendSearchResult(results []SearchResult, title, link, snippet string) []SearchResult {
+	if link == "" {
+		return results
+	}
+	return append(results, SearchResult{
+		Title:    strings.TrimSpace(title),
+		Link:     link,
+		Snippet:  strings.TrimSpace(snippet),
+		Position: len(results) + 1,
+	})
+}
+
+func limitSearchResults(results []SearchResult, maxResults int) []SearchResult {
+	if maxResults > 0 && len(results) > maxResults {
+		return results[:maxResults]
+	}
+	return results
+}
+
+// jsonPath returns the value at a dot-separated path of keys into a decoded
+// JSON value, or nil if there is none.
+func jsonPath(v any, path string) any {
+	for key := range strings.SplitSeq(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = obj[key]
+	}
+	return v
+}
+
+func jsonString(v any, path string) string {
+	s, _ := jsonPath(v, path).(string)
+	return s
+}
@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/env"
+	"github.com/stretchr/testify/require"
+)
+
+var wantSearchResults = []SearchResult{
+	{
+		Title:    "Tutorial: Getting started with generics",
+		Link:     "https://go.dev/doc/tutorial/generics",
+		Snippet:  "This tutorial introduces the basics of generics in Go.",
+		Position: 1,
+	},
+	{
+		Title:    "An Introduction To Generics",
+		Link:     "https://go.dev/blog/intro-generics",
+		Snippet:  "The Go 1.18 release adds support for generics.",
+		Position: 2,
+	},
+}
+
+// searchStub serves a search fixture from testdata/search and records the
+// requests it gets.
+type searchStub struct {
+	URL     string
+	request *http.Request
+	body    []byte
+}
+
+func newSearchStub(t *testing.T, fixture string) *searchStub {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "search", fixture))
+	require.NoError(t, err)
+
+	stub := &searchStub{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.request = r
+		stub.body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	stub.URL = srv.URL
+	return stub
+}
+
+func TestSearchProviders(t *testing.T) {
+	t.Parallel()
+
+	resolver := config.NewShellVariableResolver(env.NewFromMap(map[string]string{
+		"SEARCH_KEY": "secret",
+	}))
+
+	tests := []struct {
+		name    string
+		fixture string
+		cfg     func(url string) config.ToolWebSearch
+		check   func(t *testing.T, r *http.Request, body []byte)
+	}{
+		{
+			name:    "searxng",
+			fixture: "searxng.json",
+			cfg: func(url string) config.ToolWebSearch {
+				return config.ToolWebSearch{Provider: config.WebSearchProviderSearXNG, BaseURL: url + "/"}
+			},
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				require.Equal(t, "/search", r.URL.Path)
+				require.Equal(t, "golang generics", r.URL.Query().Get("q"))
+				require.Equal(t, "json", r.URL.Query().Get("format"))
+			},
+		},
+		{
+			name:    "brave",
+			fixture: "brave.json",
+			cfg: func(url string) config.ToolWebSearch {
+				return config.ToolWebSearch{Provider: config.WebSearchProviderBrave, BaseURL: url, APIKey: "$SEARCH_KEY"}
+			},
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				require.Equal(t, "secret", r.Header.Get("X-Subscription-Token"))
+				require.Equal(t, "golang generics", r.URL.Query().Get("q"))
+				require.Equal(t, "5", r.URL.Query().Get("count"))
+			},
+		},
+		{
+			name:    "tavily",
+			fixture: "tavily.json",
+			cfg: func(url string) config.ToolWebSearch {
+				return config.ToolWebSearch{Provider: config.WebSearchProviderTavily, BaseURL: url, APIKey: "$SEARCH_KEY"}
+			},
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				var req struct {
+					Query      string `json:"query"`
+					MaxResults int    `json:"max_results"`
+				}
+				require.NoError(t, json.Unmarshal(body, &req))
+				require.Equal(t, "golang generics", req.Query)
+				require.Equal(t, 5, req.MaxResults)
+			},
+		},
+		{
+			name:    "json",
+			fixture: "json.json",
+			cfg: func(url string) config.ToolWebSearch {
+				return config.ToolWebSearch{
+					Provider: config.WebSearchProviderJSON,
+					BaseURL:  url + "/api?lang=en",
+					Headers:  map[string]string{"Authorization": "Token $SEARCH_KEY"},
+					JSON: config.WebSearchJSON{
+						QueryParam:   "query",
+						LimitParam:   "limit",
+						ResultsPath:  "data.items",
+						TitleField:   "name",
+						URLField:     "link.href",
+						SnippetField: "summary",
+					},
+				}
+			},
+			check: func(t *testing.T, r *http.Request, body []byte) {
+				require.Equal(t, "Token secret", r.Header.Get("Authorization"))
+				require.Equal(t, "golang generics", r.URL.Query().Get("query"))
+				require.Equal(t, "5", r.URL.Query().Get("limit"))
+				require.Equal(t, "en", r.URL.Query().Get("lang"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			stub := newSearchStub(t, tt.fixture)
+			provider, err := NewSearchProvider(tt.cfg(stub.URL), resolver, http.DefaultClient)
+			require.NoError(t, err)
+
+			results, err := provider.Search(t.Context(), "golang generics", 5)
+			require.NoError(t, err)
+			require.Equal(t, wantSearchResults, results)
+			tt.check(t, stub.request, stub.body)
+
+			results, err = provider.Search(t.Context(), "golang generics", 1)
+			require.NoError(t, err)
+			require.Equal(t, wantSearchResults[:1], results)
+		})
+	}
+}
+
+func TestNewSearchProvider(t *testing.T) {
+	t.Parallel()
+
+	resolver := config.NewShellVariableResolver(env.NewFromMap(nil))
+
+	provider, err := NewSearchProvider(config.ToolWebSearch{}, resolver, http.DefaultClient)
+	require.NoError(t, err)
+	require.IsType(t, &duckDuckGoSearch{}, provider)
+
+	for cfg, wantErr := range map[*config.ToolWebSearch]string{
+		{Provider: config.WebSearchProviderSearXNG}: "requires base_url",
+		{Provider: config.WebSearchProviderJSON}:    "requires base_url",
+		{Provider: config.WebSearchProviderBrave}:   "requires api_key",
+		{Provider: config.WebSearchProviderTavily}:  "requires api_key",
+		{Provider: "bing"}:                          "unknown web search provider",
+	} {
+		_, err := NewSearchProvider(*cfg, resolver, http.DefaultClient)
+		require.ErrorContains(t, err, wantErr, cfg.Provider)
+	}
+}
+
+func TestParseLiteSearchResults(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "search", "duckduckgo.html"))
+	require.NoError(t, err)
+
+	results, err := parseLiteSearchResults(string(data), 10)
+	require.NoError(t, err)
+	require.Equal(t, wantSearchResults, results)
+}
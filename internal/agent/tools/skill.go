@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/home"
+	"github.com/charmbracelet/crush/internal/skills"
+)
+
+const SkillToolName = "skill"
+
+//go:embed skill.md
+var skillDescription []byte
+
+type SkillParams struct {
+	Name string `json:"name" description:"The name of the skill to activate"`
+}
+
+type SkillResponseMetadata struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Path         string   `json:"path"`
+	Resources    []string `json:"resources,omitempty"`
+	AllowedTools []string `json:"allowed_tools,omitempty"`
+}
+
+// skillToolAliases maps the tool names commonly used in the allowed-tools
+// of skills to the tools of Crush.
+var skillToolAliases = map[string]string{
+	"read":     ViewToolName,
+	"webfetch": FetchToolName,
+}
+
+// activeSkillTools holds the tools allowed by the skill active in each
+// session.
+var activeSkillTools = csync.NewMap[string, []string]()
+
+// ActiveSkillTools returns the tools the active skill of a session restricts
+// the agent to, if that skill lists allowed-tools.
+func ActiveSkillTools(sessionID string) ([]string, bool) {
+	return activeSkillTools.Get(sessionID)
+}
+
+// DeactivateSkill lifts the tool restrictions of the active skill of a
+// session.
+func DeactivateSkill(sessionID string) {
+	activeSkillTools.Del(sessionID)
+}
+
+func NewSkillTool(workingDir string, skillsPaths ...string) fantasy.AgentTool {
+	return fantasy.NewAgentTool(
+		SkillToolName,
+		string(skillDescription),
+		func(ctx context.Context, params SkillParams, call fantasy.ToolCall) (fantasy.ToolResponse, error) {
+			if params.Name == "" {
+				return fantasy.NewTextErrorResponse("missing name"), nil
+			}
+
+			paths := make([]string, 0, len(skillsPaths))
+			for _, p := range skillsPaths {
+				p = home.Long(p)
+				if !filepath.IsAbs(p) {
+					p = filepath.Join(workingDir, p)
+				}
+				paths = append(paths, p)
+			}
+			skill := skills.Find(paths, params.Name)
+			if skill == nil {
+				return fantasy.NewTextErrorResponse(fmt.Sprintf("skill not found: %s", params.Name)), nil
+			}
+
+			resources, err := skill.Resources()
+			if err != nil {
+				return fantasy.ToolResponse{}, fmt.Errorf("failed to list skill files: %w", err)
+			}
+
+			metadata := SkillResponseMetadata{
+				Name:        skill.Name,
+				Description: skill.Description,
+				Path:        skill.Path,
+			}
+
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "<skill name=%q path=%q>\n%s\n</skill>\n", skill.Name, filepath.ToSlash(skill.Path), skill.Instructions)
+
+			if len(resources) > 0 {
+				sb.WriteString("\n<skill_files>\n")
+				for _, r := range resources {
+					metadata.Resources = append(metadata.Resources, r.Path)
+					if r.Script {
+						fmt.Fprintf(&sb, "%s (script)\n", r.Path)
+					} else {
+						fmt.Fprintf(&sb, "%s\n", r.Path)
+					}
+				}
+				sb.WriteString("</skill_files>\n")
+				sb.WriteString("\nThe files above are relative to the skill path. Read them with the view tool and run scripts with the bash tool when the instructions call for it.\n")
+			}
+
+			sessionID := GetSessionFromContext(ctx)
+			if allowed := skill.Tools(); len(allowed) > 0 {
+				metadata.AllowedTools = skillAllowedTools(allowed)
+				if sessionID != "" {
+					activeSkillTools.Set(sessionID, metadata.AllowedTools)
+				}
+				fmt.Fprintf(&sb, "\nThis skill restricts the tools you can use to: %s.\n", strings.Join(metadata.AllowedTools, ", "))
+			} else if sessionID != "" {
+				DeactivateSkill(sessionID)
+			}
+
+			return fantasy.WithResponseMetadata(fantasy.NewTextResponse(sb.String()), metadata), nil
+		})
+}
+
+// skillAllowedTools converts the allowed-tools of a skill to the names of
+// the tools of Crush.
+func skillAllowedTools(names []string) []string {
+	tools := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if alias, ok := skillToolAliases[name]; ok {
+			name = alias
+		}
+		if !slices.Contains(tools, name) {
+			tools = append(tools, name)
+		}
+	}
+	return tools
+}
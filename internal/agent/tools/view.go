@@ -25,8 +25,8 @@ var viewDescription []byte
 
 type ViewParams struct {
 	FilePath string `json:"file_path" description:"The path to the file to read"`
-	Offset   int    `json:"offset,omitempty" description:"The line number to start reading from (0-based)"`
-	Limit    int    `json:"limit,omitempty" description:"The number of lines to read (defaults to 2000)"`
+	Offset   int    `json:"offset,omitempty" description:"The line number to start reading from (0-based). For PDFs the page and for notebooks the cell to start from"`
+	Limit    int    `json:"limit,omitempty" description:"The number of lines to read (defaults to 2000). For PDFs the number of pages (defaults to 20) and for notebooks of cells (defaults to 100)"`
 }
 
 type ViewPermissionsParams struct {
@@ -143,10 +143,35 @@ func NewViewTool(
 				return fantasy.NewTextErrorResponse(fmt.Sprintf("Path is a directory, not a file: %s", filePath)), nil
 			}
 
+			isDocument, docKind := getDocumentKind(filePath)
+			maxSize := int64(MaxReadSize)
+			if isDocument {
+				maxSize = MaxDocumentSize
+			}
+
 			// Based on the specifications we should not limit the skills read.
-			if !isSkillFile && fileInfo.Size() > MaxReadSize {
+			if !isSkillFile && fileInfo.Size() > maxSize {
 				return fantasy.NewTextErrorResponse(fmt.Sprintf("File is too large (%d bytes). Maximum size is %d bytes",
-					fileInfo.Size(), MaxReadSize)), nil
+					fileInfo.Size(), maxSize)), nil
+			}
+
+			// Documents are read in pages, cells or lines with their own
+			// default limits.
+			if isDocument {
+				output, content, err := readDocument(filePath, docKind, params.Offset, params.Limit)
+				if err != nil {
+					return fantasy.NewTextErrorResponse(fmt.Sprintf("Error reading %s: %s", filePath, err)), nil
+				}
+				output = "<file>\n" + output + "\n</file>\n"
+				output += getNestedContext(ctx, workingDir, filePath)
+				filetracker.RecordRead(ctx, sessionID, filePath)
+				return fantasy.WithResponseMetadata(
+					fantasy.NewTextResponse(output),
+					ViewResponseMetadata{
+						FilePath: filePath,
+						Content:  content,
+					},
+				), nil
 			}
 
 			// Set default limit if not provided (no limit for SKILL.md files)
@@ -196,6 +221,7 @@ func NewViewTool(
 			}
 			output += "\n</file>\n"
 			output += getDiagnostics(filePath, lspClients)
+			output += getNestedContext(ctx, workingDir, filePath)
 			filetracker.RecordRead(ctx, sessionID, filePath)
 			return fantasy.WithResponseMetadata(
 				fantasy.NewTextResponse(output),
@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/extract"
+)
+
+const (
+	// MaxDocumentSize is the maximum size of the PDF, DOCX and notebook files
+	// the view tool reads. It's larger than MaxReadSize as much of these files
+	// is markup, compressed data and embedded images.
+	MaxDocumentSize = 50 * 1024 * 1024 // 50MB
+	// DefaultPageLimit is the number of PDF pages read by default.
+	DefaultPageLimit = 20
+	// DefaultCellLimit is the number of notebook cells read by default.
+	DefaultCellLimit = 100
+)
+
+// documentKind is a kind of file the view tool extracts text from.
+type documentKind string
+
+const (
+	documentPDF      documentKind = "pdf"
+	documentDOCX     documentKind = "docx"
+	documentNotebook documentKind = "notebook"
+)
+
+// getDocumentKind returns the kind of document a file is, if it's one the
+// view tool extracts text from.
+func getDocumentKind(filePath string) (bool, documentKind) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".pdf":
+		return true, documentPDF
+	case ".docx":
+		return true, documentDOCX
+	case ".ipynb":
+		return true, documentNotebook
+	default:
+		return false, ""
+	}
+}
+
+// readDocument extracts the text of a document. For PDFs, offset and limit
+// are in pages, for notebooks in cells and for DOCX files in lines. A limit
+// of 0 reads the default amount. It returns the tool output and the
+// extracted content.
+func readDocument(filePath string, kind documentKind, offset, limit int) (string, string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", "", err
+	}
+	switch kind {
+	case documentPDF:
+		pages, err := extract.PDFPages(data)
+		if err != nil {
+			return "", "", err
+		}
+		return renderParts(pages, "page", offset, orDefault(limit, DefaultPageLimit), func(i int, text string) string {
+			if strings.TrimSpace(text) == "" {
+				text = "(no text found on this page, it may be a scanned image)"
+			}
+			return fmt.Sprintf("<page number=\"%d\">\n%s\n</page>", i+1, text)
+		})
+	case documentNotebook:
+		nb, err := extract.ParseNotebook(data)
+		if err != nil {
+			return "", "", err
+		}
+		cells := make([]string, len(nb.Cells))
+		for i := range nb.Cells {
+			cells[i] = nb.RenderCell(i)
+		}
+		return renderParts(cells, "cell", offset, orDefault(limit, DefaultCellLimit), func(_ int, cell string) string {
+			return cell
+		})
+	default:
+		text, err := extract.DOCXText(data)
+		if err != nil {
+			return "", "", err
+		}
+		lines := strings.Split(text, "\n")
+		if offset >= len(lines) {
+			return "", "", fmt.Errorf("offset %d is beyond the end of the document (%d lines)", offset, len(lines))
+		}
+		end := min(offset+orDefault(limit, DefaultReadLimit), len(lines))
+		content := strings.Join(lines[offset:end], "\n")
+		output := addLineNumbers(content, offset+1)
+		if end < len(lines) {
+			output += fmt.Sprintf("\n\n(Document has %d lines. Use 'offset' parameter to read beyond line %d)", len(lines), end)
+		}
+		return output, content, nil
+	}
+}
+
+// renderParts renders the pages or cells of a document in [offset,
+// offset+limit).
+func renderParts(parts []string, unit string, offset, limit int, render func(int, string) string) (string, string, error) {
+	if offset >= len(parts) {
+		return "", "", fmt.Errorf("offset %d is beyond the end of the document (%d %ss)", offset, len(parts), unit)
+	}
+	end := min(offset+limit, len(parts))
+	rendered := make([]string, 0, end-offset)
+	for i := offset; i < end; i++ {
+		rendered = append(rendered, render(i, parts[i]))
+	}
+	content := strings.Join(rendered, "\n\n")
+	output := content
+	if end < len(parts) {
+		output += fmt.Sprintf("\n\n(Document has %d %ss. Use 'offset' parameter to read beyond %s %d)", len(parts), unit, unit, end)
+	}
+	return output, content, nil
+}
+
+func orDefault(limit, def int) int {
+	if limit <= 0 {
+		return def
+	}
+	return limit
+}
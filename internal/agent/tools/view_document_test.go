@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDocument(t *testing.T) {
+	t.Parallel()
+
+	t.Run("notebook cells", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join("testdata", "view", "analysis.ipynb")
+		ok, kind := getDocumentKind(path)
+		require.True(t, ok)
+		require.Equal(t, documentNotebook, kind)
+
+		output, content, err := readDocument(path, kind, 1, 1)
+		require.NoError(t, err)
+		require.Equal(t, `<cell index="1" type="code" id="load" language="python" execution_count="1">
+rows = [1, 2, 3]
+print(len(rows), "rows")
+<output type="stream" name="stdout">
+3 rows
+</output>
+</cell>`, content)
+		require.Equal(t, content+"\n\n(Document has 3 cells. Use 'offset' parameter to read beyond cell 2)", output)
+
+		output, _, err = readDocument(path, kind, 2, 0)
+		require.NoError(t, err)
+		require.Contains(t, output, `<cell index="2" type="code" id="sum"`)
+		require.NotContains(t, output, "Use 'offset'")
+
+		_, _, err = readDocument(path, kind, 3, 0)
+		require.ErrorContains(t, err, "beyond the end of the document (3 cells)")
+	})
+
+	t.Run("invalid pdf", func(t *testing.T) {
+		t.Parallel()
+		path := filepath.Join(t.TempDir(), "broken.PDF")
+		require.NoError(t, os.WriteFile(path, []byte("not a pdf"), 0o644))
+		ok, kind := getDocumentKind(path)
+		require.True(t, ok)
+		_, _, err := readDocument(path, kind, 0, 0)
+		require.ErrorContains(t, err, "not a PDF file")
+	})
+}
@@ -10,13 +10,14 @@ import (
 	"time"
 
 	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/config"
 )
 
 //go:embed web_fetch.md
 var webFetchToolDescription []byte
 
 // NewWebFetchTool creates a simple web fetch tool for sub-agents (no permissions needed).
-func NewWebFetchTool(workingDir string, client *http.Client) fantasy.AgentTool {
+func NewWebFetchTool(workingDir string, client *http.Client, fetchCfg config.ToolFetch) fantasy.AgentTool {
 	if client == nil {
 		client = &http.Client{
 			Timeout: 30 * time.Second,
@@ -36,7 +37,11 @@ func NewWebFetchTool(workingDir string, client *http.Client) fantasy.AgentTool {
 				return fantasy.NewTextErrorResponse("url is required"), nil
 			}
 
-			content, err := FetchURLAndConvert(ctx, client, params.URL)
+			if _, denied := FetchDomainPolicy(fetchCfg, params.URL); denied {
+				return fantasy.NewTextErrorResponse(DeniedDomainError(params.URL)), nil
+			}
+
+			content, err := FetchURLAndConvert(ctx, client, params.URL, fetchCfg.MaxSize())
 			if err != nil {
 				return fantasy.NewTextErrorResponse(fmt.Sprintf("Failed to fetch URL: %s", err)), nil
 			}
@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/fantasy"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/shell"
+)
+
+// maxVerifyOutputLength bounds the output of each verify command kept in the
+// chat and sent back to the agent.
+const maxVerifyOutputLength = 10000
+
+// verify runs the verify commands of the project matching the files changed
+// in the session since start. Their failures are sent back to the agent to
+// fix, until they pass or the maximum number of repairs is reached. It
+// returns the result of the last run of the agent.
+func (c *coordinator) verify(ctx context.Context, sessionID string, start time.Time, opts RunOptions, result *fantasy.AgentResult) (*fantasy.AgentResult, error) {
+	verifyCfg := c.cfg.Verify
+	if len(verifyCfg.Commands) == 0 {
+		return result, nil
+	}
+
+	ctx, done := c.track(ctx, sessionID)
+	defer done()
+
+	for attempt := 1; ; attempt++ {
+		files, err := c.changedFiles(ctx, sessionID, start)
+		if err != nil {
+			return result, err
+		}
+		commands := verifyCfg.CommandsFor(files)
+		if len(commands) == 0 {
+			return result, nil
+		}
+		start = time.Now()
+
+		msg, verification, err := c.runVerifyCommands(ctx, sessionID, attempt, commands)
+		if err != nil {
+			return result, err
+		}
+		if verification.Passed() || attempt > verifyCfg.MaxRepairsOrDefault() {
+			return result, nil
+		}
+
+		// The verification moves to the message asking the agent to fix its
+		// failures.
+		if err := c.messages.Delete(ctx, msg.ID); err != nil {
+			return result, err
+		}
+		verification.Repair = true
+		slog.Debug("Verification failed, sending failures back to the agent", "session_id", sessionID, "attempt", attempt)
+		result, err = c.runWithOptions(ctx, sessionID, verifyRepairPrompt(verification), opts, &verification)
+		if err != nil || result == nil {
+			// Without result nor error the prompt was queued behind another
+			// run, which verifies its own changes.
+			return result, err
+		}
+	}
+}
+
+// runVerifyCommands runs the verify commands one after the other, showing
+// their progress in a message of the session.
+func (c *coordinator) runVerifyCommands(ctx context.Context, sessionID string, attempt int, commands []config.VerifyCommand) (message.Message, message.Verification, error) {
+	verification := message.Verification{Attempt: attempt}
+	for _, command := range commands {
+		verification.Results = append(verification.Results, message.VerifyResult{
+			Name:    command.DisplayName(),
+			Command: command.Command,
+		})
+	}
+	msg, err := c.messages.Create(ctx, sessionID, message.CreateMessageParams{
+		Role:  message.User,
+		Parts: []message.ContentPart{verification},
+	})
+	if err != nil {
+		return msg, verification, fmt.Errorf("failed to create verification message: %w", err)
+	}
+
+	for i, command := range commands {
+		verification.Results[i] = c.runVerifyCommand(ctx, command)
+		if ctx.Err() != nil {
+			break
+		}
+		msg.Parts = []message.ContentPart{verification}
+		if err := c.messages.Update(ctx, msg); err != nil {
+			return msg, verification, err
+		}
+	}
+	verification.Finished = true
+	msg.Parts = []message.ContentPart{verification}
+	// Use a fresh context, so the message is finished even when canceled.
+	if err := c.messages.Update(context.WithoutCancel(ctx), msg); err != nil {
+		return msg, verification, err
+	}
+	return msg, verification, ctx.Err()
+}
+
+func (c *coordinator) runVerifyCommand(ctx context.Context, command config.VerifyCommand) message.VerifyResult {
+	ctx, cancel := context.WithTimeout(ctx, command.TimeoutOrDefault())
+	defer cancel()
+
+	start := time.Now()
+	var output bytes.Buffer
+	sh := shell.NewShell(&shell.Options{WorkingDir: c.cfg.WorkingDir()})
+	err := sh.ExecStream(ctx, command.Command, &output, &output)
+	result := message.VerifyResult{
+		Name:     command.DisplayName(),
+		Command:  command.Command,
+		ExitCode: shell.ExitCode(err),
+		Output:   truncateVerifyOutput(strings.TrimSpace(output.String())),
+		TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Duration: time.Since(start).Milliseconds(),
+		Finished: true,
+	}
+	if err != nil && result.Output == "" && !shell.IsInterrupt(err) {
+		result.Output = err.Error()
+	}
+	return result
+}
+
+// changedFiles returns the files the agent changed in the session since
+// start, relative to the working directory.
+func (c *coordinator) changedFiles(ctx context.Context, sessionID string, start time.Time) ([]string, error) {
+	versions, err := c.history.ListBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	var files []string
+	for _, version := range versions {
+		if version.CreatedAt < start.Unix() {
+			continue
+		}
+		rel, err := filepath.Rel(c.cfg.WorkingDir(), version.Path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// verifyRepairPrompt asks the agent to fix the failures of a verification.
+func verifyRepairPrompt(verification message.Verification) string {
+	var b strings.Builder
+	b.WriteString("The verification of your changes failed. Fix the failures below, then stop: the verification runs again at the end of your turn.\n")
+	for _, result := range verification.Results {
+		if result.Passed() {
+			continue
+		}
+		fmt.Fprintf(&b, "\n<verification name=%q command=%q", result.Name, result.Command)
+		if result.TimedOut {
+			b.WriteString(" timed_out=\"true\"")
+		} else {
+			fmt.Fprintf(&b, " exit_code=\"%d\"", result.ExitCode)
+		}
+		fmt.Fprintf(&b, ">\n%s\n</verification>\n", result.Output)
+	}
+	return b.String()
+}
+
+// truncateVerifyOutput keeps the beginning and the end of long outputs.
+func truncateVerifyOutput(output string) string {
+	if len(output) <= maxVerifyOutputLength {
+		return output
+	}
+	half := maxVerifyOutputLength / 2
+	omitted := strings.Count(output[half:len(output)-half], "\n")
+	return fmt.Sprintf("%s\n\n... [%d lines truncated] ...\n\n%s", output[:half], omitted, output[len(output)-half:])
+}
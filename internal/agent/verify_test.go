@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	env := testEnv(t)
+	cfg, err := config.Init(env.workingDir, "", false)
+	require.NoError(t, err)
+	maxRepairs := 0
+	cfg.Verify = config.Verify{
+		Commands: []config.VerifyCommand{
+			{Name: "build", Command: "echo fine"},
+			{Command: "echo broken && exit 2", Files: []string{"*.go"}},
+			{Name: "docs", Command: "exit 1", Files: []string{"docs/**/*.md"}},
+		},
+		MaxRepairs: &maxRepairs,
+	}
+	c := &coordinator{
+		cfg:      cfg,
+		sessions: env.sessions,
+		messages: env.messages,
+		history:  env.history,
+		tasks:    csync.NewMap[string, context.CancelFunc](),
+	}
+
+	sess, err := env.sessions.Create(t.Context(), "Verify")
+	require.NoError(t, err)
+	start := time.Now()
+	_, err = env.history.Create(t.Context(), sess.ID, filepath.Join(env.workingDir, "pkg", "main.go"), "package main\n")
+	require.NoError(t, err)
+
+	result := &fantasy.AgentResult{}
+	got, err := c.verify(t.Context(), sess.ID, start, RunOptions{}, result)
+	require.NoError(t, err)
+	require.Same(t, result, got)
+	_, verifying := c.tasks.Get(sess.ID)
+	require.False(t, verifying)
+
+	msgs, err := env.messages.List(t.Context(), sess.ID)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, message.User, msgs[0].Role)
+	verification := msgs[0].Verification()
+	require.NotNil(t, verification)
+	require.True(t, verification.Finished)
+	require.False(t, verification.Repair)
+	require.Equal(t, 1, verification.Attempt)
+	require.False(t, verification.Passed())
+
+	require.Len(t, verification.Results, 2)
+	require.Equal(t, "build", verification.Results[0].Name)
+	require.True(t, verification.Results[0].Passed())
+	require.Equal(t, "fine", verification.Results[0].Output)
+	require.Equal(t, "echo broken && exit 2", verification.Results[1].Name)
+	require.Equal(t, 2, verification.Results[1].ExitCode)
+	require.Equal(t, "broken", verification.Results[1].Output)
+
+	// The verification is only shown to the user.
+	require.Empty(t, msgs[0].ToAIMessage())
+
+	t.Run("no changes", func(t *testing.T) {
+		_, err := c.verify(t.Context(), sess.ID, time.Now().Add(time.Second), RunOptions{}, result)
+		require.NoError(t, err)
+		msgs, err := env.messages.List(t.Context(), sess.ID)
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+	})
+}
+
+func TestVerifyRepairPrompt(t *testing.T) {
+	t.Parallel()
+
+	prompt := verifyRepairPrompt(message.Verification{
+		Results: []message.VerifyResult{
+			{Name: "lint", Command: "golangci-lint run", Finished: true},
+			{Name: "tests", Command: "go test ./...", ExitCode: 1, Output: "FAIL: TestMain", Finished: true},
+			{Name: "slow", Command: "make check", Output: "waiting", TimedOut: true, Finished: true},
+		},
+	})
+	require.NotContains(t, prompt, "lint")
+	require.Contains(t, prompt, "<verification name=\"tests\" command=\"go test ./...\" exit_code=\"1\">\nFAIL: TestMain\n</verification>")
+	require.Contains(t, prompt, "<verification name=\"slow\" command=\"make check\" timed_out=\"true\">\nwaiting\n</verification>")
+}
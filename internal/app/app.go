@@ -19,6 +19,7 @@ import (
 	"charm.land/fantasy"
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/crush/internal/agent"
+	"github.com/charmbracelet/crush/internal/agent/tools"
 	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/csync"
@@ -63,8 +64,8 @@ type App struct {
 	config *config.Config
 
 	serviceEventsWG *sync.WaitGroup
-	eventsCtx       context.Context
 	events          chan tea.Msg
+	eventStats      map[string]func() pubsub.Stats
 	tuiWG           *sync.WaitGroup
 
 	// global context and cleanup functions
@@ -101,14 +102,24 @@ func New(ctx context.Context, conn *sql.DB, cfg *config.Config) (*App, error) {
 		tuiWG:           &sync.WaitGroup{},
 	}
 
-	app.setupEvents()
-
 	// Initialize LSP clients in the background.
 	go app.initLSPClients(ctx)
 
 	// Check for updates in the background.
 	go app.checkForUpdates(ctx)
 
+	// Terminate background jobs of sessions as they are deleted.
+	go app.cleanupSessions(ctx)
+
+	// Watch the workspace for changes made outside of the edit tools, such as
+	// by commands, git or the user's editor.
+	if watcher, err := NewFileWatcher(cfg.WorkingDir(), app.LSPClients, app.FileTracker); err != nil {
+		slog.Warn("Failed to create file watcher", "error", err)
+	} else {
+		go watcher.Start(ctx)
+		app.cleanupFuncs = append(app.cleanupFuncs, watcher.Close)
+	}
+
 	go func() {
 		slog.Info("Initializing MCP clients")
 		mcp.Initialize(ctx, app.Permissions, cfg)
@@ -134,8 +145,9 @@ func (app *App) Config() *config.Config {
 }
 
 // RunNonInteractive runs the application in non-interactive mode with the
-// given prompt, printing to stdout.
-func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt, largeModel, smallModel string, hideSpinner bool) error {
+// given prompt, printing to stdout. opts overrides the model and tools of the
+// agent, as custom commands do.
+func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt string, opts agent.RunOptions, largeModel, smallModel string, hideSpinner bool) error {
 	slog.Info("Running in non-interactive mode")
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -224,6 +236,15 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt,
 	// session.
 	app.Permissions.AutoApproveSession(sess.ID)
 
+	// Nobody can fill in the forms requested by MCP servers.
+	go func() {
+		for event := range mcp.SubscribeElicitations(ctx) {
+			if event.Type == pubsub.CreatedEvent {
+				mcp.RespondElicitation(event.Payload.ID, mcp.ElicitationCancel, nil)
+			}
+		}
+	}()
+
 	type response struct {
 		result *fantasy.AgentResult
 		err    error
@@ -231,7 +252,7 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt,
 	done := make(chan response, 1)
 
 	go func(ctx context.Context, sessionID, prompt string) {
-		result, err := app.AgentCoordinator.Run(ctx, sess.ID, prompt)
+		result, err := app.AgentCoordinator.RunWithOptions(ctx, sess.ID, prompt, opts)
 		if err != nil {
 			done <- response{
 				err: fmt.Errorf("failed to start agent processing stream: %w", err),
@@ -242,7 +263,7 @@ func (app *App) RunNonInteractive(ctx context.Context, output io.Writer, prompt,
 		}
 	}(ctx, sess.ID, prompt)
 
-	messageEvents := app.Messages.Subscribe(ctx)
+	messageEvents := app.Messages.Subscribe(ctx, pubsub.WithPolicy(pubsub.Coalesce))
 	messageReadBytes := make(map[string]int)
 	var printed bool
 
@@ -406,33 +427,90 @@ func (app *App) GetDefaultSmallModel(providerID string) config.SelectedModel {
 	}
 }
 
+// setupEvents forwards the events of the services to the TUI. It's only
+// done once the TUI drains them: blocking subscriptions would otherwise hold
+// up their publishers in headless modes.
 func (app *App) setupEvents() {
 	ctx, cancel := context.WithCancel(app.globalCtx)
-	app.eventsCtx = ctx
-	setupSubscriber(ctx, app.serviceEventsWG, "sessions", app.Sessions.Subscribe, app.events)
-	setupSubscriber(ctx, app.serviceEventsWG, "messages", app.Messages.Subscribe, app.events)
-	setupSubscriber(ctx, app.serviceEventsWG, "permissions", app.Permissions.Subscribe, app.events)
-	setupSubscriber(ctx, app.serviceEventsWG, "permissions-notifications", app.Permissions.SubscribeNotifications, app.events)
-	setupSubscriber(ctx, app.serviceEventsWG, "history", app.History.Subscribe, app.events)
-	setupSubscriber(ctx, app.serviceEventsWG, "mcp", mcp.SubscribeEvents, app.events)
-	setupSubscriber(ctx, app.serviceEventsWG, "lsp", SubscribeLSPEvents, app.events)
+	app.eventStats = map[string]func() pubsub.Stats{
+		"sessions":         brokerStats(app.Sessions),
+		"messages":         brokerStats(app.Messages),
+		"permissions":      brokerStats(app.Permissions),
+		"history":          brokerStats(app.History),
+		"mcp":              mcp.EventStats,
+		"mcp-elicitations": mcp.ElicitationStats,
+		"lsp":              LSPEventStats,
+		"jobs":             shell.BackgroundEventStats,
+		"agent-runs":       agent.RunEventStats,
+	}
+	// Coalescing and blocking subscriptions never lose events, so the UI
+	// always gets the final state of sessions, messages and requests that
+	// wait on it. Status events only need the latest ones.
+	setupSubscriber(ctx, app.serviceEventsWG, "sessions", app.Sessions.Subscribe, pubsub.Coalesce, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "messages", app.Messages.Subscribe, pubsub.Coalesce, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "permissions", app.Permissions.Subscribe, pubsub.Block, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "permissions-notifications", app.Permissions.SubscribeNotifications, pubsub.Coalesce, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "history", app.History.Subscribe, pubsub.Coalesce, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "mcp", mcp.SubscribeEvents, pubsub.DropOldest, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "mcp-elicitations", mcp.SubscribeElicitations, pubsub.Block, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "lsp", SubscribeLSPEvents, pubsub.DropOldest, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "jobs", shell.SubscribeBackgroundEvents, pubsub.Coalesce, app.events)
+	setupSubscriber(ctx, app.serviceEventsWG, "agent-runs", agent.SubscribeRunEvents, pubsub.Block, app.events)
 	cleanupFunc := func() error {
 		cancel()
 		app.serviceEventsWG.Wait()
+		app.logEventStats()
 		return nil
 	}
 	app.cleanupFuncs = append(app.cleanupFuncs, cleanupFunc)
 }
 
+// brokerStats returns the event counters of a service publishing through a
+// broker, if it does.
+func brokerStats(service any) func() pubsub.Stats {
+	if s, ok := service.(interface{ Stats() pubsub.Stats }); ok {
+		return s.Stats
+	}
+	return nil
+}
+
+// EventStats returns the event counters of the brokers forwarded to the TUI,
+// by subscription name.
+func (app *App) EventStats() map[string]pubsub.Stats {
+	stats := make(map[string]pubsub.Stats, len(app.eventStats))
+	for name, fn := range app.eventStats {
+		if fn != nil {
+			stats[name] = fn()
+		}
+	}
+	return stats
+}
+
+func (app *App) logEventStats() {
+	for name, stats := range app.EventStats() {
+		slog.Debug("Event stats",
+			"name", name,
+			"subscribers", stats.Subscribers,
+			"published", stats.Published,
+			"dropped", stats.Dropped,
+			"coalesced", stats.Coalesced,
+		)
+	}
+}
+
+// setupSubscriber forwards the events of a subscription to the TUI. It waits
+// for the TUI to take each event: what a slow TUI misses is decided by the
+// policy of the subscription, never here.
 func setupSubscriber[T any](
 	ctx context.Context,
 	wg *sync.WaitGroup,
 	name string,
-	subscriber func(context.Context) <-chan pubsub.Event[T],
+	subscriber func(context.Context, ...pubsub.SubscribeOption) <-chan pubsub.Event[T],
+	policy pubsub.Policy,
 	outputCh chan<- tea.Msg,
 ) {
 	wg.Go(func() {
-		subCh := subscriber(ctx)
+		subCh := subscriber(ctx, pubsub.WithPolicy(policy))
 		for {
 			select {
 			case event, ok := <-subCh:
@@ -443,8 +521,6 @@ func setupSubscriber[T any](
 				var msg tea.Msg = event
 				select {
 				case outputCh <- msg:
-				case <-time.After(2 * time.Second):
-					slog.Debug("Message dropped due to slow consumer", "name", name)
 				case <-ctx.Done():
 					slog.Debug("Subscription cancelled", "name", name)
 					return
@@ -497,6 +573,8 @@ func (app *App) Subscribe(program *tea.Program) {
 	})
 	defer app.tuiWG.Done()
 
+	app.setupEvents()
+
 	for {
 		select {
 		case <-tuiCtx.Done():
@@ -526,7 +604,7 @@ func (app *App) Shutdown() {
 	// Now run remaining cleanup tasks in parallel.
 	var wg sync.WaitGroup
 
-	// Kill all background shells.
+	// Kill all background shells, whichever session started them.
 	wg.Go(func() {
 		shell.GetBackgroundShellManager().KillAll()
 	})
@@ -558,6 +636,35 @@ func (app *App) Shutdown() {
 	wg.Wait()
 }
 
+// cleanupSessions terminates the background jobs owned by a session, and
+// forgets the context files given to its agent, once the session is deleted.
+func (app *App) cleanupSessions(ctx context.Context) {
+	for event := range app.Sessions.Subscribe(ctx, pubsub.WithPolicy(pubsub.Coalesce)) {
+		if event.Type != pubsub.DeletedEvent {
+			continue
+		}
+		tools.ForgetContextFiles(event.Payload.ID)
+		if n := shell.GetBackgroundShellManager().KillSession(event.Payload.ID); n > 0 {
+			slog.Debug("Terminated background jobs of deleted session", "session_id", event.Payload.ID, "count", n)
+		}
+	}
+}
+
+// CloseSession terminates the background jobs of a session the user left,
+// e.g. by switching to another session. Jobs are kept while the agent is
+// still working in the session, until it is deleted or the app shuts down.
+func (app *App) CloseSession(sessionID string) {
+	if sessionID == "" {
+		return
+	}
+	if app.AgentCoordinator != nil && app.AgentCoordinator.IsSessionBusy(sessionID) {
+		return
+	}
+	if n := shell.GetBackgroundShellManager().KillSession(sessionID); n > 0 {
+		slog.Debug("Terminated background jobs of closed session", "session_id", sessionID, "count", n)
+	}
+}
+
 // checkForUpdates checks for available updates.
 func (app *App) checkForUpdates(ctx context.Context) {
 	checkCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
@@ -567,9 +674,12 @@ func (app *App) checkForUpdates(ctx context.Context) {
 	if err != nil || !info.Available() {
 		return
 	}
-	app.events <- UpdateAvailableMsg{
+	select {
+	case app.events <- UpdateAvailableMsg{
 		CurrentVersion: info.Current,
 		LatestVersion:  info.Latest,
 		IsDevelopment:  info.IsDevelopment(),
+	}:
+	case <-ctx.Done():
 	}
 }
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/stretchr/testify/require"
+)
+
+type testMessage struct {
+	ID      string
+	Content string
+}
+
+func TestSetupSubscriber_SlowConsumer(t *testing.T) {
+	t.Parallel()
+
+	messages := pubsub.NewBrokerWithKey(func(m testMessage) string { return m.ID })
+	t.Cleanup(messages.Shutdown)
+	permissions := pubsub.NewBroker[string]()
+	t.Cleanup(permissions.Shutdown)
+
+	var wg sync.WaitGroup
+	events := make(chan tea.Msg)
+	setupSubscriber(t.Context(), &wg, "messages", messages.Subscribe, pubsub.Coalesce, events)
+	setupSubscriber(t.Context(), &wg, "permissions", permissions.Subscribe, pubsub.Block, events)
+	require.Eventually(t, func() bool {
+		return messages.GetSubscriberCount() == 1 && permissions.GetSubscriberCount() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	for _, content := range []string{"a", "ab", "abc"} {
+		messages.Publish(pubsub.UpdatedEvent, testMessage{ID: "1", Content: content})
+	}
+	permissions.Publish(pubsub.CreatedEvent, "request")
+
+	// The TUI is busy for longer than any event should have to wait.
+	time.Sleep(3 * time.Second)
+
+	var final, permission bool
+	timeout := time.After(5 * time.Second)
+	for !final || !permission {
+		select {
+		case msg := <-events:
+			switch event := msg.(type) {
+			case pubsub.Event[testMessage]:
+				final = event.Payload.Content == "abc"
+			case pubsub.Event[string]:
+				permission = event.Payload == "request"
+			}
+		case <-timeout:
+			t.Fatalf("missed events: final update %v, permission %v", final, permission)
+		}
+	}
+}
@@ -42,8 +42,13 @@ var (
 )
 
 // SubscribeLSPEvents returns a channel for LSP events
-func SubscribeLSPEvents(ctx context.Context) <-chan pubsub.Event[LSPEvent] {
-	return lspBroker.Subscribe(ctx)
+func SubscribeLSPEvents(ctx context.Context, opts ...pubsub.SubscribeOption) <-chan pubsub.Event[LSPEvent] {
+	return lspBroker.Subscribe(ctx, opts...)
+}
+
+// LSPEventStats returns the event counters of the LSP events broker.
+func LSPEventStats() pubsub.Stats {
+	return lspBroker.Stats()
 }
 
 // GetLSPStates returns the current state of all LSP clients
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/format"
+	"github.com/charmbracelet/crush/internal/review"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/tui/components/anim"
+	"github.com/charmbracelet/crush/internal/tui/styles"
+	"github.com/charmbracelet/x/term"
+	"github.com/google/uuid"
+)
+
+// Review output formats.
+const (
+	ReviewFormatMarkdown = "markdown"
+	ReviewFormatJSON     = "json"
+)
+
+// StartReview creates a session for the review of a git revision range, the
+// uncommitted changes if empty.
+func (app *App) StartReview(ctx context.Context, revRange string) (session.Session, error) {
+	sess, err := app.Sessions.Create(ctx, "Review: "+review.RangeTitle(revRange))
+	if err != nil {
+		return session.Session{}, fmt.Errorf("failed to create session for review: %w", err)
+	}
+	return sess, nil
+}
+
+// RunReview reviews the changes of a git revision range non-interactively,
+// and writes the report to output in the given format.
+func (app *App) RunReview(ctx context.Context, output io.Writer, revRange, outputFormat string, hideSpinner bool) error {
+	slog.Info("Running review", "range", revRange)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if !hideSpinner && term.IsTerminal(os.Stderr.Fd()) {
+		t := styles.CurrentTheme()
+		spinner := format.NewSpinner(ctx, cancel, anim.Settings{
+			Size:        10,
+			Label:       "Reviewing",
+			LabelColor:  t.FgBase,
+			GradColorA:  t.Primary,
+			GradColorB:  t.Secondary,
+			CycleColors: true,
+		})
+		spinner.Start()
+		defer spinner.Stop()
+	}
+
+	sess, err := app.StartReview(ctx, revRange)
+	if err != nil {
+		return err
+	}
+	// The reviewer only reads files.
+	app.Permissions.AutoApproveSession(sess.ID)
+
+	report, err := app.AgentCoordinator.Review(ctx, sess.ID, revRange)
+	if err != nil {
+		return fmt.Errorf("review failed: %w", err)
+	}
+
+	switch outputFormat {
+	case ReviewFormatJSON:
+		enc := json.NewEncoder(output)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	default:
+		_, err := fmt.Fprint(output, report.Markdown())
+		return err
+	}
+}
+
+// ApplyReviewFinding applies the suggested fix of a review finding with the
+// edit tool, so it goes through the same permission flow as the edits of the
+// agent.
+func (app *App) ApplyReviewFinding(ctx context.Context, sessionID string, finding review.Finding) error {
+	if finding.Suggestion == nil {
+		return errors.New("finding has no suggested fix")
+	}
+	workingDir := app.config.WorkingDir()
+	filePath := filepath.Join(workingDir, filepath.FromSlash(finding.File))
+
+	// The edit tool only edits files read in the session. The suggestion is
+	// checked against the current content of the file anyway.
+	app.FileTracker.RecordRead(ctx, sessionID, filePath)
+
+	input, err := json.Marshal(tools.EditParams{
+		FilePath:  filePath,
+		OldString: finding.Suggestion.OldString,
+		NewString: finding.Suggestion.NewString,
+	})
+	if err != nil {
+		return err
+	}
+	editTool := tools.NewEditTool(app.LSPClients, app.Permissions, app.History, app.FileTracker, workingDir)
+	resp, err := editTool.Run(
+		context.WithValue(ctx, tools.SessionIDContextKey, sessionID),
+		fantasy.ToolCall{
+			ID:    uuid.NewString(),
+			Name:  tools.EditToolName,
+			Input: string(input),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if resp.IsError {
+		return errors.New(resp.Content)
+	}
+	return nil
+}
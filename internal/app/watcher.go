@@ -0,0 +1,271 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/filetracker"
+	"github.com/charmbracelet/crush/internal/fsext"
+	"github.com/charmbracelet/crush/internal/lsp"
+	"github.com/charmbracelet/x/powernap/pkg/lsp/protocol"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// watchDebounce is how long the watcher collects changes before
+	// dispatching them, so that bursts such as git checkouts are sent at
+	// once.
+	watchDebounce = 200 * time.Millisecond
+
+	// maxWatchedDirs bounds the number of directories watched, to stay
+	// within the limits of the OS on large workspaces.
+	maxWatchedDirs = 8192
+)
+
+// FileWatcher watches the files of the workspace, excluding the ones ignored
+// by .gitignore and .crushignore files, and dispatches their changes to the
+// LSP clients and the file tracker.
+type FileWatcher struct {
+	root        string
+	watcher     *fsnotify.Watcher
+	lspClients  *csync.Map[string, *lsp.Client]
+	fileTracker filetracker.Service
+
+	ignorer interface{ ShouldIgnore(path string) bool }
+	// dirs holds the watched directories.
+	dirs map[string]struct{}
+
+	// pending holds the changes not yet dispatched, by path.
+	pending map[string]*pendingChange
+}
+
+type pendingChange struct {
+	typ protocol.FileChangeType
+	// at is when the file changed, to the second, as read times are.
+	at time.Time
+}
+
+// NewFileWatcher creates a watcher of the files under root.
+func NewFileWatcher(root string, lspClients *csync.Map[string, *lsp.Client], fileTracker filetracker.Service) (*FileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return &FileWatcher{
+		root:        root,
+		watcher:     watcher,
+		lspClients:  lspClients,
+		fileTracker: fileTracker,
+		ignorer:     fsext.NewDirectoryLister(root),
+		dirs:        make(map[string]struct{}),
+		pending:     make(map[string]*pendingChange),
+	}, nil
+}
+
+// Start watches the workspace and dispatches changes until the context is
+// done or the watcher is closed.
+func (w *FileWatcher) Start(ctx context.Context) {
+	w.watchTree(w.root, false)
+	slog.Debug("File watcher started", "root", w.root, "dirs", len(w.dirs))
+
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	armed := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			// The timer isn't pushed back by later changes, so that a
+			// steady stream of them is still dispatched.
+			if w.handleEvent(event) && !armed {
+				timer.Reset(watchDebounce)
+				armed = true
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("File watcher error", "error", err)
+		case <-timer.C:
+			armed = false
+			w.dispatch(ctx)
+		}
+	}
+}
+
+// Close stops the watcher.
+func (w *FileWatcher) Close() error {
+	return w.watcher.Close()
+}
+
+// watchTree watches a directory and its subdirectories. When report is set,
+// the files found are reported as created, as they may have been created
+// before the directory was watched.
+func (w *FileWatcher) watchTree(dir string, report bool) {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if w.ignorer.ShouldIgnore(path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			if report {
+				w.record(path, protocol.Created)
+			}
+			return nil
+		}
+		if _, ok := w.dirs[path]; ok {
+			return nil
+		}
+		if len(w.dirs) >= maxWatchedDirs {
+			slog.Warn("Too many directories to watch, some file changes will be missed", "limit", maxWatchedDirs)
+			return fs.SkipAll
+		}
+		if err := w.watcher.Add(path); err != nil {
+			slog.Warn("Failed to watch directory", "dir", path, "error", err)
+			return filepath.SkipDir
+		}
+		w.dirs[path] = struct{}{}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("Failed to watch directory", "dir", dir, "error", err)
+	}
+}
+
+// unwatchTree stops watching a removed or renamed directory and its
+// subdirectories. Renamed ones are watched again under their new name.
+func (w *FileWatcher) unwatchTree(dir string) {
+	prefix := dir + string(filepath.Separator)
+	for path := range w.dirs {
+		if path != dir && !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		// Removed directories may already have stopped being watched.
+		_ = w.watcher.Remove(path)
+		delete(w.dirs, path)
+	}
+}
+
+// handleEvent records the change of an event, and reports whether it is one
+// to dispatch.
+func (w *FileWatcher) handleEvent(event fsnotify.Event) bool {
+	path := event.Name
+	switch filepath.Base(path) {
+	case ".gitignore", ".crushignore":
+		// Forget the cached ignore files, so the new rules apply to the
+		// following changes.
+		w.ignorer = fsext.NewDirectoryLister(w.root)
+	}
+	if w.ignorer.ShouldIgnore(path) {
+		return false
+	}
+
+	switch {
+	case event.Has(fsnotify.Create):
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			w.watchTree(path, true)
+			return true
+		}
+		w.record(path, protocol.Created)
+	case event.Has(fsnotify.Write):
+		w.record(path, protocol.Changed)
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		w.unwatchTree(path)
+		w.record(path, protocol.Deleted)
+	default:
+		return false
+	}
+	return true
+}
+
+// record adds a change to the pending ones, merging it with an earlier change
+// of the same path.
+func (w *FileWatcher) record(path string, typ protocol.FileChangeType) {
+	at := changeTime(path, typ)
+	prev, ok := w.pending[path]
+	if !ok {
+		w.pending[path] = &pendingChange{typ: typ, at: at}
+		return
+	}
+	if at.After(prev.at) {
+		prev.at = at
+	}
+	switch {
+	case prev.typ == protocol.Created && typ == protocol.Changed:
+		// Still a new file.
+	case prev.typ == protocol.Created && typ == protocol.Deleted:
+		// A temporary file that came and went.
+		delete(w.pending, path)
+	case prev.typ == protocol.Deleted && typ == protocol.Created:
+		// Replaced, as editors do when saving atomically.
+		prev.typ = protocol.Changed
+	default:
+		prev.typ = typ
+	}
+}
+
+// changeTime returns when a file changed, to the second. Events are received
+// after the fact, so the modification time of the file is used rather than
+// the current time, which would forget the reads recorded right after
+// writing it, such as by the edit tools.
+func changeTime(path string, typ protocol.FileChangeType) time.Time {
+	if typ != protocol.Deleted {
+		if info, err := os.Stat(path); err == nil {
+			return info.ModTime().Truncate(time.Second)
+		}
+	}
+	return time.Now().Truncate(time.Second)
+}
+
+// dispatch sends the pending changes to the LSP clients and invalidates the
+// reads of the changed files.
+func (w *FileWatcher) dispatch(ctx context.Context) {
+	if len(w.pending) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(w.pending))
+	for path := range w.pending {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	changes := make([]protocol.FileEvent, 0, len(paths))
+	for _, path := range paths {
+		change := w.pending[path]
+		changes = append(changes, protocol.FileEvent{
+			URI:  protocol.URIFromPath(path),
+			Type: change.typ,
+		})
+		w.fileTracker.Invalidate(ctx, path, change.at)
+	}
+	clear(w.pending)
+
+	for name, client := range w.lspClients.Seq2() {
+		if client.GetServerState() != lsp.StateReady {
+			continue
+		}
+		if err := client.NotifyWatchedFiles(ctx, changes); err != nil {
+			slog.Warn("Failed to notify LSP of file changes", "name", name, "error", err)
+		}
+	}
+}
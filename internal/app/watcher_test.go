@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/lsp"
+	"github.com/charmbracelet/x/powernap/pkg/lsp/protocol"
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/require"
+)
+
+type invalidatingTracker struct {
+	invalidated chan string
+}
+
+func (t *invalidatingTracker) RecordRead(context.Context, string, string) {}
+
+func (t *invalidatingTracker) LastReadTime(context.Context, string, string) time.Time {
+	return time.Time{}
+}
+
+func (t *invalidatingTracker) Invalidate(_ context.Context, path string, _ time.Time) {
+	t.invalidated <- path
+}
+
+func TestFileWatcher(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("generated/\n*.out\n"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "generated"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0o755))
+
+	tracker := &invalidatingTracker{invalidated: make(chan string, 10)}
+	w, err := NewFileWatcher(root, csync.NewMap[string, *lsp.Client](), tracker)
+	require.NoError(t, err)
+	t.Cleanup(func() { w.Close() })
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+	go w.Start(ctx)
+
+	// Wait for the tree to be watched.
+	require.Eventually(t, func() bool {
+		return len(w.watcher.WatchList()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "generated", "code.go"), []byte("x"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "build.out"), []byte("x"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("x"), 0o644))
+
+	select {
+	case path := <-tracker.invalidated:
+		require.Equal(t, filepath.Join(root, "src", "main.go"), path)
+	case <-time.After(5 * time.Second):
+		t.Fatal("change was not dispatched")
+	}
+	select {
+	case path := <-tracker.invalidated:
+		t.Fatalf("unexpected change of %s", path)
+	case <-time.After(2 * watchDebounce):
+	}
+}
+
+func TestFileWatcherRecord(t *testing.T) {
+	t.Parallel()
+
+	w := &FileWatcher{pending: make(map[string]*pendingChange)}
+
+	w.record("new", protocol.Created)
+	w.record("new", protocol.Changed)
+	w.record("temp", protocol.Created)
+	w.record("temp", protocol.Deleted)
+	w.record("saved", protocol.Deleted)
+	w.record("saved", protocol.Created)
+	w.record("gone", protocol.Changed)
+	w.record("gone", protocol.Deleted)
+
+	types := make(map[string]protocol.FileChangeType)
+	for path, change := range w.pending {
+		types[path] = change.typ
+	}
+	require.Equal(t, map[string]protocol.FileChangeType{
+		"new":   protocol.Created,
+		"saved": protocol.Changed,
+		"gone":  protocol.Deleted,
+	}, types)
+}
+
+func TestFileWatcherNewDirectory(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	tracker := &invalidatingTracker{invalidated: make(chan string, 10)}
+	w, err := NewFileWatcher(root, csync.NewMap[string, *lsp.Client](), tracker)
+	require.NoError(t, err)
+	t.Cleanup(func() { w.Close() })
+
+	// Files of a new directory are reported, even the ones written before
+	// the directory is watched.
+	dir := filepath.Join(root, "pkg")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte("x"), 0o644))
+	require.True(t, w.handleEvent(fsnotify.Event{Name: dir, Op: fsnotify.Create}))
+	require.Contains(t, w.watcher.WatchList(), dir)
+	require.Equal(t, protocol.Created, w.pending[filepath.Join(dir, "a.go")].typ)
+}
+
+func TestFileWatcherChangeTime(t *testing.T) {
+	t.Parallel()
+
+	// Changes are dated by the modification time of the file rather than
+	// when their events are received, which could be after the file was
+	// read again.
+	path := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main"), 0o644))
+	modTime := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	w := &FileWatcher{pending: make(map[string]*pendingChange)}
+	w.record(path, protocol.Changed)
+	require.Equal(t, modTime.Truncate(time.Second), w.pending[path].at)
+}
+
+func TestFileWatcherRemovedDirectory(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "pkg")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	w, err := NewFileWatcher(root, csync.NewMap[string, *lsp.Client](), &invalidatingTracker{invalidated: make(chan string, 10)})
+	require.NoError(t, err)
+	t.Cleanup(func() { w.Close() })
+	w.watchTree(root, false)
+	require.Len(t, w.dirs, 3)
+
+	require.NoError(t, os.RemoveAll(dir))
+	require.True(t, w.handleEvent(fsnotify.Event{Name: dir, Op: fsnotify.Remove}))
+	require.Len(t, w.dirs, 1)
+	require.NotContains(t, w.watcher.WatchList(), dir)
+}
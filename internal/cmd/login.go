@@ -14,31 +14,40 @@ import (
 	"github.com/charmbracelet/crush/internal/oauth"
 	"github.com/charmbracelet/crush/internal/oauth/copilot"
 	"github.com/charmbracelet/crush/internal/oauth/hyper"
+	mcpoauth "github.com/charmbracelet/crush/internal/oauth/mcp"
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 )
 
 var loginCmd = &cobra.Command{
 	Aliases: []string{"auth"},
-	Use:     "login [platform]",
+	Use:     "login [platform] [name]",
 	Short:   "Login Crush to a platform",
 	Long: `Login Crush to a specified platform.
 The platform should be provided as an argument.
-Available platforms are: hyper, copilot.`,
+Available platforms are: hyper, copilot, mcp.
+To authorize an HTTP or SSE MCP server, pass its name after "mcp".`,
 	Example: `
 # Authenticate with Charm Hyper
 crush login
 
 # Authenticate with GitHub Copilot
 crush login copilot
+
+# Authorize the MCP server named "linear"
+crush login mcp linear
+
+# Authorize an MCP server using the device flow, e.g. over SSH
+crush login mcp linear --device
   `,
 	ValidArgs: []cobra.Completion{
 		"hyper",
 		"copilot",
 		"github",
 		"github-copilot",
+		"mcp",
 	},
-	Args: cobra.MaximumNArgs(1),
+	Args: cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		app, err := setupAppWithProgressBar(cmd)
 		if err != nil {
@@ -55,12 +64,22 @@ crush login copilot
 			return loginHyper()
 		case "copilot", "github", "github-copilot":
 			return loginCopilot()
+		case "mcp":
+			if len(args) < 2 {
+				return fmt.Errorf("missing mcp name: crush login mcp <name>")
+			}
+			device, _ := cmd.Flags().GetBool("device")
+			return loginMCP(args[1], device)
 		default:
 			return fmt.Errorf("unknown platform: %s", args[0])
 		}
 	},
 }
 
+func init() {
+	loginCmd.Flags().Bool("device", false, "Use the device flow instead of the browser to authorize MCP servers")
+}
+
 func loginHyper() error {
 	cfg := config.Get()
 	if !hyperp.Enabled() {
@@ -190,6 +209,80 @@ func loginCopilot() error {
 	return nil
 }
 
+func loginMCP(name string, device bool) error {
+	ctx := getLoginContext()
+
+	cfg := config.Get()
+	m, ok := cfg.MCP[name]
+	if !ok {
+		return fmt.Errorf("unknown mcp: %s", name)
+	}
+	if m.Type != config.MCPHttp && m.Type != config.MCPSSE {
+		return fmt.Errorf("mcp %s does not use http or sse, so it does not need to be authorized", name)
+	}
+
+	fmt.Println("Discovering authorization server...")
+	meta, err := mcpoauth.Discover(ctx, m.URL)
+	if err != nil {
+		return err
+	}
+
+	var listener *mcpoauth.Listener
+	var redirectURIs []string
+	if !device {
+		listener, err = mcpoauth.Listen()
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+		redirectURIs = append(redirectURIs, listener.RedirectURI())
+	}
+
+	fmt.Println("Registering Crush with the authorization server...")
+	client, err := mcpoauth.Register(ctx, meta, redirectURIs)
+	if err != nil {
+		return err
+	}
+
+	var token *oauth.Token
+	if device {
+		token, err = mcpoauth.AuthorizeDevice(ctx, client, func(dc mcpoauth.DeviceCode) {
+			uri := cmp.Or(dc.VerificationURIComplete, dc.VerificationURI)
+			fmt.Println()
+			fmt.Println("Open the following URL and follow the instructions to authorize Crush:")
+			fmt.Println()
+			fmt.Println(lipgloss.NewStyle().Hyperlink(uri, "id=mcp").Render(uri))
+			fmt.Println()
+			fmt.Println("Code:", lipgloss.NewStyle().Bold(true).Render(dc.UserCode))
+			fmt.Println()
+			fmt.Println("Waiting for authorization...")
+		})
+	} else {
+		token, err = mcpoauth.AuthorizeBrowser(ctx, client, listener, func(url string) {
+			fmt.Println()
+			fmt.Println("Opening the following URL to authorize Crush:")
+			fmt.Println()
+			fmt.Println(lipgloss.NewStyle().Hyperlink(url, "id=mcp").Render(url))
+			fmt.Println()
+			if err := browser.OpenURL(url); err != nil {
+				fmt.Println("Could not open the URL. You'll need to manually open the URL in your browser.")
+			}
+			fmt.Println("Waiting for authorization...")
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.SetMCPOAuth(name, client, token); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("You're now authenticated with %s!\n", name)
+	return nil
+}
+
 func getLoginContext() context.Context {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
 	go func() {
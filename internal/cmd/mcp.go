@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/charmbracelet/crush/internal/event"
+	"github.com/charmbracelet/crush/internal/mcpserver"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Model Context Protocol commands",
+}
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the tools of Crush over MCP",
+	Long: `Serve the file and LSP tools of Crush to other agents and editors through
+the Model Context Protocol, over stdio or streamable HTTP.
+
+Tool calls ask the MCP client for permission through elicitation, unless
+permissions are skipped with --yolo or allowed in the configuration.
+
+Over HTTP, clients authenticate with the bearer token given with --token or
+the CRUSH_MCP_TOKEN environment variable. Without a token, only loopback
+addresses are served.`,
+	Example: `
+# Serve over stdio
+crush mcp serve
+
+# Serve over HTTP, also exposing the coder agent
+crush mcp serve --http localhost:8080 --agent
+
+# Serve over HTTP on all interfaces, requiring a token
+CRUSH_MCP_TOKEN=secret crush mcp serve --http :8080
+  `,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("http")
+		agent, _ := cmd.Flags().GetBool("agent")
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" {
+			token = os.Getenv("CRUSH_MCP_TOKEN")
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+		defer cancel()
+
+		app, err := setupApp(cmd)
+		if err != nil {
+			return err
+		}
+		defer app.Shutdown()
+
+		server, err := mcpserver.New(app, mcpserver.Options{Agent: agent, Token: token})
+		if err != nil {
+			return err
+		}
+
+		event.SetNonInteractive(true)
+		event.AppInitialized()
+
+		if addr != "" {
+			slog.Info("Serving MCP over HTTP", "addr", addr)
+			return server.ListenAndServe(ctx, addr)
+		}
+		slog.Info("Serving MCP over stdio")
+		return server.Run(ctx)
+	},
+	PostRun: func(cmd *cobra.Command, args []string) {
+		event.AppExited()
+	},
+}
+
+func init() {
+	mcpServeCmd.Flags().String("http", "", "Serve over streamable HTTP on the given address instead of stdio")
+	mcpServeCmd.Flags().String("token", "", "Bearer token HTTP clients must authenticate with, required outside of loopback addresses")
+	mcpServeCmd.Flags().Bool("agent", false, "Expose the run_agent tool, running prompts with the coder agent")
+	mcpServeCmd.Flags().BoolP("yolo", "y", false, "Automatically accept all permissions (dangerous mode)")
+	mcpCmd.AddCommand(mcpServeCmd)
+}
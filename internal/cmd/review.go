@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/charmbracelet/crush/internal/app"
+	"github.com/charmbracelet/crush/internal/event"
+	"github.com/spf13/cobra"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [range]",
+	Short: "Review code changes",
+	Long: `Review the changes of a git revision range and print the findings.
+Without a range, the uncommitted changes are reviewed, untracked files included.
+The range must end at the checked out commit, as the reviewer reads the files of
+the working tree. A dedicated reviewer agent reads the diff with read-only tools and reports each
+finding with its location, severity, message and an optional suggested fix.`,
+	Example: `
+# Review the uncommitted changes
+crush review
+
+# Review the commits of the current branch
+crush review main..HEAD
+
+# Output the findings as JSON
+crush review --format json main...HEAD
+  `,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		outputFormat, _ := cmd.Flags().GetString("format")
+		switch outputFormat {
+		case app.ReviewFormatMarkdown, app.ReviewFormatJSON:
+		default:
+			return fmt.Errorf("invalid format %q, must be %q or %q", outputFormat, app.ReviewFormatMarkdown, app.ReviewFormatJSON)
+		}
+
+		var revRange string
+		if len(args) > 0 {
+			revRange = args[0]
+		}
+
+		// Cancel on SIGINT or SIGTERM.
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+		defer cancel()
+
+		app, err := setupApp(cmd)
+		if err != nil {
+			return err
+		}
+		defer app.Shutdown()
+
+		if !app.Config().IsConfigured() {
+			return fmt.Errorf("no providers configured - please run 'crush' to set up a provider interactively")
+		}
+
+		event.SetNonInteractive(true)
+		event.AppInitialized()
+
+		return app.RunReview(ctx, os.Stdout, revRange, outputFormat, quiet)
+	},
+	PostRun: func(cmd *cobra.Command, args []string) {
+		event.AppExited()
+	},
+}
+
+func init() {
+	reviewCmd.Flags().BoolP("quiet", "q", false, "Hide spinner")
+	reviewCmd.Flags().StringP("format", "f", app.ReviewFormatMarkdown, "Output format (markdown or json)")
+}
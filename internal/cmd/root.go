@@ -45,6 +45,7 @@ func init() {
 
 	rootCmd.AddCommand(
 		runCmd,
+		reviewCmd,
 		dirsCmd,
 		projectsCmd,
 		updateProvidersCmd,
@@ -52,6 +53,8 @@ func init() {
 		schemaCmd,
 		loginCmd,
 		statsCmd,
+		mcpCmd,
+		skillsCmd,
 	)
 }
 
@@ -9,6 +9,9 @@ import (
 	"strings"
 
 	"charm.land/log/v2"
+	"github.com/charmbracelet/crush/internal/agent"
+	"github.com/charmbracelet/crush/internal/commands"
+	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/event"
 	"github.com/spf13/cobra"
 )
@@ -17,7 +20,8 @@ var runCmd = &cobra.Command{
 	Use:   "run [prompt...]",
 	Short: "Run a single non-interactive prompt",
 	Long: `Run a single prompt in non-interactive mode and exit.
-The prompt can be provided as arguments or piped from stdin.`,
+The prompt can be provided as arguments or piped from stdin.
+A prompt starting with the ID of a custom command runs that command.`,
 	Example: `
 # Run a simple prompt
 crush run Explain the use of context in Go
@@ -33,6 +37,9 @@ crush run --quiet "Generate a README for this project"
 
 # Run in verbose mode
 crush run --verbose "Generate a README for this project"
+
+# Run a custom command, with its arguments
+crush run /project:deploy-check ENV=staging
   `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		quiet, _ := cmd.Flags().GetBool("quiet")
@@ -60,6 +67,15 @@ crush run --verbose "Generate a README for this project"
 
 		prompt := strings.Join(args, " ")
 
+		// Expand custom commands, as in "crush run /project:deploy-check".
+		var opts agent.RunOptions
+		if strings.HasPrefix(prompt, "/") {
+			prompt, opts, err = expandCustomCommand(ctx, app.Config(), prompt)
+			if err != nil {
+				return err
+			}
+		}
+
 		prompt, err = MaybePrependStdin(prompt)
 		if err != nil {
 			slog.Error("Failed to read from stdin", "error", err)
@@ -73,7 +89,7 @@ crush run --verbose "Generate a README for this project"
 		event.SetNonInteractive(true)
 		event.AppInitialized()
 
-		return app.RunNonInteractive(ctx, os.Stdout, prompt, largeModel, smallModel, quiet || verbose)
+		return app.RunNonInteractive(ctx, os.Stdout, prompt, opts, largeModel, smallModel, quiet || verbose)
 	},
 	PostRun: func(cmd *cobra.Command, args []string) {
 		event.AppExited()
@@ -86,3 +102,28 @@ func init() {
 	runCmd.Flags().StringP("model", "m", "", "Model to use. Accepts 'model' or 'provider/model' to disambiguate models with the same name across providers")
 	runCmd.Flags().String("small-model", "", "Small model to use. If not provided, uses the default small model for the provider")
 }
+
+// expandCustomCommand expands the custom command the prompt starts with,
+// passing it the rest of the prompt as arguments. Prompts that don't start
+// with the ID of a custom command are returned as is.
+func expandCustomCommand(ctx context.Context, cfg *config.Config, prompt string) (string, agent.RunOptions, error) {
+	id, line, _ := strings.Cut(prompt, " ")
+	customCommands, err := commands.LoadCustomCommands(cfg)
+	if err != nil {
+		return "", agent.RunOptions{}, err
+	}
+	custom, ok := commands.Find(customCommands, id)
+	if !ok {
+		return prompt, agent.RunOptions{}, nil
+	}
+
+	args, err := custom.ParseArgs(line)
+	if err != nil {
+		return "", agent.RunOptions{}, err
+	}
+	expanded, err := custom.Expand(ctx, cfg.WorkingDir(), args)
+	if err != nil {
+		return "", agent.RunOptions{}, fmt.Errorf("%s: %w", custom.ID, err)
+	}
+	return expanded, custom.RunOptions(), nil
+}
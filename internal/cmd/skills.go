@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	"charm.land/lipgloss/v2/table"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/home"
+	"github.com/charmbracelet/crush/internal/skills"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+)
+
+var skillsCmd = &cobra.Command{
+	Use:   "skills",
+	Short: "Manage Agent Skills",
+	Long:  "List, validate and install Agent Skills, folders of instructions and resources the agent can activate on demand",
+}
+
+var skillsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available skills",
+	Example: `
+# List the skills found in the configured skills paths
+crush skills list
+  `,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths, err := skillsPaths(cmd)
+		if err != nil {
+			return err
+		}
+
+		found := skills.Discover(paths)
+		if len(found) == 0 {
+			cmd.Println("No skills found in " + strings.Join(paths, ", "))
+			return nil
+		}
+
+		if term.IsTerminal(os.Stdout.Fd()) {
+			// We're in a TTY: make it fancy.
+			t := table.New().
+				Border(lipgloss.RoundedBorder()).
+				StyleFunc(func(row, col int) lipgloss.Style {
+					return lipgloss.NewStyle().Padding(0, 2)
+				}).
+				Headers("Name", "Description", "Path")
+			for _, s := range found {
+				t.Row(s.Name, ansi.Truncate(strings.Join(strings.Fields(s.Description), " "), 60, "…"), home.Short(s.Path))
+			}
+			lipgloss.Println(t)
+			return nil
+		}
+
+		// Not a TTY: plain output
+		for _, s := range found {
+			cmd.Printf("%s\t%s\n", s.Name, s.Path)
+		}
+		return nil
+	},
+}
+
+var skillsValidateCmd = &cobra.Command{
+	Use:   "validate [path...]",
+	Short: "Validate skills against the Agent Skills specification",
+	Example: `
+# Validate the skills in the configured skills paths
+crush skills validate
+
+# Validate a skill being written
+crush skills validate ./my-skill
+  `,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths := args
+		if len(paths) == 0 {
+			var err error
+			if paths, err = skillsPaths(cmd); err != nil {
+				return err
+			}
+		}
+
+		files := skills.FindFiles(paths)
+		if len(files) == 0 {
+			return fmt.Errorf("no %s files found in %s", skills.SkillFileName, strings.Join(paths, ", "))
+		}
+
+		var invalid int
+		for _, file := range files {
+			skill, err := skills.Parse(file)
+			if err == nil {
+				err = skill.Validate()
+			}
+			if err != nil {
+				invalid++
+				cmd.Printf("✗ %s\n", home.Short(file))
+				for _, line := range strings.Split(err.Error(), "\n") {
+					cmd.Printf("  %s\n", line)
+				}
+				continue
+			}
+			cmd.Printf("✓ %s (%s)\n", skill.Name, home.Short(file))
+		}
+		if invalid > 0 {
+			return fmt.Errorf("%d of %d skills are invalid", invalid, len(files))
+		}
+		return nil
+	},
+}
+
+var skillsInstallCmd = &cobra.Command{
+	Use:   "install <git-url>",
+	Short: "Install skills from a git repository",
+	Long: `Clone a git repository holding one or more skills into a skills directory.
+Update installed skills with git pull in their directory.`,
+	Example: `
+# Install skills in the global skills directory
+crush skills install https://github.com/anthropics/skills
+
+# Install skills in the project
+crush skills install https://github.com/anthropics/skills --dir .crush/skills
+  `,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url := args[0]
+		dir, _ := cmd.Flags().GetString("dir")
+		if dir == "" {
+			dir = config.GlobalSkillsDirs()[0]
+		}
+		dir = home.Long(dir)
+		if !filepath.IsAbs(dir) {
+			cwd, err := ResolveCwd(cmd)
+			if err != nil {
+				return err
+			}
+			dir = filepath.Join(cwd, dir)
+		}
+
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			name = strings.TrimSuffix(path.Base(strings.TrimSuffix(url, "/")), ".git")
+		}
+		dest := filepath.Join(dir, name)
+		if _, err := os.Stat(dest); err == nil {
+			return fmt.Errorf("%s already exists, update it with git pull or choose another --name", dest)
+		}
+
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create skills directory: %w", err)
+		}
+		clone := exec.CommandContext(cmd.Context(), "git", "clone", "--depth", "1", "--", url, dest)
+		clone.Stdout = os.Stderr
+		clone.Stderr = os.Stderr
+		if err := clone.Run(); err != nil {
+			return fmt.Errorf("failed to clone %s: %w", url, err)
+		}
+
+		installed := skills.Discover([]string{dest})
+		if len(installed) == 0 {
+			return errors.Join(
+				fmt.Errorf("no valid skills found in %s", url),
+				os.RemoveAll(dest),
+			)
+		}
+		for _, s := range installed {
+			cmd.Printf("Installed %s (%s)\n", s.Name, home.Short(s.Path))
+		}
+		return nil
+	},
+}
+
+func init() {
+	skillsInstallCmd.Flags().String("dir", "", "Skills directory to install into (default is the global skills directory)")
+	skillsInstallCmd.Flags().String("name", "", "Name of the directory to clone into (default is the repository name)")
+	skillsCmd.AddCommand(skillsListCmd, skillsValidateCmd, skillsInstallCmd)
+}
+
+// skillsPaths returns the configured skills paths, expanded.
+func skillsPaths(cmd *cobra.Command) ([]string, error) {
+	cwd, err := ResolveCwd(cmd)
+	if err != nil {
+		return nil, err
+	}
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	debug, _ := cmd.Flags().GetBool("debug")
+
+	cfg, err := config.Init(cwd, dataDir, debug)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(cfg.Options.SkillsPaths))
+	for _, p := range cfg.Options.SkillsPaths {
+		p = home.Long(p)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(cwd, p)
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
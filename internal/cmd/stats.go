@@ -5,12 +5,15 @@ import (
 	"context"
 	"database/sql"
 	_ "embed"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"os"
 	"os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -42,7 +45,31 @@ var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show usage statistics",
 	Long:  "Generate and display usage statistics including token usage, costs, and activity patterns",
-	RunE:  runStats,
+	Example: `
+# Generate the HTML report and open it in the browser
+crush stats
+
+# Only count the usage of the last week
+crush stats --since 7d
+
+# Output the statistics as JSON
+crush stats --json
+
+# Output the cost and tokens by day, provider and model as CSV
+crush stats --since 2026-01-01 --csv
+
+# Aggregate the statistics of all projects
+crush stats --all-projects
+  `,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().String("since", "", "Only count usage since a date (YYYY-MM-DD) or for a duration, e.g. 7d or 12h")
+	statsCmd.Flags().Bool("json", false, "Output as JSON")
+	statsCmd.Flags().Bool("csv", false, "Output usage by day, provider and model as CSV")
+	statsCmd.Flags().Bool("all-projects", false, "Aggregate the statistics of all known projects")
+	statsCmd.MarkFlagsMutuallyExclusive("json", "csv")
 }
 
 // Day names for day of week statistics.
@@ -50,16 +77,20 @@ var dayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday",
 
 // Stats holds all the statistics data.
 type Stats struct {
-	GeneratedAt       time.Time          `json:"generated_at"`
-	Total             TotalStats         `json:"total"`
-	UsageByDay        []DailyUsage       `json:"usage_by_day"`
-	UsageByModel      []ModelUsage       `json:"usage_by_model"`
-	UsageByHour       []HourlyUsage      `json:"usage_by_hour"`
-	UsageByDayOfWeek  []DayOfWeekUsage   `json:"usage_by_day_of_week"`
-	RecentActivity    []DailyActivity    `json:"recent_activity"`
-	AvgResponseTimeMs float64            `json:"avg_response_time_ms"`
-	ToolUsage         []ToolUsage        `json:"tool_usage"`
-	HourDayHeatmap    []HourDayHeatmapPt `json:"hour_day_heatmap"`
+	GeneratedAt        time.Time          `json:"generated_at"`
+	Since              *time.Time         `json:"since,omitempty"`
+	Total              TotalStats         `json:"total"`
+	UsageByDay         []DailyUsage       `json:"usage_by_day"`
+	UsageByMonth       []MonthlyUsage     `json:"usage_by_month"`
+	UsageByModel       []ModelUsage       `json:"usage_by_model"`
+	UsageByModelAndDay []DailyModelUsage  `json:"usage_by_model_and_day"`
+	UsageByHour        []HourlyUsage      `json:"usage_by_hour"`
+	UsageByDayOfWeek   []DayOfWeekUsage   `json:"usage_by_day_of_week"`
+	RecentActivity     []DailyActivity    `json:"recent_activity"`
+	AvgResponseTimeMs  float64            `json:"avg_response_time_ms"`
+	ToolUsage          []ToolUsage        `json:"tool_usage"`
+	HourDayHeatmap     []HourDayHeatmapPt `json:"hour_day_heatmap"`
+	Projects           []ProjectStats     `json:"projects,omitempty"`
 }
 
 type TotalStats struct {
@@ -82,10 +113,24 @@ type DailyUsage struct {
 	SessionCount     int64   `json:"session_count"`
 }
 
+// ModelUsage is the usage of a model, counted from the assistant messages
+// it generated.
 type ModelUsage struct {
-	Model        string `json:"model"`
-	Provider     string `json:"provider"`
-	MessageCount int64  `json:"message_count"`
+	Model               string  `json:"model"`
+	Provider            string  `json:"provider"`
+	MessageCount        int64   `json:"message_count"`
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	CacheHitRatio       float64 `json:"cache_hit_ratio"`
+	Cost                float64 `json:"cost"`
+}
+
+// DailyModelUsage is the usage of a model on a day.
+type DailyModelUsage struct {
+	Day string `json:"day"`
+	ModelUsage
 }
 
 type HourlyUsage struct {
@@ -121,27 +166,59 @@ type HourDayHeatmapPt struct {
 
 func runStats(cmd *cobra.Command, _ []string) error {
 	dataDir, _ := cmd.Flags().GetString("data-dir")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	csvOutput, _ := cmd.Flags().GetBool("csv")
+	allProjects, _ := cmd.Flags().GetBool("all-projects")
 	ctx := cmd.Context()
 
-	if dataDir == "" {
-		cfg, err := config.Init("", "", false)
+	var since time.Time
+	if sinceFlag != "" {
+		var err error
+		since, err = parseSince(sinceFlag, time.Now())
 		if err != nil {
-			return fmt.Errorf("failed to initialize config: %w", err)
+			return err
 		}
-		dataDir = cfg.Options.DataDirectory
 	}
 
-	conn, err := db.Connect(ctx, dataDir)
-	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+	var (
+		stats *Stats
+		err   error
+	)
+	switch {
+	case allProjects:
+		// The report of all projects is kept next to the list of projects,
+		// unless a data directory is given.
+		if dataDir == "" {
+			dataDir = filepath.Dir(config.GlobalConfigData())
+		}
+		stats, err = gatherProjectsStats(ctx, cmd.ErrOrStderr(), since)
+	default:
+		if dataDir == "" {
+			cfg, err := config.Init("", "", false)
+			if err != nil {
+				return fmt.Errorf("failed to initialize config: %w", err)
+			}
+			dataDir = cfg.Options.DataDirectory
+		}
+		stats, err = gatherDataDirStats(ctx, dataDir, since)
 	}
-	defer conn.Close()
-
-	stats, err := gatherStats(ctx, conn)
 	if err != nil {
 		return fmt.Errorf("failed to gather stats: %w", err)
 	}
 
+	switch {
+	case jsonOutput:
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	case csvOutput:
+		return writeStatsCSV(cmd.OutOrStdout(), stats.UsageByModelAndDay)
+	}
+
 	if stats.Total.TotalSessions == 0 {
 		return fmt.Errorf("no data available: no sessions found in database")
 	}
@@ -156,6 +233,9 @@ func runStats(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 	project = strings.Replace(project, currentUser.HomeDir, "~", 1)
+	if allProjects {
+		project = "all projects"
+	}
 
 	htmlPath := filepath.Join(dataDir, "stats/index.html")
 	if err := generateHTML(stats, project, username, htmlPath); err != nil {
@@ -172,15 +252,20 @@ func runStats(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
-func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
+func gatherStats(ctx context.Context, conn *sql.DB, since time.Time) (*Stats, error) {
 	queries := db.New(conn)
 
 	stats := &Stats{
 		GeneratedAt: time.Now(),
 	}
+	var sinceUnix int64
+	if !since.IsZero() {
+		stats.Since = &since
+		sinceUnix = since.Unix()
+	}
 
 	// Total stats.
-	total, err := queries.GetTotalStats(ctx)
+	total, err := queries.GetTotalStats(ctx, sinceUnix)
 	if err != nil {
 		return nil, fmt.Errorf("get total stats: %w", err)
 	}
@@ -196,7 +281,7 @@ func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
 	}
 
 	// Usage by day.
-	dailyUsage, err := queries.GetUsageByDay(ctx)
+	dailyUsage, err := queries.GetUsageByDay(ctx, sinceUnix)
 	if err != nil {
 		return nil, fmt.Errorf("get usage by day: %w", err)
 	}
@@ -212,22 +297,39 @@ func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
 			SessionCount:     d.SessionCount,
 		})
 	}
+	stats.UsageByMonth = monthlyUsage(stats.UsageByDay)
 
 	// Usage by model.
-	modelUsage, err := queries.GetUsageByModel(ctx)
+	modelUsage, err := queries.GetUsageByModel(ctx, sinceUnix)
 	if err != nil {
 		return nil, fmt.Errorf("get usage by model: %w", err)
 	}
 	for _, m := range modelUsage {
-		stats.UsageByModel = append(stats.UsageByModel, ModelUsage{
-			Model:        m.Model,
-			Provider:     m.Provider,
-			MessageCount: m.MessageCount,
+		stats.UsageByModel = append(stats.UsageByModel, newModelUsage(
+			m.Model, m.Provider, m.MessageCount,
+			m.InputTokens, m.OutputTokens, m.CacheReadTokens, m.CacheCreationTokens,
+			m.Cost,
+		))
+	}
+
+	// Usage by model and day.
+	dailyModelUsage, err := queries.GetUsageByModelAndDay(ctx, sinceUnix)
+	if err != nil {
+		return nil, fmt.Errorf("get usage by model and day: %w", err)
+	}
+	for _, m := range dailyModelUsage {
+		stats.UsageByModelAndDay = append(stats.UsageByModelAndDay, DailyModelUsage{
+			Day: m.Day,
+			ModelUsage: newModelUsage(
+				m.Model, m.Provider, m.MessageCount,
+				m.InputTokens, m.OutputTokens, m.CacheReadTokens, m.CacheCreationTokens,
+				m.Cost,
+			),
 		})
 	}
 
 	// Usage by hour.
-	hourlyUsage, err := queries.GetUsageByHour(ctx)
+	hourlyUsage, err := queries.GetUsageByHour(ctx, sinceUnix)
 	if err != nil {
 		return nil, fmt.Errorf("get usage by hour: %w", err)
 	}
@@ -239,7 +341,7 @@ func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
 	}
 
 	// Usage by day of week.
-	dowUsage, err := queries.GetUsageByDayOfWeek(ctx)
+	dowUsage, err := queries.GetUsageByDayOfWeek(ctx, sinceUnix)
 	if err != nil {
 		return nil, fmt.Errorf("get usage by day of week: %w", err)
 	}
@@ -254,7 +356,7 @@ func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
 	}
 
 	// Recent activity (last 30 days).
-	recent, err := queries.GetRecentActivity(ctx)
+	recent, err := queries.GetRecentActivity(ctx, sinceUnix)
 	if err != nil {
 		return nil, fmt.Errorf("get recent activity: %w", err)
 	}
@@ -268,14 +370,14 @@ func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
 	}
 
 	// Average response time.
-	avgResp, err := queries.GetAverageResponseTime(ctx)
+	avgResp, err := queries.GetAverageResponseTime(ctx, sinceUnix)
 	if err != nil {
 		return nil, fmt.Errorf("get average response time: %w", err)
 	}
 	stats.AvgResponseTimeMs = toFloat64(avgResp) * 1000
 
 	// Tool usage.
-	toolUsage, err := queries.GetToolUsage(ctx)
+	toolUsage, err := queries.GetToolUsage(ctx, sinceUnix)
 	if err != nil {
 		return nil, fmt.Errorf("get tool usage: %w", err)
 	}
@@ -289,7 +391,7 @@ func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
 	}
 
 	// Hour/day heatmap.
-	heatmap, err := queries.GetHourDayHeatmap(ctx)
+	heatmap, err := queries.GetHourDayHeatmap(ctx, sinceUnix)
 	if err != nil {
 		return nil, fmt.Errorf("get hour day heatmap: %w", err)
 	}
@@ -304,6 +406,64 @@ func gatherStats(ctx context.Context, conn *sql.DB) (*Stats, error) {
 	return stats, nil
 }
 
+func newModelUsage(model, provider string, messages, input, output, cacheRead, cacheCreation int64, cost float64) ModelUsage {
+	usage := ModelUsage{
+		Model:               model,
+		Provider:            provider,
+		MessageCount:        messages,
+		InputTokens:         input,
+		OutputTokens:        output,
+		CacheReadTokens:     cacheRead,
+		CacheCreationTokens: cacheCreation,
+		Cost:                cost,
+	}
+	// Cache reads are not counted in the input tokens.
+	if prompt := input + cacheRead + cacheCreation; prompt > 0 {
+		usage.CacheHitRatio = float64(cacheRead) / float64(prompt)
+	}
+	return usage
+}
+
+// parseSince parses the --since flag, either a date or a duration before
+// now. Durations also accept days, e.g. "7d".
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: expected a date like 2006-01-02 or a duration like 7d or 12h", s)
+}
+
+// writeStatsCSV writes the usage by day, provider and model as CSV.
+func writeStatsCSV(w io.Writer, usage []DailyModelUsage) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"day", "provider", "model", "messages",
+		"input_tokens", "output_tokens", "cache_read_tokens", "cache_creation_tokens",
+		"cache_hit_ratio", "cost",
+	})
+	for _, u := range usage {
+		_ = cw.Write([]string{
+			u.Day, u.Provider, u.Model, strconv.FormatInt(u.MessageCount, 10),
+			strconv.FormatInt(u.InputTokens, 10),
+			strconv.FormatInt(u.OutputTokens, 10),
+			strconv.FormatInt(u.CacheReadTokens, 10),
+			strconv.FormatInt(u.CacheCreationTokens, 10),
+			strconv.FormatFloat(u.CacheHitRatio, 'f', 4, 64),
+			strconv.FormatFloat(u.Cost, 'f', 6, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
 func toInt64(v any) int64 {
 	switch val := v.(type) {
 	case int64:
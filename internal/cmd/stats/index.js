@@ -63,6 +63,28 @@ function getTopItemsWithOthers(items, countKey, labelKey, topN = 10) {
   return displayItems;
 }
 
+// The report of all projects can be filtered by project, kept in the URL
+// fragment so that the charts are rendered once.
+const selectedProject = decodeURIComponent(location.hash.slice(1));
+const stats =
+  report.projects?.find((p) => p.path === selectedProject)?.stats ?? report;
+
+if (report.projects?.length > 0) {
+  const select = document.getElementById("project-select");
+  report.projects.forEach((p) => {
+    const option = document.createElement("option");
+    option.value = p.path;
+    option.textContent = p.path;
+    option.selected = p.path === selectedProject;
+    select.appendChild(option);
+  });
+  select.addEventListener("change", () => {
+    location.hash = encodeURIComponent(select.value);
+    location.reload();
+  });
+  document.getElementById("project-filter").hidden = false;
+}
+
 // Populate summary cards
 document.getElementById("total-sessions").textContent = formatNumber(
   stats.total.total_sessions,
@@ -354,3 +376,86 @@ if (stats.usage_by_day?.length > 0) {
   });
   tableBody.appendChild(fragment);
 }
+
+// Model Cost Table
+const modelTableBody = document.querySelector("#model-table tbody");
+if (stats.usage_by_model?.length > 0) {
+  const fragment = document.createDocumentFragment();
+  stats.usage_by_model.forEach((m) => {
+    const row = document.createElement("tr");
+    [
+      m.model,
+      m.provider,
+      formatNumber(m.message_count),
+      formatNumber(m.input_tokens),
+      formatNumber(m.output_tokens),
+      formatNumber(m.cache_read_tokens),
+      `${(m.cache_hit_ratio * 100).toFixed(1)}%`,
+      formatCost(m.cost),
+    ].forEach((value) => {
+      const cell = document.createElement("td");
+      cell.textContent = value;
+      row.appendChild(cell);
+    });
+    fragment.appendChild(row);
+  });
+  modelTableBody.appendChild(fragment);
+}
+
+// Monthly Spend Chart
+if (stats.usage_by_month?.length > 0) {
+  const months = [...stats.usage_by_month].reverse();
+  new Chart(document.getElementById("monthlyChart"), {
+    type: "bar",
+    data: {
+      labels: months.map((m) => m.month),
+      datasets: [
+        {
+          label: "Cost ($)",
+          data: months.map((m) => m.cost),
+          backgroundColor: colors.tuna,
+          borderRadius: 4,
+        },
+      ],
+    },
+    options: {
+      responsive: true,
+      maintainAspectRatio: false,
+      animation: { duration: easeDuration, easing: easeType },
+      plugins: {
+        legend: { display: false },
+        tooltip: {
+          callbacks: { label: (ctx) => formatCost(ctx.raw) },
+        },
+      },
+    },
+  });
+}
+
+// Projects Table
+if (!selectedProject && report.projects?.length > 0) {
+  const projectsTableBody = document.querySelector("#projects-table tbody");
+  const fragment = document.createDocumentFragment();
+  report.projects.forEach((p) => {
+    const row = document.createElement("tr");
+    const share =
+      report.total.total_cost > 0
+        ? p.stats.total.total_cost / report.total.total_cost
+        : 0;
+    [
+      p.path,
+      formatNumber(p.stats.total.total_sessions),
+      formatNumber(p.stats.total.total_messages),
+      formatCompact(p.stats.total.total_tokens),
+      formatCost(p.stats.total.total_cost),
+      `${(share * 100).toFixed(1)}%`,
+    ].forEach((value) => {
+      const cell = document.createElement("td");
+      cell.textContent = value;
+      row.appendChild(cell);
+    });
+    fragment.appendChild(row);
+  });
+  projectsTableBody.appendChild(fragment);
+  document.getElementById("projects-card").hidden = false;
+}
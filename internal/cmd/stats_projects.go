@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/projects"
+)
+
+// ProjectStats holds the statistics of a single project, when gathering the
+// statistics of all projects.
+type ProjectStats struct {
+	Path  string `json:"path"`
+	Stats *Stats `json:"stats"`
+}
+
+// MonthlyUsage is the usage of a month, e.g. "2026-01".
+type MonthlyUsage struct {
+	Month            string  `json:"month"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	Cost             float64 `json:"cost"`
+	SessionCount     int64   `json:"session_count"`
+}
+
+// gatherProjectsStats gathers the statistics of every registered project
+// with a database, and aggregates them. Projects whose database can't be
+// read are skipped with a warning written to w.
+func gatherProjectsStats(ctx context.Context, w io.Writer, since time.Time) (*Stats, error) {
+	list, err := projects.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	var perProject []ProjectStats
+	for _, p := range list {
+		dataDir := p.DataDir
+		if !filepath.IsAbs(dataDir) {
+			dataDir = filepath.Join(p.Path, dataDir)
+		}
+		if _, err := os.Stat(filepath.Join(dataDir, "crush.db")); err != nil {
+			slog.Debug("Skipping project without database", "path", p.Path, "error", err)
+			continue
+		}
+		stats, err := gatherProjectStats(ctx, dataDir, since)
+		if err != nil {
+			slog.Warn("Skipping project with unreadable database", "path", p.Path, "error", err)
+			fmt.Fprintf(w, "Skipping project %s: %v\n", p.Path, err)
+			continue
+		}
+		perProject = append(perProject, ProjectStats{Path: p.Path, Stats: stats})
+	}
+
+	// Most expensive projects first.
+	slices.SortStableFunc(perProject, func(a, b ProjectStats) int {
+		return cmp.Compare(b.Stats.Total.TotalCost, a.Stats.Total.TotalCost)
+	})
+	return mergeStats(perProject, since), nil
+}
+
+func gatherDataDirStats(ctx context.Context, dataDir string, since time.Time) (*Stats, error) {
+	conn, err := db.Connect(ctx, dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer conn.Close()
+	return gatherStats(ctx, conn, since)
+}
+
+// gatherProjectStats gathers the statistics of another project. Its
+// database is opened read-only: it is never modified, and older databases
+// are migrated in a temporary copy.
+func gatherProjectStats(ctx context.Context, dataDir string, since time.Time) (*Stats, error) {
+	conn, closeConn, err := db.OpenReadOnly(ctx, dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer closeConn()
+	return gatherStats(ctx, conn, since)
+}
+
+// mergeStats aggregates the statistics of the given projects.
+func mergeStats(perProject []ProjectStats, since time.Time) *Stats {
+	merged := &Stats{
+		GeneratedAt: time.Now(),
+		Projects:    perProject,
+	}
+	if !since.IsZero() {
+		merged.Since = &since
+	}
+
+	days := make(map[string]*DailyUsage)
+	models := make(map[[2]string]*ModelUsage)
+	modelDays := make(map[[3]string]*DailyModelUsage)
+	hours := make(map[int]*HourlyUsage)
+	weekdays := make(map[int]*DayOfWeekUsage)
+	recent := make(map[string]*DailyActivity)
+	tools := make(map[string]*ToolUsage)
+	heatmap := make(map[[2]int]*HourDayHeatmapPt)
+	var responseTime float64
+	var responses int64
+
+	for _, p := range perProject {
+		s := p.Stats
+		merged.Total.TotalSessions += s.Total.TotalSessions
+		merged.Total.TotalPromptTokens += s.Total.TotalPromptTokens
+		merged.Total.TotalCompletionTokens += s.Total.TotalCompletionTokens
+		merged.Total.TotalTokens += s.Total.TotalTokens
+		merged.Total.TotalCost += s.Total.TotalCost
+		merged.Total.TotalMessages += s.Total.TotalMessages
+
+		for _, d := range s.UsageByDay {
+			m := upsert(days, d.Day, func() *DailyUsage { return &DailyUsage{Day: d.Day} })
+			m.PromptTokens += d.PromptTokens
+			m.CompletionTokens += d.CompletionTokens
+			m.TotalTokens += d.TotalTokens
+			m.Cost += d.Cost
+			m.SessionCount += d.SessionCount
+		}
+		var assistantMessages int64
+		for _, u := range s.UsageByModel {
+			m := upsert(models, [2]string{u.Model, u.Provider}, func() *ModelUsage {
+				return &ModelUsage{Model: u.Model, Provider: u.Provider}
+			})
+			addModelUsage(m, u)
+			assistantMessages += u.MessageCount
+		}
+		for _, u := range s.UsageByModelAndDay {
+			m := upsert(modelDays, [3]string{u.Day, u.Model, u.Provider}, func() *DailyModelUsage {
+				return &DailyModelUsage{Day: u.Day, ModelUsage: ModelUsage{Model: u.Model, Provider: u.Provider}}
+			})
+			addModelUsage(&m.ModelUsage, u.ModelUsage)
+		}
+		for _, h := range s.UsageByHour {
+			upsert(hours, h.Hour, func() *HourlyUsage { return &HourlyUsage{Hour: h.Hour} }).SessionCount += h.SessionCount
+		}
+		for _, d := range s.UsageByDayOfWeek {
+			m := upsert(weekdays, d.DayOfWeek, func() *DayOfWeekUsage {
+				return &DayOfWeekUsage{DayOfWeek: d.DayOfWeek, DayName: d.DayName}
+			})
+			m.SessionCount += d.SessionCount
+			m.PromptTokens += d.PromptTokens
+			m.CompletionTokens += d.CompletionTokens
+		}
+		for _, d := range s.RecentActivity {
+			m := upsert(recent, d.Day, func() *DailyActivity { return &DailyActivity{Day: d.Day} })
+			m.SessionCount += d.SessionCount
+			m.TotalTokens += d.TotalTokens
+			m.Cost += d.Cost
+		}
+		for _, t := range s.ToolUsage {
+			upsert(tools, t.ToolName, func() *ToolUsage { return &ToolUsage{ToolName: t.ToolName} }).CallCount += t.CallCount
+		}
+		for _, h := range s.HourDayHeatmap {
+			upsert(heatmap, [2]int{h.DayOfWeek, h.Hour}, func() *HourDayHeatmapPt {
+				return &HourDayHeatmapPt{DayOfWeek: h.DayOfWeek, Hour: h.Hour}
+			}).SessionCount += h.SessionCount
+		}
+		// Weigh the average response times by the number of responses.
+		responseTime += s.AvgResponseTimeMs * float64(assistantMessages)
+		responses += assistantMessages
+	}
+
+	if merged.Total.TotalSessions > 0 {
+		merged.Total.AvgTokensPerSession = float64(merged.Total.TotalTokens) / float64(merged.Total.TotalSessions)
+		merged.Total.AvgMessagesPerSession = float64(merged.Total.TotalMessages) / float64(merged.Total.TotalSessions)
+	}
+	if responses > 0 {
+		merged.AvgResponseTimeMs = responseTime / float64(responses)
+	}
+
+	merged.UsageByDay = sortedValues(days, func(a, b DailyUsage) int { return cmp.Compare(b.Day, a.Day) })
+	merged.UsageByModel = sortedValues(models, compareModelUsage)
+	merged.UsageByModelAndDay = sortedValues(modelDays, func(a, b DailyModelUsage) int {
+		return cmp.Or(cmp.Compare(b.Day, a.Day), compareModelUsage(a.ModelUsage, b.ModelUsage))
+	})
+	merged.UsageByHour = sortedValues(hours, func(a, b HourlyUsage) int { return cmp.Compare(a.Hour, b.Hour) })
+	merged.UsageByDayOfWeek = sortedValues(weekdays, func(a, b DayOfWeekUsage) int { return cmp.Compare(a.DayOfWeek, b.DayOfWeek) })
+	merged.RecentActivity = sortedValues(recent, func(a, b DailyActivity) int { return cmp.Compare(a.Day, b.Day) })
+	merged.ToolUsage = sortedValues(tools, func(a, b ToolUsage) int {
+		return cmp.Or(cmp.Compare(b.CallCount, a.CallCount), cmp.Compare(a.ToolName, b.ToolName))
+	})
+	merged.HourDayHeatmap = sortedValues(heatmap, func(a, b HourDayHeatmapPt) int {
+		return cmp.Or(cmp.Compare(a.DayOfWeek, b.DayOfWeek), cmp.Compare(a.Hour, b.Hour))
+	})
+	merged.UsageByMonth = monthlyUsage(merged.UsageByDay)
+	return merged
+}
+
+// monthlyUsage aggregates the daily usage by month, most recent first.
+func monthlyUsage(daily []DailyUsage) []MonthlyUsage {
+	months := []MonthlyUsage{}
+	for _, d := range daily {
+		month := d.Day
+		if len(month) > len("2006-01") {
+			month = month[:len("2006-01")]
+		}
+		if len(months) == 0 || months[len(months)-1].Month != month {
+			months = append(months, MonthlyUsage{Month: month})
+		}
+		m := &months[len(months)-1]
+		m.PromptTokens += d.PromptTokens
+		m.CompletionTokens += d.CompletionTokens
+		m.TotalTokens += d.TotalTokens
+		m.Cost += d.Cost
+		m.SessionCount += d.SessionCount
+	}
+	return months
+}
+
+func addModelUsage(m *ModelUsage, u ModelUsage) {
+	*m = newModelUsage(
+		m.Model, m.Provider, m.MessageCount+u.MessageCount,
+		m.InputTokens+u.InputTokens,
+		m.OutputTokens+u.OutputTokens,
+		m.CacheReadTokens+u.CacheReadTokens,
+		m.CacheCreationTokens+u.CacheCreationTokens,
+		m.Cost+u.Cost,
+	)
+}
+
+func compareModelUsage(a, b ModelUsage) int {
+	return cmp.Or(cmp.Compare(b.Cost, a.Cost), cmp.Compare(b.MessageCount, a.MessageCount), cmp.Compare(a.Model, b.Model))
+}
+
+func upsert[K comparable, V any](m map[K]*V, key K, create func() *V) *V {
+	v, ok := m[key]
+	if !ok {
+		v = create()
+		m[key] = v
+	}
+	return v
+}
+
+func sortedValues[K comparable, V any](m map[K]*V, compare func(a, b V) int) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, *v)
+	}
+	slices.SortFunc(values, compare)
+	return values
+}
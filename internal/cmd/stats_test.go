@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/projects"
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatherStatsByModel(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	conn, err := db.Connect(ctx, t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	q := db.New(conn)
+
+	_, err = q.CreateSession(ctx, db.CreateSessionParams{ID: "s1", Title: "Session"})
+	require.NoError(t, err)
+
+	for i, m := range []struct {
+		model, provider string
+		usage           db.UpdateMessageParams
+	}{
+		{"sonnet", "anthropic", db.UpdateMessageParams{InputTokens: 100, OutputTokens: 50, CacheReadTokens: 300, Cost: 0.5}},
+		{"sonnet", "anthropic", db.UpdateMessageParams{InputTokens: 100, OutputTokens: 50, CacheReadTokens: 100, Cost: 0.25}},
+		{"gpt", "openai", db.UpdateMessageParams{InputTokens: 10, OutputTokens: 5, Cost: 0.01}},
+	} {
+		msg, err := q.CreateMessage(ctx, db.CreateMessageParams{
+			ID:        string(rune('a' + i)),
+			SessionID: "s1",
+			Role:      "assistant",
+			Parts:     "[]",
+			Model:     sql.NullString{String: m.model, Valid: true},
+			Provider:  sql.NullString{String: m.provider, Valid: true},
+		})
+		require.NoError(t, err)
+		m.usage.ID = msg.ID
+		m.usage.Parts = "[]"
+		require.NoError(t, q.UpdateMessage(ctx, m.usage))
+	}
+
+	stats, err := gatherStats(ctx, conn, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, stats.UsageByModel, 2)
+
+	sonnet := stats.UsageByModel[0]
+	require.Equal(t, "sonnet", sonnet.Model)
+	require.Equal(t, int64(2), sonnet.MessageCount)
+	require.Equal(t, int64(200), sonnet.InputTokens)
+	require.Equal(t, int64(400), sonnet.CacheReadTokens)
+	require.InDelta(t, 0.75, sonnet.Cost, 1e-9)
+	require.InDelta(t, 400.0/600.0, sonnet.CacheHitRatio, 1e-9)
+
+	require.Len(t, stats.UsageByModelAndDay, 2)
+	require.Equal(t, time.Now().UTC().Format(time.DateOnly), stats.UsageByModelAndDay[0].Day)
+
+	var b bytes.Buffer
+	require.NoError(t, writeStatsCSV(&b, stats.UsageByModelAndDay))
+	require.Contains(t, b.String(), "day,provider,model,messages,")
+	require.Contains(t, b.String(), ",anthropic,sonnet,2,200,100,400,0,0.6667,0.750000\n")
+
+	later, err := gatherStats(ctx, conn, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, later.UsageByModel)
+	require.Zero(t, later.Total.TotalSessions)
+}
+
+func TestGatherProjectsStats(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	ctx := t.Context()
+
+	valid := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(valid, ".crush"), 0o755))
+	conn, err := db.Connect(ctx, filepath.Join(valid, ".crush"))
+	require.NoError(t, err)
+	_, err = db.New(conn).CreateSession(ctx, db.CreateSessionParams{ID: "s1", Title: "Session"})
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	require.NoError(t, projects.Register(valid, filepath.Join(valid, ".crush")))
+
+	// A database predating the usage columns is migrated in a copy. The
+	// path needs escaping in the database URI.
+	old := filepath.Join(t.TempDir(), "old?#project")
+	require.NoError(t, os.MkdirAll(filepath.Join(old, ".crush"), 0o755))
+	conn, err = db.Connect(ctx, filepath.Join(old, ".crush"))
+	require.NoError(t, err)
+	_, err = db.New(conn).CreateSession(ctx, db.CreateSessionParams{ID: "s1", Title: "Session"})
+	require.NoError(t, err)
+	goose.SetBaseFS(db.FS)
+	require.NoError(t, goose.DownTo(conn, "migrations", 20260127000000))
+	require.NoError(t, conn.Close())
+	require.NoError(t, projects.Register(old, filepath.Join(old, ".crush")))
+	oldDB := filepath.Join(old, ".crush", "crush.db")
+	before, err := os.ReadFile(oldDB)
+	require.NoError(t, err)
+
+	// An empty database can't be read, and must not be migrated.
+	empty := t.TempDir()
+	emptyDB := filepath.Join(empty, ".crush", "crush.db")
+	require.NoError(t, os.MkdirAll(filepath.Dir(emptyDB), 0o755))
+	require.NoError(t, os.WriteFile(emptyDB, nil, 0o644))
+	require.NoError(t, projects.Register(empty, filepath.Join(empty, ".crush")))
+
+	var warnings bytes.Buffer
+	stats, err := gatherProjectsStats(ctx, &warnings, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, stats.Projects, 2)
+	require.ElementsMatch(t, []string{valid, old}, []string{stats.Projects[0].Path, stats.Projects[1].Path})
+	require.Equal(t, int64(2), stats.Total.TotalSessions)
+	require.Contains(t, warnings.String(), "Skipping project "+empty)
+	require.NotContains(t, warnings.String(), "Skipping project "+old)
+
+	after, err := os.ReadFile(oldDB)
+	require.NoError(t, err)
+	require.Equal(t, before, after, "the database must not be migrated")
+
+	info, err := os.Stat(emptyDB)
+	require.NoError(t, err)
+	require.Zero(t, info.Size())
+}
+
+func TestParseSince(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	since, err := parseSince("7d", now)
+	require.NoError(t, err)
+	require.Equal(t, now.AddDate(0, 0, -7), since)
+
+	since, err = parseSince("12h", now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-12*time.Hour), since)
+
+	since, err = parseSince("2026-01-02", now)
+	require.NoError(t, err)
+	require.Equal(t, "2026-01-02", since.Format(time.DateOnly))
+
+	_, err = parseSince("yesterday", now)
+	require.Error(t, err)
+}
+
+func TestMergeStats(t *testing.T) {
+	t.Parallel()
+
+	a := &Stats{
+		Total:             TotalStats{TotalSessions: 1, TotalTokens: 100, TotalCost: 1, TotalMessages: 4},
+		UsageByDay:        []DailyUsage{{Day: "2026-02-01", Cost: 1, SessionCount: 1}},
+		UsageByModel:      []ModelUsage{{Model: "sonnet", Provider: "anthropic", MessageCount: 2, InputTokens: 10, CacheReadTokens: 30, Cost: 1}},
+		ToolUsage:         []ToolUsage{{ToolName: "bash", CallCount: 2}},
+		AvgResponseTimeMs: 1000,
+	}
+	b := &Stats{
+		Total: TotalStats{TotalSessions: 3, TotalTokens: 300, TotalCost: 3, TotalMessages: 8},
+		UsageByDay: []DailyUsage{
+			{Day: "2026-02-01", Cost: 2, SessionCount: 2},
+			{Day: "2026-01-31", Cost: 1, SessionCount: 1},
+		},
+		UsageByModel: []ModelUsage{
+			{Model: "sonnet", Provider: "anthropic", MessageCount: 6, InputTokens: 30, CacheReadTokens: 10, Cost: 2},
+			{Model: "gpt", Provider: "openai", MessageCount: 2, Cost: 1},
+		},
+		ToolUsage:         []ToolUsage{{ToolName: "view", CallCount: 5}, {ToolName: "bash", CallCount: 1}},
+		AvgResponseTimeMs: 2000,
+	}
+
+	merged := mergeStats([]ProjectStats{{Path: "/a", Stats: a}, {Path: "/b", Stats: b}}, time.Time{})
+
+	require.Equal(t, int64(4), merged.Total.TotalSessions)
+	require.Equal(t, 4.0, merged.Total.TotalCost)
+	require.Equal(t, 100.0, merged.Total.AvgTokensPerSession)
+	require.Equal(t, 3.0, merged.Total.AvgMessagesPerSession)
+	require.InDelta(t, 1800.0, merged.AvgResponseTimeMs, 1e-9)
+
+	require.Equal(t, []DailyUsage{
+		{Day: "2026-02-01", Cost: 3, SessionCount: 3},
+		{Day: "2026-01-31", Cost: 1, SessionCount: 1},
+	}, merged.UsageByDay)
+	require.Equal(t, []MonthlyUsage{
+		{Month: "2026-02", Cost: 3, SessionCount: 3},
+		{Month: "2026-01", Cost: 1, SessionCount: 1},
+	}, merged.UsageByMonth)
+
+	require.Len(t, merged.UsageByModel, 2)
+	require.Equal(t, "sonnet", merged.UsageByModel[0].Model)
+	require.Equal(t, int64(8), merged.UsageByModel[0].MessageCount)
+	require.InDelta(t, 0.5, merged.UsageByModel[0].CacheHitRatio, 1e-9)
+
+	require.Equal(t, []ToolUsage{{ToolName: "view", CallCount: 5}, {ToolName: "bash", CallCount: 3}}, merged.ToolUsage)
+	require.Len(t, merged.Projects, 2)
+}
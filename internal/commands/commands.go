@@ -2,15 +2,19 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/crush/internal/agent"
 	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/home"
+	"gopkg.in/yaml.v3"
 )
 
 var namedArgPattern = regexp.MustCompile(`\$([A-Z][A-Z0-9_]*)`)
@@ -20,12 +24,27 @@ const (
 	projectCommandPrefix = "project:"
 )
 
+// Argument types of custom commands.
+const (
+	ArgumentTypeString  = "string"
+	ArgumentTypeNumber  = "number"
+	ArgumentTypeInteger = "integer"
+	ArgumentTypeBoolean = "boolean"
+)
+
 // Argument represents a command argument with its metadata.
 type Argument struct {
 	ID          string
 	Title       string
 	Description string
 	Required    bool
+	// Type is one of the ArgumentType constants, only set for custom
+	// commands. Empty means string.
+	Type string
+	// Default is the value used when the argument is left empty.
+	Default string
+	// Options lists the accepted values, if any.
+	Options []string
 }
 
 // MCPPrompt represents a custom command loaded from an MCP server.
@@ -40,10 +59,45 @@ type MCPPrompt struct {
 
 // CustomCommand represents a user-defined custom command loaded from markdown files.
 type CustomCommand struct {
-	ID        string
-	Name      string
-	Content   string
-	Arguments []Argument
+	ID          string
+	Name        string
+	Description string
+	Content     string
+	Arguments   []Argument
+
+	// Model overrides the model the command runs with: "large", "small", a
+	// model ID or "provider/model".
+	Model string
+	// Agent is the ID of the agent the command runs with.
+	Agent string
+	// AllowedTools restricts the tools the agent can use while running the
+	// command.
+	AllowedTools []string
+}
+
+// frontmatter is the optional YAML header of custom command files.
+type frontmatter struct {
+	Description  string   `yaml:"description"`
+	Model        string   `yaml:"model"`
+	Agent        string   `yaml:"agent"`
+	AllowedTools []string `yaml:"allowed_tools"`
+	Arguments    []struct {
+		Name        string   `yaml:"name"`
+		Description string   `yaml:"description"`
+		Type        string   `yaml:"type"`
+		Options     []string `yaml:"options"`
+		Default     string   `yaml:"default"`
+		Required    *bool    `yaml:"required"`
+	} `yaml:"arguments"`
+}
+
+// RunOptions returns the options the agent runs the command with.
+func (c CustomCommand) RunOptions() agent.RunOptions {
+	return agent.RunOptions{
+		Model:        c.Model,
+		Agent:        c.Agent,
+		AllowedTools: c.AllowedTools,
+	}
 }
 
 type commandSource struct {
@@ -160,13 +214,88 @@ func loadCommand(path, baseDir, prefix string) (CustomCommand, error) {
 	}
 
 	id := buildCommandID(path, baseDir, prefix)
+	return parseCommand(id, string(content))
+}
+
+// parseCommand parses the content of a custom command file, with its
+// optional frontmatter.
+func parseCommand(id, content string) (CustomCommand, error) {
+	cmd := CustomCommand{
+		ID:      id,
+		Name:    id,
+		Content: content,
+	}
 
-	return CustomCommand{
-		ID:        id,
-		Name:      id,
-		Content:   string(content),
-		Arguments: extractArgNames(string(content)),
-	}, nil
+	header, body, ok := splitFrontmatter(content)
+	if !ok {
+		cmd.Arguments = extractArgNames(content)
+		return cmd, nil
+	}
+
+	var fm frontmatter
+	if err := yaml.Unmarshal([]byte(header), &fm); err != nil {
+		return CustomCommand{}, fmt.Errorf("parsing frontmatter of %s: %w", id, err)
+	}
+	cmd.Content = body
+	cmd.Description = fm.Description
+	cmd.Model = fm.Model
+	cmd.Agent = fm.Agent
+	cmd.AllowedTools = fm.AllowedTools
+
+	for _, def := range fm.Arguments {
+		name := strings.ToUpper(strings.TrimPrefix(def.Name, "$"))
+		if !namedArgPattern.MatchString("$" + name) {
+			return CustomCommand{}, fmt.Errorf("invalid argument name %q in %s", def.Name, id)
+		}
+		switch def.Type {
+		case "", ArgumentTypeString, ArgumentTypeNumber, ArgumentTypeInteger, ArgumentTypeBoolean:
+		default:
+			return CustomCommand{}, fmt.Errorf("invalid type %q for argument %s in %s", def.Type, name, id)
+		}
+		arg := Argument{
+			ID:          name,
+			Title:       name,
+			Description: def.Description,
+			Type:        def.Type,
+			Default:     def.Default,
+			Options:     def.Options,
+			// Arguments with a default can be left empty.
+			Required: def.Default == "",
+		}
+		if def.Required != nil {
+			arg.Required = *def.Required
+		}
+		cmd.Arguments = append(cmd.Arguments, arg)
+	}
+
+	// Placeholders not declared in the frontmatter are required strings.
+	for _, arg := range extractArgNames(body) {
+		if !slices.ContainsFunc(cmd.Arguments, func(a Argument) bool { return a.ID == arg.ID }) {
+			cmd.Arguments = append(cmd.Arguments, arg)
+		}
+	}
+	return cmd, nil
+}
+
+// splitFrontmatter splits the YAML frontmatter from the body of a custom
+// command file.
+func splitFrontmatter(content string) (header, body string, ok bool) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	rest, ok := strings.CutPrefix(content, "---\n")
+	if !ok {
+		return "", content, false
+	}
+	header, body, ok = strings.Cut(rest, "\n---")
+	if !ok {
+		return "", content, false
+	}
+	// Drop the rest of the closing delimiter line.
+	if _, after, found := strings.Cut(body, "\n"); found {
+		body = after
+	} else {
+		body = ""
+	}
+	return header, strings.TrimLeft(body, "\n"), true
 }
 
 func extractArgNames(content string) []Argument {
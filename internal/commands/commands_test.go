@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommand(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without frontmatter", func(t *testing.T) {
+		t.Parallel()
+
+		cmd, err := parseCommand("project:fix", "Fix issue $ISSUE in $FILE, see $ISSUE.")
+		require.NoError(t, err)
+		require.Equal(t, "Fix issue $ISSUE in $FILE, see $ISSUE.", cmd.Content)
+		require.Empty(t, cmd.Description)
+		require.Equal(t, []Argument{
+			{ID: "ISSUE", Title: "ISSUE", Required: true},
+			{ID: "FILE", Title: "FILE", Required: true},
+		}, cmd.Arguments)
+	})
+
+	t.Run("with frontmatter", func(t *testing.T) {
+		t.Parallel()
+
+		cmd, err := parseCommand("project:deploy-check", `---
+description: Check the project is ready to deploy
+model: anthropic/claude-sonnet-4
+agent: task
+allowed_tools: [view, grep, bash]
+arguments:
+  - name: env
+    description: Target environment
+    options: [staging, production]
+    default: staging
+  - name: retries
+    type: integer
+    default: 3
+    required: true
+---
+
+Check $ENV with $RETRIES retries for $COMMIT.
+`)
+		require.NoError(t, err)
+		require.Equal(t, "Check the project is ready to deploy", cmd.Description)
+		require.Equal(t, "anthropic/claude-sonnet-4", cmd.Model)
+		require.Equal(t, "task", cmd.Agent)
+		require.Equal(t, []string{"view", "grep", "bash"}, cmd.AllowedTools)
+		require.Equal(t, "Check $ENV with $RETRIES retries for $COMMIT.\n", cmd.Content)
+		require.Equal(t, []Argument{
+			{ID: "ENV", Title: "ENV", Description: "Target environment", Default: "staging", Options: []string{"staging", "production"}},
+			{ID: "RETRIES", Title: "RETRIES", Type: ArgumentTypeInteger, Default: "3", Required: true},
+			{ID: "COMMIT", Title: "COMMIT", Required: true},
+		}, cmd.Arguments)
+
+		opts := cmd.RunOptions()
+		require.Equal(t, "anthropic/claude-sonnet-4", opts.Model)
+		require.Equal(t, "task", opts.Agent)
+		require.Equal(t, []string{"view", "grep", "bash"}, opts.AllowedTools)
+	})
+
+	t.Run("invalid argument type", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseCommand("project:bad", "---\narguments:\n  - name: N\n    type: date\n---\n$N")
+		require.ErrorContains(t, err, `invalid type "date"`)
+	})
+}
+
+func TestResolveArgs(t *testing.T) {
+	t.Parallel()
+
+	cmd := CustomCommand{
+		ID: "project:test",
+		Arguments: []Argument{
+			{ID: "ENV", Default: "staging", Options: []string{"staging", "production"}},
+			{ID: "COUNT", Type: ArgumentTypeInteger, Required: true},
+			{ID: "DRY_RUN", Type: ArgumentTypeBoolean},
+		},
+	}
+
+	args, err := cmd.ResolveArgs(map[string]string{"COUNT": "2"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"ENV": "staging", "COUNT": "2", "DRY_RUN": ""}, args)
+
+	_, err = cmd.ResolveArgs(map[string]string{})
+	require.ErrorContains(t, err, "argument COUNT is required")
+
+	_, err = cmd.ResolveArgs(map[string]string{"COUNT": "two"})
+	require.ErrorContains(t, err, "argument COUNT must be of type integer")
+
+	_, err = cmd.ResolveArgs(map[string]string{"COUNT": "2", "ENV": "dev"})
+	require.ErrorContains(t, err, "argument ENV must be one of staging, production")
+
+	_, err = cmd.ResolveArgs(map[string]string{"COUNT": "2", "DRY_RUN": "maybe"})
+	require.ErrorContains(t, err, "argument DRY_RUN must be of type boolean")
+}
+
+func TestParseArgs(t *testing.T) {
+	t.Parallel()
+
+	single := CustomCommand{Arguments: []Argument{{ID: "QUESTION"}}}
+	args, err := single.ParseArgs("why is the sky blue?")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"QUESTION": "why is the sky blue?"}, args)
+
+	multi := CustomCommand{ID: "project:deploy", Arguments: []Argument{{ID: "ENV"}, {ID: "VERSION"}}}
+	args, err = multi.ParseArgs(`VERSION="1.2 beta" production`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"ENV": "production", "VERSION": "1.2 beta"}, args)
+
+	_, err = multi.ParseArgs("a b c")
+	require.ErrorContains(t, err, "too many arguments")
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("shell interpolation output differs on windows")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.md"), []byte("remember the milk\n"), 0o644))
+
+	cmd := CustomCommand{
+		ID:        "project:test",
+		Content:   "Deploy to $ENV.\nBranch: !`echo main`\nSee @notes.md, and @missing.md or me@example.com.",
+		Arguments: []Argument{{ID: "ENV", Default: "staging"}},
+	}
+
+	prompt, err := cmd.Expand(t.Context(), dir, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Deploy to staging.\nBranch: main\nSee \n<file path='notes.md'>\nremember the milk\n</file>\n, and @missing.md or me@example.com.", prompt)
+
+	failing := CustomCommand{ID: "project:fail", Content: "!`exit 3`"}
+	_, err = failing.Expand(t.Context(), dir, nil)
+	require.Error(t, err)
+
+	blocked := CustomCommand{ID: "project:blocked", Content: "!`sudo ls`"}
+	_, err = blocked.Expand(t.Context(), dir, nil)
+	require.Error(t, err)
+}
+
+func TestExpandArgumentsAreNotInterpolated(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("shell interpolation output differs on windows")
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("hunter2\n"), 0o644))
+
+	cmd := CustomCommand{
+		ID:        "project:echo",
+		Content:   "Value: $VALUE\nEchoed: !`echo $VALUE`\nFile: @$VALUE",
+		Arguments: []Argument{{ID: "VALUE"}},
+	}
+
+	prompt, err := cmd.Expand(t.Context(), dir, map[string]string{"VALUE": "!`touch pwned` $(touch pwned) @secret.txt"})
+	require.NoError(t, err)
+	require.Equal(t, "Value: !`touch pwned` $(touch pwned) @secret.txt\nEchoed: !`touch pwned` $(touch pwned) @secret.txt\nFile: @!`touch pwned` $(touch pwned) @secret.txt", prompt)
+	require.NoFileExists(t, filepath.Join(dir, "pwned"))
+
+	prompt, err = cmd.Expand(t.Context(), dir, map[string]string{"VALUE": "secret.txt"})
+	require.NoError(t, err)
+	require.NotContains(t, prompt, "hunter2")
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	cmds := []CustomCommand{{ID: "user:review"}, {ID: "project:deploy-check"}}
+
+	cmd, ok := Find(cmds, "/project:deploy-check")
+	require.True(t, ok)
+	require.Equal(t, "project:deploy-check", cmd.ID)
+
+	_, ok = Find(cmds, "project:missing")
+	require.False(t, ok)
+}
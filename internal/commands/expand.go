@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/home"
+	"github.com/charmbracelet/crush/internal/shell"
+	shellwords "mvdan.cc/sh/v3/shell"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// MaxIncludedFileSize is the size above which @file references are left
+// as is instead of being included in the prompt.
+const MaxIncludedFileSize = 256 * 1024
+
+// interpolationPattern matches !`command` shell interpolations and @file
+// references preceded by a space or at the start of a line.
+var interpolationPattern = regexp.MustCompile("!`([^`\n]+)`|(?m)(^|[ \t(])@([^\\s]+)")
+
+// Find returns the command with the given ID, with or without a leading
+// slash.
+func Find(cmds []CustomCommand, id string) (CustomCommand, bool) {
+	id = strings.TrimPrefix(id, "/")
+	for _, cmd := range cmds {
+		if cmd.ID == id {
+			return cmd, true
+		}
+	}
+	return CustomCommand{}, false
+}
+
+// ParseArgs parses the arguments given to the command on a single line, as
+// in "/project:deploy-check ENV=staging". Values are matched to arguments
+// by name with NAME=value, or by position otherwise. The whole line is the
+// value of commands with a single argument.
+func (c CustomCommand) ParseArgs(line string) (map[string]string, error) {
+	args := make(map[string]string)
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return args, nil
+	}
+	if len(c.Arguments) == 1 && !strings.HasPrefix(line, c.Arguments[0].ID+"=") {
+		args[c.Arguments[0].ID] = line
+		return args, nil
+	}
+
+	fields, err := shellwords.Fields(line, func(string) string { return "" })
+	if err != nil {
+		return nil, fmt.Errorf("parsing arguments: %w", err)
+	}
+	var position int
+	for _, field := range fields {
+		if name, value, ok := strings.Cut(field, "="); ok && c.hasArgument(name) {
+			args[name] = value
+			continue
+		}
+		for position < len(c.Arguments) && args[c.Arguments[position].ID] != "" {
+			position++
+		}
+		if position == len(c.Arguments) {
+			return nil, fmt.Errorf("too many arguments for %s", c.ID)
+		}
+		args[c.Arguments[position].ID] = field
+		position++
+	}
+	return args, nil
+}
+
+func (c CustomCommand) hasArgument(name string) bool {
+	return slices.ContainsFunc(c.Arguments, func(arg Argument) bool {
+		return arg.ID == name
+	})
+}
+
+// ResolveArgs applies the defaults of the arguments of the command to the
+// given values and validates them.
+func (c CustomCommand) ResolveArgs(args map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(c.Arguments))
+	for _, arg := range c.Arguments {
+		value := strings.TrimSpace(args[arg.ID])
+		if value == "" {
+			value = arg.Default
+		}
+		if value == "" {
+			if arg.Required {
+				return nil, fmt.Errorf("argument %s is required", arg.ID)
+			}
+			resolved[arg.ID] = ""
+			continue
+		}
+		if err := arg.validate(value); err != nil {
+			return nil, err
+		}
+		resolved[arg.ID] = value
+	}
+	return resolved, nil
+}
+
+func (a Argument) validate(value string) error {
+	if len(a.Options) > 0 && !slices.Contains(a.Options, value) {
+		return fmt.Errorf("argument %s must be one of %s, got %q", a.ID, strings.Join(a.Options, ", "), value)
+	}
+	var err error
+	switch a.Type {
+	case ArgumentTypeNumber:
+		_, err = strconv.ParseFloat(value, 64)
+	case ArgumentTypeInteger:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case ArgumentTypeBoolean:
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return fmt.Errorf("argument %s must be of type %s, got %q", a.ID, a.Type, value)
+	}
+	return nil
+}
+
+// Expand returns the prompt of the command for the given arguments. The
+// defaults of the arguments are applied and their values validated, then
+// !`command` interpolations of the template are replaced with the output of
+// the command run in workingDir, @file references with the content of the
+// file, and placeholders with the values of the arguments.
+//
+// Values are only substituted in the template, so they are never run or read
+// themselves: they are shell-quoted in commands and left out of @file
+// references.
+func (c CustomCommand) Expand(ctx context.Context, workingDir string, args map[string]string) (string, error) {
+	resolved, err := c.ResolveArgs(args)
+	if err != nil {
+		return "", err
+	}
+	substitute := func(s string, quote bool) (string, error) {
+		var err error
+		s = namedArgPattern.ReplaceAllStringFunc(s, func(placeholder string) string {
+			value, ok := resolved[placeholder[1:]]
+			if !ok {
+				return placeholder
+			}
+			if !quote {
+				return value
+			}
+			quoted, qerr := syntax.Quote(value, syntax.LangBash)
+			if qerr != nil && err == nil {
+				err = fmt.Errorf("quoting argument %s: %w", placeholder[1:], qerr)
+			}
+			return quoted
+		})
+		return s, err
+	}
+
+	// Expand in a single pass, so command outputs and file contents are not
+	// interpolated in turn.
+	content := c.Content
+	var sb strings.Builder
+	var last int
+	sh := shell.NewShell(&shell.Options{
+		WorkingDir: workingDir,
+		BlockFuncs: tools.BlockFuncs(),
+	})
+	for _, m := range interpolationPattern.FindAllStringSubmatchIndex(content, -1) {
+		text, _ := substitute(content[last:m[0]], false)
+		sb.WriteString(text)
+		last = m[1]
+
+		if m[2] >= 0 {
+			command, err := substitute(content[m[2]:m[3]], true)
+			if err != nil {
+				return "", err
+			}
+			stdout, stderr, err := sh.Exec(ctx, command)
+			if err != nil {
+				return "", fmt.Errorf("running %q: %w: %s", command, err, strings.TrimSpace(stderr))
+			}
+			sb.WriteString(strings.TrimRight(stdout, "\n"))
+			continue
+		}
+
+		sb.WriteString(content[m[4]:m[5]])
+		ref := content[m[6]:m[7]]
+		included, rest, ok := "", "", false
+		if !namedArgPattern.MatchString(ref) {
+			included, rest, ok = includeFile(workingDir, ref)
+		}
+		if !ok {
+			text, _ := substitute("@"+ref, false)
+			sb.WriteString(text)
+			continue
+		}
+		sb.WriteString(included)
+		sb.WriteString(rest)
+	}
+	text, _ := substitute(content[last:], false)
+	sb.WriteString(text)
+	return sb.String(), nil
+}
+
+// includeFile returns the content of the file referenced by ref, and the
+// trailing punctuation that is not part of the path.
+func includeFile(workingDir, ref string) (string, string, bool) {
+	path, rest := ref, ""
+	for path != "" {
+		if content, ok := readIncludedFile(workingDir, path); ok {
+			return fmt.Sprintf("\n<file path='%s'>\n%s\n</file>\n", path, content), rest, true
+		}
+		trimmed := strings.TrimRight(path, ".,;:!?)'\"")
+		if trimmed == path {
+			break
+		}
+		rest = path[len(trimmed):] + rest
+		path = trimmed
+	}
+	return "", "", false
+}
+
+func readIncludedFile(workingDir, path string) (string, bool) {
+	path = home.Long(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workingDir, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > MaxIncludedFileSize {
+		return "", false
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimRight(string(content), "\n"), true
+}
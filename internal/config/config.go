@@ -9,18 +9,21 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
 	"time"
 
 	"charm.land/catwalk/pkg/catwalk"
+	"github.com/bmatcuk/doublestar/v4"
 	hyperp "github.com/charmbracelet/crush/internal/agent/hyper"
 	"github.com/charmbracelet/crush/internal/csync"
 	"github.com/charmbracelet/crush/internal/env"
 	"github.com/charmbracelet/crush/internal/oauth"
 	"github.com/charmbracelet/crush/internal/oauth/copilot"
 	"github.com/charmbracelet/crush/internal/oauth/hyper"
+	mcpoauth "github.com/charmbracelet/crush/internal/oauth/mcp"
 	"github.com/invopop/jsonschema"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -127,6 +130,9 @@ type ProviderConfig struct {
 
 	// The provider models
 	Models []catwalk.Model `json:"models,omitempty" jsonschema:"description=List of models available from this provider"`
+
+	// Query the models endpoint of an openai-compat provider for its models.
+	DiscoverModels bool `json:"discover_models,omitempty" jsonschema:"description=Discover the models of an OpenAI-compatible provider from its models endpoint (and Ollama's /api/show when available),default=false"`
 }
 
 // ToProvider converts the [ProviderConfig] to a [catwalk.Provider].
@@ -181,8 +187,14 @@ type MCPConfig struct {
 	DisabledTools []string          `json:"disabled_tools,omitempty" jsonschema:"description=List of tools from this MCP server to disable,example=get-library-doc"`
 	Timeout       int               `json:"timeout,omitempty" jsonschema:"description=Timeout in seconds for MCP server connections,default=15,example=30,example=60,example=120"`
 
-	// TODO: maybe make it possible to get the value from the env
 	Headers map[string]string `json:"headers,omitempty" jsonschema:"description=HTTP headers for HTTP/SSE MCP servers"`
+
+	// OAuthToken for HTTP/SSE MCP servers that require OAuth authorization.
+	// It is set by `crush login mcp <name>`.
+	OAuthToken *oauth.Token `json:"oauth,omitempty" jsonschema:"description=OAuth2 token for authorization with HTTP/SSE MCP servers"`
+	// OAuthClient is the client registered with the authorization server of
+	// the MCP server, used to refresh OAuthToken.
+	OAuthClient *mcpoauth.Client `json:"oauth_client,omitempty" jsonschema:"description=OAuth2 client registered with the authorization server of the MCP server"`
 }
 
 type LSPConfig struct {
@@ -196,13 +208,38 @@ type LSPConfig struct {
 	Options     map[string]any    `json:"options,omitempty" jsonschema:"description=LSP server-specific settings passed during initialization"`
 }
 
+// KeyBindings maps the actions of the UI, such as "editor.send_message" or
+// "dialog.session.delete", to the keys triggering them.
+type KeyBindings map[string][]string
+
 type TUIOptions struct {
 	CompactMode bool   `json:"compact_mode,omitempty" jsonschema:"description=Enable compact mode for the TUI interface,default=false"`
 	DiffMode    string `json:"diff_mode,omitempty" jsonschema:"description=Diff mode for the TUI interface,enum=unified,enum=split"`
-	// Here we can add themes later or any TUI related options
-	//
+	Theme       string `json:"theme,omitempty" jsonschema:"description=Color theme of the TUI from the built-in themes or the themes directory,default=charmtone,example=charmtone-light,example=high-contrast"`
+
+	Completions   Completions   `json:"completions,omitzero" jsonschema:"description=Completions UI options"`
+	Notifications Notifications `json:"notifications,omitempty" jsonschema:"description=Notifications about agent events"`
+}
+
+// NotificationMethod defines how notifications are delivered.
+type NotificationMethod string
+
+const (
+	NotificationMethodNone    NotificationMethod = "none"
+	NotificationMethodBell    NotificationMethod = "bell"
+	NotificationMethodOSC9    NotificationMethod = "osc9"
+	NotificationMethodOSC777  NotificationMethod = "osc777"
+	NotificationMethodCommand NotificationMethod = "command"
+)
 
-	Completions Completions `json:"completions,omitzero" jsonschema:"description=Completions UI options"`
+// Notifications defines options for the notifications of the TUI when the
+// agent finishes or needs permission.
+type Notifications struct {
+	Method              NotificationMethod `json:"method,omitempty" jsonschema:"description=How to notify: a terminal bell or OSC 9 or OSC 777 desktop notification or the notify command,enum=none,enum=bell,enum=osc9,enum=osc777,enum=command,default=none"`
+	Command             string             `json:"command,omitempty" jsonschema:"description=Shell command run by the command method with CRUSH_NOTIFY_EVENT and CRUSH_NOTIFY_TITLE and CRUSH_NOTIFY_BODY set,example=notify-send \"$CRUSH_NOTIFY_TITLE\" \"$CRUSH_NOTIFY_BODY\""`
+	AgentFinished       *bool              `json:"agent_finished,omitempty" jsonschema:"description=Notify when the agent finishes,default=true"`
+	PermissionRequested *bool              `json:"permission_requested,omitempty" jsonschema:"description=Notify when a tool needs permission,default=true"`
+	OnlyWhenUnfocused   *bool              `json:"only_when_unfocused,omitempty" jsonschema:"description=Only notify when the terminal is not focused,default=true"`
 }
 
 // Completions defines options for the completions UI.
@@ -246,6 +283,7 @@ func (Attribution) JSONSchemaExtend(schema *jsonschema.Schema) {
 type Options struct {
 	ContextPaths              []string     `json:"context_paths,omitempty" jsonschema:"description=Paths to files containing context information for the AI,example=.cursorrules,example=CRUSH.md"`
 	SkillsPaths               []string     `json:"skills_paths,omitempty" jsonschema:"description=Paths to directories containing Agent Skills (folders with SKILL.md files),example=~/.config/crush/skills,example=./skills"`
+	AdditionalDirs            []string     `json:"additional_dirs,omitempty" jsonschema:"description=Additional directories exposed to MCP servers as roots alongside the working directory,example=../shared,example=~/notes"`
 	TUI                       *TUIOptions  `json:"tui,omitempty" jsonschema:"description=Terminal user interface options"`
 	Debug                     bool         `json:"debug,omitempty" jsonschema:"description=Enable debug logging,default=false"`
 	DebugLSP                  bool         `json:"debug_lsp,omitempty" jsonschema:"description=Enable debug logging for LSP servers,default=false"`
@@ -348,7 +386,9 @@ type Agent struct {
 }
 
 type Tools struct {
-	Ls ToolLs `json:"ls,omitempty"`
+	Ls        ToolLs        `json:"ls,omitempty"`
+	WebSearch ToolWebSearch `json:"web_search,omitempty"`
+	Fetch     ToolFetch     `json:"fetch,omitempty"`
 }
 
 type ToolLs struct {
@@ -360,6 +400,138 @@ func (t ToolLs) Limits() (depth, items int) {
 	return ptrValOr(t.MaxDepth, 0), ptrValOr(t.MaxItems, 0)
 }
 
+// defaultFetchMaxResponseSize is the default maximum size of fetched
+// content (5MB).
+const defaultFetchMaxResponseSize = 5 * 1024 * 1024
+
+// ToolFetch configures the fetch, download and agentic_fetch tools.
+type ToolFetch struct {
+	AllowedDomains  []string `json:"allowed_domains,omitempty" jsonschema:"description=Domains fetched without asking for permission. A pattern also matches subdomains and may contain * wildcards,example=go.dev,example=*.github.io"`
+	DeniedDomains   []string `json:"denied_domains,omitempty" jsonschema:"description=Domains that are never fetched. Takes precedence over allowed_domains,example=internal.example.com"`
+	MaxResponseSize *int64   `json:"max_response_size,omitempty" jsonschema:"description=Maximum size in bytes of fetched content,default=5242880"`
+	Cache           *bool    `json:"cache,omitempty" jsonschema:"description=Cache fetched pages in the data directory and revalidate them with ETag and Last-Modified,default=true"`
+	RespectRobots   *bool    `json:"respect_robots,omitempty" jsonschema:"description=Refuse to fetch pages disallowed by the robots.txt of their site,default=false"`
+}
+
+func (t ToolFetch) MaxSize() int64 {
+	return ptrValOr(t.MaxResponseSize, defaultFetchMaxResponseSize)
+}
+
+func (t ToolFetch) CacheEnabled() bool {
+	return ptrValOr(t.Cache, true)
+}
+
+func (t ToolFetch) RobotsEnabled() bool {
+	return ptrValOr(t.RespectRobots, false)
+}
+
+// WebSearchProvider is the backend of the web_search tool.
+type WebSearchProvider string
+
+const (
+	WebSearchProviderDuckDuckGo WebSearchProvider = "duckduckgo"
+	WebSearchProviderSearXNG    WebSearchProvider = "searxng"
+	WebSearchProviderBrave      WebSearchProvider = "brave"
+	WebSearchProviderTavily     WebSearchProvider = "tavily"
+	WebSearchProviderJSON       WebSearchProvider = "json"
+)
+
+// ToolWebSearch configures the backend of the web_search tool.
+type ToolWebSearch struct {
+	Provider WebSearchProvider `json:"provider,omitempty" jsonschema:"description=Search backend of the web_search tool,enum=duckduckgo,enum=searxng,enum=brave,enum=tavily,enum=json,default=duckduckgo"`
+	BaseURL  string            `json:"base_url,omitempty" jsonschema:"description=URL of the search endpoint. Required for searxng and json,example=https://searx.example.com"`
+	APIKey   string            `json:"api_key,omitempty" jsonschema:"description=API key for brave and tavily. Supports environment variables,example=$BRAVE_API_KEY"`
+	Headers  map[string]string `json:"headers,omitempty" jsonschema:"description=Additional HTTP headers sent with search requests. Values support environment variables"`
+	JSON     WebSearchJSON     `json:"json,omitempty" jsonschema:"description=Request and response mapping for the json provider"`
+}
+
+// WebSearchJSON describes the request and response of a generic JSON search
+// endpoint. Paths are dot-separated keys into the response.
+type WebSearchJSON struct {
+	QueryParam   string `json:"query_param,omitempty" jsonschema:"description=Query parameter carrying the search query,default=q"`
+	LimitParam   string `json:"limit_param,omitempty" jsonschema:"description=Query parameter carrying the maximum number of results,example=limit"`
+	ResultsPath  string `json:"results_path,omitempty" jsonschema:"description=Path to the array of results in the response,default=results,example=data.items"`
+	TitleField   string `json:"title_field,omitempty" jsonschema:"description=Path to the title of a result,default=title"`
+	URLField     string `json:"url_field,omitempty" jsonschema:"description=Path to the URL of a result,default=url"`
+	SnippetField string `json:"snippet_field,omitempty" jsonschema:"description=Path to the snippet of a result,default=snippet"`
+}
+
+const (
+	// defaultVerifyMaxRepairs is how many times failed verifications are sent
+	// back to the agent by default.
+	defaultVerifyMaxRepairs = 3
+
+	// defaultVerifyTimeout is the default timeout of a verify command, in
+	// seconds.
+	defaultVerifyTimeout = 300
+)
+
+// Verify configures the commands checking the changes of the agent at the end
+// of its turns, such as tests and linters.
+type Verify struct {
+	Commands   []VerifyCommand `json:"commands,omitempty" jsonschema:"description=Commands run at the end of an agent turn that changed files"`
+	MaxRepairs *int            `json:"max_repairs,omitempty" jsonschema:"description=Maximum number of times failures are sent back to the agent to fix per prompt. 0 only reports them,default=3,example=5"`
+}
+
+// VerifyCommand is a command verifying the changes of the agent.
+type VerifyCommand struct {
+	Name    string   `json:"name,omitempty" jsonschema:"description=Name shown in the chat. Defaults to the command,example=tests"`
+	Command string   `json:"command" jsonschema:"required,description=Shell command run in the working directory,example=go test ./..."`
+	Files   []string `json:"files,omitempty" jsonschema:"description=Glob patterns of the changed files the command runs for. Runs for any change if empty,example=**/*.go"`
+	Timeout *int     `json:"timeout,omitempty" jsonschema:"description=Timeout of the command in seconds,default=300"`
+}
+
+// MaxRepairsOrDefault returns how many times failed verifications are sent
+// back to the agent.
+func (v Verify) MaxRepairsOrDefault() int {
+	return max(0, ptrValOr(v.MaxRepairs, defaultVerifyMaxRepairs))
+}
+
+// CommandsFor returns the commands to run for the changed files, given
+// relative to the working directory with forward slashes.
+func (v Verify) CommandsFor(files []string) []VerifyCommand {
+	if len(files) == 0 {
+		return nil
+	}
+	var commands []VerifyCommand
+	for _, command := range v.Commands {
+		if command.Matches(files) {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// Matches reports whether the command runs for any of the changed files.
+func (c VerifyCommand) Matches(files []string) bool {
+	if len(c.Files) == 0 {
+		return true
+	}
+	for _, file := range files {
+		for _, pattern := range c.Files {
+			name := file
+			// Patterns without a slash match files in any directory.
+			if !strings.Contains(pattern, "/") {
+				name = path.Base(file)
+			}
+			if ok, _ := doublestar.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DisplayName returns the name of the command, or the command itself.
+func (c VerifyCommand) DisplayName() string {
+	return cmp.Or(c.Name, c.Command)
+}
+
+// TimeoutOrDefault returns the timeout of the command.
+func (c VerifyCommand) TimeoutOrDefault() time.Duration {
+	return time.Duration(ptrValOr(c.Timeout, defaultVerifyTimeout)) * time.Second
+}
+
 // Config holds the configuration for crush.
 type Config struct {
 	Schema string `json:"$schema,omitempty"`
@@ -383,6 +555,10 @@ type Config struct {
 
 	Tools Tools `json:"tools,omitempty" jsonschema:"description=Tool configurations"`
 
+	Verify Verify `json:"verify,omitempty" jsonschema:"description=Commands verifying the changes of the agent at the end of its turns"`
+
+	KeyBindings KeyBindings `json:"keybindings,omitempty" jsonschema:"description=Keys of UI actions by action name; an empty list disables the action,example={\"commands\":[\"ctrl+k\"]}"`
+
 	Agents map[string]Agent `json:"-"`
 
 	// Internal
@@ -466,6 +642,14 @@ func (c *Config) SetCompactMode(enabled bool) error {
 	return c.SetConfigField("options.tui.compact_mode", enabled)
 }
 
+func (c *Config) SetTheme(name string) error {
+	if c.Options == nil {
+		c.Options = &Options{}
+	}
+	c.Options.TUI.Theme = name
+	return c.SetConfigField("options.tui.theme", name)
+}
+
 func (c *Config) Resolve(key string) (string, error) {
 	if c.resolver == nil {
 		return "", fmt.Errorf("no variable resolver configured")
@@ -580,6 +764,24 @@ func (c *Config) RefreshOAuthToken(ctx context.Context, providerID string) error
 	return nil
 }
 
+// SetMCPOAuth persists the OAuth client and token of the given MCP.
+func (c *Config) SetMCPOAuth(name string, client *mcpoauth.Client, token *oauth.Token) error {
+	key := "mcp." + escapeConfigKey(name)
+	if err := cmp.Or(
+		c.SetConfigField(key+".oauth_client", client),
+		c.SetConfigField(key+".oauth", token),
+	); err != nil {
+		return fmt.Errorf("failed to persist oauth token for mcp %s: %w", name, err)
+	}
+	return nil
+}
+
+// escapeConfigKey escapes a config key so it can be used as a single path
+// component with [Config.SetConfigField].
+func escapeConfigKey(key string) string {
+	return strings.NewReplacer(".", `\.`, "*", `\*`, "?", `\?`).Replace(key)
+}
+
 func (c *Config) SetProviderAPIKey(providerID string, apiKey any) error {
 	var providerConfig ProviderConfig
 	var exists bool
@@ -692,18 +894,26 @@ func allToolNames() []string {
 		"agent",
 		"bash",
 		"job_output",
+		"job_input",
 		"job_kill",
 		"download",
 		"edit",
 		"multiedit",
+		"notebook_edit",
 		"lsp_diagnostics",
 		"lsp_references",
 		"lsp_restart",
+		"read_mcp_resource",
 		"fetch",
 		"agentic_fetch",
+		"git_status",
+		"git_diff",
+		"git_log",
+		"git_commit",
 		"glob",
 		"grep",
 		"ls",
+		"skill",
 		"sourcegraph",
 		"todos",
 		"view",
@@ -720,7 +930,7 @@ func resolveAllowedTools(allTools []string, disabledTools []string) []string {
 }
 
 func resolveReadOnlyTools(tools []string) []string {
-	readOnlyTools := []string{"glob", "grep", "ls", "sourcegraph", "view"}
+	readOnlyTools := []string{"git_diff", "git_log", "git_status", "glob", "grep", "ls", "sourcegraph", "view"}
 	// filter to only include tools that are in allowedtools (include mode)
 	return filterSlice(tools, readOnlyTools, true)
 }
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"charm.land/catwalk/pkg/catwalk"
+)
+
+const (
+	// discoverTimeout bounds model discovery so an unreachable local server
+	// doesn't linger.
+	discoverTimeout = 5 * time.Second
+
+	// Used when the server doesn't tell the context window of a model.
+	defaultDiscoveredContextWindow = 32_768
+	// Upper bound of the default max tokens of discovered models, which is
+	// otherwise a quarter of their context window.
+	maxDiscoveredMaxTokens = 8_192
+)
+
+// openAIModel is an entry of the /models endpoint. Besides the ID, servers
+// commonly report the context window of the model in one of these fields.
+type openAIModel struct {
+	ID string `json:"id"`
+	// vLLM
+	MaxModelLen int64 `json:"max_model_len"`
+	// LM Studio, OpenRouter and others
+	ContextLength int64 `json:"context_length"`
+	// llama.cpp
+	Meta struct {
+		NCtxTrain int64 `json:"n_ctx_train"`
+	} `json:"meta"`
+}
+
+// ollamaShow is the response of the /api/show endpoint of Ollama.
+type ollamaShow struct {
+	ModelInfo    map[string]any `json:"model_info"`
+	Capabilities []string       `json:"capabilities"`
+}
+
+// discoveries tracks the discovery of models running in the background.
+var discoveries sync.WaitGroup
+
+// discoveredModels returns the models of an openai-compat provider, merged
+// with the configured ones. The models found last time are used right away,
+// and the returned function refreshes them in the background, so a slow or
+// unreachable server doesn't hold up startup; the server is only waited for
+// when there is nothing else to use.
+func (c *Config) discoveredModels(id, baseURL, apiKey string, headers map[string]string, configured []catwalk.Model) ([]catwalk.Model, func()) {
+	cached, _, err := newCache[[]catwalk.Model](cachePathFor("models-" + id)).Get()
+	if err != nil && len(configured) == 0 {
+		return mergeModels(configured, refreshModels(id, baseURL, apiKey, headers)), nil
+	}
+
+	refresh := func() {
+		discoveries.Go(func() {
+			models := refreshModels(id, baseURL, apiKey, headers)
+			if models == nil {
+				return
+			}
+			providerConfig, ok := c.Providers.Get(id)
+			if !ok {
+				return
+			}
+			providerConfig.Models = mergeModels(configured, models)
+			c.Providers.Set(id, providerConfig)
+		})
+	}
+	return mergeModels(configured, cached), refresh
+}
+
+// refreshModels discovers the models served by a provider and caches them.
+func refreshModels(id, baseURL, apiKey string, headers map[string]string) []catwalk.Model {
+	ctx, cancel := context.WithTimeout(context.Background(), discoverTimeout)
+	defer cancel()
+
+	models, err := discoverModels(ctx, http.DefaultClient, baseURL, apiKey, headers)
+	if err != nil {
+		slog.Warn("Could not discover provider models", "provider", id, "error", err)
+		return nil
+	}
+	if err := newCache[[]catwalk.Model](cachePathFor("models-" + id)).Store(models); err != nil {
+		slog.Warn("Could not cache discovered models", "provider", id, "error", err)
+	}
+	return models
+}
+
+// discoverModels lists the models of an OpenAI-compatible server. When the
+// server is Ollama, their context window and capabilities come from
+// /api/show.
+func discoverModels(ctx context.Context, client *http.Client, baseURL, apiKey string, headers map[string]string) ([]catwalk.Model, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/models", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	setDiscoverHeaders(req, apiKey, headers)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list models: unexpected status %s", resp.Status)
+	}
+
+	var list struct {
+		Data []openAIModel `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode models: %w", err)
+	}
+	if len(list.Data) == 0 {
+		return nil, fmt.Errorf("no models found")
+	}
+
+	models := make([]catwalk.Model, len(list.Data))
+	var wg sync.WaitGroup
+	for i, m := range list.Data {
+		model := catwalk.Model{
+			ID:            m.ID,
+			Name:          m.ID,
+			ContextWindow: max(m.MaxModelLen, m.ContextLength, m.Meta.NCtxTrain),
+		}
+		models[i] = model
+		if model.ContextWindow > 0 {
+			continue
+		}
+		wg.Go(func() {
+			if show, err := showOllamaModel(ctx, client, baseURL, apiKey, headers, m.ID); err == nil {
+				show.apply(&models[i])
+			}
+		})
+	}
+	wg.Wait()
+
+	for i := range models {
+		if models[i].ContextWindow == 0 {
+			models[i].ContextWindow = defaultDiscoveredContextWindow
+		}
+		models[i].DefaultMaxTokens = min(models[i].ContextWindow/4, maxDiscoveredMaxTokens)
+	}
+	slices.SortFunc(models, func(a, b catwalk.Model) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return models, nil
+}
+
+// showOllamaModel asks Ollama for the details of a model. The native API of
+// Ollama lives next to its OpenAI-compatible one, under /api.
+func showOllamaModel(ctx context.Context, client *http.Client, baseURL, apiKey string, headers map[string]string, model string) (ollamaShow, error) {
+	var show ollamaShow
+	body, err := json.Marshal(map[string]string{"model": model})
+	if err != nil {
+		return show, err
+	}
+	root := strings.TrimSuffix(baseURL, "/v1")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, root+"/api/show", bytes.NewReader(body))
+	if err != nil {
+		return show, err
+	}
+	setDiscoverHeaders(req, apiKey, headers)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return show, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return show, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&show)
+	return show, err
+}
+
+// apply sets the context window and capabilities reported by Ollama.
+func (s ollamaShow) apply(model *catwalk.Model) {
+	for k, v := range s.ModelInfo {
+		if n, ok := v.(float64); ok && strings.HasSuffix(k, ".context_length") {
+			model.ContextWindow = int64(n)
+		}
+	}
+	model.SupportsImages = slices.Contains(s.Capabilities, "vision")
+	model.CanReason = slices.Contains(s.Capabilities, "thinking")
+}
+
+func setDiscoverHeaders(req *http.Request, apiKey string, headers map[string]string) {
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+}
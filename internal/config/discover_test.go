@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"charm.land/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/env"
+	"github.com/stretchr/testify/require"
+)
+
+func newOllamaServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/models", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"id": "qwen3:8b"},
+				{"id": "llava:7b"},
+				{"id": "served", "max_model_len": 131072},
+			},
+		})
+	})
+	mux.HandleFunc("POST /api/show", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req.Model {
+		case "qwen3:8b":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"model_info":   map[string]any{"general.architecture": "qwen3", "qwen3.context_length": 40960},
+				"capabilities": []string{"completion", "tools", "thinking"},
+			})
+		case "llava:7b":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"capabilities": []string{"completion", "vision"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDiscoverModels(t *testing.T) {
+	t.Parallel()
+
+	srv := newOllamaServer(t)
+	models, err := discoverModels(t.Context(), srv.Client(), srv.URL+"/v1/", "secret", nil)
+	require.NoError(t, err)
+	require.Equal(t, []catwalk.Model{
+		{
+			ID:               "llava:7b",
+			Name:             "llava:7b",
+			ContextWindow:    defaultDiscoveredContextWindow,
+			DefaultMaxTokens: maxDiscoveredMaxTokens,
+			SupportsImages:   true,
+		},
+		{
+			ID:               "qwen3:8b",
+			Name:             "qwen3:8b",
+			ContextWindow:    40960,
+			DefaultMaxTokens: maxDiscoveredMaxTokens,
+			CanReason:        true,
+		},
+		{
+			ID:               "served",
+			Name:             "served",
+			ContextWindow:    131072,
+			DefaultMaxTokens: maxDiscoveredMaxTokens,
+		},
+	}, models)
+}
+
+func TestDiscoverModels_Error(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := discoverModels(t.Context(), srv.Client(), srv.URL, "", nil)
+	require.Error(t, err)
+}
+
+func TestConfig_configureProvidersDiscoverModels(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	srv := newOllamaServer(t)
+	configure := func() (*Config, ProviderConfig) {
+		cfg := &Config{
+			Providers: csync.NewMapFrom(map[string]ProviderConfig{
+				"ollama": {
+					APIKey:         "secret",
+					BaseURL:        srv.URL + "/v1",
+					DiscoverModels: true,
+					Models: []catwalk.Model{{
+						ID:            "qwen3:8b",
+						Name:          "Qwen 3",
+						ContextWindow: 8192,
+					}},
+				},
+			}),
+		}
+		cfg.setDefaults("/tmp", "")
+		env := env.NewFromMap(map[string]string{})
+		require.NoError(t, cfg.configureProviders(env, NewEnvironmentVariableResolver(env), nil))
+		pc, ok := cfg.Providers.Get("ollama")
+		require.True(t, ok)
+		return cfg, pc
+	}
+
+	// The configured models are used until the models are discovered in the
+	// background.
+	cfg, pc := configure()
+	require.Len(t, pc.Models, 1)
+	discoveries.Wait()
+	pc, ok := cfg.Providers.Get("ollama")
+	require.True(t, ok)
+	require.Len(t, pc.Models, 3)
+	// Configured models take precedence over discovered ones.
+	require.Equal(t, "Qwen 3", pc.Models[0].Name)
+	require.Equal(t, int64(8192), pc.Models[0].ContextWindow)
+
+	// The models discovered last time are used right away, and kept when
+	// the server is down.
+	srv.Close()
+	cfg, pc = configure()
+	require.Len(t, pc.Models, 3)
+	discoveries.Wait()
+	pc, ok = cfg.Providers.Get("ollama")
+	require.True(t, ok)
+	require.Len(t, pc.Models, 3)
+}
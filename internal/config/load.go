@@ -299,7 +299,7 @@ func (c *Config) configureProviders(env env.Env, resolver VariableResolver, know
 			c.Providers.Del(id)
 			continue
 		}
-		if len(providerConfig.Models) == 0 {
+		if len(providerConfig.Models) == 0 && !providerConfig.DiscoverModels {
 			slog.Warn("Skipping custom provider because the provider has no models", "provider", id)
 			c.Providers.Del(id)
 			continue
@@ -324,11 +324,43 @@ func (c *Config) configureProviders(env env.Env, resolver VariableResolver, know
 			providerConfig.ExtraHeaders[k] = resolved
 		}
 
+		if providerConfig.DiscoverModels {
+			if providerConfig.Type == catwalk.TypeOpenAICompat {
+				var refresh func()
+				providerConfig.Models, refresh = c.discoveredModels(id, baseURL, apiKey, providerConfig.ExtraHeaders, providerConfig.Models)
+				if refresh != nil {
+					// Refresh once the provider is configured, so the
+					// refreshed models aren't overwritten.
+					defer refresh()
+				}
+			} else {
+				slog.Warn("Model discovery is only supported by openai-compat providers", "provider", id)
+			}
+			if len(providerConfig.Models) == 0 {
+				slog.Warn("Skipping custom provider because no models were discovered", "provider", id)
+				c.Providers.Del(id)
+				continue
+			}
+		}
+
 		c.Providers.Set(id, providerConfig)
 	}
 	return nil
 }
 
+// mergeModels adds the discovered models to the configured ones, which take
+// precedence.
+func mergeModels(configured, discovered []catwalk.Model) []catwalk.Model {
+	models := slices.Clone(configured)
+	for _, model := range discovered {
+		if slices.ContainsFunc(models, func(m catwalk.Model) bool { return m.ID == model.ID }) {
+			continue
+		}
+		models = append(models, model)
+	}
+	return models
+}
+
 func (c *Config) setDefaults(workingDir, dataDir string) {
 	c.workingDir = workingDir
 	if c.Options == nil {
@@ -727,6 +759,15 @@ func GlobalConfig() string {
 	return filepath.Join(home.Dir(), ".config", appName, fmt.Sprintf("%s.json", appName))
 }
 
+// GlobalThemesDir returns the directory custom themes are loaded from, next
+// to the global config file.
+func GlobalThemesDir() string {
+	if crushThemes := os.Getenv("CRUSH_THEMES_DIR"); crushThemes != "" {
+		return crushThemes
+	}
+	return filepath.Join(filepath.Dir(GlobalConfig()), "themes")
+}
+
 // GlobalConfigData returns the path to the main data directory for the application.
 // this config is used when the app overrides configurations instead of updating the global config.
 func GlobalConfigData() string {
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyCommandsFor(t *testing.T) {
+	t.Parallel()
+
+	verify := Verify{Commands: []VerifyCommand{
+		{Name: "all", Command: "make check"},
+		{Name: "go", Command: "go test ./...", Files: []string{"*.go", "go.mod"}},
+		{Name: "web", Command: "npm test", Files: []string{"web/**/*.ts"}},
+	}}
+	names := func(files ...string) []string {
+		var names []string
+		for _, c := range verify.CommandsFor(files) {
+			names = append(names, c.DisplayName())
+		}
+		return names
+	}
+
+	require.Empty(t, names())
+	require.Equal(t, []string{"all"}, names("README.md"))
+	require.Equal(t, []string{"all", "go"}, names("internal/app/app.go"))
+	require.Equal(t, []string{"all", "go"}, names("go.mod"))
+	require.Equal(t, []string{"all", "web"}, names("web/src/main.ts"))
+	require.Equal(t, []string{"all"}, names("src/web/main.ts"))
+	require.Equal(t, []string{"all", "go", "web"}, names("web/app.ts", "main.go"))
+}
+
+func TestVerifyDefaults(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 3, Verify{}.MaxRepairsOrDefault())
+	zero := 0
+	require.Equal(t, 0, Verify{MaxRepairs: &zero}.MaxRepairsOrDefault())
+
+	command := VerifyCommand{Command: "go vet ./..."}
+	require.Equal(t, "go vet ./...", command.DisplayName())
+	require.Equal(t, 5*time.Minute, command.TimeoutOrDefault())
+	timeout := 30
+	command.Timeout = &timeout
+	require.Equal(t, 30*time.Second, command.TimeoutOrDefault())
+}
@@ -3,9 +3,13 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pressly/goose/v3"
 )
@@ -41,3 +45,80 @@ func Connect(ctx context.Context, dataDir string) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// OpenReadOnly opens an existing SQLite database for reading, e.g. the
+// database of another project. The database is never modified: when it
+// lacks migrations, they are applied to a temporary copy of it instead. The
+// returned function closes the database and removes the copy.
+func OpenReadOnly(ctx context.Context, dataDir string) (*sql.DB, func() error, error) {
+	if dataDir == "" {
+		return nil, nil, fmt.Errorf("data.dir is not set")
+	}
+	db, err := openReadOnlyDB(filepath.Join(dataDir, "crush.db"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	pending, err := pendingMigrations(ctx, db)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	if !pending {
+		return db, db.Close, nil
+	}
+
+	dir, err := os.MkdirTemp("", "crush-db-")
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	_, err = db.ExecContext(ctx, "VACUUM INTO ?", filepath.Join(dir, "crush.db"))
+	db.Close()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("failed to copy database: %w", err)
+	}
+	copied, err := Connect(ctx, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, err
+	}
+	return copied, func() error {
+		return errors.Join(copied.Close(), os.RemoveAll(dir))
+	}, nil
+}
+
+// fileURI returns the URI of the database at the given path, with the
+// given query parameters.
+func fileURI(dbPath string, query url.Values) string {
+	path := filepath.ToSlash(dbPath)
+	if !strings.HasPrefix(path, "/") {
+		// Windows paths, e.g. C:/crush.db.
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path, RawQuery: query.Encode()}).String()
+}
+
+// pendingMigrations tells whether some migrations aren't applied to the
+// database yet.
+func pendingMigrations(ctx context.Context, db *sql.DB) (bool, error) {
+	var version int64
+	if err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied").Scan(&version); err != nil {
+		return false, fmt.Errorf("failed to get database version: %w", err)
+	}
+	goose.SetBaseFS(FS)
+	migrations, err := goose.CollectMigrations("migrations", 0, goose.MaxVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to collect migrations: %w", err)
+	}
+	last, err := migrations.Last()
+	if err != nil {
+		return false, fmt.Errorf("failed to collect migrations: %w", err)
+	}
+	return version < last.Version, nil
+}
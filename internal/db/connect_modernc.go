@@ -22,8 +22,19 @@ func openDB(dbPath string) (*sql.DB, error) {
 	params.Add("_pragma", "secure_delete(on)")
 	params.Add("_pragma", "busy_timeout(5000)")
 
-	dsn := fmt.Sprintf("file:%s?%s", dbPath, params.Encode())
-	db, err := sql.Open("sqlite", dsn)
+	db, err := sql.Open("sqlite", fileURI(dbPath, params))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, nil
+}
+
+func openReadOnlyDB(dbPath string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("mode", "ro")
+	params.Add("_pragma", "busy_timeout(5000)")
+
+	db, err := sql.Open("sqlite", fileURI(dbPath, params))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -5,6 +5,7 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
 
 	"github.com/ncruces/go-sqlite3"
 	"github.com/ncruces/go-sqlite3/driver"
@@ -36,3 +37,16 @@ func openDB(dbPath string) (*sql.DB, error) {
 	}
 	return db, nil
 }
+
+func openReadOnlyDB(dbPath string) (*sql.DB, error) {
+	db, err := driver.Open(fileURI(dbPath, url.Values{"mode": {"ro"}}), func(c *sqlite3.Conn) error {
+		if err := c.Exec("PRAGMA busy_timeout = 5000;"); err != nil {
+			return fmt.Errorf("failed to set busy timeout: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, nil
+}
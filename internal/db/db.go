@@ -90,6 +90,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getUsageByModelStmt, err = db.PrepareContext(ctx, getUsageByModel); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUsageByModel: %w", err)
 	}
+	if q.getUsageByModelAndDayStmt, err = db.PrepareContext(ctx, getUsageByModelAndDay); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUsageByModelAndDay: %w", err)
+	}
+	if q.invalidateFileReadsStmt, err = db.PrepareContext(ctx, invalidateFileReads); err != nil {
+		return nil, fmt.Errorf("error preparing query InvalidateFileReads: %w", err)
+	}
 	if q.listAllUserMessagesStmt, err = db.PrepareContext(ctx, listAllUserMessages); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAllUserMessages: %w", err)
 	}
@@ -241,6 +247,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUsageByModelStmt: %w", cerr)
 		}
 	}
+	if q.getUsageByModelAndDayStmt != nil {
+		if cerr := q.getUsageByModelAndDayStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsageByModelAndDayStmt: %w", cerr)
+		}
+	}
+	if q.invalidateFileReadsStmt != nil {
+		if cerr := q.invalidateFileReadsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing invalidateFileReadsStmt: %w", cerr)
+		}
+	}
 	if q.listAllUserMessagesStmt != nil {
 		if cerr := q.listAllUserMessagesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listAllUserMessagesStmt: %w", cerr)
@@ -362,6 +378,8 @@ type Queries struct {
 	getUsageByDayOfWeekStmt        *sql.Stmt
 	getUsageByHourStmt             *sql.Stmt
 	getUsageByModelStmt            *sql.Stmt
+	getUsageByModelAndDayStmt      *sql.Stmt
+	invalidateFileReadsStmt        *sql.Stmt
 	listAllUserMessagesStmt        *sql.Stmt
 	listFilesByPathStmt            *sql.Stmt
 	listFilesBySessionStmt         *sql.Stmt
@@ -402,6 +420,8 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getUsageByDayOfWeekStmt:        q.getUsageByDayOfWeekStmt,
 		getUsageByHourStmt:             q.getUsageByHourStmt,
 		getUsageByModelStmt:            q.getUsageByModelStmt,
+		getUsageByModelAndDayStmt:      q.getUsageByModelAndDayStmt,
+		invalidateFileReadsStmt:        q.invalidateFileReadsStmt,
 		listAllUserMessagesStmt:        q.listAllUserMessagesStmt,
 		listFilesByPathStmt:            q.listFilesByPathStmt,
 		listFilesBySessionStmt:         q.listFilesBySessionStmt,
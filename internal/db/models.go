@@ -19,16 +19,21 @@ type File struct {
 }
 
 type Message struct {
-	ID               string         `json:"id"`
-	SessionID        string         `json:"session_id"`
-	Role             string         `json:"role"`
-	Parts            string         `json:"parts"`
-	Model            sql.NullString `json:"model"`
-	CreatedAt        int64          `json:"created_at"`
-	UpdatedAt        int64          `json:"updated_at"`
-	FinishedAt       sql.NullInt64  `json:"finished_at"`
-	Provider         sql.NullString `json:"provider"`
-	IsSummaryMessage int64          `json:"is_summary_message"`
+	ID                  string         `json:"id"`
+	SessionID           string         `json:"session_id"`
+	Role                string         `json:"role"`
+	Parts               string         `json:"parts"`
+	Model               sql.NullString `json:"model"`
+	CreatedAt           int64          `json:"created_at"`
+	UpdatedAt           int64          `json:"updated_at"`
+	FinishedAt          sql.NullInt64  `json:"finished_at"`
+	Provider            sql.NullString `json:"provider"`
+	IsSummaryMessage    int64          `json:"is_summary_message"`
+	InputTokens         int64          `json:"input_tokens"`
+	OutputTokens        int64          `json:"output_tokens"`
+	CacheReadTokens     int64          `json:"cache_read_tokens"`
+	CacheCreationTokens int64          `json:"cache_creation_tokens"`
+	Cost                float64        `json:"cost"`
 }
 
 type ReadFile struct {
@@ -17,20 +17,22 @@ type Querier interface {
 	DeleteSession(ctx context.Context, id string) error
 	DeleteSessionFiles(ctx context.Context, sessionID string) error
 	DeleteSessionMessages(ctx context.Context, sessionID string) error
-	GetAverageResponseTime(ctx context.Context) (int64, error)
+	GetAverageResponseTime(ctx context.Context, since int64) (int64, error)
 	GetFile(ctx context.Context, id string) (File, error)
 	GetFileByPathAndSession(ctx context.Context, arg GetFileByPathAndSessionParams) (File, error)
 	GetFileRead(ctx context.Context, arg GetFileReadParams) (ReadFile, error)
-	GetHourDayHeatmap(ctx context.Context) ([]GetHourDayHeatmapRow, error)
+	GetHourDayHeatmap(ctx context.Context, since int64) ([]GetHourDayHeatmapRow, error)
 	GetMessage(ctx context.Context, id string) (Message, error)
-	GetRecentActivity(ctx context.Context) ([]GetRecentActivityRow, error)
+	GetRecentActivity(ctx context.Context, since int64) ([]GetRecentActivityRow, error)
 	GetSessionByID(ctx context.Context, id string) (Session, error)
-	GetToolUsage(ctx context.Context) ([]GetToolUsageRow, error)
-	GetTotalStats(ctx context.Context) (GetTotalStatsRow, error)
-	GetUsageByDay(ctx context.Context) ([]GetUsageByDayRow, error)
-	GetUsageByDayOfWeek(ctx context.Context) ([]GetUsageByDayOfWeekRow, error)
-	GetUsageByHour(ctx context.Context) ([]GetUsageByHourRow, error)
-	GetUsageByModel(ctx context.Context) ([]GetUsageByModelRow, error)
+	GetToolUsage(ctx context.Context, since int64) ([]GetToolUsageRow, error)
+	GetTotalStats(ctx context.Context, since int64) (GetTotalStatsRow, error)
+	GetUsageByDay(ctx context.Context, since int64) ([]GetUsageByDayRow, error)
+	GetUsageByDayOfWeek(ctx context.Context, since int64) ([]GetUsageByDayOfWeekRow, error)
+	GetUsageByHour(ctx context.Context, since int64) ([]GetUsageByHourRow, error)
+	GetUsageByModel(ctx context.Context, since int64) ([]GetUsageByModelRow, error)
+	GetUsageByModelAndDay(ctx context.Context, since int64) ([]GetUsageByModelAndDayRow, error)
+	InvalidateFileReads(ctx context.Context, arg InvalidateFileReadsParams) error
 	ListAllUserMessages(ctx context.Context) ([]Message, error)
 	ListFilesByPath(ctx context.Context, path string) ([]File, error)
 	ListFilesBySession(ctx context.Context, sessionID string) ([]File, error)
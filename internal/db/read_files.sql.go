@@ -30,6 +30,21 @@ func (q *Queries) GetFileRead(ctx context.Context, arg GetFileReadParams) (ReadF
 	return i, err
 }
 
+const invalidateFileReads = `-- name: InvalidateFileReads :exec
+DELETE FROM read_files
+WHERE path = ? AND read_at < ?
+`
+
+type InvalidateFileReadsParams struct {
+	Path   string `json:"path"`
+	ReadAt int64  `json:"read_at"`
+}
+
+func (q *Queries) InvalidateFileReads(ctx context.Context, arg InvalidateFileReadsParams) error {
+	_, err := q.exec(ctx, q.invalidateFileReadsStmt, invalidateFileReads, arg.Path, arg.ReadAt)
+	return err
+}
+
 const recordFileRead = `-- name: RecordFileRead :exec
 INSERT INTO read_files (
     session_id,
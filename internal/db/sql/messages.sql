@@ -30,6 +30,11 @@ UPDATE messages
 SET
     parts = ?,
     finished_at = ?,
+    input_tokens = ?,
+    output_tokens = ?,
+    cache_read_tokens = ?,
+    cache_creation_tokens = ?,
+    cost = ?,
     updated_at = strftime('%s', 'now')
 WHERE id = ?;
 
@@ -13,3 +13,7 @@ INSERT INTO read_files (
 -- name: GetFileRead :one
 SELECT * FROM read_files
 WHERE session_id = ? AND path = ? LIMIT 1;
+
+-- name: InvalidateFileReads :exec
+DELETE FROM read_files
+WHERE path = ? AND read_at < ?;
@@ -7,6 +7,7 @@ SELECT
     COUNT(*) as session_count
 FROM sessions
 WHERE parent_session_id IS NULL
+  AND created_at >= sqlc.arg(since)
 GROUP BY date(created_at, 'unixepoch')
 ORDER BY day DESC;
 
@@ -14,11 +15,34 @@ ORDER BY day DESC;
 SELECT
     COALESCE(model, 'unknown') as model,
     COALESCE(provider, 'unknown') as provider,
-    COUNT(*) as message_count
+    COUNT(*) as message_count,
+    CAST(COALESCE(SUM(input_tokens), 0) AS INTEGER) as input_tokens,
+    CAST(COALESCE(SUM(output_tokens), 0) AS INTEGER) as output_tokens,
+    CAST(COALESCE(SUM(cache_read_tokens), 0) AS INTEGER) as cache_read_tokens,
+    CAST(COALESCE(SUM(cache_creation_tokens), 0) AS INTEGER) as cache_creation_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost
 FROM messages
 WHERE role = 'assistant'
+  AND created_at >= sqlc.arg(since)
 GROUP BY model, provider
-ORDER BY message_count DESC;
+ORDER BY cost DESC, message_count DESC;
+
+-- name: GetUsageByModelAndDay :many
+SELECT
+    CAST(date(created_at, 'unixepoch') AS TEXT) as day,
+    COALESCE(model, 'unknown') as model,
+    COALESCE(provider, 'unknown') as provider,
+    COUNT(*) as message_count,
+    CAST(COALESCE(SUM(input_tokens), 0) AS INTEGER) as input_tokens,
+    CAST(COALESCE(SUM(output_tokens), 0) AS INTEGER) as output_tokens,
+    CAST(COALESCE(SUM(cache_read_tokens), 0) AS INTEGER) as cache_read_tokens,
+    CAST(COALESCE(SUM(cache_creation_tokens), 0) AS INTEGER) as cache_creation_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost
+FROM messages
+WHERE role = 'assistant'
+  AND created_at >= sqlc.arg(since)
+GROUP BY day, model, provider
+ORDER BY day DESC, cost DESC;
 
 -- name: GetUsageByHour :many
 SELECT
@@ -26,6 +50,7 @@ SELECT
     COUNT(*) as session_count
 FROM sessions
 WHERE parent_session_id IS NULL
+  AND created_at >= sqlc.arg(since)
 GROUP BY hour
 ORDER BY hour;
 
@@ -37,6 +62,7 @@ SELECT
     SUM(completion_tokens) as completion_tokens
 FROM sessions
 WHERE parent_session_id IS NULL
+  AND created_at >= sqlc.arg(since)
 GROUP BY day_of_week
 ORDER BY day_of_week;
 
@@ -50,7 +76,8 @@ SELECT
     COALESCE(AVG(prompt_tokens + completion_tokens), 0) as avg_tokens_per_session,
     COALESCE(AVG(message_count), 0) as avg_messages_per_session
 FROM sessions
-WHERE parent_session_id IS NULL;
+WHERE parent_session_id IS NULL
+  AND created_at >= sqlc.arg(since);
 
 -- name: GetRecentActivity :many
 SELECT
@@ -61,6 +88,7 @@ SELECT
 FROM sessions
 WHERE parent_session_id IS NULL
   AND created_at >= strftime('%s', 'now', '-30 days')
+  AND created_at >= sqlc.arg(since)
 GROUP BY date(created_at, 'unixepoch')
 ORDER BY day ASC;
 
@@ -70,7 +98,8 @@ SELECT
 FROM messages
 WHERE role = 'assistant'
   AND finished_at IS NOT NULL
-  AND finished_at > created_at;
+  AND finished_at > created_at
+  AND created_at >= sqlc.arg(since);
 
 -- name: GetToolUsage :many
 SELECT
@@ -79,6 +108,7 @@ SELECT
 FROM messages, json_each(parts)
 WHERE json_extract(value, '$.type') = 'tool_call'
   AND json_extract(value, '$.data.name') IS NOT NULL
+  AND messages.created_at >= sqlc.arg(since)
 GROUP BY tool_name
 ORDER BY call_count DESC;
 
@@ -89,5 +119,6 @@ SELECT
     COUNT(*) as session_count
 FROM sessions
 WHERE parent_session_id IS NULL
+  AND created_at >= sqlc.arg(since)
 GROUP BY day_of_week, hour
 ORDER BY day_of_week, hour;
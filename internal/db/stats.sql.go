@@ -17,10 +17,11 @@ FROM messages
 WHERE role = 'assistant'
   AND finished_at IS NOT NULL
   AND finished_at > created_at
+  AND created_at >= ?
 `
 
-func (q *Queries) GetAverageResponseTime(ctx context.Context) (int64, error) {
-	row := q.queryRow(ctx, q.getAverageResponseTimeStmt, getAverageResponseTime)
+func (q *Queries) GetAverageResponseTime(ctx context.Context, since int64) (int64, error) {
+	row := q.queryRow(ctx, q.getAverageResponseTimeStmt, getAverageResponseTime, since)
 	var avg_response_seconds int64
 	err := row.Scan(&avg_response_seconds)
 	return avg_response_seconds, err
@@ -33,6 +34,7 @@ SELECT
     COUNT(*) as session_count
 FROM sessions
 WHERE parent_session_id IS NULL
+  AND created_at >= ?
 GROUP BY day_of_week, hour
 ORDER BY day_of_week, hour
 `
@@ -43,8 +45,8 @@ type GetHourDayHeatmapRow struct {
 	SessionCount int64 `json:"session_count"`
 }
 
-func (q *Queries) GetHourDayHeatmap(ctx context.Context) ([]GetHourDayHeatmapRow, error) {
-	rows, err := q.query(ctx, q.getHourDayHeatmapStmt, getHourDayHeatmap)
+func (q *Queries) GetHourDayHeatmap(ctx context.Context, since int64) ([]GetHourDayHeatmapRow, error) {
+	rows, err := q.query(ctx, q.getHourDayHeatmapStmt, getHourDayHeatmap, since)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +77,7 @@ SELECT
 FROM sessions
 WHERE parent_session_id IS NULL
   AND created_at >= strftime('%s', 'now', '-30 days')
+  AND created_at >= ?
 GROUP BY date(created_at, 'unixepoch')
 ORDER BY day ASC
 `
@@ -86,8 +89,8 @@ type GetRecentActivityRow struct {
 	Cost         sql.NullFloat64 `json:"cost"`
 }
 
-func (q *Queries) GetRecentActivity(ctx context.Context) ([]GetRecentActivityRow, error) {
-	rows, err := q.query(ctx, q.getRecentActivityStmt, getRecentActivity)
+func (q *Queries) GetRecentActivity(ctx context.Context, since int64) ([]GetRecentActivityRow, error) {
+	rows, err := q.query(ctx, q.getRecentActivityStmt, getRecentActivity, since)
 	if err != nil {
 		return nil, err
 	}
@@ -121,6 +124,7 @@ SELECT
 FROM messages, json_each(parts)
 WHERE json_extract(value, '$.type') = 'tool_call'
   AND json_extract(value, '$.data.name') IS NOT NULL
+  AND messages.created_at >= ?
 GROUP BY tool_name
 ORDER BY call_count DESC
 `
@@ -130,8 +134,8 @@ type GetToolUsageRow struct {
 	CallCount int64       `json:"call_count"`
 }
 
-func (q *Queries) GetToolUsage(ctx context.Context) ([]GetToolUsageRow, error) {
-	rows, err := q.query(ctx, q.getToolUsageStmt, getToolUsage)
+func (q *Queries) GetToolUsage(ctx context.Context, since int64) ([]GetToolUsageRow, error) {
+	rows, err := q.query(ctx, q.getToolUsageStmt, getToolUsage, since)
 	if err != nil {
 		return nil, err
 	}
@@ -164,6 +168,7 @@ SELECT
     COALESCE(AVG(message_count), 0) as avg_messages_per_session
 FROM sessions
 WHERE parent_session_id IS NULL
+  AND created_at >= ?
 `
 
 type GetTotalStatsRow struct {
@@ -176,8 +181,8 @@ type GetTotalStatsRow struct {
 	AvgMessagesPerSession interface{} `json:"avg_messages_per_session"`
 }
 
-func (q *Queries) GetTotalStats(ctx context.Context) (GetTotalStatsRow, error) {
-	row := q.queryRow(ctx, q.getTotalStatsStmt, getTotalStats)
+func (q *Queries) GetTotalStats(ctx context.Context, since int64) (GetTotalStatsRow, error) {
+	row := q.queryRow(ctx, q.getTotalStatsStmt, getTotalStats, since)
 	var i GetTotalStatsRow
 	err := row.Scan(
 		&i.TotalSessions,
@@ -200,6 +205,7 @@ SELECT
     COUNT(*) as session_count
 FROM sessions
 WHERE parent_session_id IS NULL
+  AND created_at >= ?
 GROUP BY date(created_at, 'unixepoch')
 ORDER BY day DESC
 `
@@ -212,8 +218,8 @@ type GetUsageByDayRow struct {
 	SessionCount     int64           `json:"session_count"`
 }
 
-func (q *Queries) GetUsageByDay(ctx context.Context) ([]GetUsageByDayRow, error) {
-	rows, err := q.query(ctx, q.getUsageByDayStmt, getUsageByDay)
+func (q *Queries) GetUsageByDay(ctx context.Context, since int64) ([]GetUsageByDayRow, error) {
+	rows, err := q.query(ctx, q.getUsageByDayStmt, getUsageByDay, since)
 	if err != nil {
 		return nil, err
 	}
@@ -249,6 +255,7 @@ SELECT
     SUM(completion_tokens) as completion_tokens
 FROM sessions
 WHERE parent_session_id IS NULL
+  AND created_at >= ?
 GROUP BY day_of_week
 ORDER BY day_of_week
 `
@@ -260,8 +267,8 @@ type GetUsageByDayOfWeekRow struct {
 	CompletionTokens sql.NullFloat64 `json:"completion_tokens"`
 }
 
-func (q *Queries) GetUsageByDayOfWeek(ctx context.Context) ([]GetUsageByDayOfWeekRow, error) {
-	rows, err := q.query(ctx, q.getUsageByDayOfWeekStmt, getUsageByDayOfWeek)
+func (q *Queries) GetUsageByDayOfWeek(ctx context.Context, since int64) ([]GetUsageByDayOfWeekRow, error) {
+	rows, err := q.query(ctx, q.getUsageByDayOfWeekStmt, getUsageByDayOfWeek, since)
 	if err != nil {
 		return nil, err
 	}
@@ -294,6 +301,7 @@ SELECT
     COUNT(*) as session_count
 FROM sessions
 WHERE parent_session_id IS NULL
+  AND created_at >= ?
 GROUP BY hour
 ORDER BY hour
 `
@@ -303,8 +311,8 @@ type GetUsageByHourRow struct {
 	SessionCount int64 `json:"session_count"`
 }
 
-func (q *Queries) GetUsageByHour(ctx context.Context) ([]GetUsageByHourRow, error) {
-	rows, err := q.query(ctx, q.getUsageByHourStmt, getUsageByHour)
+func (q *Queries) GetUsageByHour(ctx context.Context, since int64) ([]GetUsageByHourRow, error) {
+	rows, err := q.query(ctx, q.getUsageByHourStmt, getUsageByHour, since)
 	if err != nil {
 		return nil, err
 	}
@@ -330,21 +338,32 @@ const getUsageByModel = `-- name: GetUsageByModel :many
 SELECT
     COALESCE(model, 'unknown') as model,
     COALESCE(provider, 'unknown') as provider,
-    COUNT(*) as message_count
+    COUNT(*) as message_count,
+    CAST(COALESCE(SUM(input_tokens), 0) AS INTEGER) as input_tokens,
+    CAST(COALESCE(SUM(output_tokens), 0) AS INTEGER) as output_tokens,
+    CAST(COALESCE(SUM(cache_read_tokens), 0) AS INTEGER) as cache_read_tokens,
+    CAST(COALESCE(SUM(cache_creation_tokens), 0) AS INTEGER) as cache_creation_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost
 FROM messages
 WHERE role = 'assistant'
+  AND created_at >= ?
 GROUP BY model, provider
-ORDER BY message_count DESC
+ORDER BY cost DESC, message_count DESC
 `
 
 type GetUsageByModelRow struct {
-	Model        string `json:"model"`
-	Provider     string `json:"provider"`
-	MessageCount int64  `json:"message_count"`
+	Model               string  `json:"model"`
+	Provider            string  `json:"provider"`
+	MessageCount        int64   `json:"message_count"`
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	Cost                float64 `json:"cost"`
 }
 
-func (q *Queries) GetUsageByModel(ctx context.Context) ([]GetUsageByModelRow, error) {
-	rows, err := q.query(ctx, q.getUsageByModelStmt, getUsageByModel)
+func (q *Queries) GetUsageByModel(ctx context.Context, since int64) ([]GetUsageByModelRow, error) {
+	rows, err := q.query(ctx, q.getUsageByModelStmt, getUsageByModel, since)
 	if err != nil {
 		return nil, err
 	}
@@ -352,7 +371,79 @@ func (q *Queries) GetUsageByModel(ctx context.Context) ([]GetUsageByModelRow, er
 	items := []GetUsageByModelRow{}
 	for rows.Next() {
 		var i GetUsageByModelRow
-		if err := rows.Scan(&i.Model, &i.Provider, &i.MessageCount); err != nil {
+		if err := rows.Scan(
+			&i.Model,
+			&i.Provider,
+			&i.MessageCount,
+			&i.InputTokens,
+			&i.OutputTokens,
+			&i.CacheReadTokens,
+			&i.CacheCreationTokens,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsageByModelAndDay = `-- name: GetUsageByModelAndDay :many
+SELECT
+    CAST(date(created_at, 'unixepoch') AS TEXT) as day,
+    COALESCE(model, 'unknown') as model,
+    COALESCE(provider, 'unknown') as provider,
+    COUNT(*) as message_count,
+    CAST(COALESCE(SUM(input_tokens), 0) AS INTEGER) as input_tokens,
+    CAST(COALESCE(SUM(output_tokens), 0) AS INTEGER) as output_tokens,
+    CAST(COALESCE(SUM(cache_read_tokens), 0) AS INTEGER) as cache_read_tokens,
+    CAST(COALESCE(SUM(cache_creation_tokens), 0) AS INTEGER) as cache_creation_tokens,
+    CAST(COALESCE(SUM(cost), 0) AS REAL) as cost
+FROM messages
+WHERE role = 'assistant'
+  AND created_at >= ?
+GROUP BY day, model, provider
+ORDER BY day DESC, cost DESC
+`
+
+type GetUsageByModelAndDayRow struct {
+	Day                 string  `json:"day"`
+	Model               string  `json:"model"`
+	Provider            string  `json:"provider"`
+	MessageCount        int64   `json:"message_count"`
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	Cost                float64 `json:"cost"`
+}
+
+func (q *Queries) GetUsageByModelAndDay(ctx context.Context, since int64) ([]GetUsageByModelAndDayRow, error) {
+	rows, err := q.query(ctx, q.getUsageByModelAndDayStmt, getUsageByModelAndDay, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUsageByModelAndDayRow{}
+	for rows.Next() {
+		var i GetUsageByModelAndDayRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.Model,
+			&i.Provider,
+			&i.MessageCount,
+			&i.InputTokens,
+			&i.OutputTokens,
+			&i.CacheReadTokens,
+			&i.CacheCreationTokens,
+			&i.Cost,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -0,0 +1,238 @@
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+)
+
+// Heuristics for laying out text, in units of the current font size. The
+// advance of glyphs whose width isn't known is estimated with glyphWidth.
+const (
+	glyphWidth    = 0.5
+	wordGap       = 0.15
+	paragraphGap  = 1.8
+	lineTolerance = 0.5
+	// TJ adjustments are in thousandths of the font size, and ones below
+	// kerningSpace separate words.
+	kerningUnits = 1000.0
+	kerningSpace = -200
+)
+
+// textState tracks the text position while interpreting a content stream.
+type textState struct {
+	font    *fontDecoder
+	size    float64
+	scale   float64
+	leading float64
+	// lineX and lineY are the start of the current line in text space.
+	lineX, lineY float64
+	// x and y are where the last run of text started, and shown is the
+	// advance since then.
+	x, y       float64
+	shown      float64
+	positioned bool
+}
+
+// runContent interprets the text operators of a content stream, writing the
+// shown text to w.
+func (d *pdfDocument) runContent(data []byte, resources map[string]any, w *textWriter, depth int) {
+	if depth > maxTreeDepth {
+		return
+	}
+	st := &textState{font: defaultFont, size: 1, scale: 1}
+	l := &lexer{data: data}
+	var operands []any
+	for {
+		v, err := l.value()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			operands = operands[:0]
+			continue
+		}
+		op, ok := v.(pdfKeyword)
+		if !ok {
+			operands = append(operands, v)
+			continue
+		}
+		nums := numbers(operands)
+		switch op {
+		case "BT":
+			st.lineX, st.lineY, st.scale = 0, 0, 1
+		case "Tf":
+			if len(operands) == 2 {
+				if name, ok := operands[0].(pdfName); ok {
+					st.font = d.font(resources, name)
+				}
+				if size, ok := operands[1].(float64); ok && size != 0 {
+					st.size = math.Abs(size)
+				}
+			}
+		case "TL":
+			if len(nums) == 1 {
+				st.leading = nums[0]
+			}
+		case "Tm":
+			if len(nums) == 6 {
+				if scale := math.Hypot(nums[0], nums[1]); scale > 0 {
+					st.scale = scale
+				}
+				st.lineX, st.lineY = nums[4], nums[5]
+				st.moveTo(w)
+			}
+		case "Td", "TD":
+			if len(nums) == 2 {
+				if op == "TD" {
+					st.leading = -nums[1]
+				}
+				st.lineX += nums[0] * st.scale
+				st.lineY += nums[1] * st.scale
+				st.moveTo(w)
+			}
+		case "T*":
+			st.nextLine(w)
+		case "Tj":
+			if len(operands) == 1 {
+				st.show(w, operands[0])
+			}
+		case "'":
+			if len(operands) == 1 {
+				st.nextLine(w)
+				st.show(w, operands[0])
+			}
+		case `"`:
+			if len(operands) == 3 {
+				st.nextLine(w)
+				st.show(w, operands[2])
+			}
+		case "TJ":
+			if len(operands) == 1 {
+				arr, _ := operands[0].([]any)
+				for _, item := range arr {
+					if n, ok := item.(float64); ok {
+						if n < kerningSpace {
+							w.space()
+						}
+						st.shown -= n / kerningUnits * st.fontSize()
+						continue
+					}
+					st.show(w, item)
+				}
+			}
+		case "Do":
+			if len(operands) == 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					d.runForm(resources, name, w, depth)
+				}
+			}
+		case "BI":
+			skipInlineImage(l)
+		}
+		operands = operands[:0]
+	}
+}
+
+// runForm interprets the content of a form XObject.
+func (d *pdfDocument) runForm(resources map[string]any, name pdfName, w *textWriter, depth int) {
+	obj := d.object(d.dict(resources["XObject"])[string(name)])
+	if obj == nil || obj.stream == nil {
+		return
+	}
+	dict, _ := obj.value.(map[string]any)
+	if dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := d.decodeStream(obj)
+	if err != nil {
+		return
+	}
+	formResources := d.dict(dict["Resources"])
+	if formResources == nil {
+		formResources = resources
+	}
+	// The form's text positions are unrelated to the page's, so its text
+	// starts on a line of its own.
+	w.newline()
+	d.runContent(data, formResources, w, depth+1)
+}
+
+func (st *textState) show(w *textWriter, v any) {
+	s, ok := v.([]byte)
+	if !ok {
+		return
+	}
+	text, advance := st.font.decode(s)
+	w.text(text)
+	st.shown += advance * st.fontSize()
+}
+
+func (st *textState) nextLine(w *textWriter) {
+	st.lineY -= st.leading * st.scale
+	w.newline()
+	st.x, st.y, st.shown, st.positioned = st.lineX, st.lineY, 0, true
+}
+
+// moveTo starts a run of text at the start of the current line, separating
+// it from the previous run by a newline or a space depending on where they
+// are.
+func (st *textState) moveTo(w *textWriter) {
+	x, y := st.lineX, st.lineY
+	if st.positioned {
+		size := st.fontSize()
+		switch dy := math.Abs(y - st.y); {
+		case dy > paragraphGap*size:
+			w.newline()
+			w.newline()
+		case dy > lineTolerance*size:
+			w.newline()
+		default:
+			end := st.x + st.shown
+			if x-end > wordGap*size || x < st.x {
+				w.space()
+			}
+		}
+	}
+	st.x, st.y, st.shown, st.positioned = x, y, 0, true
+}
+
+func (st *textState) fontSize() float64 {
+	return math.Max(st.size*st.scale, 1)
+}
+
+func numbers(operands []any) []float64 {
+	nums := make([]float64, 0, len(operands))
+	for _, v := range operands {
+		n, ok := v.(float64)
+		if !ok {
+			return nil
+		}
+		nums = append(nums, n)
+	}
+	return nums
+}
+
+// skipInlineImage skips the dictionary and binary data of an inline image,
+// up to and including its EI operator.
+func skipInlineImage(l *lexer) {
+	i := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if i < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos = min(l.pos+i+len("ID")+1, len(l.data))
+	for {
+		j := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if j < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		start, end := l.pos+j, l.pos+j+len("EI")
+		l.pos = end
+		if start > 0 && isPDFSpace(l.data[start-1]) && (end >= len(l.data) || isPDFSpace(l.data[end]) || isPDFDelimiter(l.data[end])) {
+			return
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxDOCXDocumentSize bounds the uncompressed size of the document part of a
+// DOCX file.
+const maxDOCXDocumentSize = 64 * 1024 * 1024
+
+// DOCXText extracts the text of a DOCX document, one paragraph per line.
+// Headings are prefixed with #, list items with - and table cells are
+// separated by |.
+func DOCXText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("not a DOCX file: %w", err)
+	}
+	f, err := zr.Open("word/document.xml")
+	if err != nil {
+		return "", errors.New("not a DOCX file: word/document.xml is missing")
+	}
+	defer f.Close()
+	return docxText(io.LimitReader(f, maxDOCXDocumentSize))
+}
+
+func docxText(r io.Reader) (string, error) {
+	var (
+		out       strings.Builder
+		para      strings.Builder
+		prefix    string
+		inText    bool
+		cellDepth int
+		row       []string
+	)
+	endParagraph := func() {
+		text := strings.TrimRight(para.String(), " ")
+		para.Reset()
+		if cellDepth > 0 {
+			// Paragraphs of a table cell are joined on one line.
+			if n := len(row) - 1; n >= 0 && text != "" {
+				if row[n] != "" {
+					row[n] += " "
+				}
+				row[n] += prefix + text
+			}
+		} else {
+			out.WriteString(prefix + text)
+			out.WriteByte('\n')
+		}
+		prefix = ""
+	}
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading DOCX document: %w", err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				para.WriteByte('\t')
+			case "br", "cr":
+				para.WriteByte('\n')
+			case "pStyle":
+				prefix = headingPrefix(attr(tok, "val"))
+			case "numPr":
+				if prefix == "" {
+					prefix = "- "
+				}
+			case "tc":
+				cellDepth++
+				row = append(row, "")
+			}
+		case xml.EndElement:
+			switch tok.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				endParagraph()
+			case "tc":
+				cellDepth--
+			case "tr":
+				out.WriteString("| " + strings.Join(row, " | ") + " |\n")
+				row = row[:0]
+			case "tbl":
+				out.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				para.Write(tok)
+			}
+		}
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// headingPrefix returns the Markdown prefix of a paragraph style.
+func headingPrefix(style string) string {
+	if style == "Title" {
+		return "# "
+	}
+	if level, ok := strings.CutPrefix(style, "Heading"); ok {
+		if n, err := strconv.Atoi(level); err == nil && n > 0 && n <= 6 {
+			return strings.Repeat("#", n) + " "
+		}
+	}
+	return ""
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
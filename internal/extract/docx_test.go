@@ -0,0 +1,49 @@
+package extract
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDocument = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Report</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Hello, </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>world</w:t></w:r><w:r><w:tab/><w:t>tabbed</w:t><w:br/><w:t>broken</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>An item</w:t></w:r></w:p>
+<w:tbl>
+<w:tr><w:tc><w:p><w:r><w:t>Name</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Value</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>a</w:t></w:r></w:p><w:p><w:r><w:t>b</w:t></w:r></w:p></w:tc><w:tc><w:p/></w:tc></w:tr>
+</w:tbl>
+<w:p><w:r><w:delText>deleted</w:delText><w:t>After</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+
+func TestDOCXText(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("word/document.xml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte(testDocument))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	text, err := DOCXText(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, `# Report
+Hello, world	tabbed
+broken
+- An item
+| Name | Value |
+| a b |  |
+
+After`, text)
+
+	_, err = DOCXText([]byte("not a zip"))
+	require.ErrorContains(t, err, "not a DOCX file")
+}
@@ -0,0 +1,370 @@
+package extract
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// maxCMapRange bounds the number of codes a single bfrange expands to.
+	maxCMapRange = 1 << 16
+	// courierWidth is the width of the glyphs of the standard Courier fonts.
+	courierWidth = 600
+)
+
+// fontDecoder maps the character codes of a font's strings to text.
+type fontDecoder struct {
+	// toUnicode maps codes, as raw bytes, to text.
+	toUnicode map[string]string
+	// codeLens are the lengths of the codes in toUnicode, shortest first.
+	codeLens []int
+	// composite is set for Type0 fonts, whose codes are two bytes long.
+	composite bool
+	// simple maps single byte codes of simple fonts to runes.
+	simple *[256]rune
+	// widths are the advances of codes in thousandths of the font size,
+	// and defaultWidth the advance of codes not in widths.
+	widths       map[int]float64
+	defaultWidth float64
+}
+
+var defaultFont = &fontDecoder{simple: &winAnsi, defaultWidth: glyphWidth * 1000}
+
+// font returns the decoder of a font resource, caching it per font object.
+func (d *pdfDocument) font(resources map[string]any, name pdfName) *fontDecoder {
+	ref := d.dict(resources["Font"])[string(name)]
+	if r, ok := ref.(pdfRef); ok {
+		if f, ok := d.fonts[r]; ok {
+			return f
+		}
+		f := d.newFont(d.dict(r))
+		d.fonts[r] = f
+		return f
+	}
+	return d.newFont(d.dict(ref))
+}
+
+func (d *pdfDocument) newFont(dict map[string]any) *fontDecoder {
+	if dict == nil {
+		return defaultFont
+	}
+	f := &fontDecoder{
+		composite:    dict["Subtype"] == pdfName("Type0"),
+		simple:       &winAnsi,
+		defaultWidth: glyphWidth * 1000,
+	}
+	d.loadWidths(f, dict)
+	if enc := d.dict(dict["Encoding"]); enc != nil {
+		if diffs, ok := d.resolve(enc["Differences"]).([]any); ok {
+			f.simple = applyDifferences(diffs)
+		}
+	}
+	if obj := d.object(dict["ToUnicode"]); obj != nil && obj.stream != nil {
+		if data, err := d.decodeStream(obj); err == nil {
+			f.toUnicode, f.codeLens = parseCMap(data)
+		}
+	}
+	return f
+}
+
+// loadWidths loads the glyph widths of a simple font, or of the descendant
+// font of a Type0 font.
+func (d *pdfDocument) loadWidths(f *fontDecoder, dict map[string]any) {
+	f.widths = make(map[int]float64)
+	if base, _ := d.resolve(dict["BaseFont"]).(pdfName); strings.Contains(string(base), "Courier") {
+		// The standard monospaced fonts often come without widths.
+		f.defaultWidth = courierWidth
+	}
+	if !f.composite {
+		first, _ := d.resolve(dict["FirstChar"]).(float64)
+		widths, _ := d.resolve(dict["Widths"]).([]any)
+		for i, w := range widths {
+			if w, ok := d.resolve(w).(float64); ok {
+				f.widths[int(first)+i] = w
+			}
+		}
+		return
+	}
+	descendants, _ := d.resolve(dict["DescendantFonts"]).([]any)
+	if len(descendants) == 0 {
+		return
+	}
+	cid := d.dict(descendants[0])
+	f.defaultWidth = 1000
+	if dw, ok := d.resolve(cid["DW"]).(float64); ok {
+		f.defaultWidth = dw
+	}
+	// W holds either "first [w1 w2 ...]" or "first last w" entries.
+	w, _ := d.resolve(cid["W"]).([]any)
+	for i := 0; i+1 < len(w); {
+		first, ok := d.resolve(w[i]).(float64)
+		if !ok {
+			return
+		}
+		if list, ok := d.resolve(w[i+1]).([]any); ok {
+			for j, v := range list {
+				if v, ok := d.resolve(v).(float64); ok {
+					f.widths[int(first)+j] = v
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			return
+		}
+		last, ok1 := d.resolve(w[i+1]).(float64)
+		width, ok2 := d.resolve(w[i+2]).(float64)
+		if !ok1 || !ok2 || last-first >= maxCMapRange {
+			return
+		}
+		for c := int(first); c <= int(last); c++ {
+			f.widths[c] = width
+		}
+		i += 3
+	}
+}
+
+func (f *fontDecoder) width(code int) float64 {
+	if w, ok := f.widths[code]; ok && w > 0 {
+		return w
+	}
+	return f.defaultWidth
+}
+
+// decode returns the text of a string shown with the font, and its advance
+// in units of the font size.
+func (f *fontDecoder) decode(s []byte) (string, float64) {
+	var b strings.Builder
+	advance := 0.0
+	for i := 0; i < len(s); {
+		if f.composite && i+1 < len(s) {
+			advance += f.width(int(s[i])<<8|int(s[i+1])) / 1000
+		} else if !f.composite {
+			advance += f.width(int(s[i])) / 1000
+		}
+		if n, text, ok := f.lookup(s[i:]); ok {
+			b.WriteString(text)
+			i += n
+			continue
+		}
+		if f.composite {
+			// A CID without a Unicode mapping can't be decoded.
+			i += 2
+			continue
+		}
+		if r := f.simple[s[i]]; r != 0 {
+			b.WriteRune(r)
+		}
+		i++
+	}
+	return b.String(), advance
+}
+
+func (f *fontDecoder) lookup(s []byte) (int, string, bool) {
+	for _, n := range f.codeLens {
+		if n > len(s) {
+			break
+		}
+		if text, ok := f.toUnicode[string(s[:n])]; ok {
+			return n, text, true
+		}
+	}
+	return 0, "", false
+}
+
+// parseCMap parses the bfchar and bfrange mappings of a ToUnicode CMap.
+func parseCMap(data []byte) (map[string]string, []int) {
+	m := make(map[string]string)
+	var lens []int
+	addLen := func(n int) {
+		if n > 0 && !slices.Contains(lens, n) {
+			lens = append(lens, n)
+		}
+	}
+
+	l := &lexer{data: data}
+	var operands []any
+	for {
+		v, err := l.value()
+		if errors.Is(err, errUnexpectedDelimiter) {
+			continue
+		}
+		if err != nil {
+			break
+		}
+		kw, ok := v.(pdfKeyword)
+		if !ok {
+			operands = append(operands, v)
+			continue
+		}
+		switch kw {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				if lo, ok := operands[i].([]byte); ok {
+					addLen(len(lo))
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].([]byte)
+				dst, ok2 := operands[i+1].([]byte)
+				if ok1 && ok2 {
+					m[string(src)] = decodeUTF16BE(dst)
+					addLen(len(src))
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].([]byte)
+				hi, ok2 := operands[i+1].([]byte)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) > 4 {
+					continue
+				}
+				addRange(m, lo, hi, operands[i+2])
+				addLen(len(lo))
+			}
+		}
+		operands = operands[:0]
+	}
+	slices.Sort(lens)
+	return m, lens
+}
+
+func addRange(m map[string]string, lo, hi []byte, dst any) {
+	start, end := beUint(lo), beUint(hi)
+	if end < start || end-start >= maxCMapRange {
+		return
+	}
+	key := make([]byte, len(lo))
+	for code := start; code <= end; code++ {
+		offset := int(code - start)
+		for i := range key {
+			key[i] = byte(code >> (8 * (len(key) - 1 - i)))
+		}
+		switch dst := dst.(type) {
+		case []byte:
+			if len(dst) == 0 {
+				return
+			}
+			// Increment the last UTF-16 code unit of the destination.
+			text := []byte(string(dst))
+			last := len(text) - 1
+			unit := int(text[last]) + offset
+			if last > 0 {
+				unit += int(text[last-1]) << 8
+				text[last-1] = byte(unit >> 8)
+			}
+			text[last] = byte(unit)
+			m[string(key)] = decodeUTF16BE(text)
+		case []any:
+			if offset >= len(dst) {
+				return
+			}
+			if text, ok := dst[offset].([]byte); ok {
+				m[string(key)] = decodeUTF16BE(text)
+			}
+		}
+	}
+}
+
+func beUint(b []byte) uint32 {
+	var n uint32
+	for _, c := range b {
+		n = n<<8 | uint32(c)
+	}
+	return n
+}
+
+func decodeUTF16BE(b []byte) string {
+	if len(b)%2 == 1 {
+		return string(b)
+	}
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+// applyDifferences returns the WinAnsi encoding changed by the Differences
+// array of a font encoding.
+func applyDifferences(diffs []any) *[256]rune {
+	table := winAnsi
+	code := 0
+	for _, v := range diffs {
+		switch v := v.(type) {
+		case float64:
+			code = int(v)
+		case pdfName:
+			if code >= 0 && code < len(table) {
+				if r, ok := glyphRune(string(v)); ok {
+					table[code] = r
+				}
+			}
+			code++
+		}
+	}
+	return &table
+}
+
+// glyphRune returns the rune of a glyph name: single characters, uniXXXX
+// names and a few common names.
+func glyphRune(name string) (rune, bool) {
+	if r := []rune(name); len(r) == 1 {
+		return r[0], true
+	}
+	if hex, ok := strings.CutPrefix(name, "uni"); ok && len(hex) == 4 {
+		if v, err := strconv.ParseUint(hex, 16, 16); err == nil {
+			return rune(v), true
+		}
+	}
+	r, ok := glyphNames[name]
+	return r, ok
+}
+
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#',
+	"dollar": '$', "percent": '%', "ampersand": '&', "quotesingle": '\'',
+	"parenleft": '(', "parenright": ')', "asterisk": '*', "plus": '+',
+	"comma": ',', "hyphen": '-', "period": '.', "slash": '/',
+	"zero": '0', "one": '1', "two": '2', "three": '3', "four": '4',
+	"five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9',
+	"colon": ':', "semicolon": ';', "less": '<', "equal": '=',
+	"greater": '>', "question": '?', "at": '@', "bracketleft": '[',
+	"backslash": '\\', "bracketright": ']', "asciicircum": '^',
+	"underscore": '_', "grave": '`', "braceleft": '{', "bar": '|',
+	"braceright": '}', "asciitilde": '~', "bullet": '•', "endash": '–',
+	"emdash": '—', "quoteleft": '‘', "quoteright": '’', "quotedblleft": '“',
+	"quotedblright": '”', "ellipsis": '…', "fi": 'ﬁ', "fl": 'ﬂ',
+	"ff": 'ﬀ', "ffi": 'ﬃ', "ffl": 'ﬄ', "copyright": '©', "registered": '®',
+	"trademark": '™', "degree": '°', "minus": '−', "multiply": '×',
+	"divide": '÷', "section": '§', "paragraph": '¶', "dagger": '†',
+	"daggerdbl": '‡', "Euro": '€', "nbspace": ' ',
+}
+
+// winAnsi is the WinAnsiEncoding of simple fonts, which is also used for
+// fonts without a known encoding. Control codes map to 0 and are dropped.
+var winAnsi = func() [256]rune {
+	var t [256]rune
+	for i := 0x20; i < 0x7f; i++ {
+		t[i] = rune(i)
+	}
+	t['\t'], t['\n'], t['\r'] = ' ', ' ', ' '
+	for i := 0xa0; i <= 0xff; i++ {
+		t[i] = rune(i)
+	}
+	for code, r := range map[int]rune{
+		0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†',
+		0x87: '‡', 0x88: 'ˆ', 0x89: '‰', 0x8a: 'Š', 0x8b: '‹', 0x8c: 'Œ',
+		0x8e: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•',
+		0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9a: 'š', 0x9b: '›',
+		0x9c: 'œ', 0x9e: 'ž', 0x9f: 'Ÿ',
+	} {
+		t[code] = r
+	}
+	return t
+}()
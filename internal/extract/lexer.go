@@ -0,0 +1,316 @@
+package extract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// PDF values are decoded as:
+//   - null as nil, booleans as bool and numbers as float64;
+//   - strings as []byte, names as pdfName, arrays as []any and
+//     dictionaries as map[string]any keyed by name;
+//   - indirect references as pdfRef, and operators and other bare keywords
+//     as pdfKeyword.
+type (
+	pdfName    string
+	pdfKeyword string
+	pdfRef     struct{ num, gen int }
+)
+
+var errUnexpectedDelimiter = errors.New("unexpected delimiter")
+
+// lexer reads PDF values from a buffer.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// value reads the next value. It returns [io.EOF] at the end of the buffer,
+// and [errUnexpectedDelimiter] at a closing delimiter, which it skips.
+func (l *lexer) value() (any, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		return l.name(), nil
+	case c == '(':
+		return l.literalString(), nil
+	case c == '<' && l.peek(1) == '<':
+		return l.dict()
+	case c == '<':
+		return l.hexString(), nil
+	case c == '[':
+		return l.array()
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		l.pos++
+		if c == '>' && l.peek(0) == '>' {
+			l.pos++
+		}
+		return nil, errUnexpectedDelimiter
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.number()
+	default:
+		word := l.regular()
+		switch word {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return pdfKeyword(word), nil
+	}
+}
+
+func (l *lexer) peek(n int) byte {
+	if l.pos+n < len(l.data) {
+		return l.data[l.pos+n]
+	}
+	return 0
+}
+
+// regular reads a run of regular characters.
+func (l *lexer) regular() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		// Not a regular character, e.g. a stray delimiter: skip it.
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+func (l *lexer) name() pdfName {
+	l.pos++ // /
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	raw := l.data[start:l.pos]
+	if bytes.IndexByte(raw, '#') < 0 {
+		return pdfName(raw)
+	}
+	var b []byte
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			if v, err := strconv.ParseUint(string(raw[i+1:i+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				i += 2
+				continue
+			}
+		}
+		b = append(b, raw[i])
+	}
+	return pdfName(b)
+}
+
+func (l *lexer) number() (any, error) {
+	start := l.pos
+	word := l.regular()
+	n, err := strconv.ParseFloat(word, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q", word)
+	}
+	// An indirect reference is two integers followed by R.
+	if bytes.ContainsAny(l.data[start:l.pos], ".+-") {
+		return n, nil
+	}
+	save := l.pos
+	l.skipSpace()
+	genStart := l.pos
+	for l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+		l.pos++
+	}
+	if l.pos > genStart && l.pos < len(l.data) && isPDFSpace(l.data[l.pos]) {
+		gen, _ := strconv.Atoi(string(l.data[genStart:l.pos]))
+		l.skipSpace()
+		if l.peek(0) == 'R' && (l.pos+1 >= len(l.data) || isPDFSpace(l.peek(1)) || isPDFDelimiter(l.peek(1))) {
+			l.pos++
+			return pdfRef{num: int(n), gen: gen}, nil
+		}
+	}
+	l.pos = save
+	return n, nil
+}
+
+func (l *lexer) literalString() []byte {
+	l.pos++ // (
+	var b []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return b
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return b
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// Line continuation.
+				if l.peek(0) == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+func (l *lexer) hexString() []byte {
+	l.pos++ // <
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	if l.pos < len(l.data) {
+		l.pos++ // >
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	b := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			continue
+		}
+		b = append(b, byte(v))
+	}
+	return b
+}
+
+func (l *lexer) array() ([]any, error) {
+	l.pos++ // [
+	var arr []any
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return arr, io.ErrUnexpectedEOF
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return arr, nil
+		}
+		v, err := l.value()
+		if errors.Is(err, errUnexpectedDelimiter) {
+			continue
+		}
+		if err != nil {
+			return arr, err
+		}
+		arr = append(arr, v)
+	}
+}
+
+func (l *lexer) dict() (map[string]any, error) {
+	l.pos += 2 // <<
+	d := make(map[string]any)
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return d, io.ErrUnexpectedEOF
+		}
+		if l.data[l.pos] == '>' && l.peek(1) == '>' {
+			l.pos += 2
+			return d, nil
+		}
+		key, err := l.value()
+		if errors.Is(err, errUnexpectedDelimiter) {
+			continue
+		}
+		if err != nil {
+			return d, err
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			continue
+		}
+		v, err := l.value()
+		if errors.Is(err, errUnexpectedDelimiter) {
+			continue
+		}
+		if err != nil {
+			return d, err
+		}
+		d[string(name)] = v
+	}
+}
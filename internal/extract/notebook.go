@@ -0,0 +1,161 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxOutputSize is the number of characters of a cell output that are
+// rendered.
+const maxOutputSize = 4000
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// Notebook is a Jupyter notebook, with the fields needed to render it.
+type Notebook struct {
+	Cells    []NotebookCell `json:"cells"`
+	Metadata struct {
+		KernelSpec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+// NotebookCell is a cell of a Jupyter notebook.
+type NotebookCell struct {
+	ID             string           `json:"id,omitempty"`
+	CellType       string           `json:"cell_type"`
+	Source         MultilineString  `json:"source"`
+	ExecutionCount *int             `json:"execution_count,omitempty"`
+	Outputs        []NotebookOutput `json:"outputs,omitempty"`
+}
+
+// NotebookOutput is an output of a code cell.
+type NotebookOutput struct {
+	OutputType string                     `json:"output_type"`
+	Name       string                     `json:"name,omitempty"`
+	Text       MultilineString            `json:"text,omitempty"`
+	Data       map[string]json.RawMessage `json:"data,omitempty"`
+	EName      string                     `json:"ename,omitempty"`
+	EValue     string                     `json:"evalue,omitempty"`
+	Traceback  []string                   `json:"traceback,omitempty"`
+}
+
+// MultilineString is a notebook string, which is stored either as a string
+// or as a list of lines.
+type MultilineString string
+
+func (s *MultilineString) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*s = MultilineString(strings.Join(lines, ""))
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*s = MultilineString(str)
+	return nil
+}
+
+// ParseNotebook parses a Jupyter notebook.
+func ParseNotebook(data []byte) (*Notebook, error) {
+	var nb Notebook
+	if err := json.Unmarshal(data, &nb); err != nil {
+		return nil, fmt.Errorf("invalid notebook: %w", err)
+	}
+	return &nb, nil
+}
+
+// Language returns the programming language of the notebook's code cells.
+func (nb *Notebook) Language() string {
+	if nb.Metadata.LanguageInfo.Name != "" {
+		return nb.Metadata.LanguageInfo.Name
+	}
+	if nb.Metadata.KernelSpec.Language != "" {
+		return nb.Metadata.KernelSpec.Language
+	}
+	return "python"
+}
+
+// RenderCell renders a cell with its outputs as text.
+func (nb *Notebook) RenderCell(index int) string {
+	cell := nb.Cells[index]
+	var b strings.Builder
+	fmt.Fprintf(&b, "<cell index=\"%d\" type=%q", index, cell.CellType)
+	if cell.ID != "" {
+		fmt.Fprintf(&b, " id=%q", cell.ID)
+	}
+	if cell.CellType == "code" {
+		fmt.Fprintf(&b, " language=%q", nb.Language())
+		if cell.ExecutionCount != nil {
+			fmt.Fprintf(&b, " execution_count=\"%d\"", *cell.ExecutionCount)
+		}
+	}
+	b.WriteString(">\n")
+	if source := strings.TrimRight(string(cell.Source), "\n"); source != "" {
+		b.WriteString(source)
+		b.WriteByte('\n')
+	}
+	for _, out := range cell.Outputs {
+		renderOutput(&b, out)
+	}
+	b.WriteString("</cell>")
+	return b.String()
+}
+
+func renderOutput(b *strings.Builder, out NotebookOutput) {
+	var text string
+	switch out.OutputType {
+	case "stream":
+		fmt.Fprintf(b, "<output type=\"stream\" name=%q>\n", out.Name)
+		text = string(out.Text)
+	case "error":
+		b.WriteString("<output type=\"error\">\n")
+		text = out.EName + ": " + out.EValue
+		if len(out.Traceback) > 0 {
+			text = strings.Join(out.Traceback, "\n")
+		}
+	default:
+		fmt.Fprintf(b, "<output type=%q>\n", out.OutputType)
+		text = renderData(out.Data)
+	}
+	text = strings.TrimRight(ansiEscape.ReplaceAllString(text, ""), "\n")
+	if len(text) > maxOutputSize {
+		text = fmt.Sprintf("%s\n[... %d more characters]", text[:maxOutputSize], len(text)-maxOutputSize)
+	}
+	if text != "" {
+		b.WriteString(text)
+		b.WriteByte('\n')
+	}
+	b.WriteString("</output>\n")
+}
+
+// renderData renders the richest textual representation of rich output, and
+// notes the representations that can't be shown as text.
+func renderData(data map[string]json.RawMessage) string {
+	for _, mime := range []string{"text/markdown", "text/plain"} {
+		if raw, ok := data[mime]; ok {
+			var s MultilineString
+			if err := json.Unmarshal(raw, &s); err == nil {
+				return string(s)
+			}
+		}
+	}
+	var omitted []string
+	for mime := range data {
+		omitted = append(omitted, mime)
+	}
+	if len(omitted) == 0 {
+		return ""
+	}
+	slices.Sort(omitted)
+	return fmt.Sprintf("[%s output omitted]", strings.Join(omitted, ", "))
+}
@@ -0,0 +1,62 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testNotebook = `{
+ "cells": [
+  {"cell_type": "markdown", "id": "intro", "metadata": {}, "source": ["# Title\n", "Some text"]},
+  {
+   "cell_type": "code", "id": "c1", "execution_count": 2, "metadata": {},
+   "source": "print('hi')\nx",
+   "outputs": [
+    {"output_type": "stream", "name": "stdout", "text": ["hi\n"]},
+    {"output_type": "execute_result", "execution_count": 2, "metadata": {}, "data": {"text/plain": ["42"]}},
+    {"output_type": "display_data", "metadata": {}, "data": {"image/png": "iVBORw0KGgo=", "text/html": "<b>x</b>"}},
+    {"output_type": "error", "ename": "ValueError", "evalue": "bad", "traceback": ["\u001b[0;31mValueError\u001b[0m: bad"]}
+   ]
+  },
+  {"cell_type": "code", "metadata": {}, "source": [], "execution_count": null, "outputs": []}
+ ],
+ "metadata": {"kernelspec": {"language": "python", "name": "python3"}, "language_info": {"name": "python"}},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+
+func TestNotebook(t *testing.T) {
+	t.Parallel()
+
+	nb, err := ParseNotebook([]byte(testNotebook))
+	require.NoError(t, err)
+	require.Len(t, nb.Cells, 3)
+	require.Equal(t, "python", nb.Language())
+
+	require.Equal(t, `<cell index="0" type="markdown" id="intro">
+# Title
+Some text
+</cell>`, nb.RenderCell(0))
+	require.Equal(t, `<cell index="1" type="code" id="c1" language="python" execution_count="2">
+print('hi')
+x
+<output type="stream" name="stdout">
+hi
+</output>
+<output type="execute_result">
+42
+</output>
+<output type="display_data">
+[image/png, text/html output omitted]
+</output>
+<output type="error">
+ValueError: bad
+</output>
+</cell>`, nb.RenderCell(1))
+	require.Equal(t, `<cell index="2" type="code" language="python">
+</cell>`, nb.RenderCell(2))
+
+	_, err = ParseNotebook([]byte("{"))
+	require.ErrorContains(t, err, "invalid notebook")
+}
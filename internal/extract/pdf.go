@@ -0,0 +1,433 @@
+// Package extract extracts text from PDF, DOCX and Jupyter notebook files.
+// It's pure Go and reads what's commonly found in these formats, without
+// aiming to handle every file: PDF text is laid out heuristically, and
+// encrypted PDFs aren't supported.
+package extract
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrEncrypted is returned for encrypted PDFs, which can't be read.
+var ErrEncrypted = errors.New("encrypted PDFs are not supported")
+
+const (
+	// maxResolveDepth bounds chains of indirect references.
+	maxResolveDepth = 16
+	// maxTreeDepth bounds the depth of page trees and nested form XObjects.
+	maxTreeDepth = 32
+	// maxStreamSize bounds the decompressed size of a stream.
+	maxStreamSize = 64 * 1024 * 1024
+)
+
+var errStreamTooLarge = errors.New("PDF stream too large")
+
+var objectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+type pdfObject struct {
+	value  any
+	stream []byte // raw, still encoded stream data
+}
+
+// pdfDocument is a PDF whose objects have been located by scanning the file
+// rather than through its cross-reference table, which makes reading
+// tolerant of damaged or incrementally updated files.
+type pdfDocument struct {
+	objects  map[int]*pdfObject
+	trailers []map[string]any
+	fonts    map[pdfRef]*fontDecoder
+}
+
+// PDFPages extracts the text of each page of a PDF.
+func PDFPages(data []byte) (texts []string, err error) {
+	// The parser is tolerant, but damaged files must not bring Crush down.
+	defer func() {
+		if r := recover(); r != nil {
+			texts, err = nil, fmt.Errorf("failed to parse PDF: %v", r)
+		}
+	}()
+	return pdfPages(data)
+}
+
+func pdfPages(data []byte) ([]string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("%PDF-")) {
+		return nil, errors.New("not a PDF file")
+	}
+	doc := parsePDF(data)
+	if doc.encrypted() {
+		return nil, ErrEncrypted
+	}
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return nil, errors.New("no pages found in PDF")
+	}
+	texts := make([]string, len(pages))
+	for i, page := range pages {
+		texts[i] = doc.pageText(page)
+	}
+	return texts, nil
+}
+
+func parsePDF(data []byte) *pdfDocument {
+	doc := &pdfDocument{
+		objects: make(map[int]*pdfObject),
+		fonts:   make(map[pdfRef]*fontDecoder),
+	}
+	end := 0
+	for _, m := range objectHeader.FindAllSubmatchIndex(data, -1) {
+		// Skip matches inside the stream data of earlier objects.
+		if m[0] < end {
+			continue
+		}
+		num := atoi(data[m[2]:m[3]])
+		l := &lexer{data: data, pos: m[1]}
+		value, err := l.value()
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			continue
+		}
+		obj := &pdfObject{value: value}
+		l.skipSpace()
+		if dict, ok := value.(map[string]any); ok && bytes.HasPrefix(data[l.pos:], []byte("stream")) {
+			obj.stream, l.pos = streamData(data, l.pos+len("stream"), dict["Length"])
+		}
+		end = l.pos
+		// Later definitions of an object replace earlier ones, as in
+		// incremental updates.
+		doc.objects[num] = obj
+	}
+	for i := bytes.Index(data, []byte("trailer")); i >= 0; {
+		l := &lexer{data: data, pos: i + len("trailer")}
+		if trailer, err := l.value(); err == nil {
+			if dict, isDict := trailer.(map[string]any); isDict {
+				doc.trailers = append(doc.trailers, dict)
+			}
+		}
+		next := bytes.Index(data[i+1:], []byte("trailer"))
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	doc.loadObjectStreams()
+	return doc
+}
+
+// streamData returns the data of a stream starting after its stream
+// keyword, and the position after it.
+func streamData(data []byte, start int, length any) ([]byte, int) {
+	if bytes.HasPrefix(data[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(data) && (data[start] == '\n' || data[start] == '\r') {
+		start++
+	}
+	// Trust a direct length if endstream follows it.
+	if n, ok := length.(float64); ok && n >= 0 && start+int(n) <= len(data) {
+		stop := start + int(n)
+		if bytes.HasPrefix(bytes.TrimLeft(data[stop:], "\r\n \t"), []byte("endstream")) {
+			return data[start:stop], stop
+		}
+	}
+	i := bytes.Index(data[start:], []byte("endstream"))
+	if i < 0 {
+		return data[start:], len(data)
+	}
+	stop := start + i
+	return bytes.TrimSuffix(bytes.TrimSuffix(data[start:stop], []byte("\n")), []byte("\r")), stop
+}
+
+// loadObjectStreams adds the objects stored in object streams.
+func (d *pdfDocument) loadObjectStreams() {
+	for _, obj := range d.objects {
+		dict, ok := obj.value.(map[string]any)
+		if !ok || obj.stream == nil || dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		data, err := d.decodeStream(obj)
+		if err != nil {
+			continue
+		}
+		n, _ := d.resolve(dict["N"]).(float64)
+		first, _ := d.resolve(dict["First"]).(float64)
+		if int(first) > len(data) {
+			continue
+		}
+		header := &lexer{data: data[:int(first)]}
+		for range int(n) {
+			num, err1 := header.value()
+			off, err2 := header.value()
+			if err1 != nil || err2 != nil {
+				break
+			}
+			numF, ok1 := num.(float64)
+			offF, ok2 := off.(float64)
+			if !ok1 || !ok2 || int(first)+int(offF) >= len(data) {
+				continue
+			}
+			if _, exists := d.objects[int(numF)]; exists {
+				continue
+			}
+			l := &lexer{data: data, pos: int(first) + int(offF)}
+			if v, err := l.value(); err == nil {
+				d.objects[int(numF)] = &pdfObject{value: v}
+			}
+		}
+	}
+}
+
+// resolve follows indirect references.
+func (d *pdfDocument) resolve(v any) any {
+	for range maxResolveDepth {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		obj := d.objects[ref.num]
+		if obj == nil {
+			return nil
+		}
+		v = obj.value
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(v any) map[string]any {
+	dict, _ := d.resolve(v).(map[string]any)
+	return dict
+}
+
+// object returns the object an indirect reference points to.
+func (d *pdfDocument) object(v any) *pdfObject {
+	if ref, ok := v.(pdfRef); ok {
+		return d.objects[ref.num]
+	}
+	return nil
+}
+
+func (d *pdfDocument) encrypted() bool {
+	for _, trailer := range d.trailers {
+		if trailer["Encrypt"] != nil {
+			return true
+		}
+	}
+	for _, obj := range d.objects {
+		if dict, ok := obj.value.(map[string]any); ok && dict["Type"] == pdfName("XRef") && dict["Encrypt"] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// pages returns the page dictionaries in order, with inherited resources
+// filled in.
+func (d *pdfDocument) pages() []map[string]any {
+	var pages []map[string]any
+	for _, num := range d.sortedObjectNumbers() {
+		dict, ok := d.objects[num].value.(map[string]any)
+		if !ok || dict["Type"] != pdfName("Catalog") {
+			continue
+		}
+		d.walkPages(dict["Pages"], nil, 0, &pages)
+		if len(pages) > 0 {
+			return pages
+		}
+	}
+	// Without a usable catalog, fall back to the page objects in object
+	// order.
+	for _, num := range d.sortedObjectNumbers() {
+		if dict, ok := d.objects[num].value.(map[string]any); ok && dict["Type"] == pdfName("Page") {
+			pages = append(pages, dict)
+		}
+	}
+	return pages
+}
+
+func (d *pdfDocument) walkPages(node, resources any, depth int, pages *[]map[string]any) {
+	dict := d.dict(node)
+	if dict == nil || depth > maxTreeDepth {
+		return
+	}
+	if r, ok := dict["Resources"]; ok {
+		resources = r
+	}
+	kids, isTree := d.resolve(dict["Kids"]).([]any)
+	if !isTree {
+		page := make(map[string]any, len(dict)+1)
+		for k, v := range dict {
+			page[k] = v
+		}
+		page["Resources"] = resources
+		*pages = append(*pages, page)
+		return
+	}
+	for _, kid := range kids {
+		d.walkPages(kid, resources, depth+1, pages)
+	}
+}
+
+func (d *pdfDocument) sortedObjectNumbers() []int {
+	nums := make([]int, 0, len(d.objects))
+	for num := range d.objects {
+		nums = append(nums, num)
+	}
+	slices.Sort(nums)
+	return nums
+}
+
+// pageText returns the text of a page.
+func (d *pdfDocument) pageText(page map[string]any) string {
+	var content bytes.Buffer
+	contents := d.resolve(page["Contents"])
+	refs, ok := contents.([]any)
+	if !ok {
+		refs = []any{page["Contents"]}
+	}
+	for _, ref := range refs {
+		obj := d.object(ref)
+		if obj == nil || obj.stream == nil {
+			continue
+		}
+		data, err := d.decodeStream(obj)
+		if err != nil {
+			continue
+		}
+		content.Write(data)
+		content.WriteByte('\n')
+	}
+
+	w := &textWriter{}
+	d.runContent(content.Bytes(), d.dict(page["Resources"]), w, 0)
+	return w.String()
+}
+
+// decodeStream returns the decoded data of a stream object.
+func (d *pdfDocument) decodeStream(obj *pdfObject) ([]byte, error) {
+	dict, _ := obj.value.(map[string]any)
+	data := obj.stream
+	var filters []any
+	switch f := d.resolve(dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{f}
+	case []any:
+		filters = f
+	}
+	for _, filter := range filters {
+		var err error
+		switch d.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			data, err = inflate(data)
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = decodeASCII85(data)
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			l := &lexer{data: append([]byte{'<'}, data...)}
+			data = l.hexString()
+		default:
+			err = fmt.Errorf("unsupported filter %v", filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate decompresses zlib data, keeping what could be read from truncated
+// or slightly damaged streams. Streams decompressing to more than
+// maxStreamSize are rejected.
+func inflate(data []byte) ([]byte, error) {
+	var r io.ReadCloser
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		// Some writers omit the zlib header.
+		r = flate.NewReader(bytes.NewReader(data))
+	}
+	defer r.Close()
+	out, err := io.ReadAll(io.LimitReader(r, maxStreamSize+1))
+	if len(out) > maxStreamSize {
+		return nil, errStreamTooLarge
+	}
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, 4*len(data)/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return out[:n], nil
+}
+
+func atoi(b []byte) int {
+	n := 0
+	for _, c := range b {
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+// textWriter accumulates extracted text, collapsing repeated separators.
+type textWriter struct {
+	b strings.Builder
+}
+
+func (w *textWriter) text(s string) {
+	w.b.WriteString(s)
+}
+
+func (w *textWriter) last() byte {
+	s := w.b.String()
+	if s == "" {
+		return '\n'
+	}
+	return s[len(s)-1]
+}
+
+func (w *textWriter) space() {
+	if c := w.last(); c != ' ' && c != '\n' {
+		w.b.WriteByte(' ')
+	}
+}
+
+func (w *textWriter) newline() {
+	if w.b.Len() > 0 {
+		w.b.WriteByte('\n')
+	}
+}
+
+// String returns the text with trailing spaces and runs of blank lines
+// removed.
+func (w *textWriter) String() string {
+	lines := strings.Split(w.b.String(), "\n")
+	out := make([]string, 0, len(lines))
+	blank := 0
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			blank++
+			if blank > 1 {
+				continue
+			}
+		} else {
+			blank = 0
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
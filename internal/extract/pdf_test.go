@@ -0,0 +1,188 @@
+package extract
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pdfBuilder writes minimal PDF files for tests.
+type pdfBuilder struct {
+	objects map[int]string
+	trailer string
+}
+
+func newPDFBuilder() *pdfBuilder {
+	return &pdfBuilder{objects: make(map[int]string), trailer: "<< /Root 1 0 R >>"}
+}
+
+func (b *pdfBuilder) object(num int, body string) {
+	b.objects[num] = body
+}
+
+func (b *pdfBuilder) stream(num int, dict string, data []byte) {
+	b.objects[num] = fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func (b *pdfBuilder) flateStream(num int, dict string, data []byte) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	_, _ = zw.Write(data)
+	_ = zw.Close()
+	b.stream(num, dict+" /Filter /FlateDecode", buf.Bytes())
+}
+
+func (b *pdfBuilder) bytes() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	nums := make([]int, 0, len(b.objects))
+	for num := range b.objects {
+		nums = append(nums, num)
+	}
+	slices.Sort(nums)
+	for _, num := range nums {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", num, b.objects[num])
+	}
+	fmt.Fprintf(&buf, "trailer\n%s\n%%%%EOF\n", b.trailer)
+	return buf.Bytes()
+}
+
+const testToUnicode = `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+1 beginbfchar
+<0004> <00E9>
+endbfchar
+1 beginbfrange
+<0001> <0003> <0041>
+endbfrange
+endcmap
+end end`
+
+func TestPDFPages(t *testing.T) {
+	t.Parallel()
+
+	b := newPDFBuilder()
+	b.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	b.object(2, "<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /Font << /F1 5 0 R >> >> >>")
+	b.object(3, "<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>")
+	b.object(4, "<< /Type /Page /Parent 2 0 R /Contents [7 0 R 8 0 R] /Resources << /Font << /F2 9 0 R >> /XObject << /X1 11 0 R >> >> >>")
+	b.object(5, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding << /Differences [39 /quoteright] >> >>")
+	b.flateStream(6, "", []byte(`BT /F1 12 Tf 72 720 Td (Hello, \(PDF\) World!) Tj
+0 -14 Td [(Sec) 20 (ond) -300 (line)] TJ
+0 -40 Td (It's a new paragraph) Tj ET`))
+	b.stream(7, "", []byte("BT /F2 10 Tf 1 0 0 1 72 700 Tm <00010002> Tj"))
+	b.stream(8, "", []byte("<00030004> Tj ET /X1 Do"))
+	b.object(9, "<< /Type /Font /Subtype /Type0 /BaseFont /Test /Encoding /Identity-H /ToUnicode 10 0 R >>")
+	b.flateStream(10, "", []byte(testToUnicode))
+	b.stream(11, "/Type /XObject /Subtype /Form /Resources << /Font << /F1 5 0 R >> >>", []byte("BT /F1 10 Tf 1 0 0 1 72 600 Tm (From a form) Tj ET"))
+
+	pages, err := PDFPages(b.bytes())
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Hello, (PDF) World!\nSecond line\n\nIt’s a new paragraph",
+		"ABCé\nFrom a form",
+	}, pages)
+}
+
+func TestPDFPagesObjectStreams(t *testing.T) {
+	t.Parallel()
+
+	objects := []string{
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+	}
+	var header, body bytes.Buffer
+	for i, obj := range objects {
+		fmt.Fprintf(&header, "%d %d ", i+2, body.Len())
+		body.WriteString(obj + "\n")
+	}
+
+	b := newPDFBuilder()
+	b.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	b.flateStream(4, "", []byte("BT 12 TL (First) Tj T* (Second) Tj ET"))
+	b.flateStream(5, fmt.Sprintf("/Type /ObjStm /N 2 /First %d", header.Len()), append(header.Bytes(), body.Bytes()...))
+
+	pages, err := PDFPages(b.bytes())
+	require.NoError(t, err)
+	require.Equal(t, []string{"First\nSecond"}, pages)
+}
+
+func TestPDFPagesErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := PDFPages([]byte("hello"))
+	require.ErrorContains(t, err, "not a PDF")
+
+	b := newPDFBuilder()
+	b.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	b.object(2, "<< /Type /Pages /Kids [] /Count 0 >>")
+	_, err = PDFPages(b.bytes())
+	require.ErrorContains(t, err, "no pages")
+
+	b.trailer = "<< /Root 1 0 R /Encrypt 3 0 R >>"
+	_, err = PDFPages(b.bytes())
+	require.ErrorIs(t, err, ErrEncrypted)
+}
+
+func TestLexer(t *testing.T) {
+	t.Parallel()
+
+	l := &lexer{data: []byte(`<< /Name#20X (a\(b\)\n\101\
+c) /Hex <48 65 6C6C 6F> /Ref 12 0 R /Arr [1 -2.5 true null] >>`)}
+	v, err := l.value()
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"Name X": []byte("a(b)\nAc"),
+		"Hex":    []byte("Hello"),
+		"Ref":    pdfRef{num: 12},
+		"Arr":    []any{1.0, -2.5, true, nil},
+	}, v)
+}
+
+func TestInflateLimit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	_, _ = zw.Write(make([]byte, maxStreamSize+1))
+	_ = zw.Close()
+	_, err := inflate(buf.Bytes())
+	require.ErrorIs(t, err, errStreamTooLarge)
+}
+
+func FuzzLexer(f *testing.F) {
+	f.Add([]byte(`<< /Name#20X (a\(b\)\n\101\
+c) /Hex <48 65 6C6C 6F> /Ref 12 0 R /Arr [1 -2.5 true null] >>`))
+	f.Add([]byte(`[(unterminated`))
+	f.Add([]byte(`<<<<>>`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		l := &lexer{data: data}
+		for range 100 {
+			if _, err := l.value(); err != nil || l.pos >= len(data) {
+				return
+			}
+		}
+	})
+}
+
+func FuzzPDFPages(f *testing.F) {
+	b := newPDFBuilder()
+	b.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	b.object(2, "<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	b.object(3, "<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>")
+	b.flateStream(4, "", []byte("BT /F1 12 Tf 12 TL (Hello) Tj T* [(Wor) -250 (ld)] TJ ET"))
+	b.object(5, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+	f.Add(b.bytes())
+	f.Add([]byte("%PDF-1.7\n1 0 obj\n<< /Length 10 >>\nstream\nabc"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = pdfPages(data)
+	})
+}
@@ -19,6 +19,11 @@ type Service interface {
 	// LastReadTime returns when a file was last read.
 	// Returns zero time if never read.
 	LastReadTime(ctx context.Context, sessionID, path string) time.Time
+
+	// Invalidate forgets the reads of a file, in all sessions, that were
+	// recorded before it changed at the given time, so it must be read again
+	// before it is edited.
+	Invalidate(ctx context.Context, path string, changedAt time.Time)
 }
 
 type service struct {
@@ -68,3 +73,17 @@ func relpath(path string) string {
 	}
 	return relpath
 }
+
+// Invalidate forgets the reads of a file that were recorded before it changed
+// at the given time.
+func (s *service) Invalidate(ctx context.Context, path string, changedAt time.Time) {
+	// Read times have a resolution of one second: reads recorded in the same
+	// second as the change, such as the ones of the tools that made it, are
+	// kept.
+	if err := s.q.InvalidateFileReads(ctx, db.InvalidateFileReadsParams{
+		Path:   relpath(path),
+		ReadAt: changedAt.Unix(),
+	}); err != nil {
+		slog.Error("Error invalidating file reads", "error", err, "file", path)
+	}
+}
@@ -114,3 +114,26 @@ func TestService_RecordRead_DifferentPaths(t *testing.T) {
 	lastRead2 := env.svc.LastReadTime(env.ctx, sessionID, path2)
 	require.True(t, lastRead2.IsZero(), "path2 should not be recorded")
 }
+
+func TestService_Invalidate(t *testing.T) {
+	env := setupTest(t)
+
+	path := "/path/to/changed.go"
+	session1, session2 := "session-4", "session-5"
+	env.createSession(t, session1)
+	env.createSession(t, session2)
+
+	env.svc.RecordRead(env.ctx, session1, path)
+	env.svc.RecordRead(env.ctx, session2, path)
+	env.svc.RecordRead(env.ctx, session1, "/path/to/other.go")
+	lastRead := env.svc.LastReadTime(env.ctx, session1, path)
+
+	// A change in the second of the read keeps it.
+	env.svc.Invalidate(env.ctx, path, lastRead)
+	require.Equal(t, lastRead, env.svc.LastReadTime(env.ctx, session1, path))
+
+	env.svc.Invalidate(env.ctx, path, lastRead.Add(time.Second))
+	require.True(t, env.svc.LastReadTime(env.ctx, session1, path).IsZero())
+	require.True(t, env.svc.LastReadTime(env.ctx, session2, path).IsZero())
+	require.False(t, env.svc.LastReadTime(env.ctx, session1, "/path/to/other.go").IsZero())
+}
@@ -173,6 +173,13 @@ func (dl *directoryLister) shouldIgnore(path string, ignorePatterns []string) bo
 	return false
 }
 
+// ShouldIgnore checks if a path is ignored by the common ignore patterns, the
+// .gitignore and .crushignore files up to the root path, or the global ignore
+// files.
+func (dl *directoryLister) ShouldIgnore(path string) bool {
+	return dl.shouldIgnore(path, nil)
+}
+
 func (dl *directoryLister) checkParentIgnores(path string) bool {
 	parent := filepath.Dir(filepath.Dir(path))
 	for parent != "." && path != "." {
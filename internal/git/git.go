@@ -0,0 +1,65 @@
+// Package git runs git commands on behalf of the agent and its tools.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrNotRepository is returned when the directory isn't in a git repository.
+var ErrNotRepository = errors.New("not a git repository")
+
+// Error is returned when git fails. It wraps the error of the process, e.g.
+// an [*exec.ExitError] telling the exit code.
+type Error struct {
+	// Command is the git subcommand, e.g. "diff".
+	Command string
+	// Message is the error git printed, if any.
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("git %s: %v", e.Command, e.Err)
+	}
+	return fmt.Sprintf("git %s: %s", e.Command, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run runs git in a directory and returns its standard output, which is
+// also returned when git fails, as some commands report their result with
+// their exit code. Read-only commands don't take optional locks, so they
+// don't get in the way of the user's own git commands.
+func Run(ctx context.Context, dir string, stdin []byte, args ...string) ([]byte, error) {
+	subcommand := args[0]
+	args = append([]string{"-c", "core.quotepath=false", "-c", "color.ui=false"}, args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0", "GIT_TERMINAL_PROMPT=0", "LC_ALL=C")
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if strings.Contains(msg, "not a git repository") {
+			return nil, ErrNotRepository
+		}
+		return stdout.Bytes(), &Error{Command: subcommand, Message: msg, Err: err}
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,35 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := Run(t.Context(), dir, nil, "status")
+	require.ErrorIs(t, err, ErrNotRepository)
+
+	_, err = Run(t.Context(), dir, nil, "init")
+	require.NoError(t, err)
+	_, err = Run(t.Context(), dir, nil, "log")
+	var gitErr *Error
+	require.ErrorAs(t, err, &gitErr)
+	require.Equal(t, "log", gitErr.Command)
+	require.Contains(t, err.Error(), "git log: ")
+
+	// Commands reporting their result with their exit code keep their output.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0o644))
+	out, err := Run(t.Context(), dir, nil, "diff", "--no-index", "--", os.DevNull, "a.txt")
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	require.Equal(t, 1, exitErr.ExitCode())
+	require.Contains(t, string(out), "+a")
+}
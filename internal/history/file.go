@@ -49,7 +49,7 @@ type service struct {
 
 func NewService(q *db.Queries, db *sql.DB) Service {
 	return &service{
-		Broker: pubsub.NewBroker[File](),
+		Broker: pubsub.NewBrokerWithKey(func(f File) string { return f.ID }),
 		q:      q,
 		db:     db,
 	}
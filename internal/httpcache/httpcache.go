@@ -0,0 +1,309 @@
+// Package httpcache caches HTTP GET responses on disk. Entries are reused
+// while fresh, and revalidated with their ETag and Last-Modified validators
+// once stale, so repeated requests for the same page neither wait on nor
+// count against the remote server.
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a response without freshness information is reused
+// before it's revalidated.
+const DefaultTTL = 5 * time.Minute
+
+// CacheHeader is set on responses served from the cache.
+const CacheHeader = "X-Crush-Cache"
+
+const (
+	// MaxAge is how long an entry is kept once it was last stored.
+	MaxAge = 7 * 24 * time.Hour
+	// MaxDirSize bounds the total size of the entries. The oldest entries
+	// are removed first to make room.
+	MaxDirSize = 100 * 1024 * 1024
+	// pruneInterval is the minimum time between two passes removing the
+	// entries beyond these bounds.
+	pruneInterval = time.Minute
+)
+
+// Transport is an [http.RoundTripper] that caches the responses of GET
+// requests in a directory.
+type Transport struct {
+	dir  string
+	next http.RoundTripper
+	// maxSize is the size of the largest body that is cached.
+	maxSize int64
+	now     func() time.Time
+
+	// maxAge and maxDirSize bound the entries kept in dir.
+	maxAge     time.Duration
+	maxDirSize int64
+	pruneMu    sync.Mutex
+	lastPrune  time.Time
+}
+
+// New returns a transport that caches the responses of next in dir, as
+// long as their body is at most maxSize bytes.
+func New(dir string, maxSize int64, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{
+		dir:        dir,
+		next:       next,
+		maxSize:    maxSize,
+		now:        time.Now,
+		maxAge:     MaxAge,
+		maxDirSize: MaxDirSize,
+	}
+}
+
+type entry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	Expires    time.Time   `json:"expires"`
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" || req.Header.Get("Authorization") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := t.load(key)
+	if cached != nil && t.now().Before(cached.Expires) && !hasDirective(req.Header, "no-cache") {
+		return cached.response(req, "hit"), nil
+	}
+
+	outReq := req
+	if cached != nil {
+		outReq = req.Clone(req.Context())
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			outReq.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			outReq.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		for _, h := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified"} {
+			if v := resp.Header.Get(h); v != "" {
+				cached.Header.Set(h, v)
+			}
+		}
+		cached.Expires = t.expires(cached.Header)
+		t.store(key, cached)
+		return cached.response(req, "revalidated"), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || !storable(resp.Header) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.maxSize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > t.maxSize {
+		// Too large to cache: hand the response on as is.
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	e := &entry{
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		Expires:    t.expires(resp.Header),
+	}
+	t.store(key, e)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// expires returns when a response with the given headers becomes stale.
+func (t *Transport) expires(header http.Header) time.Time {
+	now := t.now()
+	if hasDirective(header, "no-cache") {
+		return now
+	}
+	for directive := range strings.SplitSeq(header.Get("Cache-Control"), ",") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age="); ok {
+			if seconds, err := strconv.Atoi(v); err == nil {
+				return now.Add(time.Duration(seconds) * time.Second)
+			}
+		}
+	}
+	if v := header.Get("Expires"); v != "" {
+		if expires, err := http.ParseTime(v); err == nil {
+			return expires
+		}
+		// An invalid Expires means the response is already stale.
+		return now
+	}
+	return now.Add(DefaultTTL)
+}
+
+func (t *Transport) load(key string) *entry {
+	data, err := os.ReadFile(filepath.Join(t.dir, key))
+	if err != nil {
+		return nil
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		slog.Debug("Ignoring corrupt HTTP cache entry", "key", key, "error", err)
+		return nil
+	}
+	return &e
+}
+
+func (t *Transport) store(key string, e *entry) {
+	if err := t.write(key, e); err != nil {
+		slog.Debug("Failed to write HTTP cache entry", "url", e.URL, "error", err)
+		return
+	}
+	t.prune()
+}
+
+// prune removes the entries stored more than maxAge ago, then the oldest
+// ones until the entries fit in maxDirSize. It runs at most once every
+// pruneInterval.
+func (t *Transport) prune() {
+	t.pruneMu.Lock()
+	defer t.pruneMu.Unlock()
+	now := t.now()
+	if now.Sub(t.lastPrune) < pruneInterval {
+		return
+	}
+	t.lastPrune = now
+
+	dirEntries, err := os.ReadDir(t.dir)
+	if err != nil {
+		return
+	}
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	var total int64
+	for _, de := range dirEntries {
+		// Temporary files are being written.
+		if de.IsDir() || strings.HasSuffix(de.Name(), ".tmp") {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(t.dir, de.Name())
+		if now.Sub(info.ModTime()) > t.maxAge {
+			_ = os.Remove(path)
+			continue
+		}
+		files = append(files, file{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+	if total <= t.maxDirSize {
+		return
+	}
+	slices.SortFunc(files, func(a, b file) int { return a.modTime.Compare(b.modTime) })
+	for _, f := range files {
+		if total <= t.maxDirSize {
+			break
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+}
+
+func (t *Transport) write(key string, e *entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so concurrent readers never see a
+	// partial entry.
+	f, err := os.CreateTemp(t.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(t.dir, key))
+}
+
+func (e *entry) response(req *http.Request, status string) *http.Response {
+	header := e.Header.Clone()
+	header.Set(CacheHeader, status)
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func cacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func storable(header http.Header) bool {
+	return !hasDirective(header, "no-store") && header.Get("Vary") != "*"
+}
+
+func hasDirective(header http.Header, directive string) bool {
+	for d := range strings.SplitSeq(header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(d), directive) {
+			return true
+		}
+	}
+	return false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,167 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testServer struct {
+	*httptest.Server
+	hits        atomic.Int64
+	conditional atomic.Int64
+}
+
+func newTestServer(t *testing.T, header http.Header, body string) *testServer {
+	t.Helper()
+	srv := &testServer{}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.hits.Add(1)
+		if etag := header.Get("ETag"); etag != "" && r.Header.Get("If-None-Match") == etag {
+			srv.conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, client *http.Client, url string) (string, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body), resp.Header.Get(CacheHeader)
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reuses fresh responses", func(t *testing.T) {
+		t.Parallel()
+		srv := newTestServer(t, http.Header{"Cache-Control": {"max-age=60"}}, "hello")
+		client := &http.Client{Transport: New(t.TempDir(), 1024, nil)}
+
+		body, status := get(t, client, srv.URL)
+		require.Equal(t, "hello", body)
+		require.Empty(t, status)
+		body, status = get(t, client, srv.URL)
+		require.Equal(t, "hello", body)
+		require.Equal(t, "hit", status)
+		require.Equal(t, int64(1), srv.hits.Load())
+	})
+
+	t.Run("revalidates stale responses", func(t *testing.T) {
+		t.Parallel()
+		srv := newTestServer(t, http.Header{"Etag": {`"v1"`}}, "hello")
+		transport := New(t.TempDir(), 1024, nil)
+		now := time.Now()
+		transport.now = func() time.Time { return now }
+		client := &http.Client{Transport: transport}
+
+		get(t, client, srv.URL)
+		_, status := get(t, client, srv.URL)
+		require.Equal(t, "hit", status)
+
+		now = now.Add(DefaultTTL + time.Second)
+		body, status := get(t, client, srv.URL)
+		require.Equal(t, "hello", body)
+		require.Equal(t, "revalidated", status)
+		require.Equal(t, int64(2), srv.hits.Load())
+		require.Equal(t, int64(1), srv.conditional.Load())
+
+		// Revalidation makes the entry fresh again.
+		_, status = get(t, client, srv.URL)
+		require.Equal(t, "hit", status)
+		require.Equal(t, int64(2), srv.hits.Load())
+	})
+
+	t.Run("does not store no-store responses", func(t *testing.T) {
+		t.Parallel()
+		srv := newTestServer(t, http.Header{"Cache-Control": {"no-store"}}, "hello")
+		client := &http.Client{Transport: New(t.TempDir(), 1024, nil)}
+
+		get(t, client, srv.URL)
+		get(t, client, srv.URL)
+		require.Equal(t, int64(2), srv.hits.Load())
+	})
+
+	t.Run("does not store large responses", func(t *testing.T) {
+		t.Parallel()
+		large := strings.Repeat("x", 2048)
+		srv := newTestServer(t, http.Header{"Cache-Control": {"max-age=60"}}, large)
+		client := &http.Client{Transport: New(t.TempDir(), 1024, nil)}
+
+		body, _ := get(t, client, srv.URL)
+		require.Equal(t, large, body)
+		get(t, client, srv.URL)
+		require.Equal(t, int64(2), srv.hits.Load())
+	})
+
+	t.Run("shares entries across transports", func(t *testing.T) {
+		t.Parallel()
+		srv := newTestServer(t, nil, "hello")
+		dir := t.TempDir()
+
+		get(t, &http.Client{Transport: New(dir, 1024, nil)}, srv.URL)
+		_, status := get(t, &http.Client{Transport: New(dir, 1024, nil)}, srv.URL)
+		require.Equal(t, "hit", status)
+		require.Equal(t, int64(1), srv.hits.Load())
+	})
+
+	t.Run("evicts old entries", func(t *testing.T) {
+		t.Parallel()
+		srv := newTestServer(t, http.Header{"Cache-Control": {"max-age=60"}}, "hello")
+		dir := t.TempDir()
+		transport := New(dir, 1024, nil)
+		now := time.Now()
+		transport.now = func() time.Time { return now }
+		client := &http.Client{Transport: transport}
+
+		entryPath := func(url string) string {
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			return filepath.Join(dir, cacheKey(req))
+		}
+		age := func(url string, d time.Duration) {
+			require.NoError(t, os.Chtimes(entryPath(url), now.Add(-d), now.Add(-d)))
+		}
+
+		get(t, client, srv.URL+"/a")
+		get(t, client, srv.URL+"/b")
+		age(srv.URL+"/a", MaxAge+time.Hour)
+		age(srv.URL+"/b", time.Hour)
+		info, err := os.Stat(entryPath(srv.URL + "/b"))
+		require.NoError(t, err)
+
+		// Expired entries go first, then the oldest ones to make room.
+		transport.maxDirSize = 2 * info.Size()
+		now = now.Add(pruneInterval)
+		get(t, client, srv.URL+"/c")
+		require.NoFileExists(t, entryPath(srv.URL+"/a"))
+		require.FileExists(t, entryPath(srv.URL+"/b"))
+		require.FileExists(t, entryPath(srv.URL+"/c"))
+
+		now = now.Add(pruneInterval)
+		get(t, client, srv.URL+"/d")
+		require.NoFileExists(t, entryPath(srv.URL+"/b"))
+		require.FileExists(t, entryPath(srv.URL+"/c"))
+		require.FileExists(t, entryPath(srv.URL+"/d"))
+	})
+}
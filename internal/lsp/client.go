@@ -63,6 +63,9 @@ type Client struct {
 	// Files are currently opened by the LSP
 	openFiles *csync.Map[string, *OpenFileInfo]
 
+	// File watchers registered by the server, by registration ID
+	fileWatchers *csync.Map[string, []protocol.FileSystemWatcher]
+
 	// Server state
 	serverState atomic.Value
 }
@@ -70,13 +73,14 @@ type Client struct {
 // New creates a new LSP client using the powernap implementation.
 func New(ctx context.Context, name string, cfg config.LSPConfig, resolver config.VariableResolver) (*Client, error) {
 	client := &Client{
-		name:        name,
-		fileTypes:   cfg.FileTypes,
-		diagnostics: csync.NewVersionedMap[protocol.DocumentURI, []protocol.Diagnostic](),
-		openFiles:   csync.NewMap[string, *OpenFileInfo](),
-		config:      cfg,
-		ctx:         ctx,
-		resolver:    resolver,
+		name:         name,
+		fileTypes:    cfg.FileTypes,
+		diagnostics:  csync.NewVersionedMap[protocol.DocumentURI, []protocol.Diagnostic](),
+		openFiles:    csync.NewMap[string, *OpenFileInfo](),
+		fileWatchers: csync.NewMap[string, []protocol.FileSystemWatcher](),
+		config:       cfg,
+		ctx:          ctx,
+		resolver:     resolver,
 	}
 	client.serverState.Store(StateStarting)
 
@@ -173,7 +177,12 @@ func (c *Client) createPowernapClient() error {
 func (c *Client) registerHandlers() {
 	c.RegisterServerRequestHandler("workspace/applyEdit", HandleApplyEdit)
 	c.RegisterServerRequestHandler("workspace/configuration", HandleWorkspaceConfiguration)
-	c.RegisterServerRequestHandler("client/registerCapability", HandleRegisterCapability)
+	c.RegisterServerRequestHandler("client/registerCapability", func(_ context.Context, _ string, params json.RawMessage) (any, error) {
+		return HandleRegisterCapability(c, params)
+	})
+	c.RegisterServerRequestHandler("client/unregisterCapability", func(_ context.Context, _ string, params json.RawMessage) (any, error) {
+		return HandleUnregisterCapability(c, params)
+	})
 	c.RegisterNotificationHandler("window/showMessage", HandleServerMessage)
 	c.RegisterNotificationHandler("textDocument/publishDiagnostics", func(_ context.Context, _ string, params json.RawMessage) {
 		HandleDiagnostics(c, params)
@@ -196,6 +205,7 @@ func (c *Client) Restart() error {
 
 	c.diagCountsCache = DiagnosticCounts{}
 	c.diagCountsVersion = 0
+	c.fileWatchers.Reset(map[string][]protocol.FileSystemWatcher{})
 
 	if err := c.createPowernapClient(); err != nil {
 		return err
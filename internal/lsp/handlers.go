@@ -16,7 +16,7 @@ func HandleWorkspaceConfiguration(_ context.Context, _ string, params json.RawMe
 }
 
 // HandleRegisterCapability handles capability registration requests
-func HandleRegisterCapability(_ context.Context, _ string, params json.RawMessage) (any, error) {
+func HandleRegisterCapability(client *Client, params json.RawMessage) (any, error) {
 	var registerParams protocol.RegistrationParams
 	if err := json.Unmarshal(params, &registerParams); err != nil {
 		slog.Error("Error unmarshaling registration params", "error", err)
@@ -38,12 +38,29 @@ func HandleRegisterCapability(_ context.Context, _ string, params json.RawMessag
 				continue
 			}
 			// Store the file watchers registrations
+			client.fileWatchers.Set(reg.ID, options.Watchers)
 			notifyFileWatchRegistration(reg.ID, options.Watchers)
 		}
 	}
 	return nil, nil
 }
 
+// HandleUnregisterCapability handles capability unregistration requests
+func HandleUnregisterCapability(client *Client, params json.RawMessage) (any, error) {
+	var unregisterParams protocol.UnregistrationParams
+	if err := json.Unmarshal(params, &unregisterParams); err != nil {
+		slog.Error("Error unmarshaling unregistration params", "error", err)
+		return nil, err
+	}
+
+	for _, unreg := range unregisterParams.Unregisterations {
+		if unreg.Method == "workspace/didChangeWatchedFiles" {
+			client.fileWatchers.Del(unreg.ID)
+		}
+	}
+	return nil, nil
+}
+
 // HandleApplyEdit handles workspace edit requests
 func HandleApplyEdit(_ context.Context, _ string, params json.RawMessage) (any, error) {
 	var edit protocol.ApplyWorkspaceEditParams
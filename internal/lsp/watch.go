@@ -0,0 +1,111 @@
+package lsp
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/charmbracelet/x/powernap/pkg/lsp/protocol"
+)
+
+// NotifyWatchedFiles notifies the server about files changed on disk. Open
+// files are synced with their new content, and the changes matching the file
+// watchers the server registered are sent as a workspace/didChangeWatchedFiles
+// notification.
+func (c *Client) NotifyWatchedFiles(ctx context.Context, changes []protocol.FileEvent) error {
+	var watched []protocol.FileEvent
+	for _, change := range changes {
+		path, err := change.URI.Path()
+		if err != nil {
+			continue
+		}
+		if change.Type != protocol.Deleted && c.IsFileOpen(path) {
+			if err := c.NotifyChange(ctx, path); err != nil {
+				slog.Debug("Failed to sync changed file", "name", c.name, "file", path, "error", err)
+			}
+		}
+		if c.watchesFile(path, change.Type) {
+			watched = append(watched, change)
+		}
+	}
+	if len(watched) == 0 {
+		return nil
+	}
+	return c.DidChangeWatchedFiles(ctx, protocol.DidChangeWatchedFilesParams{Changes: watched})
+}
+
+// watchesFile checks if a change of a file matches any of the file watchers
+// registered by the server.
+func (c *Client) watchesFile(path string, change protocol.FileChangeType) bool {
+	kind := watchKind(change)
+	for _, watchers := range c.fileWatchers.Seq2() {
+		for _, w := range watchers {
+			if w.Kind != nil && *w.Kind&kind == 0 {
+				continue
+			}
+			if c.matchesWatcher(w, path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *Client) matchesWatcher(w protocol.FileSystemWatcher, path string) bool {
+	switch pattern := w.GlobPattern.Value.(type) {
+	case string:
+		if matchGlob(pattern, path) {
+			return true
+		}
+		// Patterns without a base are also matched relative to the
+		// workspace, so that ones such as "*.go" match its files.
+		if rel, ok := relativeTo(c.workDir, path); ok {
+			return matchGlob(pattern, rel)
+		}
+	case protocol.RelativePattern:
+		var uri string
+		switch base := pattern.BaseURI.Value.(type) {
+		case string:
+			uri = base
+		case protocol.WorkspaceFolder:
+			uri = base.URI
+		}
+		basePath, err := protocol.DocumentURI(uri).Path()
+		if err != nil {
+			return false
+		}
+		if rel, ok := relativeTo(basePath, path); ok {
+			return matchGlob(pattern.Pattern, rel)
+		}
+	}
+	return false
+}
+
+func watchKind(change protocol.FileChangeType) protocol.WatchKind {
+	switch change {
+	case protocol.Created:
+		return protocol.WatchCreate
+	case protocol.Deleted:
+		return protocol.WatchDelete
+	default:
+		return protocol.WatchChange
+	}
+}
+
+func matchGlob(pattern, path string) bool {
+	ok, err := doublestar.Match(pattern, filepath.ToSlash(path))
+	return err == nil && ok
+}
+
+func relativeTo(base, path string) (string, bool) {
+	if base == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
@@ -0,0 +1,68 @@
+package lsp
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/x/powernap/pkg/lsp/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchesFile(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	client := &Client{
+		workDir:      root,
+		fileWatchers: csync.NewMap[string, []protocol.FileSystemWatcher](),
+	}
+
+	params, err := json.Marshal(protocol.RegistrationParams{
+		Registrations: []protocol.Registration{{
+			ID:     "watch-1",
+			Method: "workspace/didChangeWatchedFiles",
+			RegisterOptions: map[string]any{
+				"watchers": []any{
+					map[string]any{"globPattern": "**/*.{go,mod}"},
+					map[string]any{"globPattern": "*.json", "kind": protocol.WatchDelete},
+					map[string]any{"globPattern": map[string]any{
+						"baseUri": string(protocol.URIFromPath(filepath.Join(root, "docs"))),
+						"pattern": "*.md",
+					}},
+				},
+			},
+		}},
+	})
+	require.NoError(t, err)
+	_, err = HandleRegisterCapability(client, params)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path   string
+		change protocol.FileChangeType
+		want   bool
+	}{
+		{"main.go", protocol.Changed, true},
+		{"pkg/sub/go.mod", protocol.Created, true},
+		{"main.rs", protocol.Changed, false},
+		{"config.json", protocol.Deleted, true},
+		{"config.json", protocol.Changed, false},
+		{"docs/readme.md", protocol.Changed, true},
+		{"readme.md", protocol.Changed, false},
+	}
+	for _, tt := range tests {
+		got := client.watchesFile(filepath.Join(root, tt.path), tt.change)
+		require.Equal(t, tt.want, got, "path %s, change %d", tt.path, tt.change)
+	}
+	require.False(t, client.watchesFile(filepath.Join(filepath.Dir(root), "other.json"), protocol.Deleted))
+
+	params, err = json.Marshal(protocol.UnregistrationParams{
+		Unregisterations: []protocol.Unregistration{{ID: "watch-1", Method: "workspace/didChangeWatchedFiles"}},
+	})
+	require.NoError(t, err)
+	_, err = HandleUnregisterCapability(client, params)
+	require.NoError(t, err)
+	require.False(t, client.watchesFile(filepath.Join(root, "main.go"), protocol.Changed))
+}
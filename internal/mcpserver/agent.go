@@ -0,0 +1,44 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RunAgentToolName is the name of the tool running a prompt with the coder
+// agent.
+const RunAgentToolName = "run_agent"
+
+const runAgentDescription = `Runs a task with the Crush coding agent, which can read, search and edit files, run commands and use the configured LSP and MCP servers. Calls made by the same client share their conversation history. Returns the final response of the agent.`
+
+func (s *Server) runAgent(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params struct {
+		Prompt string `json:"prompt"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+		return errorResult(fmt.Sprintf("error parsing parameters: %s", err)), nil
+	}
+	if strings.TrimSpace(params.Prompt) == "" {
+		return errorResult("prompt is required"), nil
+	}
+
+	sessionID, err := s.session(ctx, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.app.AgentCoordinator.Run(ctx, sessionID, params.Prompt)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+	if result == nil {
+		// The prompt was queued behind another run of the session.
+		return errorResult("the agent is busy with another task of this client, the prompt was queued"), nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.Response.Content.Text()}},
+	}, nil
+}
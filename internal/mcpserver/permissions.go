@@ -0,0 +1,93 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	decisionAllow        = "allow"
+	decisionAllowSession = "allow_session"
+	decisionDeny         = "deny"
+)
+
+// handlePermissions forwards the permission requests of tools to the MCP
+// client that called them, as elicitations. Requests are denied when the
+// client does not support elicitation.
+func (s *Server) handlePermissions(ctx context.Context) {
+	for event := range s.app.Permissions.Subscribe(ctx, pubsub.WithPolicy(pubsub.Block)) {
+		go s.askPermission(ctx, event.Payload)
+	}
+}
+
+func (s *Server) askPermission(ctx context.Context, req permission.PermissionRequest) {
+	ss, ok := s.requestClient(ctx, req.SessionID)
+	if !ok {
+		slog.Warn("Denying permission request of unknown MCP session", "tool", req.ToolName, "session_id", req.SessionID)
+		s.app.Permissions.Deny(req)
+		return
+	}
+	if params := ss.InitializeParams(); params == nil || params.Capabilities == nil || params.Capabilities.Elicitation == nil {
+		slog.Warn("Denying permission request: MCP client does not support elicitation", "tool", req.ToolName)
+		s.app.Permissions.Deny(req)
+		return
+	}
+
+	message := fmt.Sprintf("Crush needs permission to %s with %s in %s.", req.Action, req.ToolName, req.Path)
+	if req.Description != "" {
+		message += "\n\n" + req.Description
+	}
+	result, err := ss.Elicit(ctx, &mcp.ElicitParams{
+		Message: message,
+		RequestedSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"decision": map[string]any{
+					"type":      "string",
+					"title":     "Decision",
+					"enum":      []string{decisionAllow, decisionAllowSession, decisionDeny},
+					"enumNames": []string{"Allow", "Allow for session", "Deny"},
+				},
+			},
+			"required": []string{"decision"},
+		},
+	})
+	if err != nil {
+		slog.Warn("Failed to ask MCP client for permission", "tool", req.ToolName, "error", err)
+		s.app.Permissions.Deny(req)
+		return
+	}
+	if result.Action != "accept" {
+		s.app.Permissions.Deny(req)
+		return
+	}
+	switch result.Content["decision"] {
+	case decisionAllow:
+		s.app.Permissions.Grant(req)
+	case decisionAllowSession:
+		s.app.Permissions.GrantPersistent(req)
+	default:
+		s.app.Permissions.Deny(req)
+	}
+}
+
+// requestClient returns the MCP session a permission request originates
+// from, following sub-agent sessions up to the session of the client.
+func (s *Server) requestClient(ctx context.Context, sessionID string) (*mcp.ServerSession, bool) {
+	for sessionID != "" {
+		if ss, ok := s.client(sessionID); ok {
+			return ss, true
+		}
+		sess, err := s.app.Sessions.Get(ctx, sessionID)
+		if err != nil {
+			return nil, false
+		}
+		sessionID = sess.ParentSessionID
+	}
+	return nil, false
+}
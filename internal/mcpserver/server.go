@@ -0,0 +1,292 @@
+// Package mcpserver exposes the tools of Crush to other agents and editors
+// through the Model Context Protocol.
+package mcpserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/app"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/version"
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Options configures the MCP server.
+type Options struct {
+	// Agent exposes the run_agent tool, running prompts with the coder agent.
+	Agent bool
+	// Token is the bearer token HTTP clients must authenticate with. It's
+	// required to serve on addresses other than loopback ones.
+	Token string
+}
+
+// sessionTimeout is how long HTTP sessions are kept without requests.
+const sessionTimeout = 30 * time.Minute
+
+// Server serves the tools of Crush over MCP.
+type Server struct {
+	app    *app.App
+	server *mcp.Server
+	token  string
+
+	mu sync.Mutex
+	// sessions maps the ID of MCP sessions to the Crush sessions holding
+	// their history, and the other way around.
+	sessions map[string]string
+	clients  map[string]*mcp.ServerSession
+}
+
+// New creates an MCP server exposing the file and LSP tools of Crush, and
+// optionally the coder agent.
+func New(app *app.App, opts Options) (*Server, error) {
+	s := &Server{
+		app:      app,
+		token:    opts.Token,
+		sessions: make(map[string]string),
+		clients:  make(map[string]*mcp.ServerSession),
+	}
+	s.server = mcp.NewServer(
+		&mcp.Implementation{
+			Name:    "crush",
+			Version: version.Version,
+			Title:   "Crush",
+		},
+		&mcp.ServerOptions{
+			Instructions: "Tools of the Crush coding agent, working on " + app.Config().WorkingDir(),
+			// The tools are registered once, before serving.
+			Capabilities: &mcp.ServerCapabilities{Tools: &mcp.ToolCapabilities{}},
+		},
+	)
+
+	for _, tool := range s.tools() {
+		s.server.AddTool(toolDefinition(tool.Info()), s.toolHandler(tool))
+	}
+
+	if opts.Agent {
+		if app.AgentCoordinator == nil {
+			return nil, fmt.Errorf("no providers configured - please run 'crush' to set up a provider interactively")
+		}
+		s.server.AddTool(&mcp.Tool{
+			Name:        RunAgentToolName,
+			Description: runAgentDescription,
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"prompt": map[string]any{
+						"type":        "string",
+						"description": "The task for the agent",
+					},
+				},
+				"required": []string{"prompt"},
+			},
+		}, s.runAgent)
+	}
+	return s, nil
+}
+
+// tools returns the tools of Crush that make sense outside of its own agent
+// loop: reading, searching and editing files, and the LSP integration.
+func (s *Server) tools() []fantasy.AgentTool {
+	cfg := s.app.Config()
+	wd := cfg.WorkingDir()
+	all := []fantasy.AgentTool{
+		tools.NewEditTool(s.app.LSPClients, s.app.Permissions, s.app.History, s.app.FileTracker, wd),
+		tools.NewMultiEditTool(s.app.LSPClients, s.app.Permissions, s.app.History, s.app.FileTracker, wd),
+		tools.NewNotebookEditTool(s.app.Permissions, s.app.History, s.app.FileTracker, wd),
+		tools.NewGlobTool(wd),
+		tools.NewGrepTool(wd),
+		tools.NewLsTool(s.app.Permissions, wd, cfg.Tools.Ls),
+		tools.NewViewTool(s.app.LSPClients, s.app.Permissions, s.app.FileTracker, wd, cfg.Options.SkillsPaths...),
+		tools.NewWriteTool(s.app.LSPClients, s.app.Permissions, s.app.History, s.app.FileTracker, wd),
+	}
+	if len(cfg.LSP) > 0 {
+		all = append(all,
+			tools.NewDiagnosticsTool(s.app.LSPClients),
+			tools.NewReferencesTool(s.app.LSPClients),
+			tools.NewLSPRestartTool(s.app.LSPClients),
+		)
+	}
+
+	allowed := cfg.Agents[config.AgentCoder].AllowedTools
+	return slices.DeleteFunc(all, func(tool fantasy.AgentTool) bool {
+		return !slices.Contains(allowed, tool.Info().Name)
+	})
+}
+
+// Run serves MCP over stdin and stdout until the client disconnects.
+func (s *Server) Run(ctx context.Context) error {
+	go s.handlePermissions(ctx)
+	return s.server.Run(ctx, &mcp.StdioTransport{})
+}
+
+// ListenAndServe serves MCP over streamable HTTP on the given address. Only
+// loopback addresses are served without a token.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	if s.token == "" && !isLoopback(addr) {
+		return fmt.Errorf("refusing to serve on %s without a token: set one or listen on a loopback address such as localhost:8080", addr)
+	}
+	go s.handlePermissions(ctx)
+	var handler http.Handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return s.server
+	}, &mcp.StreamableHTTPOptions{SessionTimeout: sessionTimeout})
+	if s.token != "" {
+		handler = requireToken(s.token, handler)
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// isLoopback reports whether addr only listens on loopback interfaces. An
+// empty host listens on all of them.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// requireToken rejects the requests not authenticated with the bearer token.
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// session returns the Crush session of an MCP session, creating it on first
+// use.
+func (s *Server) session(ctx context.Context, ss *mcp.ServerSession) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.sessions[ss.ID()]; ok {
+		return id, nil
+	}
+
+	client := "MCP client"
+	if params := ss.InitializeParams(); params != nil && params.ClientInfo != nil {
+		client = params.ClientInfo.Name
+	}
+	sess, err := s.app.Sessions.Create(ctx, "MCP: "+client)
+	if err != nil {
+		return "", fmt.Errorf("failed to create session: %w", err)
+	}
+	s.sessions[ss.ID()] = sess.ID
+	s.clients[sess.ID] = ss
+	go s.forget(ss, sess.ID)
+	return sess.ID, nil
+}
+
+// forget drops an MCP session once it's closed. The Crush session is kept,
+// with its history.
+func (s *Server) forget(ss *mcp.ServerSession, sessionID string) {
+	_ = ss.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, ss.ID())
+	delete(s.clients, sessionID)
+}
+
+// client returns the MCP session using the given Crush session.
+func (s *Server) client(sessionID string) (*mcp.ServerSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ss, ok := s.clients[sessionID]
+	return ss, ok
+}
+
+func (s *Server) toolHandler(tool fantasy.AgentTool) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		sessionID, err := s.session(ctx, req.Session)
+		if err != nil {
+			return nil, err
+		}
+		ctx = context.WithValue(ctx, tools.SessionIDContextKey, sessionID)
+		ctx = context.WithValue(ctx, tools.SupportsImagesContextKey, true)
+
+		input := string(req.Params.Arguments)
+		if input == "" {
+			input = "{}"
+		}
+		resp, err := tool.Run(ctx, fantasy.ToolCall{
+			ID:    uuid.New().String(),
+			Name:  tool.Info().Name,
+			Input: input,
+		})
+		if err != nil {
+			slog.Warn("MCP server tool call failed", "tool", tool.Info().Name, "error", err)
+			return errorResult(err.Error()), nil
+		}
+		return toolResult(resp), nil
+	}
+}
+
+func toolDefinition(info fantasy.ToolInfo) *mcp.Tool {
+	required := info.Required
+	if required == nil {
+		required = []string{}
+	}
+	return &mcp.Tool{
+		Name:        info.Name,
+		Description: info.Description,
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": info.Parameters,
+			"required":   required,
+		},
+	}
+}
+
+func toolResult(resp fantasy.ToolResponse) *mcp.CallToolResult {
+	result := &mcp.CallToolResult{IsError: resp.IsError}
+	switch {
+	case len(resp.Data) == 0:
+	case strings.HasPrefix(resp.MediaType, "image/"):
+		result.Content = append(result.Content, &mcp.ImageContent{Data: resp.Data, MIMEType: resp.MediaType})
+	case strings.HasPrefix(resp.MediaType, "audio/"):
+		result.Content = append(result.Content, &mcp.AudioContent{Data: resp.Data, MIMEType: resp.MediaType})
+	}
+	if resp.Content != "" || len(result.Content) == 0 {
+		result.Content = append(result.Content, &mcp.TextContent{Text: resp.Content})
+	}
+	return result
+}
+
+func errorResult(msg string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: msg}},
+	}
+}
@@ -0,0 +1,85 @@
+package mcpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolDefinition(t *testing.T) {
+	t.Parallel()
+
+	tool := toolDefinition(fantasy.ToolInfo{
+		Name:        "glob",
+		Description: "Find files",
+		Parameters: map[string]any{
+			"pattern": map[string]any{"type": "string"},
+		},
+		Required: []string{"pattern"},
+	})
+	require.Equal(t, "glob", tool.Name)
+	require.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"pattern": map[string]any{"type": "string"},
+		},
+		"required": []string{"pattern"},
+	}, tool.InputSchema)
+}
+
+func TestToolResult(t *testing.T) {
+	t.Parallel()
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+		result := toolResult(fantasy.NewTextErrorResponse("file not found"))
+		require.True(t, result.IsError)
+		require.Equal(t, []mcp.Content{&mcp.TextContent{Text: "file not found"}}, result.Content)
+	})
+
+	t.Run("image", func(t *testing.T) {
+		t.Parallel()
+		result := toolResult(fantasy.NewImageResponse([]byte("png"), "image/png"))
+		require.False(t, result.IsError)
+		require.Equal(t, []mcp.Content{&mcp.ImageContent{Data: []byte("png"), MIMEType: "image/png"}}, result.Content)
+	})
+}
+
+func TestIsLoopback(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isLoopback("localhost:8080"))
+	require.True(t, isLoopback("127.0.0.1:8080"))
+	require.True(t, isLoopback("[::1]:8080"))
+	require.False(t, isLoopback(":8080"))
+	require.False(t, isLoopback("0.0.0.0:8080"))
+	require.False(t, isLoopback("192.168.1.10:8080"))
+	require.False(t, isLoopback("example.com:8080"))
+	require.False(t, isLoopback("localhost"))
+}
+
+func TestRequireToken(t *testing.T) {
+	t.Parallel()
+
+	handler := requireToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	for header, status := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"secret":        http.StatusUnauthorized,
+		"Bearer secret": http.StatusNoContent,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, status, rec.Code, header)
+	}
+}
@@ -125,6 +125,56 @@ type Finish struct {
 
 func (Finish) isPart() {}
 
+// Verification is the outcome of the verify commands run at the end of an
+// agent turn. It is stored in a user message, whose text is the request to fix
+// the failures when they are sent back to the agent.
+type Verification struct {
+	Results []VerifyResult `json:"results"`
+	// Attempt counts the verifications of the prompt, from 1.
+	Attempt int `json:"attempt"`
+	// Repair reports whether the failures were sent back to the agent.
+	Repair   bool `json:"repair,omitempty"`
+	Finished bool `json:"finished"`
+}
+
+// VerifyResult is the outcome of a verify command.
+type VerifyResult struct {
+	Name     string `json:"name"`
+	Command  string `json:"command"`
+	ExitCode int    `json:"exit_code"`
+	Output   string `json:"output"`
+	TimedOut bool   `json:"timed_out,omitempty"`
+	// Duration is in milliseconds.
+	Duration int64 `json:"duration"`
+	Finished bool  `json:"finished"`
+}
+
+// Passed reports whether the command succeeded.
+func (r VerifyResult) Passed() bool {
+	return r.Finished && r.ExitCode == 0 && !r.TimedOut
+}
+
+// Passed reports whether all the commands succeeded.
+func (v Verification) Passed() bool {
+	for _, r := range v.Results {
+		if !r.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+func (Verification) isPart() {}
+
+// Usage is the token usage and cost of generating an assistant message.
+type Usage struct {
+	InputTokens         int64
+	OutputTokens        int64
+	CacheReadTokens     int64
+	CacheCreationTokens int64
+	Cost                float64
+}
+
 type Message struct {
 	ID               string
 	Role             MessageRole
@@ -135,6 +185,7 @@ type Message struct {
 	CreatedAt        int64
 	UpdatedAt        int64
 	IsSummaryMessage bool
+	Usage            Usage
 }
 
 func (m *Message) Content() TextContent {
@@ -146,6 +197,16 @@ func (m *Message) Content() TextContent {
 	return TextContent{}
 }
 
+// Verification returns the verification of the message, if it holds one.
+func (m *Message) Verification() *Verification {
+	for _, part := range m.Parts {
+		if c, ok := part.(Verification); ok {
+			return &c
+		}
+	}
+	return nil
+}
+
 func (m *Message) ReasoningContent() ReasoningContent {
 	for _, part := range m.Parts {
 		if c, ok := part.(ReasoningContent); ok {
@@ -492,6 +553,11 @@ func (m *Message) ToAIMessage() []fantasy.Message {
 				MediaType: content.MIMEType,
 			})
 		}
+		// Verifications that weren't sent back to the agent are only shown
+		// to the user.
+		if len(parts) == 0 {
+			break
+		}
 		messages = append(messages, fantasy.Message{
 			Role:    fantasy.MessageRoleUser,
 			Content: parts,
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/crush/internal/db"
@@ -24,6 +25,7 @@ type Service interface {
 	pubsub.Subscriber[Message]
 	Create(ctx context.Context, sessionID string, params CreateMessageParams) (Message, error)
 	Update(ctx context.Context, message Message) error
+	UpdateStreaming(ctx context.Context, message Message) error
 	Get(ctx context.Context, id string) (Message, error)
 	List(ctx context.Context, sessionID string) ([]Message, error)
 	ListUserMessages(ctx context.Context, sessionID string) ([]Message, error)
@@ -35,12 +37,19 @@ type Service interface {
 type service struct {
 	*pubsub.Broker[Message]
 	q db.Querier
+
+	// writeMu serializes message writes so a deferred streaming write can't
+	// land after, and overwrite, a newer one.
+	writeMu   sync.Mutex
+	pendingMu sync.Mutex
+	pending   map[string]*pendingUpdate
 }
 
 func NewService(q db.Querier) Service {
 	return &service{
-		Broker: pubsub.NewBroker[Message](),
-		q:      q,
+		Broker:  pubsub.NewBrokerWithKey(func(m Message) string { return m.ID }),
+		q:       q,
+		pending: make(map[string]*pendingUpdate),
 	}
 }
 
@@ -49,7 +58,10 @@ func (s *service) Delete(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
+	s.writeMu.Lock()
+	s.takePending(message.ID)
 	err = s.q.DeleteMessage(ctx, message.ID)
+	s.writeMu.Unlock()
 	if err != nil {
 		return err
 	}
@@ -112,6 +124,23 @@ func (s *service) DeleteSessionMessages(ctx context.Context, sessionID string) e
 }
 
 func (s *service) Update(ctx context.Context, message Message) error {
+	s.writeMu.Lock()
+	// The message is written as a whole, so any pending streamed update is
+	// superseded by this one.
+	s.takePending(message.ID)
+	err := s.write(ctx, message)
+	s.writeMu.Unlock()
+	if err != nil {
+		return err
+	}
+	message.UpdatedAt = time.Now().Unix()
+	// Clone the message before publishing to avoid race conditions with
+	// concurrent modifications to the Parts slice.
+	s.Publish(pubsub.UpdatedEvent, message.Clone())
+	return nil
+}
+
+func (s *service) write(ctx context.Context, message Message) error {
 	parts, err := marshalParts(message.Parts)
 	if err != nil {
 		return err
@@ -121,22 +150,22 @@ func (s *service) Update(ctx context.Context, message Message) error {
 		finishedAt.Int64 = f.Time
 		finishedAt.Valid = true
 	}
-	err = s.q.UpdateMessage(ctx, db.UpdateMessageParams{
-		ID:         message.ID,
-		Parts:      string(parts),
-		FinishedAt: finishedAt,
+	return s.q.UpdateMessage(ctx, db.UpdateMessageParams{
+		ID:                  message.ID,
+		Parts:               string(parts),
+		FinishedAt:          finishedAt,
+		InputTokens:         message.Usage.InputTokens,
+		OutputTokens:        message.Usage.OutputTokens,
+		CacheReadTokens:     message.Usage.CacheReadTokens,
+		CacheCreationTokens: message.Usage.CacheCreationTokens,
+		Cost:                message.Usage.Cost,
 	})
-	if err != nil {
-		return err
-	}
-	message.UpdatedAt = time.Now().Unix()
-	// Clone the message before publishing to avoid race conditions with
-	// concurrent modifications to the Parts slice.
-	s.Publish(pubsub.UpdatedEvent, message.Clone())
-	return nil
 }
 
 func (s *service) Get(ctx context.Context, id string) (Message, error) {
+	if message, ok := s.pendingMessage(id); ok {
+		return message, nil
+	}
 	dbMessage, err := s.q.GetMessage(ctx, id)
 	if err != nil {
 		return Message{}, err
@@ -151,6 +180,10 @@ func (s *service) List(ctx context.Context, sessionID string) ([]Message, error)
 	}
 	messages := make([]Message, len(dbMessages))
 	for i, dbMessage := range dbMessages {
+		if message, ok := s.pendingMessage(dbMessage.ID); ok {
+			messages[i] = message
+			continue
+		}
 		messages[i], err = s.fromDBItem(dbMessage)
 		if err != nil {
 			return nil, err
@@ -204,6 +237,13 @@ func (s *service) fromDBItem(item db.Message) (Message, error) {
 		CreatedAt:        item.CreatedAt,
 		UpdatedAt:        item.UpdatedAt,
 		IsSummaryMessage: item.IsSummaryMessage != 0,
+		Usage: Usage{
+			InputTokens:         item.InputTokens,
+			OutputTokens:        item.OutputTokens,
+			CacheReadTokens:     item.CacheReadTokens,
+			CacheCreationTokens: item.CacheCreationTokens,
+			Cost:                item.Cost,
+		},
 	}, nil
 }
 
@@ -217,6 +257,7 @@ const (
 	toolCallType   partType = "tool_call"
 	toolResultType partType = "tool_result"
 	finishType     partType = "finish"
+	verifyType     partType = "verification"
 )
 
 type partWrapper struct {
@@ -245,6 +286,8 @@ func marshalParts(parts []ContentPart) ([]byte, error) {
 			typ = toolResultType
 		case Finish:
 			typ = finishType
+		case Verification:
+			typ = verifyType
 		default:
 			return nil, fmt.Errorf("unknown part type: %T", part)
 		}
@@ -319,6 +362,12 @@ func unmarshalParts(data []byte) ([]ContentPart, error) {
 				return nil, err
 			}
 			parts = append(parts, part)
+		case verifyType:
+			part := Verification{}
+			if err := json.Unmarshal(wrapper.Data, &part); err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
 		default:
 			return nil, fmt.Errorf("unknown part type: %s", wrapper.Type)
 		}
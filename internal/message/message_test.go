@@ -0,0 +1,149 @@
+package message
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/db"
+	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/stretchr/testify/require"
+)
+
+// countingQuerier counts the message writes that reach the database.
+type countingQuerier struct {
+	db.Querier
+	updates atomic.Int64
+}
+
+func (q *countingQuerier) UpdateMessage(ctx context.Context, arg db.UpdateMessageParams) error {
+	q.updates.Add(1)
+	return q.Querier.UpdateMessage(ctx, arg)
+}
+
+func newTestService(tb testing.TB) (*service, *countingQuerier, Message) {
+	tb.Helper()
+
+	conn, err := db.Connect(tb.Context(), tb.TempDir())
+	require.NoError(tb, err)
+	tb.Cleanup(func() { conn.Close() })
+
+	q := &countingQuerier{Querier: db.New(conn)}
+	_, err = q.CreateSession(tb.Context(), db.CreateSessionParams{ID: "session", Title: "test"})
+	require.NoError(tb, err)
+
+	s := NewService(q).(*service)
+	msg, err := s.Create(tb.Context(), "session", CreateMessageParams{Role: Assistant})
+	require.NoError(tb, err)
+	return s, q, msg
+}
+
+func storedText(t *testing.T, s *service, id string) string {
+	t.Helper()
+	item, err := s.q.GetMessage(t.Context(), id)
+	require.NoError(t, err)
+	msg, err := s.fromDBItem(item)
+	require.NoError(t, err)
+	return msg.Content().Text
+}
+
+func TestUpdateStreaming(t *testing.T) {
+	t.Parallel()
+
+	t.Run("coalesces writes until update", func(t *testing.T) {
+		t.Parallel()
+		s, q, msg := newTestService(t)
+		events := s.Subscribe(t.Context())
+
+		for range 10 {
+			msg.AppendContent("a")
+			require.NoError(t, s.UpdateStreaming(t.Context(), msg))
+		}
+		for range 10 {
+			event := <-events
+			require.Equal(t, pubsub.UpdatedEvent, event.Type)
+		}
+		require.Zero(t, q.updates.Load())
+
+		got, err := s.Get(t.Context(), msg.ID)
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("a", 10), got.Content().Text)
+		list, err := s.List(t.Context(), "session")
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("a", 10), list[0].Content().Text)
+
+		msg.AddFinish(FinishReasonEndTurn, "", "")
+		require.NoError(t, s.Update(t.Context(), msg))
+		require.Equal(t, int64(1), q.updates.Load())
+		require.Equal(t, strings.Repeat("a", 10), storedText(t, s, msg.ID))
+
+		// The superseded streaming write must not land later.
+		time.Sleep(2 * streamFlushInterval)
+		require.Equal(t, int64(1), q.updates.Load())
+	})
+
+	t.Run("writes within the flush interval", func(t *testing.T) {
+		t.Parallel()
+		s, _, msg := newTestService(t)
+
+		msg.AppendContent("hello")
+		require.NoError(t, s.UpdateStreaming(t.Context(), msg))
+		require.Eventually(t, func() bool {
+			return storedText(t, s, msg.ID) == "hello"
+		}, 4*streamFlushInterval, streamFlushInterval/10)
+	})
+
+	t.Run("writes once the size threshold is reached", func(t *testing.T) {
+		t.Parallel()
+		s, q, msg := newTestService(t)
+
+		msg.AppendContent("a")
+		require.NoError(t, s.UpdateStreaming(t.Context(), msg))
+		msg.AppendContent(strings.Repeat("b", streamFlushSize))
+		require.NoError(t, s.UpdateStreaming(t.Context(), msg))
+		require.Equal(t, int64(1), q.updates.Load())
+		require.Equal(t, msg.Content().Text, storedText(t, s, msg.ID))
+	})
+
+	t.Run("delete drops pending writes", func(t *testing.T) {
+		t.Parallel()
+		s, q, msg := newTestService(t)
+
+		msg.AppendContent("a")
+		require.NoError(t, s.UpdateStreaming(t.Context(), msg))
+		require.NoError(t, s.Delete(t.Context(), msg.ID))
+		time.Sleep(2 * streamFlushInterval)
+		require.Zero(t, q.updates.Load())
+	})
+}
+
+// BenchmarkStreamingUpdates compares writing every streamed delta to the
+// database with coalescing them through UpdateStreaming.
+func BenchmarkStreamingUpdates(b *testing.B) {
+	const deltas = 500
+
+	run := func(b *testing.B, update func(*service) func(context.Context, Message) error) {
+		s, q, _ := newTestService(b)
+		b.ResetTimer()
+		for range b.N {
+			msg, err := s.Create(b.Context(), "session", CreateMessageParams{Role: Assistant})
+			require.NoError(b, err)
+			for range deltas {
+				msg.AppendContent("token ")
+				require.NoError(b, update(s)(b.Context(), msg))
+			}
+			msg.AddFinish(FinishReasonEndTurn, "", "")
+			require.NoError(b, s.Update(b.Context(), msg))
+		}
+		b.ReportMetric(float64(q.updates.Load())/float64(b.N), "writes/op")
+	}
+
+	b.Run("Update", func(b *testing.B) {
+		run(b, func(s *service) func(context.Context, Message) error { return s.Update })
+	})
+	b.Run("UpdateStreaming", func(b *testing.B) {
+		run(b, func(s *service) func(context.Context, Message) error { return s.UpdateStreaming })
+	})
+}
@@ -0,0 +1,122 @@
+package message
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/charmbracelet/crush/internal/pubsub"
+)
+
+const (
+	// streamFlushInterval is the longest a streamed update is kept in memory
+	// before it's written to the database. It bounds how much of a streaming
+	// message can be lost if the process dies.
+	streamFlushInterval = 250 * time.Millisecond
+
+	// streamFlushSize is how much streamed content, in bytes, can build up
+	// before it's written to the database regardless of streamFlushInterval.
+	streamFlushSize = 4 * 1024
+)
+
+// pendingUpdate is a streamed message state that hasn't been written to the
+// database yet.
+type pendingUpdate struct {
+	message Message
+	// size is the content size of the message when it was last written.
+	size  int
+	timer *time.Timer
+}
+
+// UpdateStreaming updates a message that is being streamed. Subscribers are
+// notified right away, but the database write is deferred and coalesced
+// with the updates that follow it: the latest state is written at most
+// streamFlushInterval later, or sooner once streamFlushSize bytes of new
+// content have built up. A call to Update for the same message supersedes
+// any pending write, so callers should use Update for the final state of a
+// message, and whenever it must be on disk, such as before tool calls.
+func (s *service) UpdateStreaming(ctx context.Context, message Message) error {
+	message.UpdatedAt = time.Now().Unix()
+	size := contentSize(message)
+
+	s.pendingMu.Lock()
+	p, ok := s.pending[message.ID]
+	if !ok {
+		p = &pendingUpdate{size: size}
+		id := message.ID
+		p.timer = time.AfterFunc(streamFlushInterval, func() {
+			if err := s.flush(context.Background(), id); err != nil {
+				slog.Error("Failed to write streamed message", "id", id, "error", err)
+			}
+		})
+		s.pending[message.ID] = p
+	}
+	p.message = message.Clone()
+	full := size-p.size >= streamFlushSize
+	s.pendingMu.Unlock()
+
+	// Clone the message before publishing to avoid race conditions with
+	// concurrent modifications to the Parts slice.
+	s.Publish(pubsub.UpdatedEvent, message.Clone())
+
+	if full {
+		return s.flush(ctx, message.ID)
+	}
+	return nil
+}
+
+// flush writes the pending update of a message, if any, to the database.
+func (s *service) flush(ctx context.Context, id string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	p := s.takePending(id)
+	if p == nil {
+		return nil
+	}
+	return s.write(ctx, p.message)
+}
+
+// takePending removes and returns the pending update of a message, stopping
+// its timer. It returns nil if there is none.
+func (s *service) takePending(id string) *pendingUpdate {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	p, ok := s.pending[id]
+	if !ok {
+		return nil
+	}
+	p.timer.Stop()
+	delete(s.pending, id)
+	return p
+}
+
+// pendingMessage returns the in-memory state of a message that has streamed
+// updates not yet written to the database.
+func (s *service) pendingMessage(id string) (Message, bool) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	p, ok := s.pending[id]
+	if !ok {
+		return Message{}, false
+	}
+	return p.message.Clone(), true
+}
+
+// contentSize returns the size of the streamable content of a message.
+func contentSize(message Message) int {
+	var size int
+	for _, part := range message.Parts {
+		switch c := part.(type) {
+		case TextContent:
+			size += len(c.Text)
+		case ReasoningContent:
+			size += len(c.Thinking)
+		case ToolCall:
+			size += len(c.Input)
+		}
+	}
+	return size
+}
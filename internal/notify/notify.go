@@ -0,0 +1,189 @@
+// Package notify notifies the user about agent events with the terminal
+// bell, a desktop notification or a command, e.g. when the agent finishes
+// or a tool needs permission.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/charmbracelet/crush/internal/shell"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Event identifies what a notification is about.
+type Event string
+
+const (
+	EventAgentFinished       Event = "agent_finished"
+	EventPermissionRequested Event = "permission_requested"
+)
+
+const (
+	title          = "Crush"
+	commandTimeout = 10 * time.Second
+)
+
+// Notification is a notification about an event.
+type Notification struct {
+	Event Event
+	Title string
+	Body  string
+}
+
+// AgentFinished returns the notification of the agent finishing to run the
+// session with the given title, possibly with an error.
+func AgentFinished(sessionTitle string, err error) Notification {
+	body := "Agent finished"
+	if err != nil {
+		body = "Agent failed: " + err.Error()
+	}
+	if sessionTitle != "" {
+		body += " in " + sessionTitle
+	}
+	return Notification{Event: EventAgentFinished, Title: title, Body: body}
+}
+
+// PermissionRequested returns the notification of a tool needing
+// permission.
+func PermissionRequested(req permission.PermissionRequest) Notification {
+	body := fmt.Sprintf("%s needs permission", req.ToolName)
+	if req.Description != "" {
+		body += ": " + req.Description
+	}
+	return Notification{Event: EventPermissionRequested, Title: title, Body: body}
+}
+
+// Notifier delivers notifications as configured. It tracks whether the
+// terminal is focused and is meant to be driven by the update loop of the
+// TUI.
+type Notifier struct {
+	cfg     config.Notifications
+	focused bool
+}
+
+// New creates a notifier with the given configuration.
+func New(cfg config.Notifications) *Notifier {
+	return &Notifier{cfg: cfg}
+}
+
+// Enabled reports whether notifications are enabled at all.
+func (n *Notifier) Enabled() bool {
+	switch n.cfg.Method {
+	case "", config.NotificationMethodNone:
+		return false
+	}
+	return true
+}
+
+// ReportFocus reports whether the terminal should report focus changes to
+// the notifier.
+func (n *Notifier) ReportFocus() bool {
+	return n.Enabled() && boolOr(n.cfg.OnlyWhenUnfocused, true)
+}
+
+// SetFocused records whether the terminal is focused. Until the terminal
+// reports its focus, it is assumed not to be.
+func (n *Notifier) SetFocused(focused bool) {
+	n.focused = focused
+}
+
+// Notify returns a command delivering the notification, or nil if it is
+// disabled.
+func (n *Notifier) Notify(notification Notification) tea.Cmd {
+	if !n.wants(notification.Event) {
+		return nil
+	}
+	switch n.cfg.Method {
+	case config.NotificationMethodBell:
+		return tea.Raw(string(rune(ansi.BEL)))
+	case config.NotificationMethodOSC9, config.NotificationMethodOSC777:
+		return tea.Raw(Sequence(n.cfg.Method, notification))
+	case config.NotificationMethodCommand:
+		return n.run(notification)
+	}
+	return nil
+}
+
+func (n *Notifier) wants(event Event) bool {
+	if !n.Enabled() || (n.focused && boolOr(n.cfg.OnlyWhenUnfocused, true)) {
+		return false
+	}
+	switch event {
+	case EventAgentFinished:
+		return boolOr(n.cfg.AgentFinished, true)
+	case EventPermissionRequested:
+		return boolOr(n.cfg.PermissionRequested, true)
+	}
+	return false
+}
+
+// run returns a command running the notify command with the notification
+// in its environment.
+func (n *Notifier) run(notification Notification) tea.Cmd {
+	command := n.cfg.Command
+	if strings.TrimSpace(command) == "" {
+		slog.Warn("Notifications use the command method but no command is configured")
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+
+		sh := shell.NewShell(&shell.Options{
+			Env: append(
+				os.Environ(),
+				"CRUSH_NOTIFY_EVENT="+string(notification.Event),
+				"CRUSH_NOTIFY_TITLE="+notification.Title,
+				"CRUSH_NOTIFY_BODY="+notification.Body,
+			),
+		})
+		if _, stderr, err := sh.Exec(ctx, command); err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = ctx.Err()
+			}
+			slog.Warn("Notify command failed", "error", err, "stderr", stderr)
+		}
+		return nil
+	}
+}
+
+// Sequence returns the escape sequence of a desktop notification with the
+// given OSC method.
+func Sequence(method config.NotificationMethod, notification Notification) string {
+	title, body := sanitize(notification.Title), sanitize(notification.Body)
+	if method == config.NotificationMethodOSC777 {
+		// The title is delimited by semicolons, the body is not.
+		return "\x1b]777;notify;" + strings.ReplaceAll(title, ";", ",") + ";" + body + "\x07"
+	}
+	return ansi.Notify(title + ": " + body)
+}
+
+// sanitize removes the control characters that would end the escape
+// sequence early.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			if r == '\n' || r == '\t' {
+				return ' '
+			}
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func boolOr(b *bool, def bool) bool {
+	if b == nil {
+		return def
+	}
+	return *b
+}
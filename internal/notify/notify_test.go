@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/permission"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	disabled := false
+	finished := AgentFinished("", nil)
+	requested := PermissionRequested(permission.PermissionRequest{ToolName: "bash"})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+		n := New(config.Notifications{})
+		require.False(t, n.Enabled())
+		require.False(t, n.ReportFocus())
+		require.Nil(t, n.Notify(finished))
+	})
+
+	t.Run("per event toggles", func(t *testing.T) {
+		t.Parallel()
+		n := New(config.Notifications{
+			Method:        config.NotificationMethodBell,
+			AgentFinished: &disabled,
+		})
+		require.Nil(t, n.Notify(finished))
+		require.NotNil(t, n.Notify(requested))
+	})
+
+	t.Run("only when unfocused", func(t *testing.T) {
+		t.Parallel()
+		n := New(config.Notifications{Method: config.NotificationMethodOSC9})
+		require.True(t, n.ReportFocus())
+		require.NotNil(t, n.Notify(finished))
+		n.SetFocused(true)
+		require.Nil(t, n.Notify(finished))
+		n.SetFocused(false)
+		require.NotNil(t, n.Notify(finished))
+	})
+
+	t.Run("also when focused", func(t *testing.T) {
+		t.Parallel()
+		n := New(config.Notifications{
+			Method:            config.NotificationMethodOSC9,
+			OnlyWhenUnfocused: &disabled,
+		})
+		require.False(t, n.ReportFocus())
+		n.SetFocused(true)
+		require.NotNil(t, n.Notify(finished))
+	})
+
+	t.Run("command without command", func(t *testing.T) {
+		t.Parallel()
+		n := New(config.Notifications{Method: config.NotificationMethodCommand})
+		require.Nil(t, n.Notify(finished))
+	})
+}
+
+func TestNotifications(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "Agent finished in Fix the bug", AgentFinished("Fix the bug", nil).Body)
+	require.Equal(t, "Agent failed: boom", AgentFinished("", errors.New("boom")).Body)
+	require.Equal(t, "bash needs permission: Run tests", PermissionRequested(permission.PermissionRequest{
+		ToolName:    "bash",
+		Description: "Run tests",
+	}).Body)
+}
+
+func TestSequence(t *testing.T) {
+	t.Parallel()
+
+	n := Notification{Title: "Crush; dev", Body: "done;\nnow\x1b\x07"}
+	require.Equal(t, "\x1b]9;Crush; dev: done; now\x07", Sequence(config.NotificationMethodOSC9, n))
+	require.Equal(t, "\x1b]777;notify;Crush, dev;done; now\x07", Sequence(config.NotificationMethodOSC777, n))
+}
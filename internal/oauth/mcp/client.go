@@ -0,0 +1,219 @@
+package mcp
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/oauth"
+	"golang.org/x/oauth2"
+)
+
+// ErrRegistrationNotSupported is returned when the authorization server does
+// not support dynamic client registration.
+var ErrRegistrationNotSupported = errors.New("authorization server does not support dynamic client registration")
+
+// Client is an OAuth client registered with the authorization server of an
+// MCP server, along with the endpoints needed to obtain and refresh tokens.
+type Client struct {
+	ClientID      string   `json:"client_id"`
+	ClientSecret  string   `json:"client_secret,omitempty"`
+	AuthURL       string   `json:"auth_url"`
+	TokenURL      string   `json:"token_url"`
+	DeviceAuthURL string   `json:"device_auth_url,omitempty"`
+	Resource      string   `json:"resource,omitempty"`
+	Scopes        []string `json:"scopes,omitempty"`
+}
+
+type registrationRequest struct {
+	ClientName              string   `json:"client_name"`
+	ClientURI               string   `json:"client_uri,omitempty"`
+	RedirectURIs            []string `json:"redirect_uris,omitempty"`
+	GrantTypes              []string `json:"grant_types"`
+	ResponseTypes           []string `json:"response_types"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method"`
+	Scope                   string   `json:"scope,omitempty"`
+}
+
+type registrationResponse struct {
+	ClientID         string `json:"client_id"`
+	ClientSecret     string `json:"client_secret,omitempty"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// Register registers Crush as a public client with the authorization server
+// using dynamic client registration (RFC 7591).
+func Register(ctx context.Context, meta *Metadata, redirectURIs []string) (*Client, error) {
+	if meta.RegistrationEndpoint == "" {
+		return nil, ErrRegistrationNotSupported
+	}
+
+	grantTypes := []string{"authorization_code", "refresh_token"}
+	if meta.DeviceAuthorizationEndpoint != "" {
+		grantTypes = append(grantTypes, "urn:ietf:params:oauth:grant-type:device_code")
+	}
+	body, err := json.Marshal(registrationRequest{
+		ClientName:              "Crush",
+		ClientURI:               "https://github.com/charmbracelet/crush",
+		RedirectURIs:            redirectURIs,
+		GrantTypes:              grantTypes,
+		ResponseTypes:           []string{"code"},
+		TokenEndpointAuthMethod: "none",
+		Scope:                   strings.Join(meta.Scopes, " "),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal registration request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.RegistrationEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	var result registrationResponse
+	_ = json.Unmarshal(respBody, &result)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		if result.ErrorDescription != "" {
+			return nil, fmt.Errorf("client registration failed: %s", result.ErrorDescription)
+		}
+		return nil, fmt.Errorf("client registration failed: status %d, body %q", resp.StatusCode, string(respBody))
+	}
+	if result.ClientID == "" {
+		return nil, fmt.Errorf("client registration failed: no client id returned")
+	}
+
+	return &Client{
+		ClientID:      result.ClientID,
+		ClientSecret:  result.ClientSecret,
+		AuthURL:       meta.AuthorizationEndpoint,
+		TokenURL:      meta.TokenEndpoint,
+		DeviceAuthURL: meta.DeviceAuthorizationEndpoint,
+		Resource:      meta.Resource,
+		Scopes:        meta.Scopes,
+	}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access token.
+func RefreshToken(ctx context.Context, client *Client, refreshToken string) (*oauth.Token, error) {
+	if client == nil {
+		return nil, errors.New("no oauth client registered")
+	}
+	if refreshToken == "" {
+		return nil, errors.New("no refresh token available")
+	}
+
+	// The token source of x/oauth2 can't send the resource indicator, so
+	// the refresh request is made by hand.
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {client.ClientID},
+	}
+	if client.ClientSecret != "" {
+		form.Set("client_secret", client.ClientSecret)
+	}
+	if client.Resource != "" {
+		form.Set("resource", client.Resource)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+
+	var result struct {
+		oauth.Token
+		Error            string `json:"error,omitempty"`
+		ErrorDescription string `json:"error_description,omitempty"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.AccessToken == "" {
+		if result.Error != "" {
+			return nil, fmt.Errorf("token refresh failed: %s", cmp.Or(result.ErrorDescription, result.Error))
+		}
+		return nil, fmt.Errorf("token refresh failed: status %d, body %q", resp.StatusCode, string(body))
+	}
+
+	token := result.Token
+	if token.ExpiresIn > 0 {
+		token.SetExpiresAt()
+	}
+	if token.RefreshToken == "" {
+		// Servers may keep the current refresh token valid instead of
+		// rotating it.
+		token.RefreshToken = refreshToken
+	}
+	return &token, nil
+}
+
+func (c *Client) config(redirectURL string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:       c.AuthURL,
+			TokenURL:      c.TokenURL,
+			DeviceAuthURL: c.DeviceAuthURL,
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+		RedirectURL: redirectURL,
+		Scopes:      c.Scopes,
+	}
+}
+
+// authOptions adds the resource indicator (RFC 8707) MCP servers require on
+// authorization and token requests to the given options, when it is known.
+func (c *Client) authOptions(opts ...oauth2.AuthCodeOption) []oauth2.AuthCodeOption {
+	if c.Resource == "" {
+		return opts
+	}
+	return append(opts, oauth2.SetAuthURLParam("resource", c.Resource))
+}
+
+func toToken(t *oauth2.Token) *oauth.Token {
+	token := &oauth.Token{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+	}
+	if !t.Expiry.IsZero() {
+		token.ExpiresAt = t.Expiry.Unix()
+		token.SetExpiresIn()
+	}
+	return token
+}
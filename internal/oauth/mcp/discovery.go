@@ -0,0 +1,175 @@
+// Package mcp implements the OAuth 2.1 authorization flow of the Model Context
+// Protocol for remote HTTP and SSE MCP servers.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNoAuthorizationServer is returned when an MCP server does not advertise
+// an authorization server.
+var ErrNoAuthorizationServer = errors.New("mcp server does not support oauth authorization")
+
+// Metadata describes how to authorize with an MCP server.
+type Metadata struct {
+	// Resource is the canonical URI of the MCP server, sent as the resource
+	// indicator when requesting tokens.
+	Resource string
+	// Scopes are the scopes supported by the MCP server, if advertised.
+	Scopes []string
+
+	Issuer                      string   `json:"issuer"`
+	AuthorizationEndpoint       string   `json:"authorization_endpoint"`
+	TokenEndpoint               string   `json:"token_endpoint"`
+	RegistrationEndpoint        string   `json:"registration_endpoint,omitempty"`
+	DeviceAuthorizationEndpoint string   `json:"device_authorization_endpoint,omitempty"`
+	CodeChallengeMethods        []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// protectedResourceMetadata is the OAuth 2.0 Protected Resource Metadata
+// (RFC 9728) served by MCP servers.
+type protectedResourceMetadata struct {
+	Resource             string   `json:"resource"`
+	AuthorizationServers []string `json:"authorization_servers"`
+	ScopesSupported      []string `json:"scopes_supported,omitempty"`
+}
+
+var resourceMetadataRe = regexp.MustCompile(`resource_metadata="([^"]+)"`)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Discover finds the authorization server of the MCP server at serverURL and
+// returns its metadata.
+//
+// The protected resource metadata URL is taken from the WWW-Authenticate
+// header of an unauthorized response, falling back to the well-known
+// location. Servers that predate protected resource metadata are assumed to
+// be their own authorization server.
+func Discover(ctx context.Context, serverURL string) (*Metadata, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mcp url: %w", err)
+	}
+	origin := u.Scheme + "://" + u.Host
+
+	resource := protectedResourceMetadata{
+		Resource:             serverURL,
+		AuthorizationServers: []string{origin},
+	}
+	for _, metadataURL := range resourceMetadataURLs(ctx, serverURL, u) {
+		var prm protectedResourceMetadata
+		if err := getJSON(ctx, metadataURL, &prm); err != nil {
+			continue
+		}
+		if len(prm.AuthorizationServers) == 0 {
+			return nil, ErrNoAuthorizationServer
+		}
+		resource = prm
+		if resource.Resource == "" {
+			resource.Resource = serverURL
+		}
+		break
+	}
+
+	issuer := resource.AuthorizationServers[0]
+	meta, err := authorizationServerMetadata(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	meta.Resource = resource.Resource
+	meta.Scopes = resource.ScopesSupported
+	return meta, nil
+}
+
+// resourceMetadataURLs returns the candidate URLs of the protected resource
+// metadata of the server, in order of preference.
+func resourceMetadataURLs(ctx context.Context, serverURL string, u *url.URL) []string {
+	var urls []string
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverURL, nil)
+	if err == nil {
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if resp, err := httpClient.Do(req); err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusUnauthorized {
+				if m := resourceMetadataRe.FindStringSubmatch(resp.Header.Get("WWW-Authenticate")); m != nil {
+					urls = append(urls, m[1])
+				}
+			}
+		}
+	}
+	origin := u.Scheme + "://" + u.Host
+	if path := strings.TrimSuffix(u.Path, "/"); path != "" {
+		urls = append(urls, origin+"/.well-known/oauth-protected-resource"+path)
+	}
+	return append(urls, origin+"/.well-known/oauth-protected-resource")
+}
+
+// authorizationServerMetadata fetches the metadata of an authorization
+// server (RFC 8414), falling back to OpenID Connect discovery and, for
+// servers offering neither, to the default endpoint paths.
+func authorizationServerMetadata(ctx context.Context, issuer string) (*Metadata, error) {
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authorization server %q: %w", issuer, err)
+	}
+	origin := u.Scheme + "://" + u.Host
+	path := strings.TrimSuffix(u.Path, "/")
+
+	candidates := []string{
+		origin + "/.well-known/oauth-authorization-server" + path,
+		origin + "/.well-known/openid-configuration" + path,
+	}
+	if path != "" {
+		candidates = append(candidates, issuer+"/.well-known/openid-configuration")
+	}
+	for _, candidate := range candidates {
+		var meta Metadata
+		if err := getJSON(ctx, candidate, &meta); err != nil {
+			continue
+		}
+		if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" {
+			continue
+		}
+		return &meta, nil
+	}
+
+	return &Metadata{
+		Issuer:                issuer,
+		AuthorizationEndpoint: origin + "/authorize",
+		TokenEndpoint:         origin + "/token",
+		RegistrationEndpoint:  origin + "/register",
+	}, nil
+}
+
+func getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get %s: status %d", url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
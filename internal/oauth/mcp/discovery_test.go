@@ -0,0 +1,115 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer resource_metadata="`+srv.URL+`/prm"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/prm", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"resource":              srv.URL + "/mcp",
+			"authorization_servers": []string{srv.URL + "/auth"},
+			"scopes_supported":      []string{"read"},
+		})
+	})
+	mux.HandleFunc("/.well-known/oauth-authorization-server/auth", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 srv.URL + "/auth",
+			"authorization_endpoint": srv.URL + "/auth/authorize",
+			"token_endpoint":         srv.URL + "/auth/token",
+			"registration_endpoint":  srv.URL + "/auth/register",
+		})
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	meta, err := Discover(t.Context(), srv.URL+"/mcp")
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/mcp", meta.Resource)
+	require.Equal(t, []string{"read"}, meta.Scopes)
+	require.Equal(t, srv.URL+"/auth/authorize", meta.AuthorizationEndpoint)
+	require.Equal(t, srv.URL+"/auth/token", meta.TokenEndpoint)
+	require.Equal(t, srv.URL+"/auth/register", meta.RegistrationEndpoint)
+}
+
+func TestDiscover_DefaultEndpoints(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	meta, err := Discover(t.Context(), srv.URL+"/mcp")
+	require.NoError(t, err)
+	require.Equal(t, srv.URL+"/mcp", meta.Resource)
+	require.Equal(t, srv.URL+"/authorize", meta.AuthorizationEndpoint)
+	require.Equal(t, srv.URL+"/token", meta.TokenEndpoint)
+	require.Equal(t, srv.URL+"/register", meta.RegistrationEndpoint)
+}
+
+func TestRefreshToken_KeepsRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		require.Equal(t, "old-refresh", r.PostForm.Get("refresh_token"))
+		require.Equal(t, "https://example.com/mcp", r.PostForm.Get("resource"))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "new-access",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	token, err := RefreshToken(t.Context(), &Client{
+		ClientID: "crush",
+		TokenURL: srv.URL,
+		Resource: "https://example.com/mcp",
+	}, "old-refresh")
+	require.NoError(t, err)
+	require.Equal(t, "new-access", token.AccessToken)
+	require.Equal(t, "old-refresh", token.RefreshToken)
+	require.False(t, token.IsExpired())
+}
+
+func TestRegister_WithoutRedirectURIs(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.NotContains(t, body, "redirect_uris")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"client_id": "crush"})
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := Register(t.Context(), &Metadata{RegistrationEndpoint: srv.URL}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "crush", client.ClientID)
+}
+
+func TestClient_AuthOptions(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{ClientID: "crush", AuthURL: "https://example.com/authorize"}
+	authURL := client.config("").AuthCodeURL("state", client.authOptions()...)
+	require.NotContains(t, authURL, "resource=")
+
+	client.Resource = "https://example.com/mcp"
+	authURL = client.config("").AuthCodeURL("state", client.authOptions()...)
+	require.Contains(t, authURL, "resource=https%3A%2F%2Fexample.com%2Fmcp")
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+
+	"github.com/charmbracelet/crush/internal/oauth"
+	"golang.org/x/oauth2"
+)
+
+// ErrDeviceFlowNotSupported is returned when the authorization server does
+// not support the device authorization grant.
+var ErrDeviceFlowNotSupported = errors.New("authorization server does not support the device flow")
+
+// CallbackPath is the path of the local redirect URI used by the browser
+// flow.
+const CallbackPath = "/callback"
+
+// Listener is a local HTTP server receiving the authorization code at the
+// end of the browser flow.
+type Listener struct {
+	ln net.Listener
+}
+
+// Listen starts listening for the authorization callback on a random
+// loopback port. The redirect URI must be known before registering the
+// client, so the listener is created first.
+func Listen() (*Listener, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("could not listen for the oauth callback: %w", err)
+	}
+	return &Listener{ln: ln}, nil
+}
+
+// RedirectURI returns the redirect URI to register with the authorization
+// server.
+func (l *Listener) RedirectURI() string {
+	return fmt.Sprintf("http://%s%s", l.ln.Addr().String(), CallbackPath)
+}
+
+// Close stops the listener.
+func (l *Listener) Close() error {
+	return l.ln.Close()
+}
+
+// AuthorizeBrowser runs the authorization code flow with PKCE. The user is
+// sent to the authorization URL through open, and the authorization code is
+// received by the listener.
+func AuthorizeBrowser(ctx context.Context, client *Client, l *Listener, open func(url string)) (*oauth.Token, error) {
+	conf := client.config(l.RedirectURI())
+	verifier := oauth2.GenerateVerifier()
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != CallbackPath {
+				http.NotFound(w, r)
+				return
+			}
+			q := r.URL.Query()
+			var res result
+			switch {
+			case q.Get("error") != "":
+				res.err = fmt.Errorf("authorization failed: %s", cmp.Or(q.Get("error_description"), q.Get("error")))
+			case q.Get("state") != state:
+				res.err = errors.New("authorization failed: state mismatch")
+			case q.Get("code") == "":
+				res.err = errors.New("authorization failed: no code received")
+			default:
+				res.code = q.Get("code")
+			}
+			writeCallbackPage(w, res.err)
+			select {
+			case results <- res:
+			default:
+			}
+		}),
+	}
+	go func() { _ = srv.Serve(l.ln) }()
+	defer srv.Close()
+
+	open(conf.AuthCodeURL(state, client.authOptions(oauth2.S256ChallengeOption(verifier))...))
+
+	var res result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-results:
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	token, err := conf.Exchange(ctx, res.code, client.authOptions(oauth2.VerifierOption(verifier))...)
+	if err != nil {
+		return nil, fmt.Errorf("could not exchange authorization code: %w", err)
+	}
+	return toToken(token), nil
+}
+
+// DeviceCode holds what the user needs to complete the device flow.
+type DeviceCode struct {
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+}
+
+// AuthorizeDevice runs the device authorization grant (RFC 8628). The user
+// code is passed to prompt, and the function blocks until the user completed
+// the authorization or the code expired.
+func AuthorizeDevice(ctx context.Context, client *Client, prompt func(DeviceCode)) (*oauth.Token, error) {
+	if client.DeviceAuthURL == "" {
+		return nil, ErrDeviceFlowNotSupported
+	}
+	conf := client.config("")
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+
+	resp, err := conf.DeviceAuth(ctx, client.authOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("could not request device code: %w", err)
+	}
+	prompt(DeviceCode{
+		UserCode:                resp.UserCode,
+		VerificationURI:         resp.VerificationURI,
+		VerificationURIComplete: resp.VerificationURIComplete,
+	})
+
+	token, err := conf.DeviceAccessToken(ctx, resp, client.authOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization failed: %w", err)
+	}
+	return toToken(token), nil
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func writeCallbackPage(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	msg := "You're now authenticated. You can close this window and return to Crush."
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		msg = err.Error()
+	}
+	fmt.Fprintf(w, "<!doctype html><html><head><title>Crush</title></head><body><p>%s</p></body></html>", html.EscapeString(msg))
+}
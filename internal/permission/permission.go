@@ -51,7 +51,7 @@ type Service interface {
 	AutoApproveSession(sessionID string)
 	SetSkipRequests(skip bool)
 	SkipRequests() bool
-	SubscribeNotifications(ctx context.Context) <-chan pubsub.Event[PermissionNotification]
+	SubscribeNotifications(ctx context.Context, opts ...pubsub.SubscribeOption) <-chan pubsub.Event[PermissionNotification]
 }
 
 type permissionService struct {
@@ -221,8 +221,8 @@ func (s *permissionService) AutoApproveSession(sessionID string) {
 	s.autoApproveSessionsMu.Unlock()
 }
 
-func (s *permissionService) SubscribeNotifications(ctx context.Context) <-chan pubsub.Event[PermissionNotification] {
-	return s.notificationBroker.Subscribe(ctx)
+func (s *permissionService) SubscribeNotifications(ctx context.Context, opts ...pubsub.SubscribeOption) <-chan pubsub.Event[PermissionNotification] {
+	return s.notificationBroker.Subscribe(ctx, opts...)
 }
 
 func (s *permissionService) SetSkipRequests(skip bool) {
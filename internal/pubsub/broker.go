@@ -2,28 +2,56 @@ package pubsub
 
 import (
 	"context"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 )
 
 const bufferSize = 64
 
 type Broker[T any] struct {
-	subs      map[chan Event[T]]struct{}
-	mu        sync.RWMutex
-	done      chan struct{}
-	subCount  int
-	maxEvents int
+	subs       map[*subscription[T]]struct{}
+	mu         sync.RWMutex
+	done       chan struct{}
+	subCount   int
+	maxEvents  int
+	bufferSize int
+	key        func(T) string
+
+	published atomic.Int64
+	dropped   atomic.Int64
+	coalesced atomic.Int64
+}
+
+// Stats holds the event counters of a broker.
+type Stats struct {
+	Subscribers int
+	Published   int64
+	// Dropped is the number of events that didn't reach a subscriber.
+	Dropped int64
+	// Coalesced is the number of events merged into a newer event for the
+	// same key before a subscriber received them.
+	Coalesced int64
 }
 
 func NewBroker[T any]() *Broker[T] {
 	return NewBrokerWithOptions[T](bufferSize, 1000)
 }
 
+// NewBrokerWithKey returns a broker whose payloads are identified by key,
+// which lets [Coalesce] subscriptions merge updates to the same item.
+func NewBrokerWithKey[T any](key func(T) string) *Broker[T] {
+	b := NewBroker[T]()
+	b.key = key
+	return b
+}
+
 func NewBrokerWithOptions[T any](channelBufferSize, maxEvents int) *Broker[T] {
 	return &Broker[T]{
-		subs:      make(map[chan Event[T]]struct{}),
-		done:      make(chan struct{}),
-		maxEvents: maxEvents,
+		subs:       make(map[*subscription[T]]struct{}),
+		done:       make(chan struct{}),
+		maxEvents:  maxEvents,
+		bufferSize: channelBufferSize,
 	}
 }
 
@@ -38,15 +66,15 @@ func (b *Broker[T]) Shutdown() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	for ch := range b.subs {
-		delete(b.subs, ch)
-		close(ch)
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		sub.close()
 	}
 
 	b.subCount = 0
 }
 
-func (b *Broker[T]) Subscribe(ctx context.Context) <-chan Event[T] {
+func (b *Broker[T]) Subscribe(ctx context.Context, opts ...SubscribeOption) <-chan Event[T] {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
@@ -58,7 +86,21 @@ func (b *Broker[T]) Subscribe(ctx context.Context) <-chan Event[T] {
 	default:
 	}
 
-	sub := make(chan Event[T], bufferSize)
+	var cfg subscribeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	sub := &subscription[T]{
+		ctx:    ctx,
+		ch:     make(chan Event[T], b.bufferSize),
+		policy: cfg.policy,
+	}
+	if sub.policy == Coalesce {
+		sub.key = b.key
+		sub.index = make(map[string]int)
+		sub.wake = make(chan struct{}, 1)
+		go b.pump(sub)
+	}
 	b.subs[sub] = struct{}{}
 	b.subCount++
 
@@ -75,11 +117,11 @@ func (b *Broker[T]) Subscribe(ctx context.Context) <-chan Event[T] {
 		}
 
 		delete(b.subs, sub)
-		close(sub)
+		sub.close()
 		b.subCount--
 	}()
 
-	return sub
+	return sub.ch
 }
 
 func (b *Broker[T]) GetSubscriberCount() int {
@@ -88,6 +130,16 @@ func (b *Broker[T]) GetSubscriberCount() int {
 	return b.subCount
 }
 
+// Stats returns the event counters of the broker.
+func (b *Broker[T]) Stats() Stats {
+	return Stats{
+		Subscribers: b.GetSubscriberCount(),
+		Published:   b.published.Load(),
+		Dropped:     b.dropped.Load(),
+		Coalesced:   b.coalesced.Load(),
+	}
+}
+
 func (b *Broker[T]) Publish(t EventType, payload T) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -98,14 +150,76 @@ func (b *Broker[T]) Publish(t EventType, payload T) {
 	default:
 	}
 
+	b.published.Add(1)
 	event := Event[T]{Type: t, Payload: payload}
 
 	for sub := range b.subs {
+		b.deliver(sub, event)
+	}
+}
+
+// deliver hands an event to a subscription according to its policy.
+func (b *Broker[T]) deliver(sub *subscription[T], event Event[T]) {
+	switch sub.policy {
+	case Block:
 		select {
-		case sub <- event:
+		case sub.ch <- event:
+		case <-sub.ctx.Done():
+		case <-b.done:
+		}
+	case DropOldest:
+		for {
+			select {
+			case sub.ch <- event:
+				return
+			default:
+			}
+			select {
+			case old := <-sub.ch:
+				b.drop(sub, old)
+			default:
+			}
+		}
+	case Coalesce:
+		if sub.push(event) {
+			b.coalesced.Add(1)
+		}
+	default:
+		select {
+		case sub.ch <- event:
 		default:
 			// Channel is full, subscriber is slow - skip this event
 			// This prevents blocking the publisher
+			b.drop(sub, event)
+		}
+	}
+}
+
+func (b *Broker[T]) drop(sub *subscription[T], event Event[T]) {
+	dropped := b.dropped.Add(1)
+	slog.Debug("Event dropped due to slow subscriber", "policy", sub.policy, "type", event.Type, "dropped", dropped)
+}
+
+// pump forwards the queued events of a [Coalesce] subscription to its
+// channel. It owns the channel, and closes it once the subscription ends.
+func (b *Broker[T]) pump(sub *subscription[T]) {
+	defer close(sub.ch)
+	for {
+		select {
+		case <-sub.wake:
+		case <-sub.ctx.Done():
+			return
+		case <-b.done:
+			return
+		}
+		for _, event := range sub.take() {
+			select {
+			case sub.ch <- event:
+			case <-sub.ctx.Done():
+				return
+			case <-b.done:
+				return
+			}
 		}
 	}
 }
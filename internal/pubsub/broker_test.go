@@ -0,0 +1,165 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID    string
+	Value int
+}
+
+func newTestBroker() *Broker[item] {
+	b := NewBrokerWithKey(func(i item) string { return i.ID })
+	b.bufferSize = 2
+	return b
+}
+
+func receive(t *testing.T, ch <-chan Event[item], n int) []Event[item] {
+	t.Helper()
+	events := make([]Event[item], 0, n)
+	for range n {
+		select {
+		case event := <-ch:
+			events = append(events, event)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d events", len(events), n)
+		}
+	}
+	return events
+}
+
+func values(events []Event[item]) []int {
+	var vs []int
+	for _, e := range events {
+		vs = append(vs, e.Payload.Value)
+	}
+	return vs
+}
+
+func TestBrokerPolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drop newest", func(t *testing.T) {
+		t.Parallel()
+		b := newTestBroker()
+		ch := b.Subscribe(t.Context())
+		for i := range 4 {
+			b.Publish(UpdatedEvent, item{ID: "a", Value: i})
+		}
+		require.Equal(t, []int{0, 1}, values(receive(t, ch, 2)))
+		require.Equal(t, int64(2), b.Stats().Dropped)
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		t.Parallel()
+		b := newTestBroker()
+		ch := b.Subscribe(t.Context(), WithPolicy(DropOldest))
+		for i := range 4 {
+			b.Publish(UpdatedEvent, item{ID: "a", Value: i})
+		}
+		require.Equal(t, []int{2, 3}, values(receive(t, ch, 2)))
+		require.Equal(t, int64(2), b.Stats().Dropped)
+	})
+
+	t.Run("block", func(t *testing.T) {
+		t.Parallel()
+		b := newTestBroker()
+		ch := b.Subscribe(t.Context(), WithPolicy(Block))
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 4 {
+				b.Publish(UpdatedEvent, item{ID: "a", Value: i})
+			}
+		}()
+		require.Equal(t, []int{0, 1, 2, 3}, values(receive(t, ch, 4)))
+		<-done
+		require.Zero(t, b.Stats().Dropped)
+	})
+
+	t.Run("block ends with the subscription", func(t *testing.T) {
+		t.Parallel()
+		b := newTestBroker()
+		ctx, cancel := context.WithCancel(t.Context())
+		b.Subscribe(ctx, WithPolicy(Block))
+		for i := range 2 {
+			b.Publish(UpdatedEvent, item{ID: "a", Value: i})
+		}
+		time.AfterFunc(10*time.Millisecond, cancel)
+		b.Publish(UpdatedEvent, item{ID: "a", Value: 2})
+	})
+
+	t.Run("coalesce", func(t *testing.T) {
+		t.Parallel()
+		b := newTestBroker()
+		ch := b.Subscribe(t.Context(), WithPolicy(Coalesce))
+		for i := range 100 {
+			b.Publish(UpdatedEvent, item{ID: "a", Value: i})
+			b.Publish(UpdatedEvent, item{ID: "b", Value: i})
+		}
+		b.Publish(DeletedEvent, item{ID: "b", Value: 100})
+
+		last := map[string]Event[item]{}
+		for event := range ch {
+			last[event.Payload.ID] = event
+			if event.Type == DeletedEvent {
+				break
+			}
+		}
+		require.Equal(t, 99, last["a"].Payload.Value)
+		require.Equal(t, DeletedEvent, last["b"].Type)
+		require.Zero(t, b.Stats().Dropped)
+	})
+
+	t.Run("coalesce closes on cancel", func(t *testing.T) {
+		t.Parallel()
+		b := newTestBroker()
+		ctx, cancel := context.WithCancel(t.Context())
+		ch := b.Subscribe(ctx, WithPolicy(Coalesce))
+		b.Publish(UpdatedEvent, item{ID: "a"})
+		cancel()
+		require.Eventually(t, func() bool {
+			for {
+				select {
+				case _, ok := <-ch:
+					if !ok {
+						return true
+					}
+				default:
+					return false
+				}
+			}
+		}, time.Second, time.Millisecond)
+	})
+}
+
+func TestSubscriptionPush(t *testing.T) {
+	t.Parallel()
+
+	sub := &subscription[item]{
+		policy: Coalesce,
+		key:    func(i item) string { return i.ID },
+		index:  make(map[string]int),
+		wake:   make(chan struct{}, 1),
+	}
+	require.False(t, sub.push(Event[item]{Type: CreatedEvent, Payload: item{ID: "a", Value: 0}}))
+	for i := 1; i <= 3; i++ {
+		require.Equal(t, i > 1, sub.push(Event[item]{Type: UpdatedEvent, Payload: item{ID: "a", Value: i}}))
+		require.Equal(t, i > 1, sub.push(Event[item]{Type: UpdatedEvent, Payload: item{ID: "b", Value: 10 + i}}))
+	}
+	require.False(t, sub.push(Event[item]{Type: DeletedEvent, Payload: item{ID: "b", Value: 20}}))
+	require.False(t, sub.push(Event[item]{Type: UpdatedEvent, Payload: item{ID: "b", Value: 21}}))
+
+	events := sub.take()
+	require.Equal(t, []int{0, 3, 13, 20, 21}, values(events))
+	require.Equal(t, DeletedEvent, events[3].Type)
+	require.Empty(t, sub.take())
+
+	// Updates taken by the pump are not merged with later ones.
+	require.False(t, sub.push(Event[item]{Type: UpdatedEvent, Payload: item{ID: "a", Value: 4}}))
+}
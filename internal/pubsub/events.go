@@ -9,7 +9,7 @@ const (
 )
 
 type Subscriber[T any] interface {
-	Subscribe(context.Context) <-chan Event[T]
+	Subscribe(context.Context, ...SubscribeOption) <-chan Event[T]
 }
 
 type (
@@ -0,0 +1,119 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// Policy determines what a subscription does with new events while its
+// subscriber is behind.
+type Policy int
+
+const (
+	// DropNewest discards new events while the subscriber's buffer is full,
+	// so a slow subscriber never holds up the publisher. It's the default.
+	DropNewest Policy = iota
+	// Block makes the publisher wait until the subscriber has room for the
+	// event, or the subscription ends. Nothing is dropped.
+	Block
+	// DropOldest discards the oldest buffered event to make room for a new
+	// one, so the subscriber always gets the latest events.
+	DropOldest
+	// Coalesce queues every event without holding up the publisher, but a
+	// queued [UpdatedEvent] is replaced by a newer one for the same key, so
+	// the subscriber skips intermediate states and never misses the final
+	// one. Events are never merged on brokers without a key.
+	Coalesce
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Block:
+		return "block"
+	case DropOldest:
+		return "drop-oldest"
+	case Coalesce:
+		return "coalesce"
+	default:
+		return "drop-newest"
+	}
+}
+
+type subscribeConfig struct {
+	policy Policy
+}
+
+// SubscribeOption configures a subscription.
+type SubscribeOption func(*subscribeConfig)
+
+// WithPolicy sets what a subscription does while its subscriber is behind.
+func WithPolicy(policy Policy) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.policy = policy
+	}
+}
+
+type subscription[T any] struct {
+	ctx    context.Context
+	ch     chan Event[T]
+	policy Policy
+
+	// The queue of a Coalesce subscription, and the position of the queued
+	// update for each key.
+	key   func(T) string
+	mu    sync.Mutex
+	queue []Event[T]
+	index map[string]int
+	wake  chan struct{}
+}
+
+// push queues an event of a [Coalesce] subscription. It reports whether the
+// event replaced a queued one.
+func (s *subscription[T]) push(event Event[T]) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	coalesced := false
+	if s.key != nil {
+		k := s.key(event.Payload)
+		i, ok := s.index[k]
+		switch {
+		case event.Type != UpdatedEvent:
+			// Later updates must not move ahead of this event.
+			delete(s.index, k)
+		case ok:
+			s.queue[i] = event
+			coalesced = true
+		default:
+			s.index[k] = len(s.queue)
+		}
+	}
+	if !coalesced {
+		s.queue = append(s.queue, event)
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return coalesced
+}
+
+// take removes and returns the queued events of a [Coalesce] subscription.
+func (s *subscription[T]) take() []Event[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue := s.queue
+	s.queue = nil
+	clear(s.index)
+	return queue
+}
+
+// close ends the subscription. The channel of a [Coalesce] subscription is
+// closed by its pump instead, as it may be sending on it.
+func (s *subscription[T]) close() {
+	if s.policy != Coalesce {
+		close(s.ch)
+	}
+}
@@ -0,0 +1,126 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/git"
+)
+
+// maxUntrackedFiles bounds the number of untracked files added to the diff of
+// the uncommitted changes.
+const maxUntrackedFiles = 50
+
+// Diff is the set of changes to review.
+type Diff struct {
+	// Range is the git revision range of the changes, empty for the
+	// uncommitted ones.
+	Range string
+	// Files lists the paths of the changed files.
+	Files []string
+	// Patch is the unified diff of the changes.
+	Patch string
+}
+
+// CollectDiff collects the changes of a git revision range in dir. The range
+// is anything git diff accepts: "main..HEAD", "main...HEAD" or a single
+// revision, compared with the working tree. An empty range collects the
+// uncommitted changes, including untracked files.
+//
+// The reviewer reads the files of the working tree, and fixes are applied
+// there, so the range must end at the checked out commit: "main...feature"
+// is refused unless feature is checked out.
+func CollectDiff(ctx context.Context, dir, revRange string) (*Diff, error) {
+	if strings.HasPrefix(revRange, "-") {
+		return nil, fmt.Errorf("invalid revision range %q", revRange)
+	}
+	if err := checkHead(ctx, dir, revRange); err != nil {
+		return nil, err
+	}
+	rev := revRange
+	if rev == "" {
+		rev = "HEAD"
+	}
+
+	names, err := runGit(ctx, dir, "diff", "--name-only", "-z", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	patch, err := runGit(ctx, dir, "diff", "--no-ext-diff", "--find-renames", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	d := &Diff{
+		Range: revRange,
+		Files: splitNames(names),
+		Patch: patch,
+	}
+	if revRange != "" {
+		return d, nil
+	}
+
+	untracked, err := runGit(ctx, dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	b.WriteString(d.Patch)
+	for i, file := range splitNames(untracked) {
+		if i == maxUntrackedFiles {
+			break
+		}
+		// Compared with nothing, git diff --no-index exits with 1.
+		filePatch, err := runGit(ctx, dir, "diff", "--no-index", "--", os.DevNull, file)
+		var exitErr *exec.ExitError
+		if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+			return nil, err
+		}
+		d.Files = append(d.Files, file)
+		b.WriteString(filePatch)
+	}
+	d.Patch = b.String()
+	return d, nil
+}
+
+// checkHead makes sure the revision range ends at the checked out commit,
+// or the working tree.
+func checkHead(ctx context.Context, dir, revRange string) error {
+	_, head, ok := strings.Cut(revRange, "...")
+	if !ok {
+		_, head, ok = strings.Cut(revRange, "..")
+	}
+	if !ok || head == "" || head == "HEAD" {
+		return nil
+	}
+	want, err := runGit(ctx, dir, "rev-parse", "--verify", "--end-of-options", head+"^{commit}")
+	if err != nil {
+		return err
+	}
+	got, err := runGit(ctx, dir, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(want) != strings.TrimSpace(got) {
+		return fmt.Errorf("revision range %q ends at %s, which is not checked out: check it out to review it", revRange, head)
+	}
+	return nil
+}
+
+// splitNames splits the NUL-terminated file names git outputs with -z.
+func splitNames(out string) []string {
+	return strings.FieldsFunc(out, func(r rune) bool { return r == 0 })
+}
+
+// Empty reports whether there are no changes.
+func (d *Diff) Empty() bool {
+	return strings.TrimSpace(d.Patch) == ""
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	out, err := git.Run(ctx, dir, nil, args...)
+	return string(out), err
+}
@@ -0,0 +1,103 @@
+// Package review holds the findings of agent-driven code reviews and renders
+// them as markdown or JSON.
+package review
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Severity is how important a finding is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Severities lists the severities, from the most to the least important.
+var Severities = []Severity{SeverityError, SeverityWarning, SeverityInfo}
+
+// Valid reports whether the severity is a known one.
+func (s Severity) Valid() bool {
+	return slices.Contains(Severities, s)
+}
+
+// Suggestion is a fix of a finding, replacing a string of the file with
+// another, as the edit tool does.
+type Suggestion struct {
+	OldString string `json:"old_string"`
+	NewString string `json:"new_string"`
+	// Patch is the unified diff of the suggestion.
+	Patch string `json:"patch"`
+}
+
+// Finding is a problem found by the reviewer in a range of lines of a file.
+type Finding struct {
+	File       string      `json:"file"`
+	StartLine  int         `json:"start_line"`
+	EndLine    int         `json:"end_line"`
+	Severity   Severity    `json:"severity"`
+	Message    string      `json:"message"`
+	Suggestion *Suggestion `json:"suggestion,omitempty"`
+}
+
+// Location returns the file and line range of the finding, as in
+// "main.go:10-12".
+func (f Finding) Location() string {
+	if f.EndLine > f.StartLine {
+		return fmt.Sprintf("%s:%d-%d", f.File, f.StartLine, f.EndLine)
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.StartLine)
+}
+
+// Report is the outcome of a review.
+type Report struct {
+	// Range is the git revision range reviewed, empty for the uncommitted
+	// changes.
+	Range    string    `json:"range"`
+	Summary  string    `json:"summary"`
+	Findings []Finding `json:"findings"`
+}
+
+// Sort orders the findings by severity, then by location.
+func (r *Report) Sort() {
+	slices.SortStableFunc(r.Findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(Severities, a.Severity), slices.Index(Severities, b.Severity)),
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.StartLine, b.StartLine),
+		)
+	})
+}
+
+// Markdown renders the report as markdown.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Review of %s\n", RangeTitle(r.Range))
+	if summary := strings.TrimSpace(r.Summary); summary != "" {
+		fmt.Fprintf(&b, "\n%s\n", summary)
+	}
+	if len(r.Findings) == 0 {
+		b.WriteString("\nNo findings.\n")
+		return b.String()
+	}
+	for i, f := range r.Findings {
+		fmt.Fprintf(&b, "\n## %d. %s `%s`\n\n%s\n", i+1, f.Severity, f.Location(), strings.TrimSpace(f.Message))
+		if f.Suggestion != nil && f.Suggestion.Patch != "" {
+			fmt.Fprintf(&b, "\n```diff\n%s\n```\n", strings.TrimSuffix(f.Suggestion.Patch, "\n"))
+		}
+	}
+	return b.String()
+}
+
+// RangeTitle describes a git revision range for humans.
+func RangeTitle(revRange string) string {
+	if revRange == "" {
+		return "uncommitted changes"
+	}
+	return revRange
+}
@@ -0,0 +1,134 @@
+package review
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportSort(t *testing.T) {
+	t.Parallel()
+
+	report := &Report{Findings: []Finding{
+		{File: "b.go", StartLine: 3, Severity: SeverityInfo},
+		{File: "b.go", StartLine: 9, Severity: SeverityError},
+		{File: "a.go", StartLine: 5, Severity: SeverityWarning},
+		{File: "a.go", StartLine: 2, Severity: SeverityError},
+	}}
+	report.Sort()
+
+	var locations []string
+	for _, f := range report.Findings {
+		locations = append(locations, f.Location())
+	}
+	require.Equal(t, []string{"a.go:2", "b.go:9", "a.go:5", "b.go:3"}, locations)
+}
+
+func TestReportMarkdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no findings", func(t *testing.T) {
+		t.Parallel()
+		report := &Report{Summary: "Looks good."}
+		require.Equal(t, "# Review of uncommitted changes\n\nLooks good.\n\nNo findings.\n", report.Markdown())
+	})
+
+	t.Run("findings", func(t *testing.T) {
+		t.Parallel()
+		report := &Report{
+			Range: "main..HEAD",
+			Findings: []Finding{
+				{
+					File:      "main.go",
+					StartLine: 10,
+					EndLine:   12,
+					Severity:  SeverityError,
+					Message:   "The error is ignored.",
+					Suggestion: &Suggestion{
+						Patch: "--- a/main.go\n+++ b/main.go\n@@ -10 +10 @@\n-f()\n+_ = f()\n",
+					},
+				},
+				{File: "util.go", StartLine: 4, EndLine: 4, Severity: SeverityInfo, Message: "Unused helper."},
+			},
+		}
+		require.Equal(t, "# Review of main..HEAD\n"+
+			"\n## 1. error `main.go:10-12`\n\nThe error is ignored.\n"+
+			"\n```diff\n--- a/main.go\n+++ b/main.go\n@@ -10 +10 @@\n-f()\n+_ = f()\n```\n"+
+			"\n## 2. info `util.go:4`\n\nUnused helper.\n", report.Markdown())
+	})
+}
+
+func TestCollectDiff(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--initial-branch=main"},
+		{"config", "user.name", "Test"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		_, err := runGit(t.Context(), dir, args...)
+		require.NoError(t, err)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644))
+	_, err := runGit(t.Context(), dir, "add", "main.go")
+	require.NoError(t, err)
+	_, err = runGit(t.Context(), dir, "commit", "-m", "Initial commit")
+	require.NoError(t, err)
+
+	t.Run("no changes", func(t *testing.T) {
+		d, err := CollectDiff(t.Context(), dir, "")
+		require.NoError(t, err)
+		require.True(t, d.Empty())
+		require.Empty(t, d.Files)
+	})
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "util.go"), []byte("package main\n\nfunc helper() {}\n"), 0o644))
+
+	t.Run("uncommitted changes", func(t *testing.T) {
+		d, err := CollectDiff(t.Context(), dir, "")
+		require.NoError(t, err)
+		require.Equal(t, []string{"main.go", "util.go"}, d.Files)
+		require.Contains(t, d.Patch, "+func main() {}")
+		require.Contains(t, d.Patch, "+func helper() {}")
+	})
+
+	_, err = runGit(t.Context(), dir, "add", "-A")
+	require.NoError(t, err)
+	_, err = runGit(t.Context(), dir, "commit", "-m", "Add main")
+	require.NoError(t, err)
+
+	t.Run("range", func(t *testing.T) {
+		d, err := CollectDiff(t.Context(), dir, "HEAD~1..HEAD")
+		require.NoError(t, err)
+		require.Equal(t, "HEAD~1..HEAD", d.Range)
+		require.Equal(t, []string{"main.go", "util.go"}, d.Files)
+		require.Contains(t, d.Patch, "+func helper() {}")
+	})
+
+	t.Run("range not checked out", func(t *testing.T) {
+		_, err := runGit(t.Context(), dir, "branch", "previous", "HEAD~1")
+		require.NoError(t, err)
+		_, err = CollectDiff(t.Context(), dir, "main...previous")
+		require.ErrorContains(t, err, "not checked out")
+
+		d, err := CollectDiff(t.Context(), dir, "previous..main")
+		require.NoError(t, err)
+		require.Equal(t, []string{"main.go", "util.go"}, d.Files)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := CollectDiff(t.Context(), dir, "--output=x")
+		require.Error(t, err)
+		_, err = CollectDiff(t.Context(), dir, "nope..HEAD")
+		require.Error(t, err)
+	})
+}
@@ -243,7 +243,7 @@ func unmarshalTodos(data string) ([]Todo, error) {
 }
 
 func NewService(q *db.Queries, conn *sql.DB) Service {
-	broker := pubsub.NewBroker[Session]()
+	broker := pubsub.NewBrokerWithKey(func(s Session) string { return s.ID })
 	return &service{
 		Broker: broker,
 		db:     conn,
@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/crush/internal/csync"
+	"github.com/charmbracelet/crush/internal/pubsub"
 )
 
 const (
@@ -46,17 +49,38 @@ func (sb *syncBuffer) String() string {
 // BackgroundShell represents a shell running in the background.
 type BackgroundShell struct {
 	ID          string
+	SessionID   string
 	Command     string
 	Description string
 	Shell       *Shell
 	WorkingDir  string
+	StartedAt   time.Time
 	ctx         context.Context
 	cancel      context.CancelFunc
+	blockFuncs  []BlockFunc
 	stdout      *syncBuffer
 	stderr      *syncBuffer
 	done        chan struct{}
 	exitErr     error
-	completedAt int64 // Unix timestamp when job completed (0 if still running)
+	completedAt int64       // Unix timestamp when job completed (0 if still running)
+	background  atomic.Bool // Whether the job was moved to the background
+	usePTY      bool
+	pty         *os.File
+	ptyOut      *ptyOutput
+	ptyReady    chan struct{}
+}
+
+// BackgroundOptions configures a background shell started with
+// StartWithOptions.
+type BackgroundOptions struct {
+	SessionID   string
+	WorkingDir  string
+	BlockFuncs  []BlockFunc
+	Command     string
+	Description string
+	// PTY runs the command in a pseudo-terminal, so programs that expect a
+	// TTY work, and input can be sent to it with WriteInput.
+	PTY bool
 }
 
 // BackgroundShellManager manages background shell instances.
@@ -68,8 +92,21 @@ var (
 	backgroundManager     *BackgroundShellManager
 	backgroundManagerOnce sync.Once
 	idCounter             atomic.Uint64
+
+	backgroundBroker = pubsub.NewBroker[BackgroundShellInfo]()
 )
 
+// SubscribeBackgroundEvents returns a channel for background job events.
+// Events are only published for jobs that have been moved to the background.
+func SubscribeBackgroundEvents(ctx context.Context, opts ...pubsub.SubscribeOption) <-chan pubsub.Event[BackgroundShellInfo] {
+	return backgroundBroker.Subscribe(ctx, opts...)
+}
+
+// BackgroundEventStats returns the event counters of the background job events broker.
+func BackgroundEventStats() pubsub.Stats {
+	return backgroundBroker.Stats()
+}
+
 // newBackgroundShellManager creates a new BackgroundShellManager instance.
 func newBackgroundShellManager() *BackgroundShellManager {
 	return &BackgroundShellManager{
@@ -87,6 +124,24 @@ func GetBackgroundShellManager() *BackgroundShellManager {
 
 // Start creates and starts a new background shell with the given command.
 func (m *BackgroundShellManager) Start(ctx context.Context, workingDir string, blockFuncs []BlockFunc, command string, description string) (*BackgroundShell, error) {
+	return m.StartInSession(ctx, "", workingDir, blockFuncs, command, description)
+}
+
+// StartInSession creates and starts a new background shell owned by the
+// given session.
+func (m *BackgroundShellManager) StartInSession(ctx context.Context, sessionID, workingDir string, blockFuncs []BlockFunc, command string, description string) (*BackgroundShell, error) {
+	return m.StartWithOptions(ctx, BackgroundOptions{
+		SessionID:   sessionID,
+		WorkingDir:  workingDir,
+		BlockFuncs:  blockFuncs,
+		Command:     command,
+		Description: description,
+	})
+}
+
+// StartWithOptions creates and starts a new background shell configured by
+// the given options.
+func (m *BackgroundShellManager) StartWithOptions(ctx context.Context, opts BackgroundOptions) (*BackgroundShell, error) {
 	// Check job limit
 	if m.shells.Len() >= MaxBackgroundJobs {
 		return nil, fmt.Errorf("maximum number of background jobs (%d) reached. Please terminate or wait for some jobs to complete", MaxBackgroundJobs)
@@ -95,39 +150,76 @@ func (m *BackgroundShellManager) Start(ctx context.Context, workingDir string, b
 	id := fmt.Sprintf("%03X", idCounter.Add(1))
 
 	shell := NewShell(&Options{
-		WorkingDir: workingDir,
-		BlockFuncs: blockFuncs,
+		WorkingDir: opts.WorkingDir,
+		BlockFuncs: opts.BlockFuncs,
 	})
 
 	shellCtx, cancel := context.WithCancel(ctx)
 
 	bgShell := &BackgroundShell{
 		ID:          id,
-		Command:     command,
-		Description: description,
-		WorkingDir:  workingDir,
+		SessionID:   opts.SessionID,
+		Command:     opts.Command,
+		Description: opts.Description,
+		WorkingDir:  opts.WorkingDir,
 		Shell:       shell,
+		StartedAt:   time.Now(),
 		ctx:         shellCtx,
 		cancel:      cancel,
+		blockFuncs:  opts.BlockFuncs,
 		stdout:      &syncBuffer{},
 		stderr:      &syncBuffer{},
 		done:        make(chan struct{}),
+		usePTY:      opts.PTY,
+		ptyReady:    make(chan struct{}),
 	}
 
 	m.shells.Set(id, bgShell)
 
 	go func() {
-		defer close(bgShell.done)
-
-		err := shell.ExecStream(shellCtx, command, bgShell.stdout, bgShell.stderr)
+		var err error
+		if bgShell.usePTY {
+			err = bgShell.execPTY(shellCtx, opts.Command)
+		} else {
+			err = shell.ExecStream(shellCtx, opts.Command, bgShell.stdout, bgShell.stderr)
+		}
 
 		bgShell.exitErr = err
 		atomic.StoreInt64(&bgShell.completedAt, time.Now().Unix())
+		close(bgShell.done)
+		bgShell.publish(pubsub.UpdatedEvent)
 	}()
 
 	return bgShell, nil
 }
 
+// Restart starts a fresh copy of the given job, terminating the original if
+// it is still running. The new job keeps the session, command, description
+// and working directory of the original and starts in the background.
+func (m *BackgroundShellManager) Restart(id string) (*BackgroundShell, error) {
+	old, ok := m.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("background shell not found: %s", id)
+	}
+	if err := m.Kill(id); err != nil {
+		return nil, err
+	}
+
+	bgShell, err := m.StartWithOptions(context.Background(), BackgroundOptions{
+		SessionID:   old.SessionID,
+		WorkingDir:  old.WorkingDir,
+		BlockFuncs:  old.blockFuncs,
+		Command:     old.Command,
+		Description: old.Description,
+		PTY:         old.usePTY,
+	})
+	if err != nil {
+		return nil, err
+	}
+	bgShell.MoveToBackground()
+	return bgShell, nil
+}
+
 // Get retrieves a background shell by ID.
 func (m *BackgroundShellManager) Get(id string) (*BackgroundShell, bool) {
 	return m.shells.Get(id)
@@ -136,10 +228,11 @@ func (m *BackgroundShellManager) Get(id string) (*BackgroundShell, bool) {
 // Remove removes a background shell from the manager without terminating it.
 // This is useful when a shell has already completed and you just want to clean up tracking.
 func (m *BackgroundShellManager) Remove(id string) error {
-	_, ok := m.shells.Take(id)
+	shell, ok := m.shells.Take(id)
 	if !ok {
 		return fmt.Errorf("background shell not found: %s", id)
 	}
+	shell.publish(pubsub.DeletedEvent)
 	return nil
 }
 
@@ -152,14 +245,74 @@ func (m *BackgroundShellManager) Kill(id string) error {
 
 	shell.cancel()
 	<-shell.done
+	shell.publish(pubsub.DeletedEvent)
 	return nil
 }
 
+// KillSession terminates and removes all background shells owned by the
+// given session. It returns the number of jobs removed.
+func (m *BackgroundShellManager) KillSession(sessionID string) int {
+	var ids []string
+	for shell := range m.shells.Seq() {
+		if shell.SessionID == sessionID {
+			ids = append(ids, shell.ID)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Go(func() {
+			_ = m.Kill(id)
+		})
+	}
+	wg.Wait()
+	return len(ids)
+}
+
 // BackgroundShellInfo contains information about a background shell.
 type BackgroundShellInfo struct {
 	ID          string
+	SessionID   string
 	Command     string
 	Description string
+	WorkingDir  string
+	StartedAt   time.Time
+	CompletedAt time.Time // Zero if still running
+	Done        bool
+	ExitCode    int
+	PTY         bool
+}
+
+// Runtime returns how long the job has been running, or how long it ran for
+// if it has completed.
+func (i BackgroundShellInfo) Runtime() time.Duration {
+	if i.Done && !i.CompletedAt.IsZero() {
+		return i.CompletedAt.Sub(i.StartedAt)
+	}
+	return time.Since(i.StartedAt)
+}
+
+// Jobs returns information about the jobs that were moved to the background,
+// ordered by start time. If sessionID is not empty, only jobs owned by that
+// session are returned.
+func (m *BackgroundShellManager) Jobs(sessionID string) []BackgroundShellInfo {
+	var jobs []BackgroundShellInfo
+	for shell := range m.shells.Seq() {
+		if !shell.IsBackground() {
+			continue
+		}
+		if sessionID != "" && shell.SessionID != sessionID {
+			continue
+		}
+		jobs = append(jobs, shell.Info())
+	}
+	slices.SortFunc(jobs, func(a, b BackgroundShellInfo) int {
+		if c := a.StartedAt.Compare(b.StartedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return jobs
 }
 
 // List returns all background shell IDs.
@@ -195,6 +348,9 @@ func (m *BackgroundShellManager) Cleanup() int {
 func (m *BackgroundShellManager) KillAll() {
 	shells := slices.Collect(m.shells.Seq())
 	m.shells.Reset(map[string]*BackgroundShell{})
+	for _, shell := range shells {
+		shell.publish(pubsub.DeletedEvent)
+	}
 	done := make(chan struct{}, 1)
 	go func() {
 		var wg sync.WaitGroup
@@ -216,14 +372,82 @@ func (m *BackgroundShellManager) KillAll() {
 	}
 }
 
-// GetOutput returns the current output of a background shell.
+// GetOutput returns the current output of a background shell. The output of
+// shells running in a pseudo-terminal is returned as plain text.
 func (bs *BackgroundShell) GetOutput() (stdout string, stderr string, done bool, err error) {
 	select {
 	case <-bs.done:
-		return bs.stdout.String(), bs.stderr.String(), true, bs.exitErr
+		stdout, stderr, done, err = bs.stdout.String(), bs.stderr.String(), true, bs.exitErr
 	default:
-		return bs.stdout.String(), bs.stderr.String(), false, nil
+		stdout, stderr = bs.stdout.String(), bs.stderr.String()
+	}
+	if bs.usePTY {
+		stdout = normalizePTYOutput(stdout)
+	}
+	return stdout, stderr, done, err
+}
+
+// Info returns a snapshot of the background shell's state.
+func (bs *BackgroundShell) Info() BackgroundShellInfo {
+	info := BackgroundShellInfo{
+		ID:          bs.ID,
+		SessionID:   bs.SessionID,
+		Command:     bs.Command,
+		Description: bs.Description,
+		WorkingDir:  bs.WorkingDir,
+		StartedAt:   bs.StartedAt,
+		PTY:         bs.usePTY,
+	}
+	if bs.IsDone() {
+		info.Done = true
+		info.ExitCode = ExitCode(bs.exitErr)
+		if completedAt := atomic.LoadInt64(&bs.completedAt); completedAt > 0 {
+			info.CompletedAt = time.Unix(completedAt, 0)
+		}
+	}
+	return info
+}
+
+// Tail returns up to the last n lines of the combined stdout and stderr of
+// the background shell.
+func (bs *BackgroundShell) Tail(n int) string {
+	stdout, stderr, _, _ := bs.GetOutput()
+	output := strings.TrimRight(stdout, "\n")
+	if stderr := strings.TrimRight(stderr, "\n"); stderr != "" {
+		if output != "" {
+			output += "\n"
+		}
+		output += stderr
+	}
+	if output == "" || n <= 0 {
+		return ""
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MoveToBackground marks the shell as a background job, making it visible to
+// the user. It is a no-op if the shell was already moved to the background.
+func (bs *BackgroundShell) MoveToBackground() {
+	if bs.background.Swap(true) {
+		return
+	}
+	bs.publish(pubsub.CreatedEvent)
+}
+
+// IsBackground reports whether the shell was moved to the background.
+func (bs *BackgroundShell) IsBackground() bool {
+	return bs.background.Load()
+}
+
+func (bs *BackgroundShell) publish(eventType pubsub.EventType) {
+	if !bs.IsBackground() {
+		return
 	}
+	backgroundBroker.Publish(eventType, bs.Info())
 }
 
 // IsDone checks if the background shell has finished execution.
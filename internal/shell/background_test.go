@@ -280,3 +280,159 @@ func TestBackgroundShellManager_KillAll(t *testing.T) {
 		}
 	}
 }
+
+func TestBackgroundShellManager_Jobs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	workingDir := t.TempDir()
+	manager := newBackgroundShellManager()
+
+	foreground, err := manager.StartInSession(ctx, "session-a", workingDir, nil, "sleep 10", "")
+	if err != nil {
+		t.Fatalf("failed to start foreground shell: %v", err)
+	}
+	background, err := manager.StartInSession(ctx, "session-a", workingDir, nil, "sleep 10", "")
+	if err != nil {
+		t.Fatalf("failed to start background shell: %v", err)
+	}
+	other, err := manager.StartInSession(ctx, "session-b", workingDir, nil, "sleep 10", "")
+	if err != nil {
+		t.Fatalf("failed to start other shell: %v", err)
+	}
+	background.MoveToBackground()
+	other.MoveToBackground()
+
+	jobs := manager.Jobs("session-a")
+	if len(jobs) != 1 || jobs[0].ID != background.ID {
+		t.Errorf("expected only job %s for session-a, got %+v", background.ID, jobs)
+	}
+	if jobs := manager.Jobs(""); len(jobs) != 2 {
+		t.Errorf("expected 2 background jobs across sessions, got %d", len(jobs))
+	}
+
+	if n := manager.KillSession("session-a"); n != 2 {
+		t.Errorf("expected 2 shells killed for session-a, got %d", n)
+	}
+	if !foreground.IsDone() || !background.IsDone() {
+		t.Error("expected session-a shells to be done after KillSession")
+	}
+	if other.IsDone() {
+		t.Error("expected session-b shell to keep running")
+	}
+
+	manager.Kill(other.ID)
+}
+
+func TestBackgroundShell_Tail(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	workingDir := t.TempDir()
+	manager := newBackgroundShellManager()
+
+	bgShell, err := manager.Start(ctx, workingDir, nil, "echo one; echo two; echo three; echo four >&2", "")
+	if err != nil {
+		t.Fatalf("failed to start background shell: %v", err)
+	}
+	bgShell.Wait()
+
+	if got, want := bgShell.Tail(2), "three\nfour"; got != want {
+		t.Errorf("expected tail %q, got %q", want, got)
+	}
+	if got := bgShell.Tail(0); got != "" {
+		t.Errorf("expected empty tail, got %q", got)
+	}
+
+	manager.Kill(bgShell.ID)
+}
+
+func TestBackgroundShell_PTYInput(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("pseudo-terminals are not supported on windows")
+	}
+
+	ctx := context.Background()
+	workingDir := t.TempDir()
+	manager := newBackgroundShellManager()
+
+	bgShell, err := manager.StartWithOptions(ctx, BackgroundOptions{
+		WorkingDir: workingDir,
+		Command:    "read answer; echo got $answer",
+		PTY:        true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start background shell: %v", err)
+	}
+	defer manager.Kill(bgShell.ID)
+
+	if !bgShell.IsPTY() {
+		t.Fatal("expected background shell to run in a pty")
+	}
+	if err := bgShell.WriteInput([]byte("hello\n")); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	select {
+	case <-bgShell.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for background shell")
+	}
+
+	stdout, _, _, _ := bgShell.GetOutput()
+	if !strings.Contains(stdout, "got hello") {
+		t.Errorf("expected output to contain %q, got %q", "got hello", stdout)
+	}
+	if err := bgShell.WriteInput([]byte("late\n")); err == nil {
+		t.Error("expected error writing to finished shell")
+	}
+}
+
+func TestBackgroundShell_PTYInterrupt(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("pseudo-terminals are not supported on windows")
+	}
+
+	ctx := context.Background()
+	workingDir := t.TempDir()
+	manager := newBackgroundShellManager()
+
+	bgShell, err := manager.StartWithOptions(ctx, BackgroundOptions{
+		WorkingDir: workingDir,
+		Command:    "sleep 30",
+		PTY:        true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start background shell: %v", err)
+	}
+	defer manager.Kill(bgShell.ID)
+
+	// Wait for the program to start before interrupting it.
+	time.Sleep(500 * time.Millisecond)
+	if err := bgShell.WriteInput([]byte("\x03")); err != nil {
+		t.Fatalf("failed to write input: %v", err)
+	}
+
+	select {
+	case <-bgShell.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected ctrl+c to interrupt the program")
+	}
+	if code := ExitCode(bgShell.exitErr); code != 130 {
+		t.Errorf("expected exit code 130, got %d", code)
+	}
+}
+
+func TestClampPTYSize(t *testing.T) {
+	t.Parallel()
+
+	for n, want := range map[int]uint16{-5: 1, 0: 1, 80: 80, 70000: 65535} {
+		if got := clampPTYSize(n); got != want {
+			t.Errorf("clampPTYSize(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/creack/pty"
+	"github.com/muesli/cancelreader"
+)
+
+const (
+	// DefaultPTYWidth is the initial width of pseudo-terminals.
+	DefaultPTYWidth = 120
+	// DefaultPTYHeight is the initial height of pseudo-terminals.
+	DefaultPTYHeight = 40
+)
+
+// ErrNotPTY is returned when interacting with a background shell that was not
+// started with a pseudo-terminal.
+var ErrNotPTY = errors.New("background shell was not started with a pty")
+
+// ptyOutput copies the output of a pseudo-terminal into the shell's output
+// buffer and, while someone is attached, to their terminal as well.
+type ptyOutput struct {
+	buf      *syncBuffer
+	mu       sync.Mutex
+	attached io.Writer
+}
+
+func (o *ptyOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.attached != nil {
+		_, _ = o.attached.Write(p)
+	}
+	return o.buf.Write(p)
+}
+
+func (o *ptyOutput) attach(w io.Writer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.attached = w
+}
+
+// execPTY runs the command attached to a new pseudo-terminal, copying
+// everything written to it into the shell's stdout buffer.
+func (bs *BackgroundShell) execPTY(ctx context.Context, command string) error {
+	ptm, pts, err := pty.Open()
+	if err != nil {
+		return fmt.Errorf("could not open pty: %w", err)
+	}
+	defer ptm.Close()
+
+	if err := pty.Setsize(ptm, &pty.Winsize{Cols: DefaultPTYWidth, Rows: DefaultPTYHeight}); err != nil {
+		pts.Close()
+		return fmt.Errorf("could not set pty size: %w", err)
+	}
+
+	bs.pty = ptm
+	bs.ptyOut = &ptyOutput{buf: bs.stdout}
+	close(bs.ptyReady)
+
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		// Reading from the pty fails with EIO once the terminal side is
+		// closed and all output has been read.
+		_, _ = io.Copy(bs.ptyOut, ptm)
+	}()
+
+	bs.Shell.mu.Lock()
+	err = bs.Shell.execCommon(ctx, command, pts, pts, pts, ptyExecHandler(pts))
+	bs.Shell.mu.Unlock()
+	pts.Close()
+	<-copied
+	return err
+}
+
+// IsPTY reports whether the background shell runs in a pseudo-terminal.
+func (bs *BackgroundShell) IsPTY() bool {
+	return bs.usePTY
+}
+
+// WriteInput sends input to the pseudo-terminal of the background shell, as
+// if it was typed by the user.
+func (bs *BackgroundShell) WriteInput(input []byte) error {
+	if !bs.usePTY {
+		return ErrNotPTY
+	}
+	if bs.IsDone() {
+		return fmt.Errorf("background shell %s has already finished", bs.ID)
+	}
+	ptm, err := bs.waitPTY()
+	if err != nil {
+		return err
+	}
+	if _, err := ptm.Write(input); err != nil {
+		return fmt.Errorf("could not write to pty: %w", err)
+	}
+	return nil
+}
+
+// Resize changes the size of the pseudo-terminal of the background shell.
+func (bs *BackgroundShell) Resize(width, height int) error {
+	ptm, err := bs.waitPTY()
+	if err != nil {
+		return err
+	}
+	return pty.Setsize(ptm, &pty.Winsize{Cols: clampPTYSize(width), Rows: clampPTYSize(height)})
+}
+
+// clampPTYSize converts a dimension of the terminal to the range supported
+// by pseudo-terminals.
+func clampPTYSize(n int) uint16 {
+	return uint16(min(max(n, 1), math.MaxUint16))
+}
+
+// Attach connects the given input and output to the pseudo-terminal of the
+// background shell. The current output is replayed first; afterwards,
+// everything read from in is forwarded to the job until the detach byte is
+// read, in is exhausted, or the job finishes.
+func (bs *BackgroundShell) Attach(in io.Reader, out io.Writer, detach byte) error {
+	ptm, err := bs.waitPTY()
+	if err != nil {
+		return err
+	}
+
+	bs.ptyOut.mu.Lock()
+	_, _ = io.WriteString(out, bs.stdout.String())
+	bs.ptyOut.attached = out
+	bs.ptyOut.mu.Unlock()
+	defer bs.ptyOut.attach(nil)
+
+	// Make sure the input is no longer read once we are detached, so no
+	// keystrokes are lost by whoever reads from it next.
+	reader, err := cancelreader.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("could not read input: %w", err)
+	}
+	defer reader.Close()
+	defer reader.Cancel()
+	in = reader
+
+	inputDone := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := in.Read(buf)
+			if n > 0 {
+				data := buf[:n]
+				if i := strings.IndexByte(string(data), detach); i >= 0 {
+					_, _ = ptm.Write(data[:i])
+					inputDone <- nil
+					return
+				}
+				if _, werr := ptm.Write(data); werr != nil {
+					inputDone <- werr
+					return
+				}
+			}
+			if err != nil {
+				inputDone <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case err := <-inputDone:
+		if errors.Is(err, io.EOF) || errors.Is(err, os.ErrClosed) || errors.Is(err, cancelreader.ErrCanceled) {
+			return nil
+		}
+		return err
+	case <-bs.done:
+		return nil
+	}
+}
+
+// waitPTY returns the pseudo-terminal of the background shell. The pty is
+// opened asynchronously when the shell starts, so this waits until it is
+// available or the shell finishes.
+func (bs *BackgroundShell) waitPTY() (*os.File, error) {
+	if !bs.usePTY {
+		return nil, ErrNotPTY
+	}
+	select {
+	case <-bs.ptyReady:
+	case <-bs.done:
+	}
+	if bs.pty == nil {
+		return nil, fmt.Errorf("pty of background shell %s is not available", bs.ID)
+	}
+	return bs.pty, nil
+}
+
+// normalizePTYOutput turns raw terminal output into plain text, dropping
+// escape sequences and carriage returns.
+func normalizePTYOutput(s string) string {
+	s = ansi.Strip(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "")
+}
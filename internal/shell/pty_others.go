@@ -0,0 +1,98 @@
+//go:build !windows
+
+package shell
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+)
+
+// ptyKillTimeout is how long a command is given to exit after it was
+// interrupted, before it is killed.
+const ptyKillTimeout = 2 * time.Second
+
+// ptyExecHandler runs programs in a new session controlled by the
+// pseudo-terminal, as a shell does in a terminal, so the keys typed in it
+// send signals, e.g. ctrl+c sends SIGINT. A terminal controls a single
+// session: programs running alongside it, e.g. in a pipeline, or not
+// reading from or writing to the terminal run as usual.
+func ptyExecHandler(pts *os.File) func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+	var controlled atomic.Bool
+	return func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+		return func(ctx context.Context, args []string) error {
+			hc := interp.HandlerCtx(ctx)
+			ctty := slices.Index([]any{hc.Stdin, hc.Stdout, hc.Stderr}, any(pts))
+			if ctty < 0 || !controlled.CompareAndSwap(false, true) {
+				return next(ctx, args)
+			}
+			defer controlled.Store(false)
+
+			path, err := interp.LookPathDir(hc.Dir, hc.Env, args[0])
+			if err != nil {
+				fmt.Fprintln(hc.Stderr, err)
+				return interp.ExitStatus(127)
+			}
+			cmd := exec.Cmd{
+				Path:        path,
+				Args:        args,
+				Env:         ptyEnv(hc.Env),
+				Dir:         hc.Dir,
+				Stdin:       hc.Stdin,
+				Stdout:      hc.Stdout,
+				Stderr:      hc.Stderr,
+				SysProcAttr: &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: ctty},
+			}
+			if err := cmd.Start(); err != nil {
+				fmt.Fprintln(hc.Stderr, err)
+				return interp.ExitStatus(127)
+			}
+			// The program leads its own process group: signal the whole
+			// group, as the interpreter does.
+			stop := context.AfterFunc(ctx, func() {
+				_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+				time.Sleep(ptyKillTimeout)
+				_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+			})
+			defer stop()
+
+			err = cmd.Wait()
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					return interp.ExitStatus(128 + uint8(status.Signal()))
+				}
+				return interp.ExitStatus(exitErr.ExitCode())
+			}
+			return err
+		}
+	}
+}
+
+// ptyEnv returns the exported variables of the environment of a program.
+func ptyEnv(env expand.Environ) []string {
+	var list []string
+	for name, vr := range env.Each {
+		if !vr.IsSet() {
+			// A variable set globally may be unset by the command.
+			list = slices.DeleteFunc(list, func(kv string) bool {
+				return strings.HasPrefix(kv, name+"=")
+			})
+		}
+		if vr.Exported && vr.Kind == expand.String {
+			list = append(list, name+"="+vr.String())
+		}
+	}
+	return list
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package shell
+
+import (
+	"os"
+
+	"mvdan.cc/sh/v3/interp"
+)
+
+// ptyExecHandler runs programs as usual: Windows has no sessions controlled
+// by terminals.
+func ptyExecHandler(_ *os.File) func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+	return func(next interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+		return next
+	}
+}
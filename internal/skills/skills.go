@@ -9,8 +9,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/charlievieth/fastwalk"
 	"gopkg.in/yaml.v3"
@@ -21,6 +23,7 @@ const (
 	MaxNameLength          = 64
 	MaxDescriptionLength   = 1024
 	MaxCompatibilityLength = 500
+	MaxResources           = 200
 )
 
 var namePattern = regexp.MustCompile(`^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$`)
@@ -32,6 +35,7 @@ type Skill struct {
 	License       string            `yaml:"license,omitempty" json:"license,omitempty"`
 	Compatibility string            `yaml:"compatibility,omitempty" json:"compatibility,omitempty"`
 	Metadata      map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	AllowedTools  string            `yaml:"allowed-tools,omitempty" json:"allowed_tools,omitempty"`
 	Instructions  string            `yaml:"-" json:"instructions"`
 	Path          string            `yaml:"-" json:"path"`
 	SkillFilePath string            `yaml:"-" json:"skill_file_path"`
@@ -68,6 +72,81 @@ func (s *Skill) Validate() error {
 	return errors.Join(errs...)
 }
 
+// Tools returns the names of the tools listed in allowed-tools. Entries may
+// be separated by spaces or commas, and arguments patterns such as
+// `Bash(git:*)` are dropped.
+func (s *Skill) Tools() []string {
+	var tools []string
+	var current strings.Builder
+	depth := 0
+	flush := func() {
+		name, _, _ := strings.Cut(current.String(), "(")
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(tools, name) {
+			tools = append(tools, name)
+		}
+		current.Reset()
+	}
+	for _, r := range s.AllowedTools {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth = max(depth-1, 0)
+		case depth == 0 && (r == ',' || unicode.IsSpace(r)):
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+	return tools
+}
+
+// Resource is a file bundled with a skill.
+type Resource struct {
+	// Path is relative to the directory of the skill.
+	Path string
+	// Script is set for files in scripts/ and executable files.
+	Script bool
+}
+
+// Resources lists the files bundled with the skill, up to MaxResources.
+func (s *Skill) Resources() ([]Resource, error) {
+	var resources []Resource
+	err := filepath.WalkDir(s.Path, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == s.Path {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || path == s.SkillFilePath {
+			return nil
+		}
+		if len(resources) == MaxResources {
+			return filepath.SkipAll
+		}
+		rel, err := filepath.Rel(s.Path, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		script := strings.HasPrefix(rel, "scripts/")
+		if info, err := d.Info(); err == nil && info.Mode()&0o111 != 0 {
+			script = true
+		}
+		resources = append(resources, Resource{Path: rel, Script: script})
+		return nil
+	})
+	return resources, err
+}
+
 // Parse parses a SKILL.md file.
 func Parse(path string) (*Skill, error) {
 	content, err := os.ReadFile(path)
@@ -112,14 +191,35 @@ func splitFrontmatter(content string) (frontmatter, body string, err error) {
 // Discover finds all valid skills in the given paths.
 func Discover(paths []string) []*Skill {
 	var skills []*Skill
+	for _, path := range FindFiles(paths) {
+		skill, err := Parse(path)
+		if err != nil {
+			slog.Warn("Failed to parse skill file", "path", path, "error", err)
+			continue
+		}
+		if err := skill.Validate(); err != nil {
+			slog.Warn("Skill validation failed", "path", path, "error", err)
+			continue
+		}
+		slog.Debug("Successfully loaded skill", "name", skill.Name, "path", path)
+		skills = append(skills, skill)
+	}
+	return skills
+}
+
+// FindFiles returns the SKILL.md files in the given paths, in the order of
+// the paths and sorted within each of them.
+func FindFiles(paths []string) []string {
+	var files []string
 	var mu sync.Mutex
 	seen := make(map[string]bool)
 
 	for _, base := range paths {
+		var found []string
 		// We use fastwalk with Follow: true instead of filepath.WalkDir because
 		// WalkDir doesn't follow symlinked directories at any depth—only entry
 		// points. This ensures skills in symlinked subdirectories are discovered.
-		// fastwalk is concurrent, so we protect shared state (seen, skills) with mu.
+		// fastwalk is concurrent, so we protect shared state (seen, found) with mu.
 		conf := fastwalk.Config{
 			Follow:  true,
 			ToSlash: fastwalk.DefaultToSlash(),
@@ -132,30 +232,27 @@ func Discover(paths []string) []*Skill {
 				return nil
 			}
 			mu.Lock()
-			if seen[path] {
-				mu.Unlock()
-				return nil
+			defer mu.Unlock()
+			if !seen[path] {
+				seen[path] = true
+				found = append(found, path)
 			}
-			seen[path] = true
-			mu.Unlock()
-			skill, err := Parse(path)
-			if err != nil {
-				slog.Warn("Failed to parse skill file", "path", path, "error", err)
-				return nil
-			}
-			if err := skill.Validate(); err != nil {
-				slog.Warn("Skill validation failed", "path", path, "error", err)
-				return nil
-			}
-			slog.Debug("Successfully loaded skill", "name", skill.Name, "path", path)
-			mu.Lock()
-			skills = append(skills, skill)
-			mu.Unlock()
 			return nil
 		})
+		slices.Sort(found)
+		files = append(files, found...)
 	}
+	return files
+}
 
-	return skills
+// Find returns the skill with the given name in the given paths, or nil.
+func Find(paths []string, name string) *Skill {
+	for _, skill := range Discover(paths) {
+		if strings.EqualFold(skill.Name, name) {
+			return skill
+		}
+	}
+	return nil
 }
 
 // ToPromptXML generates XML for injection into the system prompt.
@@ -3,6 +3,7 @@ package skills
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -34,6 +35,7 @@ compatibility: Requires python 3.8+, pdfplumber, pdfrw libraries
 metadata:
   author: example-org
   version: "1.0"
+allowed-tools: Bash(pdftotext:*) Read
 ---
 
 # PDF Processing
@@ -46,6 +48,7 @@ Use this skill when the user needs to work with PDF files.
 			wantLicense: "Apache-2.0",
 			wantCompat:  "Requires python 3.8+, pdfplumber, pdfrw libraries",
 			wantMeta:    map[string]string{"author": "example-org", "version": "1.0"},
+			wantTools:   "Bash(pdftotext:*) Read",
 			wantInstr:   "# PDF Processing\n\n## When to use this skill\nUse this skill when the user needs to work with PDF files.",
 		},
 		{
@@ -95,6 +98,7 @@ Instructions here.
 				require.Equal(t, tt.wantMeta, skill.Metadata)
 			}
 
+			require.Equal(t, tt.wantTools, skill.AllowedTools)
 			require.Equal(t, tt.wantInstr, skill.Instructions)
 		})
 	}
@@ -226,6 +230,72 @@ description: Name doesn't match directory.
 	require.True(t, names["skill-two"])
 }
 
+func TestSkillTools(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		allowed string
+		want    []string
+	}{
+		{allowed: "", want: nil},
+		{allowed: "Read Grep", want: []string{"Read", "Grep"}},
+		{allowed: "Bash(git status:*), Bash(git diff:*), view", want: []string{"Bash", "view"}},
+	}
+	for _, tt := range tests {
+		skill := Skill{AllowedTools: tt.allowed}
+		require.Equal(t, tt.want, skill.Tools(), tt.allowed)
+	}
+}
+
+func TestSkillResources(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "pdf-processing")
+	for name, mode := range map[string]os.FileMode{
+		"SKILL.md":             0o644,
+		"references/forms.md":  0o644,
+		"scripts/extract.py":   0o644,
+		"bin/merge":            0o755,
+		".git/config":          0o644,
+		"assets/template.docx": 0o644,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("x"), mode))
+	}
+
+	skill := Skill{Path: dir, SkillFilePath: filepath.Join(dir, "SKILL.md")}
+	resources, err := skill.Resources()
+	require.NoError(t, err)
+	require.Equal(t, []Resource{
+		{Path: "assets/template.docx"},
+		{Path: "bin/merge", Script: runtime.GOOS != "windows"},
+		{Path: "references/forms.md"},
+		{Path: "scripts/extract.py", Script: true},
+	}, resources)
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	first := t.TempDir()
+	second := t.TempDir()
+	for _, base := range []string{first, second} {
+		dir := filepath.Join(base, "my-skill")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(`---
+name: my-skill
+description: A skill.
+---
+`), 0o644))
+	}
+
+	skill := Find([]string{first, second}, "My-Skill")
+	require.NotNil(t, skill)
+	require.Equal(t, filepath.Join(first, "my-skill"), filepath.FromSlash(skill.Path))
+	require.Nil(t, Find([]string{first, second}, "other"))
+}
+
 func TestToPromptXML(t *testing.T) {
 	t.Parallel()
 
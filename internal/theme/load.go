@@ -0,0 +1,342 @@
+package theme
+
+import (
+	"cmp"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"charm.land/glamour/v2/ansi"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/styles"
+	xansi "github.com/charmbracelet/x/ansi"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/pelletier/go-toml/v2"
+)
+
+//go:embed themes/*.json
+var builtinFS embed.FS
+
+// file is a theme file. Themes extend another theme, the default one
+// unless set, and only need to set the colors they change.
+type file struct {
+	Name    string            `json:"name" toml:"name"`
+	Extends string            `json:"extends" toml:"extends"`
+	Dark    *bool             `json:"dark" toml:"dark"`
+	Syntax  string            `json:"syntax" toml:"syntax"`
+	Colors  map[string]string `json:"colors" toml:"colors"`
+
+	path string
+}
+
+// colorFields maps the color names of theme files to the colors of a theme.
+var colorFields = map[string]func(*Theme) *color.Color{
+	"primary":                    func(t *Theme) *color.Color { return &t.Primary },
+	"secondary":                  func(t *Theme) *color.Color { return &t.Secondary },
+	"tertiary":                   func(t *Theme) *color.Color { return &t.Tertiary },
+	"accent":                     func(t *Theme) *color.Color { return &t.Accent },
+	"bg_base":                    func(t *Theme) *color.Color { return &t.BgBase },
+	"bg_base_lighter":            func(t *Theme) *color.Color { return &t.BgBaseLighter },
+	"bg_subtle":                  func(t *Theme) *color.Color { return &t.BgSubtle },
+	"bg_overlay":                 func(t *Theme) *color.Color { return &t.BgOverlay },
+	"fg_base":                    func(t *Theme) *color.Color { return &t.FgBase },
+	"fg_muted":                   func(t *Theme) *color.Color { return &t.FgMuted },
+	"fg_half_muted":              func(t *Theme) *color.Color { return &t.FgHalfMuted },
+	"fg_subtle":                  func(t *Theme) *color.Color { return &t.FgSubtle },
+	"fg_selected":                func(t *Theme) *color.Color { return &t.FgSelected },
+	"border":                     func(t *Theme) *color.Color { return &t.Border },
+	"border_focus":               func(t *Theme) *color.Color { return &t.BorderFocus },
+	"success":                    func(t *Theme) *color.Color { return &t.Success },
+	"error":                      func(t *Theme) *color.Color { return &t.Error },
+	"warning":                    func(t *Theme) *color.Color { return &t.Warning },
+	"info":                       func(t *Theme) *color.Color { return &t.Info },
+	"white":                      func(t *Theme) *color.Color { return &t.White },
+	"blue_light":                 func(t *Theme) *color.Color { return &t.BlueLight },
+	"blue":                       func(t *Theme) *color.Color { return &t.Blue },
+	"blue_dark":                  func(t *Theme) *color.Color { return &t.BlueDark },
+	"yellow":                     func(t *Theme) *color.Color { return &t.Yellow },
+	"citron":                     func(t *Theme) *color.Color { return &t.Citron },
+	"green":                      func(t *Theme) *color.Color { return &t.Green },
+	"green_dark":                 func(t *Theme) *color.Color { return &t.GreenDark },
+	"green_light":                func(t *Theme) *color.Color { return &t.GreenLight },
+	"red":                        func(t *Theme) *color.Color { return &t.Red },
+	"red_dark":                   func(t *Theme) *color.Color { return &t.RedDark },
+	"red_light":                  func(t *Theme) *color.Color { return &t.RedLight },
+	"cherry":                     func(t *Theme) *color.Color { return &t.Cherry },
+	"link":                       func(t *Theme) *color.Color { return &t.Link },
+	"image":                      func(t *Theme) *color.Color { return &t.Image },
+	"diff_insert":                func(t *Theme) *color.Color { return &t.DiffInsert },
+	"diff_insert_bg":             func(t *Theme) *color.Color { return &t.DiffInsertBg },
+	"diff_insert_line_number_bg": func(t *Theme) *color.Color { return &t.DiffInsertLineNumBg },
+	"diff_delete":                func(t *Theme) *color.Color { return &t.DiffDelete },
+	"diff_delete_bg":             func(t *Theme) *color.Color { return &t.DiffDeleteBg },
+	"diff_delete_line_number_bg": func(t *Theme) *color.Color { return &t.DiffDeleteLineNumBg },
+}
+
+// Builtin returns the built-in themes, the default one first.
+func Builtin() []*Theme {
+	files, err := readDir(builtinFS, "themes")
+	if err != nil {
+		panic(err)
+	}
+	themes, errs := resolve(files)
+	if len(errs) > 0 {
+		panic(errors.Join(errs...))
+	}
+	return themes
+}
+
+// Load returns the built-in themes followed by the themes of the .json and
+// .toml files in dir. A theme with the name of a built-in one replaces it.
+// Invalid theme files are skipped and reported in the returned error.
+func Load(dir string) ([]*Theme, error) {
+	builtin, err := readDir(builtinFS, "themes")
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	custom, err := readDir(os.DirFS(dir), ".")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		errs = append(errs, err)
+	}
+	for i := range custom {
+		custom[i].path = filepath.Join(dir, custom[i].path)
+	}
+
+	files := builtin
+	for _, f := range custom {
+		if f.Name == "" {
+			errs = append(errs, fmt.Errorf("theme %s: missing name", f.path))
+			continue
+		}
+		files = slices.DeleteFunc(files, func(b file) bool { return b.Name == f.Name })
+		files = append(files, f)
+	}
+	themes, resolveErrs := resolve(files)
+	return themes, errors.Join(append(errs, resolveErrs...)...)
+}
+
+// Find returns the theme with the given name.
+func Find(themes []*Theme, name string) (*Theme, bool) {
+	for _, t := range themes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func readDir(fsys fs.FS, dir string) ([]file, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []file
+	var errs []error
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+		path := filepath.ToSlash(filepath.Join(dir, entry.Name()))
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		f := file{path: path}
+		if ext == ".toml" {
+			err = toml.Unmarshal(data, &f)
+		} else {
+			err = json.Unmarshal(data, &f)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("theme %s: %w", path, err))
+			continue
+		}
+		files = append(files, f)
+	}
+	return files, errors.Join(errs...)
+}
+
+// resolve builds the themes of the given files on top of the themes they
+// extend. The default theme comes first, the others are sorted by name.
+func resolve(files []file) ([]*Theme, []error) {
+	byName := make(map[string]file, len(files))
+	for _, f := range files {
+		byName[f.Name] = f
+	}
+
+	// The default theme is built in code. A file with its name replaces it,
+	// on top of the built-in one.
+	base := Charmtone()
+	resolved := make(map[string]*Theme, len(files)+1)
+	var build func(f file, chain []string) (*Theme, error)
+	build = func(f file, chain []string) (*Theme, error) {
+		if t, ok := resolved[f.Name]; ok {
+			return t, nil
+		}
+		if slices.Contains(chain, f.Name) {
+			return nil, fmt.Errorf("theme %q extends itself", f.Name)
+		}
+		parentName := cmp.Or(f.Extends, DefaultName)
+		var parent *Theme
+		if p, ok := byName[parentName]; ok && parentName != f.Name {
+			var err error
+			if parent, err = build(p, append(chain, f.Name)); err != nil {
+				return nil, err
+			}
+		} else if parentName == DefaultName {
+			parent = base
+		} else {
+			return nil, fmt.Errorf("theme %q extends unknown theme %q", f.Name, parentName)
+		}
+		t, err := f.apply(*parent)
+		if err != nil {
+			return nil, fmt.Errorf("theme %s: %w", f.path, err)
+		}
+		resolved[f.Name] = t
+		return t, nil
+	}
+
+	var errs []error
+	for _, f := range files {
+		if _, err := build(f, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, ok := resolved[DefaultName]; !ok {
+		resolved[DefaultName] = base
+	}
+
+	themes := make([]*Theme, 0, len(resolved))
+	for _, t := range resolved {
+		themes = append(themes, t)
+	}
+	slices.SortFunc(themes, func(a, b *Theme) int {
+		switch {
+		case a.Name == DefaultName:
+			return -1
+		case b.Name == DefaultName:
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return themes, errs
+}
+
+// apply returns a copy of the parent theme with the settings of the file.
+func (f file) apply(t Theme) (*Theme, error) {
+	t.Name = f.Name
+	t.Path = f.path
+	if f.Dark != nil {
+		t.IsDark = *f.Dark
+	}
+	if f.Syntax != "" {
+		syntax, err := syntaxStyle(f.Syntax)
+		if err != nil {
+			return nil, err
+		}
+		t.Syntax = syntax
+	}
+	for name, value := range f.Colors {
+		field, ok := colorFields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown color %q", name)
+		}
+		c, err := parseColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("color %q: %w", name, err)
+		}
+		*field(&t) = c
+	}
+	return &t, nil
+}
+
+// parseColor parses a hex color, or the number of an ANSI color.
+func parseColor(s string) (color.Color, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || n > 255 {
+			return nil, fmt.Errorf("invalid ANSI color %d", n)
+		}
+		return xansi.IndexedColor(uint8(n)), nil
+	}
+	c, err := colorful.Hex(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	r, g, b := c.RGB255()
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}, nil
+}
+
+// syntaxStyle converts the chroma style with the given name, such as
+// "github" or "dracula", to the syntax highlighting of a theme.
+func syntaxStyle(name string) (ansi.Chroma, error) {
+	style, ok := styles.Registry[strings.ToLower(name)]
+	if !ok {
+		return ansi.Chroma{}, fmt.Errorf("unknown syntax style %q", name)
+	}
+	entry := func(ttype chroma.TokenType) ansi.StylePrimitive {
+		e := style.Get(ttype)
+		var p ansi.StylePrimitive
+		if e.Colour.IsSet() {
+			p.Color = stringPtr(e.Colour.String())
+		}
+		if ttype == chroma.Background || ttype == chroma.Error {
+			if e.Background.IsSet() {
+				p.BackgroundColor = stringPtr(e.Background.String())
+			}
+		}
+		if e.Bold == chroma.Yes {
+			p.Bold = boolPtr(true)
+		}
+		if e.Italic == chroma.Yes {
+			p.Italic = boolPtr(true)
+		}
+		if e.Underline == chroma.Yes {
+			p.Underline = boolPtr(true)
+		}
+		return p
+	}
+	return ansi.Chroma{
+		Text:                entry(chroma.Text),
+		Error:               entry(chroma.Error),
+		Comment:             entry(chroma.Comment),
+		CommentPreproc:      entry(chroma.CommentPreproc),
+		Keyword:             entry(chroma.Keyword),
+		KeywordReserved:     entry(chroma.KeywordReserved),
+		KeywordNamespace:    entry(chroma.KeywordNamespace),
+		KeywordType:         entry(chroma.KeywordType),
+		Operator:            entry(chroma.Operator),
+		Punctuation:         entry(chroma.Punctuation),
+		Name:                entry(chroma.Name),
+		NameBuiltin:         entry(chroma.NameBuiltin),
+		NameTag:             entry(chroma.NameTag),
+		NameAttribute:       entry(chroma.NameAttribute),
+		NameClass:           entry(chroma.NameClass),
+		NameConstant:        entry(chroma.NameConstant),
+		NameDecorator:       entry(chroma.NameDecorator),
+		NameException:       entry(chroma.NameException),
+		NameFunction:        entry(chroma.NameFunction),
+		NameOther:           entry(chroma.NameOther),
+		Literal:             entry(chroma.Literal),
+		LiteralNumber:       entry(chroma.LiteralNumber),
+		LiteralDate:         entry(chroma.LiteralDate),
+		LiteralString:       entry(chroma.LiteralString),
+		LiteralStringEscape: entry(chroma.LiteralStringEscape),
+		GenericDeleted:      entry(chroma.GenericDeleted),
+		GenericEmph:         entry(chroma.GenericEmph),
+		GenericInserted:     entry(chroma.GenericInserted),
+		GenericStrong:       entry(chroma.GenericStrong),
+		GenericSubheading:   entry(chroma.GenericSubheading),
+		Background:          entry(chroma.Background),
+	}, nil
+}
@@ -0,0 +1,234 @@
+// Package theme defines the color themes of the UI. Besides the built-in
+// themes, custom themes are loaded from JSON and TOML files.
+package theme
+
+import (
+	"image/color"
+
+	"charm.land/glamour/v2/ansi"
+	"github.com/charmbracelet/x/exp/charmtone"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// DefaultName is the name of the default theme.
+const DefaultName = "charmtone"
+
+// Theme is a color palette, and the syntax highlighting style of code.
+type Theme struct {
+	Name   string
+	IsDark bool
+	// Path is the file the theme was loaded from, empty for built-in themes.
+	Path string
+
+	Primary   color.Color
+	Secondary color.Color
+	Tertiary  color.Color
+	Accent    color.Color
+
+	BgBase        color.Color
+	BgBaseLighter color.Color
+	BgSubtle      color.Color
+	BgOverlay     color.Color
+
+	FgBase      color.Color
+	FgMuted     color.Color
+	FgHalfMuted color.Color
+	FgSubtle    color.Color
+	FgSelected  color.Color
+
+	Border      color.Color
+	BorderFocus color.Color
+
+	Success color.Color
+	Error   color.Color
+	Warning color.Color
+	Info    color.Color
+
+	White color.Color
+
+	BlueLight color.Color
+	Blue      color.Color
+	BlueDark  color.Color
+
+	Yellow color.Color
+	Citron color.Color
+
+	Green      color.Color
+	GreenDark  color.Color
+	GreenLight color.Color
+
+	Red      color.Color
+	RedDark  color.Color
+	RedLight color.Color
+	Cherry   color.Color
+
+	// Markdown links and images.
+	Link  color.Color
+	Image color.Color
+
+	// Diffs.
+	DiffInsert          color.Color
+	DiffInsertBg        color.Color
+	DiffInsertLineNumBg color.Color
+	DiffDelete          color.Color
+	DiffDeleteBg        color.Color
+	DiffDeleteLineNumBg color.Color
+
+	// Syntax is the style of highlighted code, in markdown code blocks, file
+	// views and diffs.
+	Syntax ansi.Chroma
+}
+
+// Hex returns the hex representation of a color, as used by markdown
+// styles.
+func Hex(c color.Color) string {
+	cc, _ := colorful.MakeColor(c)
+	return cc.Hex()
+}
+
+// Charmtone returns the default theme, based on the charmtone palette.
+func Charmtone() *Theme {
+	return &Theme{
+		Name:   DefaultName,
+		IsDark: true,
+
+		Primary:   charmtone.Charple,
+		Secondary: charmtone.Dolly,
+		Tertiary:  charmtone.Bok,
+		Accent:    charmtone.Zest,
+
+		BgBase:        charmtone.Pepper,
+		BgBaseLighter: charmtone.BBQ,
+		BgSubtle:      charmtone.Charcoal,
+		BgOverlay:     charmtone.Iron,
+
+		FgBase:      charmtone.Ash,
+		FgMuted:     charmtone.Squid,
+		FgHalfMuted: charmtone.Smoke,
+		FgSubtle:    charmtone.Oyster,
+		FgSelected:  charmtone.Salt,
+
+		Border:      charmtone.Charcoal,
+		BorderFocus: charmtone.Charple,
+
+		Success: charmtone.Guac,
+		Error:   charmtone.Sriracha,
+		Warning: charmtone.Zest,
+		Info:    charmtone.Malibu,
+
+		White: charmtone.Butter,
+
+		BlueLight: charmtone.Sardine,
+		Blue:      charmtone.Malibu,
+		BlueDark:  charmtone.Damson,
+
+		Yellow: charmtone.Mustard,
+		Citron: charmtone.Citron,
+
+		Green:      charmtone.Julep,
+		GreenDark:  charmtone.Guac,
+		GreenLight: charmtone.Bok,
+
+		Red:      charmtone.Coral,
+		RedDark:  charmtone.Sriracha,
+		RedLight: charmtone.Salmon,
+		Cherry:   charmtone.Cherry,
+
+		Link:  charmtone.Zinc,
+		Image: charmtone.Cheeky,
+
+		DiffInsert:          color.RGBA{R: 0x62, G: 0x96, B: 0x57, A: 0xff},
+		DiffInsertBg:        color.RGBA{R: 0x32, G: 0x39, B: 0x31, A: 0xff},
+		DiffInsertLineNumBg: color.RGBA{R: 0x2b, G: 0x32, B: 0x2a, A: 0xff},
+		DiffDelete:          color.RGBA{R: 0xa4, G: 0x5c, B: 0x59, A: 0xff},
+		DiffDeleteBg:        color.RGBA{R: 0x38, G: 0x30, B: 0x30, A: 0xff},
+		DiffDeleteLineNumBg: color.RGBA{R: 0x31, G: 0x29, B: 0x29, A: 0xff},
+
+		Syntax: ansi.Chroma{
+			Text: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Smoke.Hex()),
+			},
+			Error: ansi.StylePrimitive{
+				Color:           stringPtr(charmtone.Butter.Hex()),
+				BackgroundColor: stringPtr(charmtone.Sriracha.Hex()),
+			},
+			Comment: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Oyster.Hex()),
+			},
+			CommentPreproc: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Bengal.Hex()),
+			},
+			Keyword: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Malibu.Hex()),
+			},
+			KeywordReserved: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Pony.Hex()),
+			},
+			KeywordNamespace: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Pony.Hex()),
+			},
+			KeywordType: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Guppy.Hex()),
+			},
+			Operator: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Salmon.Hex()),
+			},
+			Punctuation: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Zest.Hex()),
+			},
+			Name: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Smoke.Hex()),
+			},
+			NameBuiltin: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Cheeky.Hex()),
+			},
+			NameTag: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Mauve.Hex()),
+			},
+			NameAttribute: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Hazy.Hex()),
+			},
+			NameClass: ansi.StylePrimitive{
+				Color:     stringPtr(charmtone.Salt.Hex()),
+				Underline: boolPtr(true),
+				Bold:      boolPtr(true),
+			},
+			NameDecorator: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Citron.Hex()),
+			},
+			NameFunction: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Guac.Hex()),
+			},
+			LiteralNumber: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Julep.Hex()),
+			},
+			LiteralString: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Cumin.Hex()),
+			},
+			LiteralStringEscape: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Bok.Hex()),
+			},
+			GenericDeleted: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Coral.Hex()),
+			},
+			GenericEmph: ansi.StylePrimitive{
+				Italic: boolPtr(true),
+			},
+			GenericInserted: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Guac.Hex()),
+			},
+			GenericStrong: ansi.StylePrimitive{
+				Bold: boolPtr(true),
+			},
+			GenericSubheading: ansi.StylePrimitive{
+				Color: stringPtr(charmtone.Squid.Hex()),
+			},
+			Background: ansi.StylePrimitive{
+				BackgroundColor: stringPtr(charmtone.Charcoal.Hex()),
+			},
+		},
+	}
+}
+
+func boolPtr(b bool) *bool       { return &b }
+func stringPtr(s string) *string { return &s }
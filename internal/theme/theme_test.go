@@ -0,0 +1,122 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltin(t *testing.T) {
+	t.Parallel()
+
+	themes := Builtin()
+	require.Equal(t, DefaultName, themes[0].Name)
+
+	light, ok := Find(themes, "charmtone-light")
+	require.True(t, ok)
+	require.False(t, light.IsDark)
+	require.Equal(t, "#fffaf1", Hex(light.BgBase))
+	require.NotEqual(t, Charmtone().Syntax, light.Syntax)
+
+	highContrast, ok := Find(themes, "high-contrast")
+	require.True(t, ok)
+	require.True(t, highContrast.IsDark)
+	// Colors it does not set come from the default theme.
+	require.Equal(t, Hex(Charmtone().Cherry), Hex(highContrast.Cherry))
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	writeFile("paper.toml", `
+name = "paper"
+extends = "charmtone-light"
+syntax = "solarized-light"
+
+[colors]
+primary = "#112233"
+border = "244"
+`)
+	writeFile("ink.json", `{"name": "ink", "extends": "paper", "colors": {"fg_base": "#000"}}`)
+	writeFile("loop-a.json", `{"name": "loop-a", "extends": "loop-b"}`)
+	writeFile("loop-b.json", `{"name": "loop-b", "extends": "loop-a"}`)
+	writeFile("typo.json", `{"name": "typo", "colors": {"primray": "#fff"}}`)
+	writeFile("bad-syntax.json", `{"name": "bad-syntax", "syntax": "nope"}`)
+	writeFile("notes.txt", "not a theme")
+
+	themes, err := Load(dir)
+	require.ErrorContains(t, err, `theme "loop-a" extends itself`)
+	require.ErrorContains(t, err, `unknown color "primray"`)
+	require.ErrorContains(t, err, `unknown syntax style "nope"`)
+
+	paper, ok := Find(themes, "paper")
+	require.True(t, ok)
+	require.False(t, paper.IsDark)
+	require.Equal(t, filepath.Join(dir, "paper.toml"), paper.Path)
+	require.Equal(t, "#112233", Hex(paper.Primary))
+	require.Equal(t, "#808080", Hex(paper.Border))
+	require.Equal(t, "#fffaf1", Hex(paper.BgBase))
+
+	ink, ok := Find(themes, "ink")
+	require.True(t, ok)
+	require.Equal(t, "#000000", Hex(ink.FgBase))
+	require.Equal(t, "#112233", Hex(ink.Primary))
+	require.Equal(t, paper.Syntax, ink.Syntax)
+
+	for _, name := range []string{"loop-a", "loop-b", "typo", "bad-syntax"} {
+		_, ok := Find(themes, name)
+		require.False(t, ok, name)
+	}
+	_, ok = Find(themes, DefaultName)
+	require.True(t, ok)
+}
+
+func TestLoadMissingDir(t *testing.T) {
+	t.Parallel()
+
+	themes, err := Load(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	require.Len(t, themes, len(Builtin()))
+}
+
+func TestLoadBrokenFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "good.json"), []byte(`{"name": "good"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"name": `), 0o644))
+
+	themes, err := Load(dir)
+	require.ErrorContains(t, err, "broken.json")
+	_, ok := Find(themes, "good")
+	require.True(t, ok)
+	require.Len(t, themes, len(Builtin())+1)
+}
+
+func TestLoadDefaultOverride(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "charmtone.json"), []byte(`{"name": "charmtone", "colors": {"primary": "#112233"}}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "child.json"), []byte(`{"name": "child", "colors": {"border": "#445566"}}`), 0o644))
+
+	themes, err := Load(dir)
+	require.NoError(t, err)
+	require.Equal(t, DefaultName, themes[0].Name)
+	require.Equal(t, filepath.Join(dir, "charmtone.json"), themes[0].Path)
+	require.Equal(t, "#112233", Hex(themes[0].Primary))
+	// Colors it does not set come from the built-in theme.
+	require.Equal(t, Hex(Charmtone().Cherry), Hex(themes[0].Cherry))
+
+	child, ok := Find(themes, "child")
+	require.True(t, ok)
+	require.Equal(t, "#112233", Hex(child.Primary))
+	require.Equal(t, "#445566", Hex(child.Border))
+}
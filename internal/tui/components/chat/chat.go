@@ -49,6 +49,7 @@ type MessageListCmp interface {
 	layout.Help
 
 	SetSession(session.Session) tea.Cmd
+	Reload() tea.Cmd
 	GoToBottom() tea.Cmd
 	GetSelectedText() string
 	CopySelectedText(bool) tea.Cmd
@@ -323,6 +324,8 @@ func (m *messageListCmp) handleMessageEvent(event pubsub.Event[message.Message])
 			return m.handleChildSession(event)
 		}
 		switch event.Payload.Role {
+		case message.User:
+			return m.handleUpdateUserMessage(event.Payload)
 		case message.Assistant:
 			return m.handleUpdateAssistantMessage(event.Payload)
 		case message.Tool:
@@ -375,6 +378,19 @@ func (m *messageListCmp) handleNewUserMessage(msg message.Message) tea.Cmd {
 	return m.listCmp.AppendItem(messages.NewMessageCmp(msg))
 }
 
+// handleUpdateUserMessage updates a user message, as verifications are while
+// their commands run.
+func (m *messageListCmp) handleUpdateUserMessage(msg message.Message) tea.Cmd {
+	items := m.listCmp.Items()
+	for i := len(items) - 1; i >= 0; i-- {
+		if uiMsg, ok := items[i].(messages.MessageCmp); ok && uiMsg.GetMessage().ID == msg.ID {
+			uiMsg.SetMessage(msg)
+			return m.listCmp.UpdateItem(uiMsg.ID(), uiMsg)
+		}
+	}
+	return nil
+}
+
 // handleToolMessage updates existing tool calls with their results.
 func (m *messageListCmp) handleToolMessage(msg message.Message) tea.Cmd {
 	items := m.listCmp.Items()
@@ -565,6 +581,17 @@ func (m *messageListCmp) SetSession(session session.Session) tea.Cmd {
 	return m.listCmp.SetItems(uiMessages)
 }
 
+// Reload renders the messages of the current session again, for instance
+// after the theme changed.
+func (m *messageListCmp) Reload() tea.Cmd {
+	current := m.session
+	if current.ID == "" {
+		return nil
+	}
+	m.session = session.Session{}
+	return m.SetSession(current)
+}
+
 // buildToolResultMap creates a map of tool call ID to tool result for efficient lookup.
 func (m *messageListCmp) buildToolResultMap(messages []message.Message) map[string]message.ToolResult {
 	toolResultMap := make(map[string]message.ToolResult)
@@ -17,9 +17,11 @@ import (
 	"charm.land/bubbles/v2/textarea"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/app"
 	"github.com/charmbracelet/crush/internal/fsext"
 	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/pubsub"
 	"github.com/charmbracelet/crush/internal/session"
 	"github.com/charmbracelet/crush/internal/tui/components/chat"
 	"github.com/charmbracelet/crush/internal/tui/components/completions"
@@ -69,6 +71,7 @@ type editorCmp struct {
 	sessionFileReads   []string
 	textarea           textarea.Model
 	attachments        []message.Attachment
+	resources          map[string]ResourceCompletionItem // attached MCP resources by URI
 	deleteMode         bool
 	readyPlaceholder   string
 	workingPlaceholder string
@@ -170,6 +173,7 @@ func (m *editorCmp) send() tea.Cmd {
 			Text:        value,
 			Attachments: attachments,
 		}),
+		m.releaseResources(),
 	)
 }
 
@@ -244,7 +248,41 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 				Content:  content,
 			})
 		}
+		if item, ok := msg.Value.(ResourceCompletionItem); ok {
+			word := m.textarea.Word()
+			value := m.textarea.Value()
+			value = value[:m.completionsStartIndex] + // Remove the current query
+				item.URI + // Insert the resource URI
+				value[m.completionsStartIndex+len(word):] // Append the rest of the value
+			m.textarea.SetValue(value)
+			m.textarea.MoveToEnd()
+			if !msg.Insert {
+				m.isCompletionsOpen = false
+				m.currentQuery = ""
+				m.completionsStartIndex = 0
+			}
+			if _, ok := m.resources[item.URI]; ok {
+				return m, nil
+			}
+			return m, loadResource(item, false)
+		}
+	case ResourceLoadedMsg:
+		if _, attached := m.resources[msg.item.URI]; msg.refresh && !attached {
+			// The resource was detached while it was being refreshed.
+			return m, nil
+		}
+		if msg.err != nil {
+			return m, util.ReportError(fmt.Errorf("could not read resource %s: %w", msg.item.URI, msg.err))
+		}
+		m.setResourceAttachment(msg.item, msg.attachment)
+	case pubsub.Event[mcp.Event]:
+		if msg.Payload.Type == mcp.EventResourceUpdated {
+			return m, m.refreshResource(msg.Payload.Name, msg.Payload.URI)
+		}
 
+	case commands.ThemeChangedMsg:
+		m.textarea.SetStyles(styles.CurrentTheme().S().TextArea)
+		return m, nil
 	case commands.OpenExternalEditorMsg:
 		if m.app.AgentCoordinator.IsSessionBusy(m.session.ID) {
 			return m, util.ReportWarn("Agent is working, please wait...")
@@ -328,7 +366,7 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 		if key.Matches(msg, DeleteKeyMaps.DeleteAllAttachments) && m.deleteMode {
 			m.deleteMode = false
 			m.attachments = nil
-			return m, nil
+			return m, m.releaseResources()
 		}
 		rune := msg.Code
 		if m.deleteMode && unicode.IsDigit(rune) {
@@ -340,7 +378,7 @@ func (m *editorCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 				} else {
 					m.attachments = slices.Delete(m.attachments, num, num+1)
 				}
-				return m, nil
+				return m, m.releaseResources()
 			}
 		}
 		if key.Matches(msg, m.keyMap.OpenEditor) {
@@ -639,6 +677,7 @@ func (m *editorCmp) startCompletions() tea.Msg {
 			},
 		})
 	}
+	completionItems = append(completionItems, resourceCompletions()...)
 
 	x, y := m.completionsPosition()
 	return completions.OpenCompletionsMsg{
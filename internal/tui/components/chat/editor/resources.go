@@ -0,0 +1,155 @@
+package editor
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/tui/components/completions"
+)
+
+// ResourceCompletionItem is an MCP resource offered by the @ completions.
+type ResourceCompletionItem struct {
+	MCP  string // The name of the MCP serving the resource
+	URI  string // The resource URI
+	Name string // The display name of the resource
+}
+
+// ResourceLoadedMsg is sent when the contents of an attached MCP resource
+// were read, either when attaching it or after the MCP reported an update.
+type ResourceLoadedMsg struct {
+	item       ResourceCompletionItem
+	attachment message.Attachment
+	refresh    bool
+	err        error
+}
+
+// resourceCompletions returns the MCP resources that can be attached.
+func resourceCompletions() []completions.Completion {
+	var items []completions.Completion
+	for mcpName, resources := range mcp.Resources() {
+		for _, resource := range resources {
+			name := cmp.Or(resource.Title, resource.Name, resource.URI)
+			items = append(items, completions.Completion{
+				Title: fmt.Sprintf("%s:%s", mcpName, name),
+				Value: ResourceCompletionItem{
+					MCP:  mcpName,
+					URI:  resource.URI,
+					Name: name,
+				},
+			})
+		}
+	}
+	slices.SortFunc(items, func(a, b completions.Completion) int {
+		return strings.Compare(a.Title, b.Title)
+	})
+	return items
+}
+
+// loadResource reads an MCP resource. Unless this is a refresh of an already
+// attached resource, it also subscribes to its updates.
+func loadResource(item ResourceCompletionItem, refresh bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		contents, err := mcp.ReadResource(ctx, item.MCP, item.URI)
+		if err != nil {
+			return ResourceLoadedMsg{item: item, refresh: refresh, err: err}
+		}
+		if !refresh {
+			if err := mcp.SubscribeResource(ctx, item.MCP, item.URI); err != nil {
+				slog.Warn("Failed to subscribe to MCP resource", "mcp", item.MCP, "uri", item.URI, "error", err)
+			}
+		}
+		return ResourceLoadedMsg{
+			item:       item,
+			attachment: resourceAttachment(item, contents),
+			refresh:    refresh,
+		}
+	}
+}
+
+// resourceAttachment turns the contents of an MCP resource into an
+// attachment. Binary contents are only used when the resource has nothing
+// else to offer.
+func resourceAttachment(item ResourceCompletionItem, contents []*mcp.ResourceContents) message.Attachment {
+	attachment := message.Attachment{
+		FilePath: item.URI,
+		FileName: item.Name,
+		MimeType: "text/plain",
+	}
+	var texts []string
+	for _, c := range contents {
+		if c.Text != "" {
+			texts = append(texts, c.Text)
+		}
+	}
+	if len(texts) > 0 {
+		attachment.Content = []byte(strings.Join(texts, "\n"))
+		return attachment
+	}
+	for _, c := range contents {
+		if len(c.Blob) > 0 {
+			attachment.MimeType = cmp.Or(c.MIMEType, "application/octet-stream")
+			attachment.Content = c.Blob
+			break
+		}
+	}
+	return attachment
+}
+
+// setResourceAttachment adds the attachment of an MCP resource, replacing the
+// previous version if it is already attached.
+func (m *editorCmp) setResourceAttachment(item ResourceCompletionItem, attachment message.Attachment) {
+	if m.resources == nil {
+		m.resources = make(map[string]ResourceCompletionItem)
+	}
+	m.resources[item.URI] = item
+	for i, a := range m.attachments {
+		if a.FilePath == item.URI {
+			m.attachments[i] = attachment
+			return
+		}
+	}
+	m.attachments = append(m.attachments, attachment)
+}
+
+// refreshResource reloads an attached resource after its MCP reported that it
+// changed.
+func (m *editorCmp) refreshResource(mcpName, uri string) tea.Cmd {
+	item, ok := m.resources[uri]
+	if !ok || item.MCP != mcpName {
+		return nil
+	}
+	return loadResource(item, true)
+}
+
+// releaseResources unsubscribes from the resources that are no longer
+// attached.
+func (m *editorCmp) releaseResources() tea.Cmd {
+	var released []ResourceCompletionItem
+	for uri, item := range m.resources {
+		if !slices.ContainsFunc(m.attachments, func(a message.Attachment) bool {
+			return a.FilePath == uri
+		}) {
+			released = append(released, item)
+			delete(m.resources, uri)
+		}
+	}
+	if len(released) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		for _, item := range released {
+			if err := mcp.UnsubscribeResource(context.Background(), item.MCP, item.URI); err != nil {
+				slog.Warn("Failed to unsubscribe from MCP resource", "mcp", item.MCP, "uri", item.URI, "error", err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceAttachment(t *testing.T) {
+	t.Parallel()
+
+	item := ResourceCompletionItem{MCP: "docs", URI: "file:///readme", Name: "README"}
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+		attachment := resourceAttachment(item, []*mcp.ResourceContents{
+			{URI: item.URI, Text: "# Readme"},
+			{URI: item.URI, MIMEType: "image/png", Blob: []byte("png")},
+			{URI: item.URI, Text: "More"},
+		})
+		require.Equal(t, message.Attachment{
+			FilePath: "file:///readme",
+			FileName: "README",
+			MimeType: "text/plain",
+			Content:  []byte("# Readme\nMore"),
+		}, attachment)
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		t.Parallel()
+		attachment := resourceAttachment(item, []*mcp.ResourceContents{
+			{URI: item.URI, MIMEType: "image/png", Blob: []byte("png")},
+		})
+		require.Equal(t, "image/png", attachment.MimeType)
+		require.Equal(t, []byte("png"), attachment.Content)
+	})
+}
+
+func TestSetResourceAttachment(t *testing.T) {
+	t.Parallel()
+
+	m := &editorCmp{}
+	item := ResourceCompletionItem{MCP: "docs", URI: "file:///readme", Name: "README"}
+	m.setResourceAttachment(item, message.Attachment{FilePath: item.URI, Content: []byte("v1")})
+	m.setResourceAttachment(item, message.Attachment{FilePath: item.URI, Content: []byte("v2")})
+	require.Len(t, m.attachments, 1)
+	require.Equal(t, []byte("v2"), m.attachments[0].Content)
+	require.Equal(t, item, m.resources[item.URI])
+
+	// An update of a resource of another MCP with the same URI is ignored.
+	require.Nil(t, m.refreshResource("other", item.URI))
+	require.NotNil(t, m.refreshResource("docs", item.URI))
+
+	m.attachments = nil
+	require.NotNil(t, m.releaseResources())
+	require.Empty(t, m.resources)
+	require.Nil(t, m.releaseResources())
+}
@@ -58,6 +58,9 @@ type messageCmp struct {
 
 	// Thinking viewport for displaying reasoning content
 	thinkingViewport viewport.Model
+
+	// expanded shows the output of a verification.
+	expanded bool
 }
 
 var focusedMessageBorder = lipgloss.Border{
@@ -104,6 +107,10 @@ func (m *messageCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
 			return m, cmd
 		}
 	case tea.KeyPressMsg:
+		if key.Matches(msg, ToggleExpandKey) && m.message.Verification() != nil {
+			m.expanded = !m.expanded
+			return m, nil
+		}
 		if key.Matches(msg, CopyKey) {
 			return m, tea.Sequence(
 				tea.SetClipboard(m.message.Content().Text),
@@ -131,6 +138,9 @@ func (m *messageCmp) View() string {
 		// this is a user or assistant message
 		switch m.message.Role {
 		case message.User:
+			if verification := m.message.Verification(); verification != nil {
+				return m.renderVerification(*verification)
+			}
 			return m.renderUserMessage()
 		default:
 			return m.renderAssistantMessage()
@@ -164,7 +174,7 @@ func (msg *messageCmp) style() lipgloss.Style {
 	}
 
 	style := t.S().Text
-	if msg.message.Role == message.User {
+	if msg.message.Role == message.User && msg.message.Verification() == nil {
 		style = style.PaddingLeft(1).BorderLeft(true).BorderStyle(borderStyle).BorderForeground(t.Primary)
 	} else {
 		if msg.focused {
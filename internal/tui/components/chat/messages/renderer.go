@@ -183,21 +183,29 @@ func (br baseRenderer) renderError(v *toolCallCmp, message string) string {
 func init() {
 	registry.register(tools.BashToolName, func() renderer { return bashRenderer{} })
 	registry.register(tools.JobOutputToolName, func() renderer { return bashOutputRenderer{} })
+	registry.register(tools.JobInputToolName, func() renderer { return bashInputRenderer{} })
 	registry.register(tools.JobKillToolName, func() renderer { return bashKillRenderer{} })
 	registry.register(tools.DownloadToolName, func() renderer { return downloadRenderer{} })
 	registry.register(tools.ViewToolName, func() renderer { return viewRenderer{} })
 	registry.register(tools.EditToolName, func() renderer { return editRenderer{} })
 	registry.register(tools.MultiEditToolName, func() renderer { return multiEditRenderer{} })
+	registry.register(tools.NotebookEditToolName, func() renderer { return notebookEditRenderer{} })
 	registry.register(tools.WriteToolName, func() renderer { return writeRenderer{} })
 	registry.register(tools.FetchToolName, func() renderer { return simpleFetchRenderer{} })
 	registry.register(tools.AgenticFetchToolName, func() renderer { return agenticFetchRenderer{} })
 	registry.register(tools.WebFetchToolName, func() renderer { return webFetchRenderer{} })
 	registry.register(tools.WebSearchToolName, func() renderer { return webSearchRenderer{} })
+	registry.register(tools.GitStatusToolName, func() renderer { return gitRenderer{} })
+	registry.register(tools.GitDiffToolName, func() renderer { return gitRenderer{} })
+	registry.register(tools.GitLogToolName, func() renderer { return gitRenderer{} })
+	registry.register(tools.GitCommitToolName, func() renderer { return gitRenderer{} })
 	registry.register(tools.GlobToolName, func() renderer { return globRenderer{} })
 	registry.register(tools.GrepToolName, func() renderer { return grepRenderer{} })
 	registry.register(tools.LSToolName, func() renderer { return lsRenderer{} })
 	registry.register(tools.SourcegraphToolName, func() renderer { return sourcegraphRenderer{} })
 	registry.register(tools.DiagnosticsToolName, func() renderer { return diagnosticsRenderer{} })
+	registry.register(tools.ReadMCPResourceToolName, func() renderer { return mcpResourceRenderer{} })
+	registry.register(tools.SkillToolName, func() renderer { return skillRenderer{} })
 	registry.register(tools.TodosToolName, func() renderer { return todosRenderer{} })
 	registry.register(agent.AgentToolName, func() renderer { return agentRenderer{} })
 }
@@ -371,6 +379,49 @@ func (bor bashOutputRenderer) Render(v *toolCallCmp) string {
 	return joinHeaderBody(header, body)
 }
 
+// -----------------------------------------------------------------------------
+//  Bash Input renderer
+// -----------------------------------------------------------------------------
+
+// bashInputRenderer handles sending input to interactive background shells
+type bashInputRenderer struct {
+	baseRenderer
+}
+
+// Render displays the shell ID, the input sent and the resulting output
+func (bir bashInputRenderer) Render(v *toolCallCmp) string {
+	var params tools.JobInputParams
+	if err := bir.unmarshalParams(v.call.Input, &params); err != nil {
+		return bir.renderError(v, "Invalid job_input parameters")
+	}
+
+	description := fmt.Sprintf("%q", params.Input)
+	var meta tools.JobInputResponseMetadata
+	if v.result.Metadata != "" {
+		if err := bir.unmarshalParams(v.result.Metadata, &meta); err == nil {
+			if meta.Description != "" {
+				description = meta.Description + " " + description
+			} else {
+				description = meta.Command + " " + description
+			}
+		}
+	}
+
+	width := v.textWidth()
+	if v.isNested {
+		width -= 4 // Adjust for nested tool call indentation
+	}
+	header := makeJobHeader(v, "Input", fmt.Sprintf("PID %s", params.ShellID), description, width)
+	if v.isNested {
+		return v.style().Render(header)
+	}
+	if res, done := earlyState(header, v); done {
+		return res
+	}
+	body := renderPlainContent(v, v.result.Content)
+	return joinHeaderBody(header, body)
+}
+
 // -----------------------------------------------------------------------------
 //  Bash Kill renderer
 // -----------------------------------------------------------------------------
@@ -567,6 +618,60 @@ func (mer multiEditRenderer) Render(v *toolCallCmp) string {
 	})
 }
 
+// -----------------------------------------------------------------------------
+//  Notebook edit renderer
+// -----------------------------------------------------------------------------
+
+// notebookEditRenderer handles notebook cell edits with diff visualization
+type notebookEditRenderer struct {
+	baseRenderer
+}
+
+// Render displays the edited notebook cell with a formatted diff of changes
+func (nr notebookEditRenderer) Render(v *toolCallCmp) string {
+	t := styles.CurrentTheme()
+	var params tools.NotebookEditParams
+	var args []string
+	if err := nr.unmarshalParams(v.call.Input, &params); err == nil {
+		builder := newParamBuilder().
+			addMain(fsext.PrettyPath(params.NotebookPath)).
+			addKeyValue("mode", params.EditMode).
+			addKeyValue("cell_id", params.CellID)
+		if params.CellIndex != nil {
+			builder = builder.addKeyValue("cell_index", fmt.Sprintf("%d", *params.CellIndex))
+		}
+		args = builder.build()
+	}
+
+	return nr.renderWithParams(v, "Notebook Edit", args, func() string {
+		var meta tools.NotebookEditResponseMetadata
+		if err := nr.unmarshalParams(v.result.Metadata, &meta); err != nil {
+			return renderPlainContent(v, v.result.Content)
+		}
+
+		name := fmt.Sprintf("%s (cell %d)", fsext.PrettyPath(params.NotebookPath), meta.CellIndex)
+		formatter := core.DiffFormatter().
+			Before(name, meta.OldContent).
+			After(name, meta.NewContent).
+			Width(v.textWidth() - 2) // -2 for padding
+		if v.textWidth() > 120 {
+			formatter = formatter.Split()
+		}
+		// add a message to the bottom if the content was truncated
+		formatted := formatter.String()
+		if lipgloss.Height(formatted) > responseContextHeight {
+			contentLines := strings.Split(formatted, "\n")
+			truncateMessage := t.S().Muted.
+				Background(t.BgBaseLighter).
+				PaddingLeft(2).
+				Width(v.textWidth() - 2).
+				Render(fmt.Sprintf("… (%d lines)", len(contentLines)-responseContextHeight))
+			formatted = strings.Join(contentLines[:responseContextHeight], "\n") + "\n" + truncateMessage
+		}
+		return formatted
+	})
+}
+
 // -----------------------------------------------------------------------------
 //  Write renderer
 // -----------------------------------------------------------------------------
@@ -866,6 +971,58 @@ func (lr lsRenderer) Render(v *toolCallCmp) string {
 	})
 }
 
+// -----------------------------------------------------------------------------
+//  Git renderer
+// -----------------------------------------------------------------------------
+
+// gitRenderer handles the git tools, showing the main parameter of each
+type gitRenderer struct {
+	baseRenderer
+}
+
+// Render displays the git tool parameters and its plain output
+func (gr gitRenderer) Render(v *toolCallCmp) string {
+	var args []string
+	switch v.call.Name {
+	case tools.GitStatusToolName:
+		var params tools.GitStatusParams
+		if err := gr.unmarshalParams(v.call.Input, &params); err == nil && params.Path != "" {
+			args = newParamBuilder().addMain(fsext.PrettyPath(params.Path)).build()
+		}
+	case tools.GitDiffToolName:
+		var params tools.GitDiffParams
+		if err := gr.unmarshalParams(v.call.Input, &params); err == nil {
+			args = newParamBuilder().
+				addMain(strings.Join(params.Paths, " ")).
+				addKeyValue("ref", params.Ref).
+				addFlag("staged", params.Staged).
+				build()
+		}
+	case tools.GitLogToolName:
+		var params tools.GitLogParams
+		if err := gr.unmarshalParams(v.call.Input, &params); err == nil {
+			args = newParamBuilder().
+				addMain(params.Ref).
+				addKeyValue("path", params.Path).
+				addKeyValue("limit", formatNonZero(params.Limit)).
+				build()
+		}
+	case tools.GitCommitToolName:
+		var params tools.GitCommitParams
+		if err := gr.unmarshalParams(v.call.Input, &params); err == nil {
+			subject, _, _ := strings.Cut(params.Message, "\n")
+			args = newParamBuilder().
+				addMain(subject).
+				addFlag("all", params.All).
+				build()
+		}
+	}
+
+	return gr.renderWithParams(v, prettifyToolName(v.call.Name), args, func() string {
+		return renderPlainContent(v, v.result.Content)
+	})
+}
+
 // -----------------------------------------------------------------------------
 //  Sourcegraph renderer
 // -----------------------------------------------------------------------------
@@ -892,6 +1049,72 @@ func (sr sourcegraphRenderer) Render(v *toolCallCmp) string {
 	})
 }
 
+// -----------------------------------------------------------------------------
+//  MCP resource renderer
+// -----------------------------------------------------------------------------
+
+// mcpResourceRenderer handles listing and reading MCP resources
+type mcpResourceRenderer struct {
+	baseRenderer
+}
+
+// Render displays the resource URI, or the server whose resources are listed
+func (mr mcpResourceRenderer) Render(v *toolCallCmp) string {
+	var params tools.ReadMCPResourceParams
+	var args []string
+	if err := mr.unmarshalParams(v.call.Input, &params); err == nil {
+		if params.URI != "" {
+			args = newParamBuilder().
+				addMain(params.URI).
+				addKeyValue("server", params.Server).
+				build()
+		} else {
+			args = newParamBuilder().addMain(cmp.Or(params.Server, "all servers")).build()
+		}
+	}
+
+	return mr.renderWithParams(v, prettifyToolName(v.call.Name), args, func() string {
+		if strings.HasPrefix(v.result.MIMEType, "image/") {
+			return renderImageContent(v, v.result.Data, v.result.MIMEType, v.result.Content)
+		}
+		return renderPlainContent(v, v.result.Content)
+	})
+}
+
+// -----------------------------------------------------------------------------
+//  Skill renderer
+// -----------------------------------------------------------------------------
+
+// skillRenderer handles skill activation
+type skillRenderer struct {
+	baseRenderer
+}
+
+// Render displays the skill name, its description and bundled files rather
+// than its full instructions
+func (sr skillRenderer) Render(v *toolCallCmp) string {
+	var params tools.SkillParams
+	var args []string
+	if err := sr.unmarshalParams(v.call.Input, &params); err == nil {
+		args = newParamBuilder().addMain(params.Name).build()
+	}
+
+	return sr.renderWithParams(v, prettifyToolName(v.call.Name), args, func() string {
+		var meta tools.SkillResponseMetadata
+		if err := sr.unmarshalParams(v.result.Metadata, &meta); err != nil {
+			return renderPlainContent(v, v.result.Content)
+		}
+		content := meta.Description
+		if len(meta.Resources) > 0 {
+			content += "\n\n" + strings.Join(meta.Resources, "\n")
+		}
+		if len(meta.AllowedTools) > 0 {
+			content += "\n\nAllowed tools: " + strings.Join(meta.AllowedTools, ", ")
+		}
+		return renderPlainContent(v, content)
+	})
+}
+
 // -----------------------------------------------------------------------------
 //  Diagnostics renderer
 // -----------------------------------------------------------------------------
@@ -1276,6 +1499,8 @@ func prettifyToolName(name string) string {
 		return "Bash"
 	case tools.JobOutputToolName:
 		return "Job: Output"
+	case tools.JobInputToolName:
+		return "Job: Input"
 	case tools.JobKillToolName:
 		return "Job: Kill"
 	case tools.DownloadToolName:
@@ -1284,6 +1509,8 @@ func prettifyToolName(name string) string {
 		return "Edit"
 	case tools.MultiEditToolName:
 		return "Multi-Edit"
+	case tools.NotebookEditToolName:
+		return "Notebook Edit"
 	case tools.FetchToolName:
 		return "Fetch"
 	case tools.AgenticFetchToolName:
@@ -1292,6 +1519,14 @@ func prettifyToolName(name string) string {
 		return "Fetch"
 	case tools.WebSearchToolName:
 		return "Search"
+	case tools.GitStatusToolName:
+		return "Git Status"
+	case tools.GitDiffToolName:
+		return "Git Diff"
+	case tools.GitLogToolName:
+		return "Git Log"
+	case tools.GitCommitToolName:
+		return "Git Commit"
 	case tools.GlobToolName:
 		return "Glob"
 	case tools.GrepToolName:
@@ -1300,6 +1535,10 @@ func prettifyToolName(name string) string {
 		return "List"
 	case tools.SourcegraphToolName:
 		return "Sourcegraph"
+	case tools.ReadMCPResourceToolName:
+		return "MCP Resource"
+	case tools.SkillToolName:
+		return "Skill"
 	case tools.TodosToolName:
 		return "To-Do"
 	case tools.ViewToolName:
@@ -279,6 +279,42 @@ func (m *toolCallCmp) formatParametersForCopy() string {
 			parts = append(parts, fmt.Sprintf("**Edits:** %d", len(params.Edits)))
 			return strings.Join(parts, "\n")
 		}
+	case tools.NotebookEditToolName:
+		var params tools.NotebookEditParams
+		if json.Unmarshal([]byte(m.call.Input), &params) == nil {
+			var parts []string
+			parts = append(parts, fmt.Sprintf("**Notebook:** %s", fsext.PrettyPath(params.NotebookPath)))
+			if params.EditMode != "" {
+				parts = append(parts, fmt.Sprintf("**Mode:** %s", params.EditMode))
+			}
+			if params.CellID != "" {
+				parts = append(parts, fmt.Sprintf("**Cell ID:** %s", params.CellID))
+			}
+			if params.CellIndex != nil {
+				parts = append(parts, fmt.Sprintf("**Cell Index:** %d", *params.CellIndex))
+			}
+			return strings.Join(parts, "\n")
+		}
+	case tools.GitDiffToolName:
+		var params tools.GitDiffParams
+		if json.Unmarshal([]byte(m.call.Input), &params) == nil {
+			var parts []string
+			if params.Ref != "" {
+				parts = append(parts, fmt.Sprintf("**Ref:** %s", params.Ref))
+			}
+			if params.Staged {
+				parts = append(parts, "**Staged:** true")
+			}
+			if len(params.Paths) > 0 {
+				parts = append(parts, fmt.Sprintf("**Paths:** %s", strings.Join(params.Paths, ", ")))
+			}
+			return strings.Join(parts, "\n")
+		}
+	case tools.GitCommitToolName:
+		var params tools.GitCommitParams
+		if json.Unmarshal([]byte(m.call.Input), &params) == nil {
+			return fmt.Sprintf("**Message:** %s", params.Message)
+		}
 	case tools.WriteToolName:
 		var params tools.WriteParams
 		if json.Unmarshal([]byte(m.call.Input), &params) == nil {
@@ -0,0 +1,90 @@
+package messages
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/tui/styles"
+)
+
+// ToggleExpandKey is the key binding for showing or hiding the output of a
+// verification.
+var ToggleExpandKey = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "toggle output"))
+
+// maxVerifyOutputLines bounds the output shown for each verify command.
+const maxVerifyOutputLines = 40
+
+// renderVerification renders the verify commands run at the end of an agent
+// turn, with their output when expanded.
+func (m *messageCmp) renderVerification(verification message.Verification) string {
+	t := styles.CurrentTheme()
+	width := m.textWidth() - 2
+
+	var status string
+	switch {
+	case !verification.Finished:
+		status = t.S().Muted.Render("Running…")
+	case verification.Passed():
+		status = t.S().Base.Foreground(t.Success).Render("Passed")
+	case verification.Repair:
+		status = t.S().Base.Foreground(t.Error).Render("Failed") + t.S().Subtle.Render(", sent back to the agent")
+	default:
+		status = t.S().Base.Foreground(t.Error).Render("Failed")
+	}
+	header := t.S().Base.Foreground(t.Blue).Render("Verification")
+	if verification.Attempt > 1 {
+		header += t.S().Subtle.Render(fmt.Sprintf(" #%d", verification.Attempt))
+	}
+	lines := []string{header + " " + status}
+
+	hasOutput := false
+	for _, result := range verification.Results {
+		var icon string
+		switch {
+		case !result.Finished:
+			icon = t.S().Muted.Render(styles.ToolPending)
+		case result.Passed():
+			icon = t.S().Base.Foreground(t.Success).Render(styles.ToolSuccess)
+		default:
+			icon = t.S().Base.Foreground(t.Error).Render(styles.ToolError)
+		}
+		line := fmt.Sprintf("%s %s", icon, t.S().Text.Render(result.Name))
+		switch {
+		case result.TimedOut:
+			line += t.S().Subtle.Render(" timed out")
+		case result.Finished:
+			line += t.S().Subtle.Render(" " + (time.Duration(result.Duration) * time.Millisecond).Round(100*time.Millisecond).String())
+		}
+		lines = append(lines, ansi.Truncate(line, width, "…"))
+		if result.Output != "" {
+			hasOutput = true
+		}
+		if m.expanded && result.Output != "" {
+			lines = append(lines, verifyOutput(result.Output, width))
+		}
+	}
+	if hasOutput && !m.expanded && m.focused {
+		lines = append(lines, t.S().Subtle.Render(fmt.Sprintf("%s to show the output", ToggleExpandKey.Help().Key)))
+	}
+	return m.style().Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func verifyOutput(output string, width int) string {
+	t := styles.CurrentTheme()
+	outputLines := strings.Split(output, "\n")
+	var lines []string
+	for i, line := range outputLines {
+		if i == maxVerifyOutputLines {
+			lines = append(lines, t.S().Subtle.Render(fmt.Sprintf("… %d more lines", len(outputLines)-i)))
+			break
+		}
+		lines = append(lines, t.S().Muted.Render(ansi.Truncate(ansi.Strip(line), width-2, "…")))
+	}
+	return t.S().Base.PaddingLeft(2).Render(strings.Join(lines, "\n"))
+}
@@ -17,10 +17,12 @@ import (
 	"github.com/charmbracelet/crush/internal/lsp"
 	"github.com/charmbracelet/crush/internal/pubsub"
 	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/shell"
 	"github.com/charmbracelet/crush/internal/tui/components/chat"
 	"github.com/charmbracelet/crush/internal/tui/components/core"
 	"github.com/charmbracelet/crush/internal/tui/components/core/layout"
 	"github.com/charmbracelet/crush/internal/tui/components/files"
+	"github.com/charmbracelet/crush/internal/tui/components/jobs"
 	"github.com/charmbracelet/crush/internal/tui/components/logo"
 	lspcomponent "github.com/charmbracelet/crush/internal/tui/components/lsp"
 	"github.com/charmbracelet/crush/internal/tui/components/mcp"
@@ -43,6 +45,7 @@ const (
 	DefaultMaxFilesShown = 10
 	DefaultMaxLSPsShown  = 8
 	DefaultMaxMCPsShown  = 8
+	DefaultMaxJobsShown  = 5
 	MinItemsPerSection   = 2 // Minimum items to show per section
 )
 
@@ -160,6 +163,9 @@ func (m *sidebarCmp) View() string {
 		// Vertical layout (default)
 		if m.session.ID != "" {
 			parts = append(parts, "", m.filesBlock())
+			if len(m.sessionJobs()) > 0 {
+				parts = append(parts, "", m.jobsBlock())
+			}
 		}
 		parts = append(parts,
 			"",
@@ -319,6 +325,10 @@ func (m *sidebarCmp) calculateAvailableHeight() int {
 
 	usedHeight += 6 // 3 sections × 2 lines each (header + empty line)
 
+	if jobs := len(m.sessionJobs()); jobs > 0 {
+		usedHeight += 2 + min(jobs, DefaultMaxJobsShown) // Jobs header, empty line and items
+	}
+
 	// Base padding
 	usedHeight += 2 // Top and bottom padding
 
@@ -476,6 +486,24 @@ func (m *sidebarCmp) filesBlock() string {
 	}, true)
 }
 
+// sessionJobs returns the background jobs owned by the current session.
+func (m *sidebarCmp) sessionJobs() []shell.BackgroundShellInfo {
+	if m.session.ID == "" {
+		return nil
+	}
+	return shell.GetBackgroundShellManager().Jobs(m.session.ID)
+}
+
+func (m *sidebarCmp) jobsBlock() string {
+	sessionJobs := m.sessionJobs()
+	return jobs.RenderJobBlock(sessionJobs, jobs.RenderOptions{
+		MaxWidth:    m.getMaxWidth(),
+		MaxItems:    min(len(sessionJobs), DefaultMaxJobsShown),
+		ShowSection: true,
+		SectionName: core.Section("Jobs", m.getMaxWidth()),
+	}, true)
+}
+
 func (m *sidebarCmp) lspBlock() string {
 	// Limit the number of LSPs shown
 	_, maxLSPs, _ := m.getDynamicLimits()
@@ -49,6 +49,7 @@ type commandArgumentsDialogCmp struct {
 
 type Argument struct {
 	Name, Title, Description string
+	Default                  string
 	Required                 bool
 }
 
@@ -66,6 +67,7 @@ func NewCommandArgumentsDialog(
 		ti.SetWidth(40)
 		ti.SetVirtualCursor(false)
 		ti.Prompt = ""
+		ti.SetValue(arg.Default)
 
 		ti.SetStyles(t.S().TextInput)
 		// Only focus the first input initially
@@ -13,6 +13,7 @@ import (
 
 	"github.com/charmbracelet/crush/internal/agent"
 	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
+	uicommands "github.com/charmbracelet/crush/internal/commands"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/csync"
 	"github.com/charmbracelet/crush/internal/pubsub"
@@ -79,9 +80,20 @@ type (
 	OpenReasoningDialogMsg struct{}
 	OpenExternalEditorMsg  struct{}
 	ToggleYoloModeMsg      struct{}
-	CompactMsg             struct {
+	OpenJobsDialogMsg      struct{}
+	OpenReviewDialogMsg    struct{}
+	OpenThemesDialogMsg    struct{}
+	// ThemeChangedMsg is sent to the pages after the theme changed, for
+	// components to style themselves again.
+	ThemeChangedMsg struct{}
+	CompactMsg      struct {
 		SessionID string
 	}
+	// StartReviewMsg starts the review of a git revision range, the
+	// uncommitted changes if empty.
+	StartReviewMsg struct {
+		Range string
+	}
 )
 
 func NewCommandDialog(sessionID string) CommandsDialog {
@@ -337,6 +349,45 @@ func (c *commandDialogCmp) defaultCommands() []Command {
 				return util.CmdHandler(SwitchModelMsg{})
 			},
 		},
+		{
+			ID:          "review",
+			Title:       "Review Changes",
+			Description: "Review the uncommitted changes or a git revision range",
+			Handler: func(cmd Command) tea.Cmd {
+				return util.CmdHandler(ShowArgumentsDialogMsg{
+					CommandID:   cmd.ID,
+					Description: "Review the changes of a git revision range",
+					Arguments: []uicommands.Argument{{
+						ID:          "RANGE",
+						Title:       "Range",
+						Description: "Revision range, as in main..HEAD (empty for the uncommitted changes)",
+					}},
+					OnSubmit: func(args map[string]string) tea.Cmd {
+						return util.CmdHandler(StartReviewMsg{Range: strings.TrimSpace(args["RANGE"])})
+					},
+				})
+			},
+		},
+		{
+			ID:          "review_findings",
+			Title:       "Review Findings",
+			Description: "Show the findings of the last review and apply their fixes",
+			Handler: func(cmd Command) tea.Cmd {
+				return util.CmdHandler(OpenReviewDialogMsg{})
+			},
+		},
+	}
+
+	// Only show background jobs command if there's an active session
+	if c.sessionID != "" {
+		commands = append(commands, Command{
+			ID:          "background_jobs",
+			Title:       "Background Jobs",
+			Description: "View, kill or restart background jobs of the current session",
+			Handler: func(cmd Command) tea.Cmd {
+				return util.CmdHandler(OpenJobsDialogMsg{})
+			},
+		})
 	}
 
 	// Only show compact command if there's an active session
@@ -439,6 +490,14 @@ func (c *commandDialogCmp) defaultCommands() []Command {
 				return util.CmdHandler(ToggleYoloModeMsg{})
 			},
 		},
+		{
+			ID:          "switch_theme",
+			Title:       "Switch Theme",
+			Description: "Switch the color theme",
+			Handler: func(cmd Command) tea.Cmd {
+				return util.CmdHandler(OpenThemesDialogMsg{})
+			},
+		},
 		{
 			ID:          "toggle_help",
 			Title:       "Toggle Help",
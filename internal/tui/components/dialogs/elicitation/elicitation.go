@@ -0,0 +1,254 @@
+// Package elicitation implements the dialog answering the requests of MCP
+// servers for input from the user.
+package elicitation
+
+import (
+	"fmt"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
+	"github.com/charmbracelet/crush/internal/tui/components/dialogs"
+	"github.com/charmbracelet/crush/internal/tui/styles"
+	"github.com/charmbracelet/crush/internal/tui/util"
+	"github.com/pkg/browser"
+)
+
+// ResponseMsg is sent when the user answered an elicitation request.
+type ResponseMsg struct {
+	ID      string
+	Action  mcp.ElicitationAction
+	Content map[string]any
+}
+
+// DialogID returns the ID of the dialog of the given request.
+func DialogID(requestID string) dialogs.DialogID {
+	return dialogs.DialogID("elicitation-" + requestID)
+}
+
+// Dialog represents the elicitation dialog.
+type Dialog interface {
+	dialogs.DialogModel
+}
+
+type dialogCmp struct {
+	wWidth, wHeight int
+	width           int
+
+	request mcp.ElicitationRequest
+	inputs  []textinput.Model
+	focused int
+	err     error
+
+	keys KeyMap
+	help help.Model
+}
+
+// New creates a dialog for the given elicitation request.
+func New(request mcp.ElicitationRequest) Dialog {
+	t := styles.CurrentTheme()
+	keys := DefaultKeyMap()
+	if len(request.Fields) == 0 {
+		keys.Next.SetEnabled(false)
+		keys.Previous.SetEnabled(false)
+		if request.URL != "" {
+			keys.Confirm.SetHelp("enter", "open")
+		} else {
+			keys.Confirm.SetHelp("enter", "accept")
+		}
+	}
+
+	inputs := make([]textinput.Model, len(request.Fields))
+	for i, field := range request.Fields {
+		ti := textinput.New()
+		ti.Placeholder = field.Hint()
+		ti.SetValue(field.Default)
+		ti.SetWidth(40)
+		ti.Prompt = ""
+		ti.SetStyles(t.S().TextInput)
+		if i == 0 {
+			ti.Focus()
+		}
+		inputs[i] = ti
+	}
+
+	return &dialogCmp{
+		width:   60,
+		request: request,
+		inputs:  inputs,
+		keys:    keys,
+		help:    help.New(),
+	}
+}
+
+// Init implements Dialog.
+func (d *dialogCmp) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements Dialog.
+func (d *dialogCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.wWidth = msg.Width
+		d.wHeight = msg.Height
+		d.width = min(90, d.wWidth)
+		for i := range d.inputs {
+			d.inputs[i].SetWidth(d.width - 6)
+		}
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.keys.Close):
+			return d, d.respond(mcp.ElicitationCancel, nil)
+		case key.Matches(msg, d.keys.Decline):
+			return d, d.respond(mcp.ElicitationDecline, nil)
+		case key.Matches(msg, d.keys.Confirm):
+			if len(d.inputs) > 0 && d.focused < len(d.inputs)-1 {
+				d.focus(d.focused + 1)
+				return d, nil
+			}
+			return d, d.submit()
+		case key.Matches(msg, d.keys.Next):
+			d.focus((d.focused + 1) % len(d.inputs))
+		case key.Matches(msg, d.keys.Previous):
+			d.focus((d.focused - 1 + len(d.inputs)) % len(d.inputs))
+		default:
+			if len(d.inputs) > 0 {
+				var cmd tea.Cmd
+				d.inputs[d.focused], cmd = d.inputs[d.focused].Update(msg)
+				return d, cmd
+			}
+		}
+	case tea.PasteMsg:
+		if len(d.inputs) > 0 {
+			var cmd tea.Cmd
+			d.inputs[d.focused], cmd = d.inputs[d.focused].Update(msg)
+			return d, cmd
+		}
+	}
+	return d, nil
+}
+
+func (d *dialogCmp) focus(i int) {
+	d.inputs[d.focused].Blur()
+	d.focused = i
+	d.inputs[d.focused].Focus()
+}
+
+// submit accepts the request, unless the values entered are invalid.
+func (d *dialogCmp) submit() tea.Cmd {
+	if d.request.URL != "" {
+		url := d.request.URL
+		return tea.Batch(
+			d.respond(mcp.ElicitationAccept, nil),
+			func() tea.Msg {
+				if err := browser.OpenURL(url); err != nil {
+					return util.ReportError(fmt.Errorf("failed to open browser: %w", err))()
+				}
+				return nil
+			},
+		)
+	}
+
+	input := make(map[string]string, len(d.inputs))
+	for i, field := range d.request.Fields {
+		input[field.Name] = d.inputs[i].Value()
+	}
+	content, err := d.request.Values(input)
+	if err != nil {
+		d.err = err
+		return nil
+	}
+	return d.respond(mcp.ElicitationAccept, content)
+}
+
+func (d *dialogCmp) respond(action mcp.ElicitationAction, content map[string]any) tea.Cmd {
+	return tea.Sequence(
+		util.CmdHandler(dialogs.CloseDialogMsg{}),
+		util.CmdHandler(ResponseMsg{
+			ID:      d.request.ID,
+			Action:  action,
+			Content: content,
+		}),
+	)
+}
+
+// View implements Dialog.
+func (d *dialogCmp) View() string {
+	t := styles.CurrentTheme()
+	baseStyle := t.S().Base
+	contentWidth := d.width - 4
+
+	title := lipgloss.NewStyle().
+		Foreground(t.Primary).
+		Bold(true).
+		Padding(0, 1).
+		Render(fmt.Sprintf("%s requests input", d.request.MCP))
+
+	elements := []string{title}
+	if d.request.Message != "" {
+		elements = append(elements, t.S().Text.
+			Padding(1, 1, 0, 1).
+			Width(contentWidth).
+			Render(d.request.Message))
+	}
+	if d.request.URL != "" {
+		elements = append(elements, t.S().Subtle.
+			Padding(1, 1, 0, 1).
+			Width(contentWidth).
+			Render(d.request.URL))
+	}
+
+	for i, input := range d.inputs {
+		field := d.request.Fields[i]
+		labelStyle := baseStyle.Padding(1, 1, 0, 1)
+		if i == d.focused {
+			labelStyle = labelStyle.Foreground(t.FgBase).Bold(true)
+		} else {
+			labelStyle = labelStyle.Foreground(t.FgMuted)
+		}
+		label := field.Label()
+		if field.Required {
+			label += "*"
+		}
+		elements = append(elements, labelStyle.Render(label+":"))
+		if field.Description != "" {
+			elements = append(elements, t.S().Subtle.
+				Padding(0, 1).
+				Width(contentWidth).
+				Render(field.Description))
+		}
+		elements = append(elements, t.S().Text.Padding(0, 1).Render(input.View()))
+	}
+
+	if d.err != nil {
+		elements = append(elements, t.S().Error.
+			Padding(1, 1, 0, 1).
+			Width(contentWidth).
+			Render(d.err.Error()))
+	}
+
+	d.help.ShowAll = false
+	elements = append(elements, "", baseStyle.Padding(0, 1).Render(d.help.View(d.keys)))
+
+	return baseStyle.Padding(1, 1, 0, 1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.BorderFocus).
+		Width(d.width).
+		Render(lipgloss.JoinVertical(lipgloss.Left, elements...))
+}
+
+// Position implements Dialog.
+func (d *dialogCmp) Position() (int, int) {
+	row := (d.wHeight / 2) - (lipgloss.Height(d.View()) / 2)
+	col := (d.wWidth / 2) - (d.width / 2)
+	return max(row, 0), max(col, 0)
+}
+
+// ID implements Dialog.
+func (d *dialogCmp) ID() dialogs.DialogID {
+	return DialogID(d.request.ID)
+}
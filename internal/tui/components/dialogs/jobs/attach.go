@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/crush/internal/shell"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+// detachKey is the byte that detaches the terminal from a job (ctrl+]).
+const detachKey = 0x1d
+
+// attachCmd implements tea.ExecCommand. It hands the terminal over to the
+// pseudo-terminal of a background job until the user detaches.
+type attachCmd struct {
+	shell  *shell.BackgroundShell
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func (c *attachCmd) SetStdin(r io.Reader)  { c.stdin = r }
+func (c *attachCmd) SetStdout(w io.Writer) { c.stdout = w }
+func (c *attachCmd) SetStderr(io.Writer)   {}
+
+func (c *attachCmd) Run() error {
+	if f, ok := c.stdin.(interface{ Fd() uintptr }); ok {
+		state, err := term.MakeRaw(f.Fd())
+		if err != nil {
+			return fmt.Errorf("could not set terminal to raw mode: %w", err)
+		}
+		defer term.Restore(f.Fd(), state) //nolint:errcheck
+	}
+	if f, ok := c.stdout.(interface{ Fd() uintptr }); ok {
+		if width, height, err := term.GetSize(f.Fd()); err == nil {
+			_ = c.shell.Resize(width, height)
+		}
+	}
+
+	_, _ = io.WriteString(c.stdout, ansi.EraseEntireScreen+ansi.CursorHomePosition)
+	_, _ = fmt.Fprintf(c.stdout, "Attached to job %s. Press ctrl+] to detach.\r\n\r\n", c.shell.ID)
+	return c.shell.Attach(c.stdin, c.stdout, detachKey)
+}
@@ -0,0 +1,306 @@
+package jobs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/charmbracelet/crush/internal/home"
+	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/charmbracelet/crush/internal/shell"
+	"github.com/charmbracelet/crush/internal/tui/components/core"
+	"github.com/charmbracelet/crush/internal/tui/components/dialogs"
+	jobcomponent "github.com/charmbracelet/crush/internal/tui/components/jobs"
+	"github.com/charmbracelet/crush/internal/tui/styles"
+	"github.com/charmbracelet/crush/internal/tui/util"
+)
+
+const (
+	JobsDialogID dialogs.DialogID = "jobs"
+
+	defaultWidth    = 100
+	maxListItems    = 8
+	refreshInterval = time.Second
+)
+
+// JobsDialog interface for the background jobs dialog.
+type JobsDialog interface {
+	dialogs.DialogModel
+}
+
+type refreshMsg struct{}
+
+type jobsDialogCmp struct {
+	wWidth    int
+	wHeight   int
+	width     int
+	sessionID string
+	jobs      []shell.BackgroundShellInfo
+	selected  int
+	keyMap    KeyMap
+	help      help.Model
+}
+
+// NewJobsDialogCmp creates a new dialog listing the background jobs of the
+// given session. If sessionID is empty, the jobs of all sessions are listed.
+func NewJobsDialogCmp(sessionID string) JobsDialog {
+	t := styles.CurrentTheme()
+	help := help.New()
+	help.Styles = t.S().Help
+	d := &jobsDialogCmp{
+		sessionID: sessionID,
+		keyMap:    DefaultKeyMap(),
+		help:      help,
+	}
+	d.refresh()
+	// Select the most recent job by default.
+	d.selected = max(0, len(d.jobs)-1)
+	return d
+}
+
+func (d *jobsDialogCmp) Init() tea.Cmd {
+	return d.tick()
+}
+
+func (d *jobsDialogCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.wWidth = msg.Width
+		d.wHeight = msg.Height
+		d.width = min(defaultWidth, d.wWidth-8)
+	case refreshMsg:
+		d.refresh()
+		return d, d.tick()
+	case pubsub.Event[shell.BackgroundShellInfo]:
+		d.refresh()
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.keyMap.Next):
+			if len(d.jobs) > 0 {
+				d.selected = (d.selected + 1) % len(d.jobs)
+			}
+		case key.Matches(msg, d.keyMap.Previous):
+			if len(d.jobs) > 0 {
+				d.selected = (d.selected - 1 + len(d.jobs)) % len(d.jobs)
+			}
+		case key.Matches(msg, d.keyMap.Kill):
+			job, ok := d.selectedJob()
+			if !ok {
+				return d, nil
+			}
+			if err := shell.GetBackgroundShellManager().Kill(job.ID); err != nil {
+				return d, util.ReportError(err)
+			}
+			d.refresh()
+			return d, util.ReportInfo(fmt.Sprintf("Job %s terminated", job.ID))
+		case key.Matches(msg, d.keyMap.Restart):
+			job, ok := d.selectedJob()
+			if !ok {
+				return d, nil
+			}
+			restarted, err := shell.GetBackgroundShellManager().Restart(job.ID)
+			if err != nil {
+				return d, util.ReportError(err)
+			}
+			d.refresh()
+			d.selectJob(restarted.ID)
+			return d, util.ReportInfo(fmt.Sprintf("Job %s restarted as %s", job.ID, restarted.ID))
+		case key.Matches(msg, d.keyMap.Attach):
+			return d, d.attach()
+		case key.Matches(msg, d.keyMap.Close):
+			return d, util.CmdHandler(dialogs.CloseDialogMsg{})
+		}
+	}
+	return d, nil
+}
+
+// attach hands the terminal over to the selected job, if it runs in a
+// pseudo-terminal.
+func (d *jobsDialogCmp) attach() tea.Cmd {
+	job, ok := d.selectedJob()
+	if !ok {
+		return nil
+	}
+	bgShell, ok := shell.GetBackgroundShellManager().Get(job.ID)
+	if !ok {
+		return nil
+	}
+	if !bgShell.IsPTY() {
+		return util.ReportWarn(fmt.Sprintf("Job %s is not interactive", job.ID))
+	}
+	if bgShell.IsDone() {
+		return util.ReportWarn(fmt.Sprintf("Job %s has already finished", job.ID))
+	}
+	return tea.Exec(&attachCmd{shell: bgShell}, func(err error) tea.Msg {
+		if err != nil {
+			return util.InfoMsg{
+				Type: util.InfoTypeError,
+				Msg:  err.Error(),
+			}
+		}
+		return util.InfoMsg{
+			Type: util.InfoTypeInfo,
+			Msg:  fmt.Sprintf("Detached from job %s", job.ID),
+		}
+	})
+}
+
+func (d *jobsDialogCmp) tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(time.Time) tea.Msg {
+		return refreshMsg{}
+	})
+}
+
+// refresh reloads the job list, keeping the current selection when possible.
+func (d *jobsDialogCmp) refresh() {
+	selectedID := ""
+	if job, ok := d.selectedJob(); ok {
+		selectedID = job.ID
+	}
+	d.jobs = shell.GetBackgroundShellManager().Jobs(d.sessionID)
+	d.selectJob(selectedID)
+	d.selected = min(d.selected, max(0, len(d.jobs)-1))
+}
+
+func (d *jobsDialogCmp) selectJob(id string) {
+	for i, job := range d.jobs {
+		if job.ID == id {
+			d.selected = i
+			return
+		}
+	}
+}
+
+func (d *jobsDialogCmp) selectedJob() (shell.BackgroundShellInfo, bool) {
+	if d.selected < 0 || d.selected >= len(d.jobs) {
+		return shell.BackgroundShellInfo{}, false
+	}
+	return d.jobs[d.selected], true
+}
+
+func (d *jobsDialogCmp) View() string {
+	t := styles.CurrentTheme()
+	parts := []string{
+		t.S().Base.Padding(0, 1, 1, 1).Render(core.Title("Background Jobs", d.width-4)),
+		d.listView(),
+	}
+	if job, ok := d.selectedJob(); ok {
+		parts = append(parts, "", d.detailsView(job))
+	}
+	parts = append(parts,
+		"",
+		t.S().Base.Width(d.width-2).PaddingLeft(1).AlignHorizontal(lipgloss.Left).Render(d.help.View(d.keyMap)),
+	)
+	return d.style().Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
+
+func (d *jobsDialogCmp) listView() string {
+	t := styles.CurrentTheme()
+	contentWidth := d.width - 4
+	if len(d.jobs) == 0 {
+		return t.S().Base.PaddingLeft(1).Foreground(t.FgSubtle).Render("No background jobs")
+	}
+
+	// Keep the selected job visible.
+	start := max(0, d.selected-maxListItems+1)
+	end := min(len(d.jobs), start+maxListItems)
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		job := d.jobs[i]
+		icon, status := jobcomponent.Status(job)
+		line := core.Status(core.StatusOpts{
+			Icon:         icon.String(),
+			Title:        ansi.Truncate(fmt.Sprintf("%s %s", job.ID, jobcomponent.Title(job)), contentWidth/2, "…"),
+			Description:  status,
+			ExtraContent: t.S().Subtle.Render(jobcomponent.FormatRuntime(job.Runtime())),
+		}, contentWidth-2)
+		style := t.S().Base.Width(contentWidth).PaddingLeft(1)
+		if i == d.selected {
+			style = style.Background(t.BgSubtle)
+		}
+		lines = append(lines, style.Render(line))
+	}
+	return t.S().Base.PaddingLeft(1).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func (d *jobsDialogCmp) detailsView(job shell.BackgroundShellInfo) string {
+	t := styles.CurrentTheme()
+	contentWidth := d.width - 4
+
+	field := func(name, value string) string {
+		label := t.S().Muted.Render(fmt.Sprintf("%-12s", name))
+		return label + t.S().Text.Render(ansi.Truncate(value, contentWidth-12, "…"))
+	}
+
+	_, status := jobcomponent.Status(job)
+	lines := []string{
+		core.Section("Details", contentWidth),
+		field("Command", strings.ReplaceAll(job.Command, "\n", " ")),
+	}
+	if job.Description != "" {
+		lines = append(lines, field("Description", job.Description))
+	}
+	lines = append(lines,
+		field("Directory", home.Short(job.WorkingDir)),
+		field("Status", status),
+		field("Interactive", interactive(job)),
+		field("Runtime", jobcomponent.FormatRuntime(job.Runtime())),
+		"",
+		core.Section("Output", contentWidth),
+	)
+
+	tail := ""
+	if bgShell, ok := shell.GetBackgroundShellManager().Get(job.ID); ok {
+		tail = bgShell.Tail(d.outputHeight())
+	}
+	if tail == "" {
+		lines = append(lines, t.S().Subtle.Render("No output yet"))
+	} else {
+		for line := range strings.SplitSeq(tail, "\n") {
+			lines = append(lines, t.S().Muted.Render(ansi.Truncate(ansi.Strip(line), contentWidth, "…")))
+		}
+	}
+	return t.S().Base.PaddingLeft(1).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func interactive(job shell.BackgroundShellInfo) string {
+	if job.PTY {
+		return "yes"
+	}
+	return "no"
+}
+
+// outputHeight returns how many lines of output to show for the selected job.
+func (d *jobsDialogCmp) outputHeight() int {
+	listHeight := min(len(d.jobs), maxListItems)
+	// The dialog sits a quarter down the screen; leave room for the title,
+	// details, section headers, help and borders.
+	return max(3, d.wHeight*3/4-listHeight-17)
+}
+
+func (d *jobsDialogCmp) style() lipgloss.Style {
+	t := styles.CurrentTheme()
+	return t.S().Base.
+		Width(d.width).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.BorderFocus)
+}
+
+func (d *jobsDialogCmp) Position() (int, int) {
+	row := d.wHeight/4 - 2 // just a bit above the center
+	col := d.wWidth / 2
+	col -= d.width / 2
+	return max(0, row), col
+}
+
+// ID implements JobsDialog.
+func (d *jobsDialogCmp) ID() dialogs.DialogID {
+	return JobsDialogID
+}
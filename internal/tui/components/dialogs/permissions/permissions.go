@@ -92,7 +92,11 @@ func (p *permissionDialogCmp) Init() tea.Cmd {
 }
 
 func (p *permissionDialogCmp) supportsDiffView() bool {
-	return p.permission.ToolName == tools.EditToolName || p.permission.ToolName == tools.WriteToolName || p.permission.ToolName == tools.MultiEditToolName
+	switch p.permission.ToolName {
+	case tools.EditToolName, tools.WriteToolName, tools.MultiEditToolName, tools.NotebookEditToolName:
+		return true
+	}
+	return false
 }
 
 func (p *permissionDialogCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
@@ -381,6 +385,29 @@ func (p *permissionDialogCmp) renderHeader() string {
 			),
 			baseStyle.Render(strings.Repeat(" ", p.width)),
 		)
+	case tools.NotebookEditToolName:
+		params := p.permission.Params.(tools.NotebookEditPermissionsParams)
+		fileKey := t.S().Muted.Render("Notebook")
+		filePath := t.S().Text.
+			Width(p.width - lipgloss.Width(fileKey)).
+			Render(fmt.Sprintf(" %s", fsext.PrettyPath(params.NotebookPath)))
+		cellKey := t.S().Muted.Render("Cell")
+		cellValue := t.S().Text.
+			Width(p.width - lipgloss.Width(cellKey)).
+			Render(fmt.Sprintf(" %s (%s)", notebookCellName(params), params.EditMode))
+		headerParts = append(headerParts,
+			lipgloss.JoinHorizontal(
+				lipgloss.Left,
+				fileKey,
+				filePath,
+			),
+			lipgloss.JoinHorizontal(
+				lipgloss.Left,
+				cellKey,
+				cellValue,
+			),
+			baseStyle.Render(strings.Repeat(" ", p.width)),
+		)
 	case tools.FetchToolName:
 		headerParts = append(headerParts,
 			baseStyle.Render(strings.Repeat(" ", p.width)),
@@ -443,6 +470,10 @@ func (p *permissionDialogCmp) getOrGenerateContent() string {
 		content = p.generateWriteContent()
 	case tools.MultiEditToolName:
 		content = p.generateMultiEditContent()
+	case tools.NotebookEditToolName:
+		content = p.generateNotebookEditContent()
+	case tools.GitCommitToolName:
+		content = p.generateGitCommitContent()
 	case tools.FetchToolName:
 		content = p.generateFetchContent()
 	case tools.AgenticFetchToolName:
@@ -505,6 +536,38 @@ func (p *permissionDialogCmp) generateBashContent() string {
 	return ""
 }
 
+func (p *permissionDialogCmp) generateGitCommitContent() string {
+	t := styles.CurrentTheme()
+	baseStyle := t.S().Base.Background(t.BgSubtle)
+	if pr, ok := p.permission.Params.(tools.GitCommitPermissionsParams); ok {
+		content := strings.TrimSpace(pr.Message)
+		switch {
+		case len(pr.Files) > 0:
+			content += "\n\nStaging: " + strings.Join(pr.Files, ", ")
+		case pr.All:
+			content += "\n\nStaging: all changes of tracked files"
+		}
+		lines := strings.Split(content, "\n")
+
+		width := p.width - 4
+		var out []string
+		for _, ln := range lines {
+			out = append(out, t.S().Muted.
+				Width(width).
+				Padding(0, 3).
+				Foreground(t.FgBase).
+				Background(t.BgSubtle).
+				Render(ln))
+		}
+
+		return baseStyle.
+			Width(p.contentViewPort.Width()).
+			Padding(1, 0).
+			Render(strings.Join(out, "\n"))
+	}
+	return ""
+}
+
 func (p *permissionDialogCmp) generateEditContent() string {
 	if pr, ok := p.permission.Params.(tools.EditPermissionsParams); ok {
 		formatter := core.DiffFormatter().
@@ -588,6 +651,37 @@ func (p *permissionDialogCmp) generateMultiEditContent() string {
 	return ""
 }
 
+func (p *permissionDialogCmp) generateNotebookEditContent() string {
+	if pr, ok := p.permission.Params.(tools.NotebookEditPermissionsParams); ok {
+		name := fmt.Sprintf("%s (%s)", fsext.PrettyPath(pr.NotebookPath), notebookCellName(pr))
+		formatter := core.DiffFormatter().
+			Before(name, pr.OldContent).
+			After(name, pr.NewContent).
+			Height(p.contentViewPort.Height()).
+			Width(p.contentViewPort.Width()).
+			XOffset(p.diffXOffset).
+			YOffset(p.diffYOffset)
+		if p.useDiffSplitMode() {
+			formatter = formatter.Split()
+		} else {
+			formatter = formatter.Unified()
+		}
+
+		diff := formatter.String()
+		return diff
+	}
+	return ""
+}
+
+// notebookCellName names the cell of a notebook edit by index and, if it
+// has one, ID.
+func notebookCellName(params tools.NotebookEditPermissionsParams) string {
+	if params.CellID != "" {
+		return fmt.Sprintf("cell %d, id %s", params.CellIndex, params.CellID)
+	}
+	return fmt.Sprintf("cell %d", params.CellIndex)
+}
+
 func (p *permissionDialogCmp) generateFetchContent() string {
 	t := styles.CurrentTheme()
 	baseStyle := t.S().Base.Background(t.BgSubtle)
@@ -823,6 +917,12 @@ func (p *permissionDialogCmp) SetSize() tea.Cmd {
 	case tools.MultiEditToolName:
 		p.width = int(float64(p.wWidth) * 0.8)
 		p.height = int(float64(p.wHeight) * 0.8)
+	case tools.NotebookEditToolName:
+		p.width = int(float64(p.wWidth) * 0.8)
+		p.height = int(float64(p.wHeight) * 0.8)
+	case tools.GitCommitToolName:
+		p.width = int(float64(p.wWidth) * 0.8)
+		p.height = int(float64(p.wHeight) * 0.4)
 	case tools.FetchToolName:
 		p.width = int(float64(p.wWidth) * 0.8)
 		p.height = int(float64(p.wHeight) * 0.3)
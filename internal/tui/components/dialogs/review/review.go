@@ -0,0 +1,286 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	codereview "github.com/charmbracelet/crush/internal/review"
+	"github.com/charmbracelet/crush/internal/tui/components/core"
+	"github.com/charmbracelet/crush/internal/tui/components/dialogs"
+	"github.com/charmbracelet/crush/internal/tui/styles"
+	"github.com/charmbracelet/crush/internal/tui/util"
+)
+
+const (
+	ReviewDialogID dialogs.DialogID = "review"
+
+	defaultWidth = 110
+	maxListItems = 8
+)
+
+// ReviewDialog interface for the review findings dialog.
+type ReviewDialog interface {
+	dialogs.DialogModel
+}
+
+// ApplyFunc applies the suggested fix of a finding.
+type ApplyFunc func(ctx context.Context, finding codereview.Finding) error
+
+// Review is the outcome of a review in the TUI, with the findings whose fix
+// was applied.
+type Review struct {
+	SessionID string
+	Report    *codereview.Report
+	Applied   map[int]bool
+}
+
+type appliedMsg struct {
+	index int
+	err   error
+}
+
+type reviewDialogCmp struct {
+	wWidth   int
+	wHeight  int
+	width    int
+	review   *Review
+	apply    ApplyFunc
+	applying bool
+	selected int
+	keyMap   KeyMap
+	help     help.Model
+}
+
+// NewReviewDialogCmp creates a new dialog listing the findings of a review,
+// and applying their suggested fixes one by one.
+func NewReviewDialogCmp(review *Review, apply ApplyFunc) ReviewDialog {
+	t := styles.CurrentTheme()
+	help := help.New()
+	help.Styles = t.S().Help
+	if review.Applied == nil {
+		review.Applied = make(map[int]bool)
+	}
+	return &reviewDialogCmp{
+		review: review,
+		apply:  apply,
+		keyMap: DefaultKeyMap(),
+		help:   help,
+	}
+}
+
+func (d *reviewDialogCmp) Init() tea.Cmd {
+	return nil
+}
+
+func (d *reviewDialogCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	findings := d.review.Report.Findings
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.wWidth = msg.Width
+		d.wHeight = msg.Height
+		d.width = min(defaultWidth, d.wWidth-8)
+	case appliedMsg:
+		d.applying = false
+		if msg.err != nil {
+			return d, util.ReportError(msg.err)
+		}
+		d.review.Applied[msg.index] = true
+		finding := findings[msg.index]
+		// Move on to the next finding.
+		if d.selected == msg.index && d.selected < len(findings)-1 {
+			d.selected++
+		}
+		return d, util.ReportInfo(fmt.Sprintf("Applied fix to %s", finding.Location()))
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.keyMap.Next):
+			if len(findings) > 0 {
+				d.selected = (d.selected + 1) % len(findings)
+			}
+		case key.Matches(msg, d.keyMap.Previous):
+			if len(findings) > 0 {
+				d.selected = (d.selected - 1 + len(findings)) % len(findings)
+			}
+		case key.Matches(msg, d.keyMap.Apply):
+			return d, d.applySelected()
+		case key.Matches(msg, d.keyMap.Close):
+			return d, util.CmdHandler(dialogs.CloseDialogMsg{})
+		}
+	}
+	return d, nil
+}
+
+// applySelected applies the fix of the selected finding. The edit asks for
+// permission as the edits of the agent do.
+func (d *reviewDialogCmp) applySelected() tea.Cmd {
+	finding, ok := d.selectedFinding()
+	if !ok || d.applying {
+		return nil
+	}
+	switch {
+	case finding.Suggestion == nil:
+		return util.ReportWarn("This finding has no suggested fix")
+	case d.review.Applied[d.selected]:
+		return util.ReportWarn("This fix was already applied")
+	}
+	d.applying = true
+	index := d.selected
+	return func() tea.Msg {
+		return appliedMsg{
+			index: index,
+			err:   d.apply(context.Background(), finding),
+		}
+	}
+}
+
+func (d *reviewDialogCmp) selectedFinding() (codereview.Finding, bool) {
+	findings := d.review.Report.Findings
+	if d.selected < 0 || d.selected >= len(findings) {
+		return codereview.Finding{}, false
+	}
+	return findings[d.selected], true
+}
+
+func (d *reviewDialogCmp) View() string {
+	t := styles.CurrentTheme()
+	title := fmt.Sprintf("Review of %s", codereview.RangeTitle(d.review.Report.Range))
+	parts := []string{
+		t.S().Base.Padding(0, 1, 1, 1).Render(core.Title(title, d.width-4)),
+		d.listView(),
+	}
+	if finding, ok := d.selectedFinding(); ok {
+		parts = append(parts, "", d.detailsView(finding))
+	}
+	parts = append(parts,
+		"",
+		t.S().Base.Width(d.width-2).PaddingLeft(1).AlignHorizontal(lipgloss.Left).Render(d.help.View(d.keyMap)),
+	)
+	return d.style().Render(lipgloss.JoinVertical(lipgloss.Left, parts...))
+}
+
+func (d *reviewDialogCmp) listView() string {
+	t := styles.CurrentTheme()
+	contentWidth := d.width - 4
+	findings := d.review.Report.Findings
+	if len(findings) == 0 {
+		return t.S().Base.PaddingLeft(1).Foreground(t.FgSubtle).Render("No findings")
+	}
+
+	// Keep the selected finding visible.
+	start := max(0, d.selected-maxListItems+1)
+	end := min(len(findings), start+maxListItems)
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		finding := findings[i]
+		extra := ""
+		switch {
+		case d.review.Applied[i]:
+			extra = t.S().Base.Foreground(t.Success).Render(styles.CheckIcon + " applied")
+		case finding.Suggestion != nil:
+			extra = t.S().Subtle.Render("fix available")
+		}
+		line := core.Status(core.StatusOpts{
+			Icon:         severityIcon(finding.Severity),
+			Title:        ansi.Truncate(finding.Location(), contentWidth/3, "…"),
+			Description:  firstLine(finding.Message),
+			ExtraContent: extra,
+		}, contentWidth-2)
+		style := t.S().Base.Width(contentWidth).PaddingLeft(1)
+		if i == d.selected {
+			style = style.Background(t.BgSubtle)
+		}
+		lines = append(lines, style.Render(line))
+	}
+	return t.S().Base.PaddingLeft(1).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func (d *reviewDialogCmp) detailsView(finding codereview.Finding) string {
+	t := styles.CurrentTheme()
+	contentWidth := d.width - 4
+
+	lines := []string{
+		core.Section(fmt.Sprintf("%s %s", finding.Severity, finding.Location()), contentWidth),
+		t.S().Text.Width(contentWidth).Render(strings.TrimSpace(finding.Message)),
+	}
+	if finding.Suggestion == nil || finding.Suggestion.Patch == "" {
+		return t.S().Base.PaddingLeft(1).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	}
+
+	lines = append(lines, "", core.Section("Suggested fix", contentWidth))
+	patch := strings.Split(strings.TrimSuffix(finding.Suggestion.Patch, "\n"), "\n")
+	height := d.patchHeight()
+	for i, line := range patch {
+		if i == height {
+			lines = append(lines, t.S().Subtle.Render(fmt.Sprintf("… %d more lines", len(patch)-i)))
+			break
+		}
+		line = ansi.Truncate(line, contentWidth, "…")
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "@@"):
+			lines = append(lines, t.S().Muted.Render(line))
+		case strings.HasPrefix(line, "+"):
+			lines = append(lines, t.S().Base.Foreground(t.Success).Render(line))
+		case strings.HasPrefix(line, "-"):
+			lines = append(lines, t.S().Base.Foreground(t.Error).Render(line))
+		default:
+			lines = append(lines, t.S().Text.Render(line))
+		}
+	}
+	return t.S().Base.PaddingLeft(1).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// patchHeight returns how many lines of the suggested fix to show.
+func (d *reviewDialogCmp) patchHeight() int {
+	listHeight := min(len(d.review.Report.Findings), maxListItems)
+	// The dialog sits a quarter down the screen; leave room for the title,
+	// message, section headers, help and borders.
+	return max(3, d.wHeight*3/4-listHeight-16)
+}
+
+func severityIcon(severity codereview.Severity) string {
+	t := styles.CurrentTheme()
+	switch severity {
+	case codereview.SeverityError:
+		return t.S().Base.Foreground(t.Error).Render(styles.ErrorIcon)
+	case codereview.SeverityWarning:
+		return t.S().Base.Foreground(t.Warning).Render(styles.WarningIcon)
+	default:
+		return t.S().Base.Foreground(t.Info).Render(styles.InfoIcon)
+	}
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func (d *reviewDialogCmp) style() lipgloss.Style {
+	t := styles.CurrentTheme()
+	return t.S().Base.
+		Width(d.width).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.BorderFocus)
+}
+
+func (d *reviewDialogCmp) Position() (int, int) {
+	row := d.wHeight/4 - 2 // just a bit above the center
+	col := d.wWidth / 2
+	col -= d.width / 2
+	return max(0, row), col
+}
+
+// ID implements ReviewDialog.
+func (d *reviewDialogCmp) ID() dialogs.DialogID {
+	return ReviewDialogID
+}
@@ -0,0 +1,222 @@
+package themes
+
+import (
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/charmbracelet/crush/internal/tui/components/core"
+	"github.com/charmbracelet/crush/internal/tui/components/dialogs"
+	"github.com/charmbracelet/crush/internal/tui/exp/list"
+	"github.com/charmbracelet/crush/internal/tui/styles"
+	"github.com/charmbracelet/crush/internal/tui/util"
+)
+
+const (
+	ThemesDialogID dialogs.DialogID = "themes"
+
+	defaultWidth int = 50
+)
+
+type listModel = list.FilterableList[list.CompletionItem[string]]
+
+type ThemesDialog interface {
+	dialogs.DialogModel
+}
+
+type themesDialogCmp struct {
+	width   int
+	wWidth  int // Width of the terminal window
+	wHeight int // Height of the terminal window
+
+	themeList listModel
+	keyMap    ThemesDialogKeyMap
+	help      help.Model
+}
+
+// ThemeSelectedMsg is sent when a theme is selected.
+type ThemeSelectedMsg struct {
+	Name string
+}
+
+type ThemesDialogKeyMap struct {
+	Next     key.Binding
+	Previous key.Binding
+	Select   key.Binding
+	Close    key.Binding
+}
+
+func DefaultThemesDialogKeyMap() ThemesDialogKeyMap {
+	return ThemesDialogKeyMap{
+		Next: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓/ctrl+n", "next"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑/ctrl+p", "previous"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "ctrl+c"),
+			key.WithHelp("esc/ctrl+c", "close"),
+		),
+	}
+}
+
+func (k ThemesDialogKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Select, k.Close}
+}
+
+func (k ThemesDialogKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Next, k.Previous},
+		{k.Select, k.Close},
+	}
+}
+
+func NewThemesDialog() ThemesDialog {
+	keyMap := DefaultThemesDialogKeyMap()
+	listKeyMap := list.DefaultKeyMap()
+	listKeyMap.Down.SetEnabled(false)
+	listKeyMap.Up.SetEnabled(false)
+	listKeyMap.DownOneItem = keyMap.Next
+	listKeyMap.UpOneItem = keyMap.Previous
+
+	t := styles.CurrentTheme()
+	inputStyle := t.S().Base.PaddingLeft(1).PaddingBottom(1)
+	themeList := list.NewFilterableList(
+		[]list.CompletionItem[string]{},
+		list.WithFilterInputStyle(inputStyle),
+		list.WithFilterListOptions(
+			list.WithKeyMap(listKeyMap),
+			list.WithWrapNavigation(),
+			list.WithResizeByList(),
+		),
+	)
+	help := help.New()
+	help.Styles = t.S().Help
+
+	return &themesDialogCmp{
+		themeList: themeList,
+		width:     defaultWidth,
+		keyMap:    keyMap,
+		help:      help,
+	}
+}
+
+func (d *themesDialogCmp) Init() tea.Cmd {
+	manager := styles.DefaultManager()
+	current := manager.Current().Name
+
+	items := []list.CompletionItem[string]{}
+	for _, name := range manager.List() {
+		shortcut := "dark"
+		if theme, ok := manager.Get(name); ok && !theme.IsDark {
+			shortcut = "light"
+		}
+		if name == current {
+			shortcut = "current"
+		}
+		items = append(items, list.NewCompletionItem(
+			name,
+			name,
+			list.WithCompletionID(name),
+			list.WithCompletionShortcut(shortcut),
+		))
+	}
+	return tea.Sequence(d.themeList.SetItems(items), d.themeList.SetSelected(current))
+}
+
+func (d *themesDialogCmp) Update(msg tea.Msg) (util.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.wWidth = msg.Width
+		d.wHeight = msg.Height
+		return d, d.themeList.SetSize(d.listWidth(), d.listHeight())
+	case tea.KeyPressMsg:
+		switch {
+		case key.Matches(msg, d.keyMap.Select):
+			selectedItem := d.themeList.SelectedItem()
+			if selectedItem == nil {
+				return d, nil // No item selected, do nothing
+			}
+			name := (*selectedItem).Value()
+			return d, tea.Sequence(
+				util.CmdHandler(dialogs.CloseDialogMsg{}),
+				util.CmdHandler(ThemeSelectedMsg{Name: name}),
+			)
+		case key.Matches(msg, d.keyMap.Close):
+			return d, util.CmdHandler(dialogs.CloseDialogMsg{})
+		default:
+			u, cmd := d.themeList.Update(msg)
+			d.themeList = u.(listModel)
+			return d, cmd
+		}
+	}
+	return d, nil
+}
+
+func (d *themesDialogCmp) View() string {
+	t := styles.CurrentTheme()
+	header := t.S().Base.Padding(0, 1, 1, 1).Render(core.Title("Switch Theme", d.width-4))
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		header,
+		d.themeList.View(),
+		"",
+		t.S().Base.Width(d.width-2).PaddingLeft(1).AlignHorizontal(lipgloss.Left).Render(d.help.View(d.keyMap)),
+	)
+	return d.style().Render(content)
+}
+
+func (d *themesDialogCmp) Cursor() *tea.Cursor {
+	if cursor, ok := d.themeList.(util.Cursor); ok {
+		cursor := cursor.Cursor()
+		if cursor != nil {
+			cursor = d.moveCursor(cursor)
+		}
+		return cursor
+	}
+	return nil
+}
+
+func (d *themesDialogCmp) listWidth() int {
+	return d.width - 2
+}
+
+func (d *themesDialogCmp) listHeight() int {
+	listHeight := len(d.themeList.Items()) + 2 + 4 // height based on items + 2 for the input + 4 for the sections
+	return min(listHeight, d.wHeight/2)
+}
+
+func (d *themesDialogCmp) moveCursor(cursor *tea.Cursor) *tea.Cursor {
+	row, col := d.Position()
+	offset := row + 3
+	cursor.Y += offset
+	cursor.X = cursor.X + col + 2
+	return cursor
+}
+
+func (d *themesDialogCmp) style() lipgloss.Style {
+	t := styles.CurrentTheme()
+	return t.S().Base.
+		Width(d.width).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.BorderFocus)
+}
+
+func (d *themesDialogCmp) Position() (int, int) {
+	row := d.wHeight/4 - 2 // just a bit above the center
+	col := d.wWidth / 2
+	col -= d.width / 2
+	return row, col
+}
+
+func (d *themesDialogCmp) ID() dialogs.DialogID {
+	return ThemesDialogID
+}
@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/charmbracelet/crush/internal/shell"
+	"github.com/charmbracelet/crush/internal/tui/components/core"
+	"github.com/charmbracelet/crush/internal/tui/styles"
+)
+
+// RenderOptions contains options for rendering background job lists.
+type RenderOptions struct {
+	MaxWidth    int
+	MaxItems    int
+	ShowSection bool
+	SectionName string
+}
+
+// RenderJobList renders a list of background job status items with the given
+// options.
+func RenderJobList(jobs []shell.BackgroundShellInfo, opts RenderOptions) []string {
+	t := styles.CurrentTheme()
+	jobList := []string{}
+
+	if opts.ShowSection {
+		sectionName := opts.SectionName
+		if sectionName == "" {
+			sectionName = "Jobs"
+		}
+		section := t.S().Subtle.Render(sectionName)
+		jobList = append(jobList, section, "")
+	}
+
+	if len(jobs) == 0 {
+		jobList = append(jobList, t.S().Base.Foreground(t.Border).Render("None"))
+		return jobList
+	}
+
+	// Determine how many items to show
+	maxItems := len(jobs)
+	if opts.MaxItems > 0 {
+		maxItems = min(opts.MaxItems, len(jobs))
+	}
+
+	// Show the most recent jobs first.
+	for i := len(jobs) - 1; i >= len(jobs)-maxItems; i-- {
+		job := jobs[i]
+		icon, description := Status(job)
+		jobList = append(jobList,
+			core.Status(
+				core.StatusOpts{
+					Icon:         icon.String(),
+					Title:        ansi.Truncate(Title(job), opts.MaxWidth/2, "…"),
+					Description:  t.S().Subtle.Render(description),
+					ExtraContent: t.S().Subtle.Render(FormatRuntime(job.Runtime())),
+				},
+				opts.MaxWidth,
+			),
+		)
+	}
+
+	return jobList
+}
+
+// RenderJobBlock renders a complete background job block with optional
+// truncation indicator.
+func RenderJobBlock(jobs []shell.BackgroundShellInfo, opts RenderOptions, showTruncationIndicator bool) string {
+	t := styles.CurrentTheme()
+	jobList := RenderJobList(jobs, opts)
+
+	// Add truncation indicator if needed
+	if showTruncationIndicator && opts.MaxItems > 0 && len(jobs) > opts.MaxItems {
+		remaining := len(jobs) - opts.MaxItems
+		if remaining == 1 {
+			jobList = append(jobList, t.S().Base.Foreground(t.FgMuted).Render("…"))
+		} else {
+			jobList = append(jobList,
+				t.S().Base.Foreground(t.FgSubtle).Render(fmt.Sprintf("…and %d more", remaining)),
+			)
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, jobList...)
+	if opts.MaxWidth > 0 {
+		return lipgloss.NewStyle().Width(opts.MaxWidth).Render(content)
+	}
+	return content
+}
+
+// Title returns the display title of a job, preferring its description over
+// the raw command.
+func Title(job shell.BackgroundShellInfo) string {
+	if job.Description != "" {
+		return job.Description
+	}
+	return job.Command
+}
+
+// Status returns the icon and short status text of a job.
+func Status(job shell.BackgroundShellInfo) (lipgloss.Style, string) {
+	t := styles.CurrentTheme()
+	switch {
+	case !job.Done:
+		return t.ItemBusyIcon, "running"
+	case job.ExitCode != 0:
+		return t.ItemErrorIcon, fmt.Sprintf("exit %d", job.ExitCode)
+	default:
+		return t.ItemOnlineIcon, "done"
+	}
+}
+
+// FormatRuntime formats a job runtime in a compact, human-readable form.
+func FormatRuntime(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
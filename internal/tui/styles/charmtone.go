@@ -2,82 +2,99 @@ package styles
 
 import (
 	"charm.land/lipgloss/v2"
-	"github.com/charmbracelet/x/exp/charmtone"
+	"github.com/charmbracelet/crush/internal/theme"
 )
 
 func NewCharmtoneTheme() *Theme {
+	return NewTheme(theme.Charmtone())
+}
+
+// NewTheme returns the TUI theme with the colors of the given theme.
+func NewTheme(c *theme.Theme) *Theme {
 	t := &Theme{
-		Name:   "charmtone",
-		IsDark: true,
+		Name:   c.Name,
+		IsDark: c.IsDark,
 
-		Primary:   charmtone.Charple,
-		Secondary: charmtone.Dolly,
-		Tertiary:  charmtone.Bok,
-		Accent:    charmtone.Zest,
+		Primary:   c.Primary,
+		Secondary: c.Secondary,
+		Tertiary:  c.Tertiary,
+		Accent:    c.Accent,
 
 		// Backgrounds
-		BgBase:        charmtone.Pepper,
-		BgBaseLighter: charmtone.BBQ,
-		BgSubtle:      charmtone.Charcoal,
-		BgOverlay:     charmtone.Iron,
+		BgBase:        c.BgBase,
+		BgBaseLighter: c.BgBaseLighter,
+		BgSubtle:      c.BgSubtle,
+		BgOverlay:     c.BgOverlay,
 
 		// Foregrounds
-		FgBase:      charmtone.Ash,
-		FgMuted:     charmtone.Squid,
-		FgHalfMuted: charmtone.Smoke,
-		FgSubtle:    charmtone.Oyster,
-		FgSelected:  charmtone.Salt,
+		FgBase:      c.FgBase,
+		FgMuted:     c.FgMuted,
+		FgHalfMuted: c.FgHalfMuted,
+		FgSubtle:    c.FgSubtle,
+		FgSelected:  c.FgSelected,
 
 		// Borders
-		Border:      charmtone.Charcoal,
-		BorderFocus: charmtone.Charple,
+		Border:      c.Border,
+		BorderFocus: c.BorderFocus,
 
 		// Status
-		Success: charmtone.Guac,
-		Error:   charmtone.Sriracha,
-		Warning: charmtone.Zest,
-		Info:    charmtone.Malibu,
+		Success: c.Success,
+		Error:   c.Error,
+		Warning: c.Warning,
+		Info:    c.Info,
 
 		// Colors
-		White: charmtone.Butter,
+		White: c.White,
+
+		BlueLight: c.BlueLight,
+		BlueDark:  c.BlueDark,
+		Blue:      c.Blue,
+
+		Yellow: c.Yellow,
+		Citron: c.Citron,
+
+		Green:      c.Green,
+		GreenDark:  c.GreenDark,
+		GreenLight: c.GreenLight,
 
-		BlueLight: charmtone.Sardine,
-		BlueDark:  charmtone.Damson,
-		Blue:      charmtone.Malibu,
+		Red:      c.Red,
+		RedDark:  c.RedDark,
+		RedLight: c.RedLight,
+		Cherry:   c.Cherry,
 
-		Yellow: charmtone.Mustard,
-		Citron: charmtone.Citron,
+		Link:  c.Link,
+		Image: c.Image,
 
-		Green:      charmtone.Julep,
-		GreenDark:  charmtone.Guac,
-		GreenLight: charmtone.Bok,
+		DiffInsert:          c.DiffInsert,
+		DiffInsertBg:        c.DiffInsertBg,
+		DiffInsertLineNumBg: c.DiffInsertLineNumBg,
+		DiffDelete:          c.DiffDelete,
+		DiffDeleteBg:        c.DiffDeleteBg,
+		DiffDeleteLineNumBg: c.DiffDeleteLineNumBg,
 
-		Red:      charmtone.Coral,
-		RedDark:  charmtone.Sriracha,
-		RedLight: charmtone.Salmon,
-		Cherry:   charmtone.Cherry,
+		Syntax: c.Syntax,
 	}
 
 	// Text selection.
-	t.TextSelection = lipgloss.NewStyle().Foreground(charmtone.Salt).Background(charmtone.Charple)
+	t.TextSelection = lipgloss.NewStyle().Foreground(t.FgSelected).Background(t.Primary)
 
 	// LSP and MCP status.
-	t.ItemOfflineIcon = lipgloss.NewStyle().Foreground(charmtone.Squid).SetString("●")
-	t.ItemBusyIcon = t.ItemOfflineIcon.Foreground(charmtone.Citron)
-	t.ItemErrorIcon = t.ItemOfflineIcon.Foreground(charmtone.Coral)
-	t.ItemOnlineIcon = t.ItemOfflineIcon.Foreground(charmtone.Guac)
+	t.ItemOfflineIcon = lipgloss.NewStyle().Foreground(t.FgMuted).SetString("●")
+	t.ItemBusyIcon = t.ItemOfflineIcon.Foreground(t.Citron)
+	t.ItemErrorIcon = t.ItemOfflineIcon.Foreground(t.Red)
+	t.ItemOnlineIcon = t.ItemOfflineIcon.Foreground(t.GreenDark)
 
 	// Editor: Yolo Mode.
-	t.YoloIconFocused = lipgloss.NewStyle().Foreground(charmtone.Oyster).Background(charmtone.Citron).Bold(true).SetString(" ! ")
-	t.YoloIconBlurred = t.YoloIconFocused.Foreground(charmtone.Pepper).Background(charmtone.Squid)
-	t.YoloDotsFocused = lipgloss.NewStyle().Foreground(charmtone.Zest).SetString(":::")
-	t.YoloDotsBlurred = t.YoloDotsFocused.Foreground(charmtone.Squid)
+	t.YoloIconFocused = lipgloss.NewStyle().Foreground(t.FgSubtle).Background(t.Citron).Bold(true).SetString(" ! ")
+	t.YoloIconBlurred = t.YoloIconFocused.Foreground(t.BgBase).Background(t.FgMuted)
+	t.YoloDotsFocused = lipgloss.NewStyle().Foreground(t.Accent).SetString(":::")
+	t.YoloDotsBlurred = t.YoloDotsFocused.Foreground(t.FgMuted)
 
 	// oAuth Chooser.
-	t.AuthBorderSelected = lipgloss.NewStyle().BorderForeground(charmtone.Guac)
-	t.AuthTextSelected = lipgloss.NewStyle().Foreground(charmtone.Julep)
-	t.AuthBorderUnselected = lipgloss.NewStyle().BorderForeground(charmtone.Iron)
-	t.AuthTextUnselected = lipgloss.NewStyle().Foreground(charmtone.Squid)
+	t.AuthBorderSelected = lipgloss.NewStyle().BorderForeground(t.GreenDark)
+	t.AuthTextSelected = lipgloss.NewStyle().Foreground(t.Green)
+	t.AuthBorderUnselected = lipgloss.NewStyle().BorderForeground(t.BgOverlay)
+	t.AuthTextUnselected = lipgloss.NewStyle().Foreground(t.FgMuted)
 
 	return t
 }
@@ -3,6 +3,8 @@ package styles
 import (
 	"fmt"
 	"image/color"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 
@@ -13,8 +15,8 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/glamour/v2/ansi"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/theme"
 	"github.com/charmbracelet/crush/internal/tui/exp/diffview"
-	"github.com/charmbracelet/x/exp/charmtone"
 	"github.com/lucasb-eyer/go-colorful"
 	"github.com/rivo/uniseg"
 )
@@ -77,6 +79,21 @@ type Theme struct {
 	RedLight color.Color
 	Cherry   color.Color
 
+	// Markdown links and images.
+	Link  color.Color
+	Image color.Color
+
+	// Diffs.
+	DiffInsert          color.Color
+	DiffInsertBg        color.Color
+	DiffInsertLineNumBg color.Color
+	DiffDelete          color.Color
+	DiffDeleteBg        color.Color
+	DiffDeleteLineNumBg color.Color
+
+	// Syntax highlighting of code.
+	Syntax ansi.Chroma
+
 	// Text selection.
 	TextSelection lipgloss.Style
 
@@ -145,6 +162,7 @@ func (t *Theme) S() *Styles {
 func (t *Theme) buildStyles() *Styles {
 	base := lipgloss.NewStyle().
 		Foreground(t.FgBase)
+	syntax := t.Syntax
 	return &Styles{
 		Base: base,
 
@@ -223,7 +241,7 @@ func (t *Theme) buildStyles() *Styles {
 				StylePrimitive: ansi.StylePrimitive{
 					// BlockPrefix: "\n",
 					// BlockSuffix: "\n",
-					Color: stringPtr(charmtone.Smoke.Hex()),
+					Color: stringPtr(theme.Hex(t.FgHalfMuted)),
 				},
 				// Margin: uintPtr(defaultMargin),
 			},
@@ -238,7 +256,7 @@ func (t *Theme) buildStyles() *Styles {
 			Heading: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{
 					BlockSuffix: "\n",
-					Color:       stringPtr(charmtone.Malibu.Hex()),
+					Color:       stringPtr(theme.Hex(t.Info)),
 					Bold:        boolPtr(true),
 				},
 			},
@@ -246,8 +264,8 @@ func (t *Theme) buildStyles() *Styles {
 				StylePrimitive: ansi.StylePrimitive{
 					Prefix:          " ",
 					Suffix:          " ",
-					Color:           stringPtr(charmtone.Zest.Hex()),
-					BackgroundColor: stringPtr(charmtone.Charple.Hex()),
+					Color:           stringPtr(theme.Hex(t.Accent)),
+					BackgroundColor: stringPtr(theme.Hex(t.Primary)),
 					Bold:            boolPtr(true),
 				},
 			},
@@ -274,7 +292,7 @@ func (t *Theme) buildStyles() *Styles {
 			H6: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{
 					Prefix: "###### ",
-					Color:  stringPtr(charmtone.Guac.Hex()),
+					Color:  stringPtr(theme.Hex(t.GreenDark)),
 					Bold:   boolPtr(false),
 				},
 			},
@@ -288,7 +306,7 @@ func (t *Theme) buildStyles() *Styles {
 				Bold: boolPtr(true),
 			},
 			HorizontalRule: ansi.StylePrimitive{
-				Color:  stringPtr(charmtone.Charcoal.Hex()),
+				Color:  stringPtr(theme.Hex(t.Border)),
 				Format: "\n--------\n",
 			},
 			Item: ansi.StylePrimitive{
@@ -303,119 +321,37 @@ func (t *Theme) buildStyles() *Styles {
 				Unticked:       "[ ] ",
 			},
 			Link: ansi.StylePrimitive{
-				Color:     stringPtr(charmtone.Zinc.Hex()),
+				Color:     stringPtr(theme.Hex(t.Link)),
 				Underline: boolPtr(true),
 			},
 			LinkText: ansi.StylePrimitive{
-				Color: stringPtr(charmtone.Guac.Hex()),
+				Color: stringPtr(theme.Hex(t.GreenDark)),
 				Bold:  boolPtr(true),
 			},
 			Image: ansi.StylePrimitive{
-				Color:     stringPtr(charmtone.Cheeky.Hex()),
+				Color:     stringPtr(theme.Hex(t.Image)),
 				Underline: boolPtr(true),
 			},
 			ImageText: ansi.StylePrimitive{
-				Color:  stringPtr(charmtone.Squid.Hex()),
+				Color:  stringPtr(theme.Hex(t.FgMuted)),
 				Format: "Image: {{.text}} →",
 			},
 			Code: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{
 					Prefix:          " ",
 					Suffix:          " ",
-					Color:           stringPtr(charmtone.Coral.Hex()),
-					BackgroundColor: stringPtr(charmtone.Charcoal.Hex()),
+					Color:           stringPtr(theme.Hex(t.Red)),
+					BackgroundColor: stringPtr(theme.Hex(t.BgSubtle)),
 				},
 			},
 			CodeBlock: ansi.StyleCodeBlock{
 				StyleBlock: ansi.StyleBlock{
 					StylePrimitive: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Charcoal.Hex()),
+						Color: stringPtr(theme.Hex(t.Border)),
 					},
 					Margin: uintPtr(defaultMargin),
 				},
-				Chroma: &ansi.Chroma{
-					Text: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Smoke.Hex()),
-					},
-					Error: ansi.StylePrimitive{
-						Color:           stringPtr(charmtone.Butter.Hex()),
-						BackgroundColor: stringPtr(charmtone.Sriracha.Hex()),
-					},
-					Comment: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Oyster.Hex()),
-					},
-					CommentPreproc: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Bengal.Hex()),
-					},
-					Keyword: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Malibu.Hex()),
-					},
-					KeywordReserved: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Pony.Hex()),
-					},
-					KeywordNamespace: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Pony.Hex()),
-					},
-					KeywordType: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Guppy.Hex()),
-					},
-					Operator: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Salmon.Hex()),
-					},
-					Punctuation: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Zest.Hex()),
-					},
-					Name: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Smoke.Hex()),
-					},
-					NameBuiltin: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Cheeky.Hex()),
-					},
-					NameTag: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Mauve.Hex()),
-					},
-					NameAttribute: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Hazy.Hex()),
-					},
-					NameClass: ansi.StylePrimitive{
-						Color:     stringPtr(charmtone.Salt.Hex()),
-						Underline: boolPtr(true),
-						Bold:      boolPtr(true),
-					},
-					NameDecorator: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Citron.Hex()),
-					},
-					NameFunction: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Guac.Hex()),
-					},
-					LiteralNumber: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Julep.Hex()),
-					},
-					LiteralString: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Cumin.Hex()),
-					},
-					LiteralStringEscape: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Bok.Hex()),
-					},
-					GenericDeleted: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Coral.Hex()),
-					},
-					GenericEmph: ansi.StylePrimitive{
-						Italic: boolPtr(true),
-					},
-					GenericInserted: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Guac.Hex()),
-					},
-					GenericStrong: ansi.StylePrimitive{
-						Bold: boolPtr(true),
-					},
-					GenericSubheading: ansi.StylePrimitive{
-						Color: stringPtr(charmtone.Squid.Hex()),
-					},
-					Background: ansi.StylePrimitive{
-						BackgroundColor: stringPtr(charmtone.Charcoal.Hex()),
-					},
-				},
+				Chroma: &syntax,
 			},
 			Table: ansi.StyleTable{
 				StyleBlock: ansi.StyleBlock{
@@ -462,23 +398,23 @@ func (t *Theme) buildStyles() *Styles {
 			},
 			InsertLine: diffview.LineStyle{
 				LineNumber: lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#629657")).
-					Background(lipgloss.Color("#2b322a")),
+					Foreground(t.DiffInsert).
+					Background(t.DiffInsertLineNumBg),
 				Symbol: lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#629657")).
-					Background(lipgloss.Color("#323931")),
+					Foreground(t.DiffInsert).
+					Background(t.DiffInsertBg),
 				Code: lipgloss.NewStyle().
-					Background(lipgloss.Color("#323931")),
+					Background(t.DiffInsertBg),
 			},
 			DeleteLine: diffview.LineStyle{
 				LineNumber: lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#a45c59")).
-					Background(lipgloss.Color("#312929")),
+					Foreground(t.DiffDelete).
+					Background(t.DiffDeleteLineNumBg),
 				Symbol: lipgloss.NewStyle().
-					Foreground(lipgloss.Color("#a45c59")).
-					Background(lipgloss.Color("#383030")),
+					Foreground(t.DiffDelete).
+					Background(t.DiffDeleteBg),
 				Code: lipgloss.NewStyle().
-					Background(lipgloss.Color("#383030")),
+					Background(t.DiffDeleteBg),
 			},
 		},
 		FilePicker: filepicker.Styles{
@@ -555,11 +491,13 @@ func (m *Manager) SetTheme(name string) error {
 }
 
 func (m *Manager) List() []string {
-	names := make([]string, 0, len(m.themes))
-	for name := range m.themes {
-		names = append(names, name)
-	}
-	return names
+	return slices.Sorted(maps.Keys(m.themes))
+}
+
+// Get returns the registered theme with the given name.
+func (m *Manager) Get(name string) (*Theme, bool) {
+	theme, ok := m.themes[name]
+	return theme, ok
 }
 
 // ParseHex converts hex string to color
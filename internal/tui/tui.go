@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"regexp"
 	"slices"
@@ -12,13 +15,18 @@ import (
 	"charm.land/bubbles/v2/key"
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/agent"
 	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/app"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/event"
 	"github.com/charmbracelet/crush/internal/home"
+	"github.com/charmbracelet/crush/internal/notify"
 	"github.com/charmbracelet/crush/internal/permission"
 	"github.com/charmbracelet/crush/internal/pubsub"
+	"github.com/charmbracelet/crush/internal/review"
+	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/theme"
 	cmpChat "github.com/charmbracelet/crush/internal/tui/components/chat"
 	"github.com/charmbracelet/crush/internal/tui/components/chat/splash"
 	"github.com/charmbracelet/crush/internal/tui/components/completions"
@@ -27,11 +35,15 @@ import (
 	"github.com/charmbracelet/crush/internal/tui/components/core/status"
 	"github.com/charmbracelet/crush/internal/tui/components/dialogs"
 	"github.com/charmbracelet/crush/internal/tui/components/dialogs/commands"
+	"github.com/charmbracelet/crush/internal/tui/components/dialogs/elicitation"
 	"github.com/charmbracelet/crush/internal/tui/components/dialogs/filepicker"
+	"github.com/charmbracelet/crush/internal/tui/components/dialogs/jobs"
 	"github.com/charmbracelet/crush/internal/tui/components/dialogs/models"
 	"github.com/charmbracelet/crush/internal/tui/components/dialogs/permissions"
 	"github.com/charmbracelet/crush/internal/tui/components/dialogs/quit"
+	reviewdialog "github.com/charmbracelet/crush/internal/tui/components/dialogs/review"
 	"github.com/charmbracelet/crush/internal/tui/components/dialogs/sessions"
+	"github.com/charmbracelet/crush/internal/tui/components/dialogs/themes"
 	"github.com/charmbracelet/crush/internal/tui/page"
 	"github.com/charmbracelet/crush/internal/tui/page/chat"
 	"github.com/charmbracelet/crush/internal/tui/styles"
@@ -81,6 +93,9 @@ type appModel struct {
 	// Chat Page Specific
 	selectedSessionID string // The ID of the currently selected session
 
+	// review is the last review, whose findings can be applied.
+	review *reviewdialog.Review
+
 	// sendProgressBar instructs the TUI to send progress bar updates to the
 	// terminal.
 	sendProgressBar bool
@@ -88,6 +103,22 @@ type appModel struct {
 	// QueryVersion instructs the TUI to query for the terminal version when it
 	// starts.
 	QueryVersion bool
+
+	// notifier notifies the user when the agent finishes or needs permission.
+	notifier *notify.Notifier
+}
+
+// reviewStartedMsg is sent when the session of a review is created.
+type reviewStartedMsg struct {
+	session  session.Session
+	revRange string
+}
+
+// reviewFinishedMsg is sent when a review finishes.
+type reviewFinishedMsg struct {
+	sessionID string
+	report    *review.Report
+	err       error
 }
 
 // Init initializes the application model and returns initial commands.
@@ -166,6 +197,8 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, handleMCPPromptsEvent(context.Background(), msg.Payload.Name)
 		case mcp.EventToolsListChanged:
 			return a, handleMCPToolsEvent(context.Background(), msg.Payload.Name)
+		case mcp.EventResourcesListChanged:
+			return a, handleMCPResourcesEvent(context.Background(), msg.Payload.Name)
 		}
 
 	// Completions messages
@@ -187,11 +220,13 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, tea.Batch(completionCmd, dialogCmd)
 	case commands.ShowArgumentsDialogMsg:
 		var args []commands.Argument
-		for _, arg := range msg.ArgNames {
+		for _, arg := range msg.Arguments {
 			args = append(args, commands.Argument{
-				Name:     arg,
-				Title:    cases.Title(language.English).String(arg),
-				Required: true,
+				Name:        arg.ID,
+				Title:       cases.Title(language.English).String(arg.Title),
+				Description: arg.Description,
+				Default:     arg.Default,
+				Required:    arg.Required,
 			})
 		}
 		return a, util.CmdHandler(
@@ -209,7 +244,12 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case commands.ShowMCPPromptArgumentsDialogMsg:
 		args := make([]commands.Argument, 0, len(msg.Prompt.Arguments))
 		for _, arg := range msg.Prompt.Arguments {
-			args = append(args, commands.Argument(*arg))
+			args = append(args, commands.Argument{
+				Name:        arg.Name,
+				Title:       arg.Title,
+				Description: arg.Description,
+				Required:    arg.Required,
+			})
 		}
 		dialog := commands.NewCommandArgumentsDialog(
 			msg.Prompt.Name,
@@ -237,8 +277,12 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Session
 	case cmpChat.SessionSelectedMsg:
+		if a.selectedSessionID != msg.ID {
+			cmds = append(cmds, a.closeSession(a.selectedSessionID))
+		}
 		a.selectedSessionID = msg.ID
 	case cmpChat.SessionClearedMsg:
+		cmds = append(cmds, a.closeSession(a.selectedSessionID))
 		a.selectedSessionID = ""
 	// Commands
 	case commands.SwitchSessionsMsg:
@@ -249,6 +293,33 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case commands.OpenJobsDialogMsg:
+		return a, util.CmdHandler(
+			dialogs.OpenDialogMsg{
+				Model: jobs.NewJobsDialogCmp(a.selectedSessionID),
+			},
+		)
+	case commands.OpenThemesDialogMsg:
+		return a, util.CmdHandler(
+			dialogs.OpenDialogMsg{
+				Model: themes.NewThemesDialog(),
+			},
+		)
+	case themes.ThemeSelectedMsg:
+		if err := styles.DefaultManager().SetTheme(msg.Name); err != nil {
+			return a, util.ReportError(err)
+		}
+		var cmds []tea.Cmd
+		if err := config.Get().SetTheme(msg.Name); err != nil {
+			cmds = append(cmds, util.ReportError(err))
+		}
+		for p, page := range a.pages {
+			updated, pageCmd := page.Update(commands.ThemeChangedMsg{})
+			a.pages[p] = updated
+			cmds = append(cmds, pageCmd)
+		}
+		cmds = append(cmds, a.handleWindowResize(a.wWidth, a.wHeight), util.ReportInfo("Theme set to "+msg.Name))
+		return a, tea.Batch(cmds...)
 	case commands.SwitchModelMsg:
 		return a, util.CmdHandler(
 			dialogs.OpenDialogMsg{
@@ -264,6 +335,43 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return nil
 		}
+	case commands.StartReviewMsg:
+		if a.app.AgentCoordinator.IsBusy() {
+			return a, util.ReportWarn("Agent is busy, please wait...")
+		}
+		return a, func() tea.Msg {
+			sess, err := a.app.StartReview(context.Background(), msg.Range)
+			if err != nil {
+				return util.ReportError(err)()
+			}
+			return reviewStartedMsg{session: sess, revRange: msg.Range}
+		}
+	case reviewStartedMsg:
+		return a, tea.Sequence(
+			util.CmdHandler(cmpChat.SessionSelectedMsg(msg.session)),
+			util.ReportInfo(fmt.Sprintf("Reviewing %s...", review.RangeTitle(msg.revRange))),
+			func() tea.Msg {
+				report, err := a.app.AgentCoordinator.Review(context.Background(), msg.session.ID, msg.revRange)
+				return reviewFinishedMsg{sessionID: msg.session.ID, report: report, err: err}
+			},
+		)
+	case reviewFinishedMsg:
+		if msg.err != nil {
+			return a, util.ReportError(fmt.Errorf("review failed: %w", msg.err))
+		}
+		a.review = &reviewdialog.Review{
+			SessionID: msg.sessionID,
+			Report:    msg.report,
+		}
+		return a, tea.Batch(
+			util.ReportInfo(fmt.Sprintf("Review finished with %d findings", len(msg.report.Findings))),
+			a.openReviewDialog(),
+		)
+	case commands.OpenReviewDialogMsg:
+		if a.review == nil {
+			return a, util.ReportWarn("No review yet, run Review Changes first")
+		}
+		return a, a.openReviewDialog()
 	case commands.QuitMsg:
 		return a, util.CmdHandler(dialogs.OpenDialogMsg{
 			Model: quit.NewQuitDialog(),
@@ -317,11 +425,38 @@ func (a *appModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return a, itemCmd
 	case pubsub.Event[permission.PermissionRequest]:
-		return a, util.CmdHandler(dialogs.OpenDialogMsg{
-			Model: permissions.NewPermissionDialogCmp(msg.Payload, &permissions.Options{
-				DiffMode: config.Get().Options.TUI.DiffMode,
+		return a, tea.Batch(
+			util.CmdHandler(dialogs.OpenDialogMsg{
+				Model: permissions.NewPermissionDialogCmp(msg.Payload, &permissions.Options{
+					DiffMode: config.Get().Options.TUI.DiffMode,
+				}),
 			}),
-		})
+			a.notifier.Notify(notify.PermissionRequested(msg.Payload)),
+		)
+	case pubsub.Event[agent.RunEvent]:
+		return a, a.notifyRunFinished(msg.Payload)
+	case tea.FocusMsg:
+		a.notifier.SetFocused(true)
+		return a, nil
+	case tea.BlurMsg:
+		a.notifier.SetFocused(false)
+		return a, nil
+	case pubsub.Event[mcp.ElicitationRequest]:
+		switch msg.Type {
+		case pubsub.CreatedEvent:
+			return a, util.CmdHandler(dialogs.OpenDialogMsg{
+				Model: elicitation.New(msg.Payload),
+			})
+		case pubsub.DeletedEvent:
+			// The server gave up waiting for an answer.
+			if a.dialog.ActiveDialogID() == elicitation.DialogID(msg.Payload.ID) {
+				return a, util.CmdHandler(dialogs.CloseDialogMsg{})
+			}
+		}
+		return a, nil
+	case elicitation.ResponseMsg:
+		mcp.RespondElicitation(msg.ID, msg.Action, msg.Content)
+		return a, nil
 	case permissions.PermissionResponseMsg:
 		switch msg.Action {
 		case permissions.PermissionAllow:
@@ -592,6 +727,7 @@ func (a *appModel) View() tea.View {
 	t := styles.CurrentTheme()
 	view.AltScreen = true
 	view.MouseMode = tea.MouseModeCellMotion
+	view.ReportFocus = a.notifier.ReportFocus()
 	view.BackgroundColor = t.BgBase
 	view.WindowTitle = "crush " + home.Short(config.Get().WorkingDir())
 	if a.wWidth < 25 || a.wHeight < 15 {
@@ -687,8 +823,17 @@ func handleMCPToolsEvent(ctx context.Context, name string) tea.Cmd {
 	}
 }
 
+func handleMCPResourcesEvent(ctx context.Context, name string) tea.Cmd {
+	return func() tea.Msg {
+		mcp.RefreshResources(ctx, name)
+		return nil
+	}
+}
+
 // New creates and initializes a new TUI application model.
 func New(app *app.App) *appModel {
+	loadThemes(app.Config())
+
 	chatPage := chat.New(app)
 	keyMap := DefaultKeyMap()
 	keyMap.pageBindings = chatPage.Bindings()
@@ -706,7 +851,62 @@ func New(app *app.App) *appModel {
 
 		dialog:      dialogs.NewDialogCmp(),
 		completions: completions.New(),
+		notifier:    notify.New(app.Config().Options.TUI.Notifications),
 	}
 
 	return model
 }
+
+// notifyRunFinished notifies the user that the agent finished, unless the
+// run was cancelled or denied by the user.
+func (a *appModel) notifyRunFinished(ev agent.RunEvent) tea.Cmd {
+	if errors.Is(ev.Err, context.Canceled) || errors.Is(ev.Err, permission.ErrorPermissionDenied) {
+		return nil
+	}
+	var title string
+	if sess, err := a.app.Sessions.Get(context.Background(), ev.SessionID); err == nil {
+		title = sess.Title
+	}
+	return a.notifier.Notify(notify.AgentFinished(title, ev.Err))
+}
+
+// closeSession terminates the background jobs of the session the user
+// left.
+func (a *appModel) closeSession(sessionID string) tea.Cmd {
+	if sessionID == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		a.app.CloseSession(sessionID)
+		return nil
+	}
+}
+
+// loadThemes registers the built-in themes and the custom themes of the
+// themes directory, and makes the theme of the configuration the current one.
+func loadThemes(cfg *config.Config) {
+	manager := styles.DefaultManager()
+	loaded, err := theme.Load(config.GlobalThemesDir())
+	if err != nil {
+		slog.Warn("Failed to load themes", "dir", config.GlobalThemesDir(), "error", err)
+	}
+	for _, t := range loaded {
+		manager.Register(styles.NewTheme(t))
+	}
+	name := cmp.Or(cfg.Options.TUI.Theme, theme.DefaultName)
+	if err := manager.SetTheme(name); err != nil {
+		slog.Warn("Theme not found, using the default one", "theme", name)
+	}
+}
+
+// openReviewDialog opens the findings of the last review. The fixes are
+// applied in the session of the review, so their edits ask for permission
+// there.
+func (a *appModel) openReviewDialog() tea.Cmd {
+	sessionID := a.review.SessionID
+	return util.CmdHandler(dialogs.OpenDialogMsg{
+		Model: reviewdialog.NewReviewDialogCmp(a.review, func(ctx context.Context, finding review.Finding) error {
+			return a.app.ApplyReviewFinding(ctx, sessionID, finding)
+		}),
+	})
+}
@@ -277,6 +277,82 @@ func (m *MultiEditToolRenderContext) RenderTool(sty *styles.Styles, width int, o
 	return joinToolParts(header, body)
 }
 
+// -----------------------------------------------------------------------------
+// NotebookEdit Tool
+// -----------------------------------------------------------------------------
+
+// NotebookEditToolMessageItem is a message item that represents a notebook
+// edit tool call.
+type NotebookEditToolMessageItem struct {
+	*baseToolMessageItem
+}
+
+var _ ToolMessageItem = (*NotebookEditToolMessageItem)(nil)
+
+// NewNotebookEditToolMessageItem creates a new [NotebookEditToolMessageItem].
+func NewNotebookEditToolMessageItem(
+	sty *styles.Styles,
+	toolCall message.ToolCall,
+	result *message.ToolResult,
+	canceled bool,
+) ToolMessageItem {
+	return newBaseToolMessageItem(sty, toolCall, result, &NotebookEditToolRenderContext{}, canceled)
+}
+
+// NotebookEditToolRenderContext renders notebook edit tool messages.
+type NotebookEditToolRenderContext struct{}
+
+// RenderTool implements the [ToolRenderer] interface.
+func (n *NotebookEditToolRenderContext) RenderTool(sty *styles.Styles, width int, opts *ToolRenderOpts) string {
+	// NotebookEdit tool uses full width for diffs.
+	if opts.IsPending() {
+		return pendingTool(sty, "Notebook Edit", opts.Anim)
+	}
+
+	var params tools.NotebookEditParams
+	if err := json.Unmarshal([]byte(opts.ToolCall.Input), &params); err != nil {
+		return toolErrorContent(sty, &message.ToolResult{Content: "Invalid parameters"}, width)
+	}
+
+	file := fsext.PrettyPath(params.NotebookPath)
+	toolParams := []string{file}
+	if params.EditMode != "" {
+		toolParams = append(toolParams, "mode", params.EditMode)
+	}
+	if params.CellID != "" {
+		toolParams = append(toolParams, "cell_id", params.CellID)
+	}
+	if params.CellIndex != nil {
+		toolParams = append(toolParams, "cell_index", fmt.Sprintf("%d", *params.CellIndex))
+	}
+
+	header := toolHeader(sty, opts.Status, "Notebook Edit", width, opts.Compact, toolParams...)
+	if opts.Compact {
+		return header
+	}
+
+	if earlyState, ok := toolEarlyStateContent(sty, opts, width); ok {
+		return joinToolParts(header, earlyState)
+	}
+
+	if !opts.HasResult() {
+		return header
+	}
+
+	// Get diff content from metadata.
+	var meta tools.NotebookEditResponseMetadata
+	if err := json.Unmarshal([]byte(opts.Result.Metadata), &meta); err != nil {
+		bodyWidth := width - toolBodyLeftPaddingTotal
+		body := sty.Tool.Body.Render(toolOutputPlainContent(sty, opts.Result.Content, bodyWidth, opts.ExpandedContent))
+		return joinToolParts(header, body)
+	}
+
+	// Render diff of the cell source.
+	name := fmt.Sprintf("%s (cell %d)", file, meta.CellIndex)
+	body := toolOutputDiffContent(sty, name, meta.OldContent, meta.NewContent, width, opts.ExpandedContent)
+	return joinToolParts(header, body)
+}
+
 // -----------------------------------------------------------------------------
 // Download Tool
 // -----------------------------------------------------------------------------
@@ -0,0 +1,121 @@
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/fsext"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/styles"
+)
+
+// -----------------------------------------------------------------------------
+// Git Tools
+// -----------------------------------------------------------------------------
+
+// GitToolMessageItem is a message item that represents a call of one of the
+// git tools.
+type GitToolMessageItem struct {
+	*baseToolMessageItem
+}
+
+var _ ToolMessageItem = (*GitToolMessageItem)(nil)
+
+// NewGitToolMessageItem creates a new [GitToolMessageItem].
+func NewGitToolMessageItem(
+	sty *styles.Styles,
+	toolCall message.ToolCall,
+	result *message.ToolResult,
+	canceled bool,
+) ToolMessageItem {
+	return newBaseToolMessageItem(sty, toolCall, result, &GitToolRenderContext{}, canceled)
+}
+
+// GitToolRenderContext renders git tool messages.
+type GitToolRenderContext struct{}
+
+// RenderTool implements the [ToolRenderer] interface.
+func (g *GitToolRenderContext) RenderTool(sty *styles.Styles, width int, opts *ToolRenderOpts) string {
+	cappedWidth := cappedMessageWidth(width)
+	name := prettifyToolName(opts.ToolCall.Name)
+	if opts.IsPending() {
+		return pendingTool(sty, name, opts.Anim)
+	}
+
+	toolParams, err := gitToolParams(opts.ToolCall)
+	if err != nil {
+		return toolErrorContent(sty, &message.ToolResult{Content: "Invalid parameters"}, cappedWidth)
+	}
+
+	header := toolHeader(sty, opts.Status, name, cappedWidth, opts.Compact, toolParams...)
+	if opts.Compact {
+		return header
+	}
+
+	if earlyState, ok := toolEarlyStateContent(sty, opts, cappedWidth); ok {
+		return joinToolParts(header, earlyState)
+	}
+
+	if opts.HasEmptyResult() {
+		return header
+	}
+
+	bodyWidth := cappedWidth - toolBodyLeftPaddingTotal
+	body := sty.Tool.Body.Render(toolOutputPlainContent(sty, opts.Result.Content, bodyWidth, opts.ExpandedContent))
+	return joinToolParts(header, body)
+}
+
+// gitToolParams returns the parameters shown in the header of a git tool.
+func gitToolParams(toolCall message.ToolCall) ([]string, error) {
+	var toolParams []string
+	switch toolCall.Name {
+	case tools.GitStatusToolName:
+		var params tools.GitStatusParams
+		if err := json.Unmarshal([]byte(toolCall.Input), &params); err != nil {
+			return nil, err
+		}
+		if params.Path != "" {
+			toolParams = append(toolParams, fsext.PrettyPath(params.Path))
+		}
+	case tools.GitDiffToolName:
+		var params tools.GitDiffParams
+		if err := json.Unmarshal([]byte(toolCall.Input), &params); err != nil {
+			return nil, err
+		}
+		if len(params.Paths) > 0 {
+			toolParams = append(toolParams, strings.Join(params.Paths, " "))
+		}
+		if params.Ref != "" {
+			toolParams = append(toolParams, "ref", params.Ref)
+		}
+		if params.Staged {
+			toolParams = append(toolParams, "staged", "true")
+		}
+	case tools.GitLogToolName:
+		var params tools.GitLogParams
+		if err := json.Unmarshal([]byte(toolCall.Input), &params); err != nil {
+			return nil, err
+		}
+		if params.Ref != "" {
+			toolParams = append(toolParams, params.Ref)
+		}
+		if params.Path != "" {
+			toolParams = append(toolParams, "path", fsext.PrettyPath(params.Path))
+		}
+		if params.Limit != 0 {
+			toolParams = append(toolParams, "limit", formatNonZero(params.Limit))
+		}
+	case tools.GitCommitToolName:
+		var params tools.GitCommitParams
+		if err := json.Unmarshal([]byte(toolCall.Input), &params); err != nil {
+			return nil, err
+		}
+		subject, _, _ := strings.Cut(params.Message, "\n")
+		toolParams = append(toolParams, subject)
+		if params.All {
+			toolParams = append(toolParams, "all", "true")
+		}
+	}
+	return toolParams, nil
+}
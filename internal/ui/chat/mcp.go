@@ -1,10 +1,12 @@
 package chat
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/crush/internal/agent/tools"
 	"github.com/charmbracelet/crush/internal/message"
 	"github.com/charmbracelet/crush/internal/stringext"
 	"github.com/charmbracelet/crush/internal/ui/styles"
@@ -92,6 +94,72 @@ func (b *MCPToolRenderContext) RenderTool(sty *styles.Styles, width int, opts *T
 	return joinToolParts(header, body)
 }
 
+// MCPResourceToolMessageItem is a message item that represents a
+// read_mcp_resource tool call.
+type MCPResourceToolMessageItem struct {
+	*baseToolMessageItem
+}
+
+var _ ToolMessageItem = (*MCPResourceToolMessageItem)(nil)
+
+// NewMCPResourceToolMessageItem creates a new [MCPResourceToolMessageItem].
+func NewMCPResourceToolMessageItem(
+	sty *styles.Styles,
+	toolCall message.ToolCall,
+	result *message.ToolResult,
+	canceled bool,
+) ToolMessageItem {
+	return newBaseToolMessageItem(sty, toolCall, result, &MCPResourceToolRenderContext{}, canceled)
+}
+
+// MCPResourceToolRenderContext renders read_mcp_resource tool messages.
+type MCPResourceToolRenderContext struct{}
+
+// RenderTool implements the [ToolRenderer] interface.
+func (r *MCPResourceToolRenderContext) RenderTool(sty *styles.Styles, width int, opts *ToolRenderOpts) string {
+	cappedWidth := cappedMessageWidth(width)
+	if opts.IsPending() {
+		return pendingTool(sty, "MCP Resource", opts.Anim)
+	}
+
+	var params tools.ReadMCPResourceParams
+	if err := json.Unmarshal([]byte(opts.ToolCall.Input), &params); err != nil {
+		return toolErrorContent(sty, &message.ToolResult{Content: "Invalid parameters"}, cappedWidth)
+	}
+
+	var toolParams []string
+	if params.URI != "" {
+		toolParams = append(toolParams, params.URI)
+		if params.Server != "" {
+			toolParams = append(toolParams, "server", params.Server)
+		}
+	} else {
+		toolParams = append(toolParams, cmp.Or(params.Server, "all servers"))
+	}
+
+	header := toolHeader(sty, opts.Status, "MCP Resource", cappedWidth, opts.Compact, toolParams...)
+	if opts.Compact {
+		return header
+	}
+
+	if earlyState, ok := toolEarlyStateContent(sty, opts, cappedWidth); ok {
+		return joinToolParts(header, earlyState)
+	}
+
+	if opts.HasEmptyResult() {
+		return header
+	}
+
+	if opts.Result.Data != "" && strings.HasPrefix(opts.Result.MIMEType, "image/") {
+		body := sty.Tool.Body.Render(toolOutputImageContent(sty, opts.Result.Data, opts.Result.MIMEType))
+		return joinToolParts(header, body)
+	}
+
+	bodyWidth := cappedWidth - toolBodyLeftPaddingTotal
+	body := sty.Tool.Body.Render(toolOutputPlainContent(sty, opts.Result.Content, bodyWidth, opts.ExpandedContent))
+	return joinToolParts(header, body)
+}
+
 func prettyName(name string) string {
 	name = strings.ReplaceAll(name, "_", " ")
 	name = strings.ReplaceAll(name, "-", " ")
@@ -0,0 +1,71 @@
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/charmbracelet/crush/internal/agent/tools"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/styles"
+)
+
+// SkillToolMessageItem is a message item that represents a skill tool call.
+type SkillToolMessageItem struct {
+	*baseToolMessageItem
+}
+
+var _ ToolMessageItem = (*SkillToolMessageItem)(nil)
+
+// NewSkillToolMessageItem creates a new [SkillToolMessageItem].
+func NewSkillToolMessageItem(
+	sty *styles.Styles,
+	toolCall message.ToolCall,
+	result *message.ToolResult,
+	canceled bool,
+) ToolMessageItem {
+	return newBaseToolMessageItem(sty, toolCall, result, &SkillToolRenderContext{}, canceled)
+}
+
+// SkillToolRenderContext renders skill tool messages.
+type SkillToolRenderContext struct{}
+
+// RenderTool implements the [ToolRenderer] interface.
+func (r *SkillToolRenderContext) RenderTool(sty *styles.Styles, width int, opts *ToolRenderOpts) string {
+	cappedWidth := cappedMessageWidth(width)
+	if opts.IsPending() {
+		return pendingTool(sty, "Skill", opts.Anim)
+	}
+
+	var params tools.SkillParams
+	_ = json.Unmarshal([]byte(opts.ToolCall.Input), &params)
+
+	header := toolHeader(sty, opts.Status, "Skill", cappedWidth, opts.Compact, params.Name)
+	if opts.Compact {
+		return header
+	}
+
+	if earlyState, ok := toolEarlyStateContent(sty, opts, cappedWidth); ok {
+		return joinToolParts(header, earlyState)
+	}
+
+	if opts.HasEmptyResult() {
+		return header
+	}
+
+	// Show what the skill is about rather than its full instructions.
+	content := opts.Result.Content
+	var meta tools.SkillResponseMetadata
+	if json.Unmarshal([]byte(opts.Result.Metadata), &meta) == nil {
+		content = meta.Description
+		if len(meta.Resources) > 0 {
+			content += "\n\n" + strings.Join(meta.Resources, "\n")
+		}
+		if len(meta.AllowedTools) > 0 {
+			content += "\n\nAllowed tools: " + strings.Join(meta.AllowedTools, ", ")
+		}
+	}
+
+	bodyWidth := cappedWidth - toolBodyLeftPaddingTotal
+	body := sty.Tool.Body.Render(toolOutputPlainContent(sty, content, bodyWidth, opts.ExpandedContent))
+	return joinToolParts(header, body)
+}
@@ -166,7 +166,7 @@ func newBaseToolMessageItem(
 	canceled bool,
 ) *baseToolMessageItem {
 	// we only do full width for diffs (as far as I know)
-	hasCappedWidth := toolCall.Name != tools.EditToolName && toolCall.Name != tools.MultiEditToolName
+	hasCappedWidth := toolCall.Name != tools.EditToolName && toolCall.Name != tools.MultiEditToolName && toolCall.Name != tools.NotebookEditToolName
 
 	status := ToolStatusRunning
 	if canceled {
@@ -214,6 +214,8 @@ func NewToolMessageItem(
 		item = NewBashToolMessageItem(sty, toolCall, result, canceled)
 	case tools.JobOutputToolName:
 		item = NewJobOutputToolMessageItem(sty, toolCall, result, canceled)
+	case tools.JobInputToolName:
+		item = NewJobInputToolMessageItem(sty, toolCall, result, canceled)
 	case tools.JobKillToolName:
 		item = NewJobKillToolMessageItem(sty, toolCall, result, canceled)
 	case tools.ViewToolName:
@@ -224,6 +226,10 @@ func NewToolMessageItem(
 		item = NewEditToolMessageItem(sty, toolCall, result, canceled)
 	case tools.MultiEditToolName:
 		item = NewMultiEditToolMessageItem(sty, toolCall, result, canceled)
+	case tools.NotebookEditToolName:
+		item = NewNotebookEditToolMessageItem(sty, toolCall, result, canceled)
+	case tools.GitStatusToolName, tools.GitDiffToolName, tools.GitLogToolName, tools.GitCommitToolName:
+		item = NewGitToolMessageItem(sty, toolCall, result, canceled)
 	case tools.GlobToolName:
 		item = NewGlobToolMessageItem(sty, toolCall, result, canceled)
 	case tools.GrepToolName:
@@ -252,6 +258,10 @@ func NewToolMessageItem(
 		item = NewReferencesToolMessageItem(sty, toolCall, result, canceled)
 	case tools.LSPRestartToolName:
 		item = NewLSPRestartToolMessageItem(sty, toolCall, result, canceled)
+	case tools.ReadMCPResourceToolName:
+		item = NewMCPResourceToolMessageItem(sty, toolCall, result, canceled)
+	case tools.SkillToolName:
+		item = NewSkillToolMessageItem(sty, toolCall, result, canceled)
 	default:
 		if strings.HasPrefix(toolCall.Name, "mcp_") {
 			item = NewMCPToolMessageItem(sty, toolCall, result, canceled)
@@ -1357,6 +1367,8 @@ func prettifyToolName(name string) string {
 		return "Bash"
 	case tools.JobOutputToolName:
 		return "Job: Output"
+	case tools.JobInputToolName:
+		return "Job: Input"
 	case tools.JobKillToolName:
 		return "Job: Kill"
 	case tools.DownloadToolName:
@@ -1365,6 +1377,8 @@ func prettifyToolName(name string) string {
 		return "Edit"
 	case tools.MultiEditToolName:
 		return "Multi-Edit"
+	case tools.NotebookEditToolName:
+		return "Notebook Edit"
 	case tools.FetchToolName:
 		return "Fetch"
 	case tools.AgenticFetchToolName:
@@ -1373,6 +1387,14 @@ func prettifyToolName(name string) string {
 		return "Fetch"
 	case tools.WebSearchToolName:
 		return "Search"
+	case tools.GitStatusToolName:
+		return "Git Status"
+	case tools.GitDiffToolName:
+		return "Git Diff"
+	case tools.GitLogToolName:
+		return "Git Log"
+	case tools.GitCommitToolName:
+		return "Git Commit"
 	case tools.GlobToolName:
 		return "Glob"
 	case tools.GrepToolName:
@@ -1381,6 +1403,10 @@ func prettifyToolName(name string) string {
 		return "List"
 	case tools.SourcegraphToolName:
 		return "Sourcegraph"
+	case tools.ReadMCPResourceToolName:
+		return "MCP Resource"
+	case tools.SkillToolName:
+		return "Skill"
 	case tools.TodosToolName:
 		return "To-Do"
 	case tools.ViewToolName:
@@ -0,0 +1,131 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/ui/styles"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// VerificationMessageItem renders the verify commands run at the end of an
+// agent turn. Their output is shown when expanded.
+type VerificationMessageItem struct {
+	*highlightableMessageItem
+	*cachedMessageItem
+	*focusableMessageItem
+
+	message  *message.Message
+	sty      *styles.Styles
+	expanded bool
+}
+
+// NewVerificationMessageItem creates a new VerificationMessageItem.
+func NewVerificationMessageItem(sty *styles.Styles, message *message.Message) MessageItem {
+	return &VerificationMessageItem{
+		highlightableMessageItem: defaultHighlighter(sty),
+		cachedMessageItem:        &cachedMessageItem{},
+		focusableMessageItem:     &focusableMessageItem{},
+		message:                  message,
+		sty:                      sty,
+	}
+}
+
+// RawRender implements [MessageItem].
+func (v *VerificationMessageItem) RawRender(width int) string {
+	cappedWidth := cappedMessageWidth(width)
+
+	content, height, ok := v.getCachedRender(cappedWidth)
+	if ok {
+		return v.renderHighlighted(content, cappedWidth, height)
+	}
+
+	verification := v.message.Verification()
+	if verification == nil {
+		return ""
+	}
+
+	sty := v.sty
+	var status string
+	switch {
+	case !verification.Finished:
+		status = sty.Subtle.Render("Running…")
+	case verification.Passed():
+		status = sty.Tool.IconSuccess.Render("Passed")
+	case verification.Repair:
+		status = sty.Tool.IconError.Render("Failed") + sty.Subtle.Render(", sent back to the agent")
+	default:
+		status = sty.Tool.IconError.Render("Failed")
+	}
+	header := sty.Tool.NameNormal.Render("Verification")
+	if verification.Attempt > 1 {
+		header += sty.Subtle.Render(fmt.Sprintf(" #%d", verification.Attempt))
+	}
+	lines := []string{header + " " + status}
+
+	for _, result := range verification.Results {
+		var icon string
+		switch {
+		case !result.Finished:
+			icon = sty.Tool.IconPending.String()
+		case result.Passed():
+			icon = sty.Tool.IconSuccess.String()
+		default:
+			icon = sty.Tool.IconError.String()
+		}
+		line := fmt.Sprintf("%s %s", icon, sty.Tool.ParamMain.Render(result.Name))
+		switch {
+		case result.TimedOut:
+			line += sty.Subtle.Render(" timed out")
+		case result.Finished:
+			line += sty.Subtle.Render(" " + (time.Duration(result.Duration) * time.Millisecond).Round(100*time.Millisecond).String())
+		}
+		lines = append(lines, ansi.Truncate(line, cappedWidth, "…"))
+		if v.expanded && result.Output != "" {
+			lines = append(lines, sty.Tool.Body.Render(toolOutputPlainContent(sty, result.Output, cappedWidth-toolBodyLeftPaddingTotal, true)))
+		}
+	}
+
+	content = strings.Join(lines, "\n")
+	height = lipgloss.Height(content)
+	v.setCachedRender(content, cappedWidth, height)
+	return v.renderHighlighted(content, cappedWidth, height)
+}
+
+// Render implements MessageItem.
+func (v *VerificationMessageItem) Render(width int) string {
+	style := v.sty.Chat.Message.AssistantBlurred
+	if v.focused {
+		style = v.sty.Chat.Message.AssistantFocused
+	}
+	return style.Render(v.RawRender(width))
+}
+
+// ID implements MessageItem.
+func (v *VerificationMessageItem) ID() string {
+	return v.message.ID
+}
+
+// SetMessage is used to update the underlying message while the commands run.
+func (v *VerificationMessageItem) SetMessage(message *message.Message) {
+	v.message = message
+	v.clearCache()
+}
+
+// ToggleExpanded implements Expandable.
+func (v *VerificationMessageItem) ToggleExpanded() {
+	v.expanded = !v.expanded
+	v.clearCache()
+}
+
+// HandleMouseClick implements MouseClickable.
+func (v *VerificationMessageItem) HandleMouseClick(btn ansi.MouseButton, x, y int) bool {
+	if btn != ansi.MouseLeft {
+		return false
+	}
+	v.ToggleExpanded()
+	return true
+}
@@ -3,12 +3,14 @@ package common
 import (
 	"fmt"
 	"image"
+	"log/slog"
 	"os"
 
 	tea "charm.land/bubbletea/v2"
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/crush/internal/app"
 	"github.com/charmbracelet/crush/internal/config"
+	"github.com/charmbracelet/crush/internal/theme"
 	"github.com/charmbracelet/crush/internal/ui/styles"
 	"github.com/charmbracelet/crush/internal/uiutil"
 	uv "github.com/charmbracelet/ultraviolet"
@@ -33,13 +35,40 @@ func (c *Common) Config() *config.Config {
 
 // DefaultCommon returns the default common UI configurations.
 func DefaultCommon(app *app.App) *Common {
-	s := styles.DefaultStyles()
+	s := styles.NewStyles(ConfiguredTheme(app.Config()))
 	return &Common{
 		App:    app,
 		Styles: &s,
 	}
 }
 
+// Themes returns the built-in themes and the custom themes of the themes
+// directory. Invalid theme files are logged and skipped.
+func Themes() []*theme.Theme {
+	themes, err := theme.Load(config.GlobalThemesDir())
+	if err != nil {
+		slog.Warn("Failed to load themes", "dir", config.GlobalThemesDir(), "error", err)
+	}
+	if len(themes) == 0 {
+		return theme.Builtin()
+	}
+	return themes
+}
+
+// ConfiguredTheme returns the theme set in the configuration, or the default
+// theme if it is not set or not found.
+func ConfiguredTheme(cfg *config.Config) *theme.Theme {
+	name := theme.DefaultName
+	if cfg != nil && cfg.Options != nil && cfg.Options.TUI.Theme != "" {
+		name = cfg.Options.TUI.Theme
+	}
+	if t, ok := theme.Find(Themes(), name); ok {
+		return t
+	}
+	slog.Warn("Theme not found, using the default one", "theme", name)
+	return theme.Charmtone()
+}
+
 // CenterRect returns a new [Rectangle] centered within the given area with the
 // specified width and height.
 func CenterRect(area uv.Rectangle, width, height int) uv.Rectangle {
@@ -0,0 +1,155 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"charm.land/bubbles/v2/key"
+	"github.com/charmbracelet/crush/internal/config"
+)
+
+var bindingType = reflect.TypeFor[key.Binding]()
+
+// ApplyKeyBindings returns the key map with the actions remapped in the
+// configured key bindings. A key map is a struct of [key.Binding] fields,
+// possibly nested in structs. The name of an action is made of the scope
+// and the snake_case names of the fields leading to its binding, e.g.
+// "editor.send_message" or "dialog.models.select". An empty list of keys
+// disables the action.
+func ApplyKeyBindings[T any](bindings config.KeyBindings, scope string, keyMap T) T {
+	applyKeyBindings(bindings, scope, reflect.ValueOf(&keyMap).Elem())
+	return keyMap
+}
+
+func applyKeyBindings(bindings config.KeyBindings, scope string, v reflect.Value) {
+	if len(bindings) == 0 {
+		return
+	}
+	walkKeyBindings(scope, scope, v, func(action, _ string, b *key.Binding) {
+		keys, ok := bindings[action]
+		if !ok {
+			return
+		}
+		if len(keys) == 0 {
+			b.SetEnabled(false)
+			return
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(keys[0], b.Help().Desc)
+		b.SetEnabled(true)
+	})
+}
+
+// ValidateKeyBindings checks the configured key bindings against the given
+// key maps, by scope: every action must exist, have non-empty keys, and
+// not share a key with another action active at the same time. Actions are
+// active at the same time when they belong to the same struct, or when one
+// belongs to a struct enclosing the other, as global actions do.
+func ValidateKeyBindings(bindings config.KeyBindings, keyMaps map[string]any) error {
+	if len(bindings) == 0 {
+		return nil
+	}
+
+	type action struct {
+		name  string
+		group string
+		keys  []string
+	}
+
+	var errs []error
+	known := make(map[string]bool)
+	for _, scope := range slices.Sorted(maps.Keys(keyMaps)) {
+		v := reflect.New(reflect.TypeOf(keyMaps[scope])).Elem()
+		v.Set(reflect.ValueOf(keyMaps[scope]))
+		applyKeyBindings(bindings, scope, v)
+
+		var actions []action
+		walkKeyBindings(scope, scope, v, func(name, group string, b *key.Binding) {
+			known[name] = true
+			if b.Enabled() {
+				actions = append(actions, action{name: name, group: group, keys: b.Keys()})
+			}
+		})
+		for i, a := range actions {
+			for _, b := range actions[i+1:] {
+				_, aRemapped := bindings[a.name]
+				_, bRemapped := bindings[b.name]
+				if !aRemapped && !bRemapped {
+					continue
+				}
+				if !encloses(a.group, b.group) && !encloses(b.group, a.group) {
+					continue
+				}
+				for _, k := range a.keys {
+					if slices.Contains(b.keys, k) {
+						errs = append(errs, fmt.Errorf("key %q of %s conflicts with %s", k, a.name, b.name))
+						break
+					}
+				}
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(bindings)) {
+		if !known[name] {
+			errs = append(errs, fmt.Errorf("unknown key binding action %q", name))
+			continue
+		}
+		if slices.Contains(bindings[name], "") {
+			errs = append(errs, fmt.Errorf("empty key for %s", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// encloses reports whether the actions of the outer group are active along
+// with those of the inner group.
+func encloses(outer, inner string) bool {
+	return outer == inner || outer == "" || strings.HasPrefix(inner, outer+".")
+}
+
+// walkKeyBindings calls fn for every key binding of v, with the name of its
+// action and the group of the struct holding it.
+func walkKeyBindings(prefix, group string, v reflect.Value, fn func(action, group string, b *key.Binding)) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := snakeCase(field.Name)
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		switch {
+		case field.Type == bindingType:
+			fn(name, group, v.Field(i).Addr().Interface().(*key.Binding))
+		case field.Type.Kind() == reflect.Struct:
+			walkKeyBindings(name, name, v.Field(i), fn)
+		}
+	}
+}
+
+// snakeCase converts a Go field name to snake_case, e.g. "SendMessage" to
+// "send_message".
+func snakeCase(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+package common
+
+import (
+	"testing"
+
+	"charm.land/bubbles/v2/key"
+	"github.com/charmbracelet/crush/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+type testKeyMap struct {
+	Editor struct {
+		SendMessage key.Binding
+		Newline     key.Binding
+	}
+	Quit key.Binding
+	Help key.Binding
+}
+
+func newTestKeyMap() testKeyMap {
+	var km testKeyMap
+	km.Editor.SendMessage = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "send"))
+	km.Editor.Newline = key.NewBinding(key.WithKeys("ctrl+j"), key.WithHelp("ctrl+j", "newline"))
+	km.Quit = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit"))
+	km.Help = key.NewBinding(key.WithKeys("ctrl+g"), key.WithHelp("ctrl+g", "more"))
+	return km
+}
+
+func TestApplyKeyBindings(t *testing.T) {
+	t.Parallel()
+
+	km := ApplyKeyBindings(config.KeyBindings{
+		"editor.newline": {"shift+enter", "ctrl+j"},
+		"help":           {},
+	}, "", newTestKeyMap())
+
+	require.Equal(t, []string{"shift+enter", "ctrl+j"}, km.Editor.Newline.Keys())
+	require.Equal(t, "shift+enter", km.Editor.Newline.Help().Key)
+	require.Equal(t, "newline", km.Editor.Newline.Help().Desc)
+	require.False(t, km.Help.Enabled())
+	require.Equal(t, []string{"ctrl+c"}, km.Quit.Keys())
+}
+
+func TestApplyKeyBindingsScope(t *testing.T) {
+	t.Parallel()
+
+	km := ApplyKeyBindings(config.KeyBindings{
+		"quit":          {"ctrl+q"},
+		"dialog.x.quit": {"q"},
+	}, "dialog.x", newTestKeyMap())
+
+	require.Equal(t, []string{"q"}, km.Quit.Keys())
+}
+
+func TestValidateKeyBindings(t *testing.T) {
+	t.Parallel()
+
+	keyMaps := map[string]any{"": newTestKeyMap()}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateKeyBindings(config.KeyBindings{
+			"editor.send_message": {"ctrl+s"},
+			"help":                {},
+		}, keyMaps)
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateKeyBindings(config.KeyBindings{"nope": {"x"}}, keyMaps)
+		require.ErrorContains(t, err, `unknown key binding action "nope"`)
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateKeyBindings(config.KeyBindings{"quit": {""}}, keyMaps)
+		require.ErrorContains(t, err, "empty key for quit")
+	})
+
+	t.Run("conflict with global", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateKeyBindings(config.KeyBindings{"editor.newline": {"ctrl+c"}}, keyMaps)
+		require.ErrorContains(t, err, `key "ctrl+c"`)
+	})
+
+	t.Run("disabled action frees its key", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateKeyBindings(config.KeyBindings{
+			"quit":           {},
+			"editor.newline": {"ctrl+c"},
+		}, keyMaps)
+		require.NoError(t, err)
+	})
+}
+
+func TestSnakeCase(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"Quit":        "quit",
+		"SendMessage": "send_message",
+		"UpDown":      "up_down",
+		"OpenURL":     "open_url",
+		"URLInput":    "url_input",
+	} {
+		require.Equal(t, want, snakeCase(in), in)
+	}
+}
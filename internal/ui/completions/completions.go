@@ -92,6 +92,11 @@ func (c *Completions) KeyMap() KeyMap {
 	return c.keyMap
 }
 
+// SetKeyMap sets the key bindings.
+func (c *Completions) SetKeyMap(keyMap KeyMap) {
+	c.keyMap = keyMap
+}
+
 // OpenWithFiles opens the completions with file items from the filesystem.
 func (c *Completions) OpenWithFiles(depth, limit int) tea.Cmd {
 	return func() tea.Msg {
@@ -8,12 +8,14 @@ import (
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/catwalk/pkg/catwalk"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/commands"
 	"github.com/charmbracelet/crush/internal/config"
 	"github.com/charmbracelet/crush/internal/message"
 	"github.com/charmbracelet/crush/internal/oauth"
 	"github.com/charmbracelet/crush/internal/permission"
 	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/theme"
 	"github.com/charmbracelet/crush/internal/ui/common"
 	"github.com/charmbracelet/crush/internal/uiutil"
 )
@@ -58,13 +60,24 @@ type (
 	ActionSelectReasoningEffort struct {
 		Effort string
 	}
+	// ActionSelectTheme is a message indicating a theme has been selected.
+	ActionSelectTheme struct {
+		Theme *theme.Theme
+	}
 	ActionPermissionResponse struct {
 		Permission permission.PermissionRequest
 		Action     PermissionAction
 	}
+	// ActionElicitationResponse is a message answering the input requested
+	// by an MCP server.
+	ActionElicitationResponse struct {
+		Request mcp.ElicitationRequest
+		Action  mcp.ElicitationAction
+		Content map[string]any
+	}
 	// ActionRunCustomCommand is a message to run a custom command.
 	ActionRunCustomCommand struct {
-		Content   string
+		Command   commands.CustomCommand
 		Arguments []commands.Argument
 		Args      map[string]string // Actual argument values
 	}
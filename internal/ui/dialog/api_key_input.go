@@ -16,7 +16,6 @@ import (
 	"github.com/charmbracelet/crush/internal/ui/styles"
 	"github.com/charmbracelet/crush/internal/uiutil"
 	uv "github.com/charmbracelet/ultraviolet"
-	"github.com/charmbracelet/x/exp/charmtone"
 )
 
 type APIKeyInputState int
@@ -43,10 +42,7 @@ type APIKeyInput struct {
 	width int
 	state APIKeyInputState
 
-	keyMap struct {
-		Submit key.Binding
-		Close  key.Binding
-	}
+	keyMap  apiKeyInputKeyMap
 	input   textinput.Model
 	spinner spinner.Model
 	help    help.Model
@@ -54,6 +50,21 @@ type APIKeyInput struct {
 
 var _ Dialog = (*APIKeyInput)(nil)
 
+type apiKeyInputKeyMap struct {
+	Submit key.Binding
+	Close  key.Binding
+}
+
+func defaultAPIKeyInputKeyMap() apiKeyInputKeyMap {
+	return apiKeyInputKeyMap{
+		Submit: key.NewBinding(
+			key.WithKeys("enter", "ctrl+y"),
+			key.WithHelp("enter", "submit"),
+		),
+		Close: CloseKey,
+	}
+}
+
 // NewAPIKeyInput creates a new Models dialog.
 func NewAPIKeyInput(
 	com *common.Common,
@@ -89,11 +100,7 @@ func NewAPIKeyInput(
 	m.help = help.New()
 	m.help.Styles = t.DialogHelpStyles()
 
-	m.keyMap.Submit = key.NewBinding(
-		key.WithKeys("enter", "ctrl+y"),
-		key.WithHelp("enter", "submit"),
-	)
-	m.keyMap.Close = CloseKey
+	m.keyMap = dialogKeyMap(com, APIKeyInputID, defaultAPIKeyInputKeyMap())
 
 	return &m, nil
 }
@@ -254,7 +261,7 @@ func (m *APIKeyInput) inputView() string {
 		m.input.Blur()
 	case APIKeyInputStateError:
 		ts := t.TextInput
-		ts.Focused.Prompt = ts.Focused.Prompt.Foreground(charmtone.Cherry)
+		ts.Focused.Prompt = ts.Focused.Prompt.Foreground(t.Cherry)
 
 		m.input.Prompt = styles.ErrorIcon + " "
 		m.input.SetStyles(ts)
@@ -44,20 +44,40 @@ type Arguments struct {
 	resultAction Action
 
 	help   help.Model
-	keyMap struct {
-		Confirm,
-		Next,
-		Previous,
-		ScrollUp,
-		ScrollDown,
-		Close key.Binding
-	}
+	keyMap argumentsKeyMap
 
 	viewport viewport.Model
 }
 
 var _ Dialog = (*Arguments)(nil)
 
+type argumentsKeyMap struct {
+	Confirm,
+	Next,
+	Previous,
+	ScrollUp,
+	ScrollDown,
+	Close key.Binding
+}
+
+func defaultArgumentsKeyMap() argumentsKeyMap {
+	return argumentsKeyMap{
+		Confirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "confirm"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("down", "tab"),
+			key.WithHelp("↓/tab", "next"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("up", "shift+tab"),
+			key.WithHelp("↑/shift+tab", "previous"),
+		),
+		Close: CloseKey,
+	}
+}
+
 // NewArguments creates a new arguments dialog.
 func NewArguments(com *common.Common, title, description string, arguments []commands.Argument, resultAction Action) *Arguments {
 	a := &Arguments{
@@ -71,19 +91,7 @@ func NewArguments(com *common.Common, title, description string, arguments []com
 	a.help = help.New()
 	a.help.Styles = com.Styles.DialogHelpStyles()
 
-	a.keyMap.Confirm = key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "confirm"),
-	)
-	a.keyMap.Next = key.NewBinding(
-		key.WithKeys("down", "tab"),
-		key.WithHelp("↓/tab", "next"),
-	)
-	a.keyMap.Previous = key.NewBinding(
-		key.WithKeys("up", "shift+tab"),
-		key.WithHelp("↑/shift+tab", "previous"),
-	)
-	a.keyMap.Close = CloseKey
+	a.keyMap = dialogKeyMap(com, ArgumentsID, defaultArgumentsKeyMap())
 
 	// Create input fields for each argument.
 	a.inputs = make([]textinput.Model, len(arguments))
@@ -98,6 +106,10 @@ func NewArguments(com *common.Common, title, description string, arguments []com
 		} else {
 			input.Placeholder = arg.Title
 		}
+		if len(arg.Options) > 0 {
+			input.Placeholder += " (" + strings.Join(arg.Options, ", ") + ")"
+		}
+		input.SetValue(arg.Default)
 
 		if i == 0 {
 			input.Focus()
@@ -122,10 +134,13 @@ func (a *Arguments) ID() string {
 
 // focusInput changes focus to a new input by index with wrap-around.
 func (a *Arguments) focusInput(newIndex int) {
+	n := len(a.inputs)
+	if n == 0 {
+		return
+	}
 	a.inputs[a.focused].Blur()
 
 	// Wrap around: Go's modulo can return negative, so add len first.
-	n := len(a.inputs)
 	a.focused = ((newIndex % n) + n) % n
 
 	a.inputs[a.focused].Focus()
@@ -225,6 +240,9 @@ func (a *Arguments) HandleMsg(msg tea.Msg) Action {
 		case key.Matches(msg, a.keyMap.Previous):
 			a.focusInput(a.focused - 1)
 		default:
+			if len(a.inputs) == 0 {
+				break
+			}
 			var cmd tea.Cmd
 			a.inputs[a.focused], cmd = a.inputs[a.focused].Update(msg)
 			return ActionCmd{Cmd: cmd}
@@ -236,6 +254,9 @@ func (a *Arguments) HandleMsg(msg tea.Msg) Action {
 			a.focusInput(a.findVisibleFieldByOffset(msg.Button == tea.MouseWheelDown))
 		}
 	case tea.PasteMsg:
+		if len(a.inputs) == 0 {
+			break
+		}
 		var cmd tea.Cmd
 		a.inputs[a.focused], cmd = a.inputs[a.focused].Update(msg)
 		return ActionCmd{Cmd: cmd}
@@ -246,6 +267,9 @@ func (a *Arguments) HandleMsg(msg tea.Msg) Action {
 // Cursor returns the cursor position relative to the dialog.
 // we pass the description height to offset the cursor correctly.
 func (a *Arguments) Cursor(descriptionHeight int) *tea.Cursor {
+	if len(a.inputs) == 0 {
+		return nil
+	}
 	cursor := InputCursor(a.com.Styles, a.inputs[a.focused].Cursor())
 	if cursor == nil {
 		return nil
@@ -307,6 +331,10 @@ func (a *Arguments) Draw(scr uv.Screen, area uv.Rectangle) *tea.Cursor {
 	const scrollbarWidth = 1
 	width := lipgloss.Width(renderedFields)
 	height := lipgloss.Height(renderedFields)
+	if len(a.inputs) == 0 {
+		// Without fields, size the dialog to the description.
+		width = min(max(lipgloss.Width(a.description), minInputWidth), possibleWidth, maxInputWidth)
+	}
 
 	// Use standard header
 	titleStyle := s.Dialog.Title
@@ -41,15 +41,7 @@ const (
 // Commands represents a dialog that shows available commands.
 type Commands struct {
 	com    *common.Common
-	keyMap struct {
-		Select,
-		UpDown,
-		Next,
-		Previous,
-		Tab,
-		ShiftTab,
-		Close key.Binding
-	}
+	keyMap commandsKeyMap
 
 	sessionID string // can be empty for non-session-specific commands
 	selected  CommandType
@@ -69,6 +61,48 @@ type Commands struct {
 
 var _ Dialog = (*Commands)(nil)
 
+type commandsKeyMap struct {
+	Select,
+	UpDown,
+	Next,
+	Previous,
+	Tab,
+	ShiftTab,
+	Close key.Binding
+}
+
+func defaultCommandsKeyMap() commandsKeyMap {
+	closeKey := CloseKey
+	closeKey.SetHelp("esc", "cancel")
+	return commandsKeyMap{
+		Select: key.NewBinding(
+			key.WithKeys("enter", "ctrl+y"),
+			key.WithHelp("enter", "confirm"),
+		),
+		UpDown: key.NewBinding(
+			key.WithKeys("up", "down"),
+			key.WithHelp("↑/↓", "choose"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("down"),
+			key.WithHelp("↓", "next item"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑", "previous item"),
+		),
+		Tab: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "switch selection"),
+		),
+		ShiftTab: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "switch selection prev"),
+		),
+		Close: closeKey,
+	}
+}
+
 // NewCommands creates a new commands dialog.
 func NewCommands(com *common.Common, sessionID string, customCommands []commands.CustomCommand, mcpPrompts []commands.MCPPrompt) (*Commands, error) {
 	c := &Commands{
@@ -94,33 +128,7 @@ func NewCommands(com *common.Common, sessionID string, customCommands []commands
 	c.input.SetStyles(com.Styles.TextInput)
 	c.input.Focus()
 
-	c.keyMap.Select = key.NewBinding(
-		key.WithKeys("enter", "ctrl+y"),
-		key.WithHelp("enter", "confirm"),
-	)
-	c.keyMap.UpDown = key.NewBinding(
-		key.WithKeys("up", "down"),
-		key.WithHelp("↑/↓", "choose"),
-	)
-	c.keyMap.Next = key.NewBinding(
-		key.WithKeys("down"),
-		key.WithHelp("↓", "next item"),
-	)
-	c.keyMap.Previous = key.NewBinding(
-		key.WithKeys("up", "ctrl+p"),
-		key.WithHelp("↑", "previous item"),
-	)
-	c.keyMap.Tab = key.NewBinding(
-		key.WithKeys("tab"),
-		key.WithHelp("tab", "switch selection"),
-	)
-	c.keyMap.ShiftTab = key.NewBinding(
-		key.WithKeys("shift+tab"),
-		key.WithHelp("shift+tab", "switch selection prev"),
-	)
-	closeKey := CloseKey
-	closeKey.SetHelp("esc", "cancel")
-	c.keyMap.Close = closeKey
+	c.keyMap = dialogKeyMap(com, CommandsID, defaultCommandsKeyMap())
 
 	// Set initial commands
 	c.setCommandItems(c.selected)
@@ -357,7 +365,7 @@ func (c *Commands) setCommandItems(commandType CommandType) {
 	case UserCommands:
 		for _, cmd := range c.customCommands {
 			action := ActionRunCustomCommand{
-				Content:   cmd.Content,
+				Command:   cmd,
 				Arguments: cmd.Arguments,
 			}
 			commandItems = append(commandItems, NewCommandItem(c.com.Styles, "custom_"+cmd.ID, cmd.Name, "", action))
@@ -424,6 +432,9 @@ func (c *Commands) defaultCommands() []*CommandItem {
 	if c.windowWidth >= sidebarCompactModeBreakpoint && c.sessionID != "" {
 		commands = append(commands, NewCommandItem(c.com.Styles, "toggle_sidebar", "Toggle Sidebar", "", ActionToggleCompactMode{}))
 	}
+	commands = append(commands, NewCommandItem(c.com.Styles, "switch_theme", "Switch Theme", "", ActionOpenDialog{
+		DialogID: ThemesID,
+	}))
 	if c.sessionID != "" {
 		cfg := c.com.Config()
 		agentCfg := cfg.Agents[config.AgentCoder]
@@ -26,6 +26,30 @@ var CloseKey = key.NewBinding(
 	key.WithHelp("esc", "exit"),
 )
 
+// dialogKeyMap returns the key map of a dialog with the key bindings of the
+// configuration applied.
+func dialogKeyMap[T any](com *common.Common, id string, keyMap T) T {
+	return common.ApplyKeyBindings(com.Config().KeyBindings, "dialog."+id, keyMap)
+}
+
+// KeyMaps returns the default key maps of the dialogs, by key binding
+// scope.
+func KeyMaps() map[string]any {
+	return map[string]any{
+		"dialog." + APIKeyInputID: defaultAPIKeyInputKeyMap(),
+		"dialog." + ArgumentsID:   defaultArgumentsKeyMap(),
+		"dialog." + CommandsID:    defaultCommandsKeyMap(),
+		"dialog." + FilePickerID:  defaultFilePickerKeyMap(),
+		"dialog." + ModelsID:      defaultModelsKeyMap(),
+		"dialog." + OAuthID:       defaultOAuthKeyMap(),
+		"dialog." + PermissionsID: defaultPermissionsKeyMap(),
+		"dialog." + QuitID:        defaultQuitKeyMap(),
+		"dialog." + ReasoningID:   defaultReasoningKeyMap(),
+		"dialog." + SessionsID:    defaultSessionsKeyMap(),
+		"dialog." + ThemesID:      defaultThemesKeyMap(),
+	}
+}
+
 // Action represents an action taken in a dialog after handling a message.
 type Action any
 
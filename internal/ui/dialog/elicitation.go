@@ -0,0 +1,105 @@
+package dialog
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
+	"github.com/charmbracelet/crush/internal/commands"
+	"github.com/charmbracelet/crush/internal/ui/common"
+	"github.com/charmbracelet/crush/internal/uiutil"
+)
+
+// ElicitationID is the prefix of the identifiers of elicitation dialogs.
+// Several requests can be pending at once, so the identifier of each dialog
+// ends with the ID of its request.
+const ElicitationID = "elicitation"
+
+// ElicitationDialogID returns the identifier of the dialog answering the
+// given elicitation request.
+func ElicitationDialogID(requestID string) string {
+	return ElicitationID + ":" + requestID
+}
+
+// Elicitation is a dialog for the input requested by an MCP server. Forms
+// are filled like command arguments; for URL requests, the user is sent to
+// the page.
+type Elicitation struct {
+	*Arguments
+	request mcp.ElicitationRequest
+}
+
+var _ Dialog = (*Elicitation)(nil)
+
+// NewElicitation creates a dialog for the given elicitation request.
+func NewElicitation(com *common.Common, request mcp.ElicitationRequest) *Elicitation {
+	arguments := make([]commands.Argument, 0, len(request.Fields))
+	for _, field := range request.Fields {
+		arg := commands.Argument{
+			ID:          field.Name,
+			Title:       field.Label(),
+			Description: field.Description,
+			Required:    field.Required,
+			Default:     field.Default,
+		}
+		if field.Description == "" {
+			arg.Description = field.Hint()
+		} else if len(field.Options) > 0 || field.Type == "boolean" {
+			arg.Description += " (" + field.Hint() + ")"
+		}
+		arguments = append(arguments, arg)
+	}
+
+	description := request.Message
+	if request.URL != "" {
+		description = strings.TrimSpace(description + "\n\n" + request.URL)
+	}
+
+	e := &Elicitation{
+		Arguments: NewArguments(com, fmt.Sprintf("%s requests input", request.MCP), description, arguments, nil),
+		request:   request,
+	}
+	if len(request.Fields) == 0 {
+		if request.URL != "" {
+			e.keyMap.Confirm.SetHelp("enter", "open")
+		} else {
+			e.keyMap.Confirm.SetHelp("enter", "accept")
+		}
+		e.keyMap.Next.SetEnabled(false)
+		e.keyMap.Previous.SetEnabled(false)
+	}
+	return e
+}
+
+// ID implements Dialog.
+func (e *Elicitation) ID() string {
+	return ElicitationDialogID(e.request.ID)
+}
+
+// HandleMsg implements Dialog.
+func (e *Elicitation) HandleMsg(msg tea.Msg) Action {
+	if msg, ok := msg.(tea.KeyPressMsg); ok {
+		switch {
+		case key.Matches(msg, e.keyMap.Close):
+			return ActionElicitationResponse{Request: e.request, Action: mcp.ElicitationCancel}
+		case key.Matches(msg, e.keyMap.Confirm) && e.focused >= len(e.inputs)-1:
+			return e.submit()
+		}
+	}
+	return e.Arguments.HandleMsg(msg)
+}
+
+// submit accepts the request, unless the values entered are invalid.
+func (e *Elicitation) submit() Action {
+	input := make(map[string]string, len(e.inputs))
+	for i, field := range e.request.Fields {
+		input[field.Name] = e.inputs[i].Value()
+	}
+	content, err := e.request.Values(input)
+	if err != nil {
+		return ActionCmd{Cmd: uiutil.ReportWarn(err.Error())}
+	}
+	return ActionElicitationResponse{Request: e.request, Action: mcp.ElicitationAccept, Content: content}
+}
@@ -36,15 +36,7 @@ type FilePicker struct {
 	previewingImage bool // indicates if an image is being previewed
 	isTmux          bool
 
-	km struct {
-		Select,
-		Down,
-		Up,
-		Forward,
-		Backward,
-		Navigate,
-		Close key.Binding
-	}
+	km filePickerKeyMap
 }
 
 // CellSize returns the cell size used for image rendering.
@@ -57,6 +49,49 @@ func (f *FilePicker) CellSize() fimage.CellSize {
 
 var _ Dialog = (*FilePicker)(nil)
 
+type filePickerKeyMap struct {
+	Select,
+	Down,
+	Up,
+	Forward,
+	Backward,
+	Navigate,
+	Close key.Binding
+}
+
+func defaultFilePickerKeyMap() filePickerKeyMap {
+	return filePickerKeyMap{
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "accept"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("down/j", "move down"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("up/k", "move up"),
+		),
+		Forward: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("right/l", "move forward"),
+		),
+		Backward: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("left/h", "move backward"),
+		),
+		Navigate: key.NewBinding(
+			key.WithKeys("right", "l", "left", "h", "up", "k", "down", "j"),
+			key.WithHelp("↑↓←→", "navigate"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "alt+esc"),
+			key.WithHelp("esc", "close/exit"),
+		),
+	}
+}
+
 // NewFilePicker creates a new [FilePicker] dialog.
 func NewFilePicker(com *common.Common) (*FilePicker, tea.Cmd) {
 	f := new(FilePicker)
@@ -67,34 +102,7 @@ func NewFilePicker(com *common.Common) (*FilePicker, tea.Cmd) {
 
 	f.help = help
 
-	f.km.Select = key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "accept"),
-	)
-	f.km.Down = key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("down/j", "move down"),
-	)
-	f.km.Up = key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("up/k", "move up"),
-	)
-	f.km.Forward = key.NewBinding(
-		key.WithKeys("right", "l"),
-		key.WithHelp("right/l", "move forward"),
-	)
-	f.km.Backward = key.NewBinding(
-		key.WithKeys("left", "h"),
-		key.WithHelp("left/h", "move backward"),
-	)
-	f.km.Navigate = key.NewBinding(
-		key.WithKeys("right", "l", "left", "h", "up", "k", "down", "j"),
-		key.WithHelp("↑↓←→", "navigate"),
-	)
-	f.km.Close = key.NewBinding(
-		key.WithKeys("esc", "alt+esc"),
-		key.WithHelp("esc", "close/exit"),
-	)
+	f.km = dialogKeyMap(com, FilePickerID, defaultFilePickerKeyMap())
 
 	fp := filepicker.New()
 	fp.AllowedTypes = common.AllowedImageTypes
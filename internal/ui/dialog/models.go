@@ -80,21 +80,49 @@ type Models struct {
 	modelType ModelType
 	providers []catwalk.Provider
 
-	keyMap struct {
-		Tab      key.Binding
-		UpDown   key.Binding
-		Select   key.Binding
-		Next     key.Binding
-		Previous key.Binding
-		Close    key.Binding
-	}
-	list  *ModelsList
-	input textinput.Model
-	help  help.Model
+	keyMap modelsKeyMap
+	list   *ModelsList
+	input  textinput.Model
+	help   help.Model
 }
 
 var _ Dialog = (*Models)(nil)
 
+type modelsKeyMap struct {
+	Tab      key.Binding
+	UpDown   key.Binding
+	Select   key.Binding
+	Next     key.Binding
+	Previous key.Binding
+	Close    key.Binding
+}
+
+func defaultModelsKeyMap() modelsKeyMap {
+	return modelsKeyMap{
+		Tab: key.NewBinding(
+			key.WithKeys("tab", "shift+tab"),
+			key.WithHelp("tab", "toggle type"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter", "ctrl+y"),
+			key.WithHelp("enter", "confirm"),
+		),
+		UpDown: key.NewBinding(
+			key.WithKeys("up", "down"),
+			key.WithHelp("↑/↓", "choose"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓", "next item"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑", "previous item"),
+		),
+		Close: CloseKey,
+	}
+}
+
 // NewModels creates a new Models dialog.
 func NewModels(com *common.Common, isOnboarding bool) (*Models, error) {
 	t := com.Styles
@@ -116,27 +144,7 @@ func NewModels(com *common.Common, isOnboarding bool) (*Models, error) {
 	m.input.SetStyles(com.Styles.TextInput)
 	m.input.Focus()
 
-	m.keyMap.Tab = key.NewBinding(
-		key.WithKeys("tab", "shift+tab"),
-		key.WithHelp("tab", "toggle type"),
-	)
-	m.keyMap.Select = key.NewBinding(
-		key.WithKeys("enter", "ctrl+y"),
-		key.WithHelp("enter", "confirm"),
-	)
-	m.keyMap.UpDown = key.NewBinding(
-		key.WithKeys("up", "down"),
-		key.WithHelp("↑/↓", "choose"),
-	)
-	m.keyMap.Next = key.NewBinding(
-		key.WithKeys("down", "ctrl+n"),
-		key.WithHelp("↓", "next item"),
-	)
-	m.keyMap.Previous = key.NewBinding(
-		key.WithKeys("up", "ctrl+p"),
-		key.WithHelp("↑", "previous item"),
-	)
-	m.keyMap.Close = CloseKey
+	m.keyMap = dialogKeyMap(com, ModelsID, defaultModelsKeyMap())
 
 	providers, err := getFilteredProviders(com.Config())
 	if err != nil {
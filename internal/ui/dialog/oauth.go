@@ -53,11 +53,7 @@ type OAuth struct {
 
 	spinner spinner.Model
 	help    help.Model
-	keyMap  struct {
-		Copy   key.Binding
-		Submit key.Binding
-		Close  key.Binding
-	}
+	keyMap  oAuthKeyMap
 
 	width           int
 	deviceCode      string
@@ -71,6 +67,26 @@ type OAuth struct {
 
 var _ Dialog = (*OAuth)(nil)
 
+type oAuthKeyMap struct {
+	Copy   key.Binding
+	Submit key.Binding
+	Close  key.Binding
+}
+
+func defaultOAuthKeyMap() oAuthKeyMap {
+	return oAuthKeyMap{
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "copy code"),
+		),
+		Submit: key.NewBinding(
+			key.WithKeys("enter", "ctrl+y"),
+			key.WithHelp("enter", "copy & open"),
+		),
+		Close: CloseKey,
+	}
+}
+
 // newOAuth creates a new device flow component.
 func newOAuth(
 	com *common.Common,
@@ -100,15 +116,7 @@ func newOAuth(
 	m.help = help.New()
 	m.help.Styles = t.DialogHelpStyles()
 
-	m.keyMap.Copy = key.NewBinding(
-		key.WithKeys("c"),
-		key.WithHelp("c", "copy code"),
-	)
-	m.keyMap.Submit = key.NewBinding(
-		key.WithKeys("enter", "ctrl+y"),
-		key.WithHelp("enter", "copy & open"),
-	)
-	m.keyMap.Close = CloseKey
+	m.keyMap = dialogKeyMap(com, OAuthID, defaultOAuthKeyMap())
 
 	return &m, tea.Batch(m.spinner.Tick, m.oAuthProvider.initiateAuth)
 }
@@ -180,7 +180,7 @@ func NewPermissions(com *common.Common, perm permission.PermissionRequest, opts
 	h := help.New()
 	h.Styles = com.Styles.DialogHelpStyles()
 
-	km := defaultPermissionsKeyMap()
+	km := dialogKeyMap(com, PermissionsID, defaultPermissionsKeyMap())
 
 	// Configure viewport with matching keybindings.
 	vp := viewport.New()
@@ -316,7 +316,7 @@ func (p *Permissions) respond(action PermissionAction) tea.Msg {
 
 func (p *Permissions) hasDiffView() bool {
 	switch p.permission.ToolName {
-	case tools.EditToolName, tools.WriteToolName, tools.MultiEditToolName:
+	case tools.EditToolName, tools.WriteToolName, tools.MultiEditToolName, tools.NotebookEditToolName:
 		return true
 	}
 	return false
@@ -477,10 +477,24 @@ func (p *Permissions) renderHeader(contentWidth int) string {
 		if filePath != "" {
 			lines = append(lines, p.renderKeyValue("File", fsext.PrettyPath(filePath), contentWidth))
 		}
+	case tools.NotebookEditToolName:
+		if params, ok := p.permission.Params.(tools.NotebookEditPermissionsParams); ok {
+			lines = append(lines, p.renderKeyValue("Notebook", fsext.PrettyPath(params.NotebookPath), contentWidth))
+			lines = append(lines, p.renderKeyValue("Cell", fmt.Sprintf("%d (%s)", params.CellIndex, params.EditMode), contentWidth))
+		}
 	case tools.LSToolName:
 		if params, ok := p.permission.Params.(tools.LSPermissionsParams); ok {
 			lines = append(lines, p.renderKeyValue("Directory", fsext.PrettyPath(params.Path), contentWidth))
 		}
+	case tools.GitCommitToolName:
+		if params, ok := p.permission.Params.(tools.GitCommitPermissionsParams); ok {
+			switch {
+			case len(params.Files) > 0:
+				lines = append(lines, p.renderKeyValue("Staging", strings.Join(params.Files, ", "), contentWidth))
+			case params.All:
+				lines = append(lines, p.renderKeyValue("Staging", "all changes of tracked files", contentWidth))
+			}
+		}
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
@@ -530,6 +544,10 @@ func (p *Permissions) renderContent(width int) string {
 		return p.renderWriteContent(width)
 	case tools.MultiEditToolName:
 		return p.renderMultiEditContent(width)
+	case tools.NotebookEditToolName:
+		return p.renderNotebookEditContent(width)
+	case tools.GitCommitToolName:
+		return p.renderGitCommitContent(width)
 	case tools.DownloadToolName:
 		return p.renderDownloadContent(width)
 	case tools.FetchToolName:
@@ -554,6 +572,14 @@ func (p *Permissions) renderBashContent(width int) string {
 	return p.renderContentPanel(params.Command, width)
 }
 
+func (p *Permissions) renderGitCommitContent(width int) string {
+	params, ok := p.permission.Params.(tools.GitCommitPermissionsParams)
+	if !ok {
+		return ""
+	}
+	return p.renderContentPanel(params.Message, width)
+}
+
 func (p *Permissions) renderEditContent(contentWidth int) string {
 	params, ok := p.permission.Params.(tools.EditPermissionsParams)
 	if !ok {
@@ -578,6 +604,14 @@ func (p *Permissions) renderMultiEditContent(contentWidth int) string {
 	return p.renderDiff(params.FilePath, params.OldContent, params.NewContent, contentWidth)
 }
 
+func (p *Permissions) renderNotebookEditContent(contentWidth int) string {
+	params, ok := p.permission.Params.(tools.NotebookEditPermissionsParams)
+	if !ok {
+		return ""
+	}
+	return p.renderDiff(params.NotebookPath, params.OldContent, params.NewContent, contentWidth)
+}
+
 func (p *Permissions) renderDiff(filePath, oldContent, newContent string, contentWidth int) string {
 	if !p.viewportDirty {
 		if p.isSplitMode() {
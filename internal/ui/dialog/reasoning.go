@@ -31,13 +31,7 @@ type Reasoning struct {
 	list  *list.FilterableList
 	input textinput.Model
 
-	keyMap struct {
-		Select   key.Binding
-		Next     key.Binding
-		Previous key.Binding
-		UpDown   key.Binding
-		Close    key.Binding
-	}
+	keyMap reasoningKeyMap
 }
 
 // ReasoningItem represents a reasoning effort list item.
@@ -56,6 +50,36 @@ var (
 	_ ListItem = (*ReasoningItem)(nil)
 )
 
+type reasoningKeyMap struct {
+	Select   key.Binding
+	Next     key.Binding
+	Previous key.Binding
+	UpDown   key.Binding
+	Close    key.Binding
+}
+
+func defaultReasoningKeyMap() reasoningKeyMap {
+	return reasoningKeyMap{
+		Select: key.NewBinding(
+			key.WithKeys("enter", "ctrl+y"),
+			key.WithHelp("enter", "confirm"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓", "next item"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑", "previous item"),
+		),
+		UpDown: key.NewBinding(
+			key.WithKeys("up", "down"),
+			key.WithHelp("↑/↓", "choose"),
+		),
+		Close: CloseKey,
+	}
+}
+
 // NewReasoning creates a new reasoning effort dialog.
 func NewReasoning(com *common.Common) (*Reasoning, error) {
 	r := &Reasoning{com: com}
@@ -73,23 +97,7 @@ func NewReasoning(com *common.Common) (*Reasoning, error) {
 	r.input.SetStyles(com.Styles.TextInput)
 	r.input.Focus()
 
-	r.keyMap.Select = key.NewBinding(
-		key.WithKeys("enter", "ctrl+y"),
-		key.WithHelp("enter", "confirm"),
-	)
-	r.keyMap.Next = key.NewBinding(
-		key.WithKeys("down", "ctrl+n"),
-		key.WithHelp("↓", "next item"),
-	)
-	r.keyMap.Previous = key.NewBinding(
-		key.WithKeys("up", "ctrl+p"),
-		key.WithHelp("↑", "previous item"),
-	)
-	r.keyMap.UpDown = key.NewBinding(
-		key.WithKeys("up", "down"),
-		key.WithHelp("↑/↓", "choose"),
-	)
-	r.keyMap.Close = CloseKey
+	r.keyMap = dialogKeyMap(com, ReasoningID, defaultReasoningKeyMap())
 
 	if err := r.setReasoningItems(); err != nil {
 		return nil, err
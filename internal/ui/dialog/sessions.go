@@ -39,23 +39,71 @@ type Session struct {
 
 	sessionsMode sessionsMode
 
-	keyMap struct {
-		Select        key.Binding
-		Next          key.Binding
-		Previous      key.Binding
-		UpDown        key.Binding
-		Delete        key.Binding
-		Rename        key.Binding
-		ConfirmRename key.Binding
-		CancelRename  key.Binding
-		ConfirmDelete key.Binding
-		CancelDelete  key.Binding
-		Close         key.Binding
-	}
+	keyMap sessionsKeyMap
 }
 
 var _ Dialog = (*Session)(nil)
 
+type sessionsKeyMap struct {
+	Select        key.Binding
+	Next          key.Binding
+	Previous      key.Binding
+	UpDown        key.Binding
+	Delete        key.Binding
+	Rename        key.Binding
+	ConfirmRename key.Binding
+	CancelRename  key.Binding
+	ConfirmDelete key.Binding
+	CancelDelete  key.Binding
+	Close         key.Binding
+}
+
+func defaultSessionsKeyMap() sessionsKeyMap {
+	return sessionsKeyMap{
+		Select: key.NewBinding(
+			key.WithKeys("enter", "tab", "ctrl+y"),
+			key.WithHelp("enter", "choose"),
+		),
+		Next: key.NewBinding(
+			key.WithKeys("down", "ctrl+n"),
+			key.WithHelp("↓", "next item"),
+		),
+		Previous: key.NewBinding(
+			key.WithKeys("up", "ctrl+p"),
+			key.WithHelp("↑", "previous item"),
+		),
+		UpDown: key.NewBinding(
+			key.WithKeys("up", "down"),
+			key.WithHelp("↑↓", "choose"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("ctrl+x"),
+			key.WithHelp("ctrl+x", "delete"),
+		),
+		Rename: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "rename"),
+		),
+		ConfirmRename: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "confirm"),
+		),
+		CancelRename: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+		ConfirmDelete: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "delete"),
+		),
+		CancelDelete: key.NewBinding(
+			key.WithKeys("n", "esc"),
+			key.WithHelp("n", "cancel"),
+		),
+		Close: CloseKey,
+	}
+}
+
 // NewSessions creates a new Session dialog.
 func NewSessions(com *common.Common, selectedSessionID string) (*Session, error) {
 	s := new(Session)
@@ -88,47 +136,7 @@ func NewSessions(com *common.Common, selectedSessionID string) (*Session, error)
 	s.input.SetStyles(com.Styles.TextInput)
 	s.input.Focus()
 
-	s.keyMap.Select = key.NewBinding(
-		key.WithKeys("enter", "tab", "ctrl+y"),
-		key.WithHelp("enter", "choose"),
-	)
-	s.keyMap.Next = key.NewBinding(
-		key.WithKeys("down", "ctrl+n"),
-		key.WithHelp("↓", "next item"),
-	)
-	s.keyMap.Previous = key.NewBinding(
-		key.WithKeys("up", "ctrl+p"),
-		key.WithHelp("↑", "previous item"),
-	)
-	s.keyMap.UpDown = key.NewBinding(
-		key.WithKeys("up", "down"),
-		key.WithHelp("↑↓", "choose"),
-	)
-	s.keyMap.Delete = key.NewBinding(
-		key.WithKeys("ctrl+x"),
-		key.WithHelp("ctrl+x", "delete"),
-	)
-	s.keyMap.Rename = key.NewBinding(
-		key.WithKeys("ctrl+r"),
-		key.WithHelp("ctrl+r", "rename"),
-	)
-	s.keyMap.ConfirmRename = key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "confirm"),
-	)
-	s.keyMap.CancelRename = key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "cancel"),
-	)
-	s.keyMap.ConfirmDelete = key.NewBinding(
-		key.WithKeys("y"),
-		key.WithHelp("y", "delete"),
-	)
-	s.keyMap.CancelDelete = key.NewBinding(
-		key.WithKeys("n", "esc"),
-		key.WithHelp("n", "cancel"),
-	)
-	s.keyMap.Close = CloseKey
+	s.keyMap = dialogKeyMap(com, SessionsID, defaultSessionsKeyMap())
 
 	return s, nil
 }
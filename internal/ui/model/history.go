@@ -33,6 +33,10 @@ func (m *UI) loadPromptHistory() tea.Cmd {
 
 		texts := make([]string, 0, len(messages))
 		for _, msg := range messages {
+			// Skip the prompts asking the agent to fix failed verifications.
+			if msg.Verification() != nil {
+				continue
+			}
 			if text := msg.Content().Text; text != "" {
 				texts = append(texts, text)
 			}
@@ -24,6 +24,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/catwalk/pkg/catwalk"
 	"charm.land/lipgloss/v2"
+	"github.com/charmbracelet/crush/internal/agent"
 	"github.com/charmbracelet/crush/internal/agent/tools/mcp"
 	"github.com/charmbracelet/crush/internal/app"
 	"github.com/charmbracelet/crush/internal/commands"
@@ -32,9 +33,11 @@ import (
 	"github.com/charmbracelet/crush/internal/history"
 	"github.com/charmbracelet/crush/internal/home"
 	"github.com/charmbracelet/crush/internal/message"
+	"github.com/charmbracelet/crush/internal/notify"
 	"github.com/charmbracelet/crush/internal/permission"
 	"github.com/charmbracelet/crush/internal/pubsub"
 	"github.com/charmbracelet/crush/internal/session"
+	"github.com/charmbracelet/crush/internal/theme"
 	"github.com/charmbracelet/crush/internal/ui/anim"
 	"github.com/charmbracelet/crush/internal/ui/attachments"
 	"github.com/charmbracelet/crush/internal/ui/chat"
@@ -48,6 +51,7 @@ import (
 	uv "github.com/charmbracelet/ultraviolet"
 	"github.com/charmbracelet/ultraviolet/screen"
 	"github.com/charmbracelet/x/editor"
+	"github.com/pkg/browser"
 )
 
 // Compact mode breakpoints.
@@ -98,10 +102,11 @@ type (
 		Prompts []commands.MCPPrompt
 	}
 	// sendMessageMsg is sent to send a message.
-	// currently only used for mcp prompts.
+	// currently only used for mcp prompts and custom commands.
 	sendMessageMsg struct {
 		Content     string
 		Attachments []message.Attachment
+		Options     agent.RunOptions
 	}
 
 	// closeDialogMsg is sent to close the current dialog.
@@ -216,6 +221,10 @@ type UI struct {
 		index    int
 		draft    string
 	}
+
+	// notifier notifies the user when the agent finishes or needs
+	// permission.
+	notifier *notify.Notifier
 }
 
 // New creates a new instance of the [UI] model.
@@ -230,7 +239,8 @@ func New(com *common.Common) *UI {
 
 	ch := NewChat(com)
 
-	keyMap := DefaultKeyMap()
+	bindings := com.Config().KeyBindings
+	keyMap := common.ApplyKeyBindings(bindings, "", DefaultKeyMap())
 
 	// Completions component
 	comp := completions.New(
@@ -238,6 +248,7 @@ func New(com *common.Common) *UI {
 		com.Styles.Completions.Focused,
 		com.Styles.Completions.Match,
 	)
+	comp.SetKeyMap(common.ApplyKeyBindings(bindings, "completions", completions.DefaultKeyMap()))
 
 	todoSpinner := spinner.New(
 		spinner.WithSpinner(spinner.MiniDot),
@@ -299,6 +310,8 @@ func New(com *common.Common) *UI {
 	// disable indeterminate progress bar
 	ui.progressBarEnabled = com.Config().Options.Progress == nil || *com.Config().Options.Progress
 
+	ui.notifier = notify.New(com.Config().Options.TUI.Notifications)
+
 	return ui
 }
 
@@ -314,9 +327,25 @@ func (m *UI) Init() tea.Cmd {
 	cmds = append(cmds, m.loadCustomCommands())
 	// load prompt history async
 	cmds = append(cmds, m.loadPromptHistory())
+	if err := m.validateKeyBindings(); err != nil {
+		cmds = append(cmds, uiutil.ReportWarn("Invalid key bindings: "+strings.ReplaceAll(err.Error(), "\n", "; ")))
+	}
 	return tea.Batch(cmds...)
 }
 
+// validateKeyBindings checks the configured key bindings against all the
+// key maps of the UI.
+func (m *UI) validateKeyBindings() error {
+	keyMaps := dialog.KeyMaps()
+	keyMaps[""] = DefaultKeyMap()
+	keyMaps["completions"] = completions.DefaultKeyMap()
+	if err := common.ValidateKeyBindings(m.com.Config().KeyBindings, keyMaps); err != nil {
+		slog.Warn("Invalid key bindings", "error", err)
+		return err
+	}
+	return nil
+}
+
 // setState changes the UI state and focus.
 func (m *UI) setState(state uiState, focus uiFocusState) {
 	m.state = state
@@ -375,6 +404,9 @@ func (m *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.isCompact = true
 		}
 		m.setState(uiChat, m.focus)
+		if m.session != nil && m.session.ID != msg.session.ID {
+			cmds = append(cmds, m.closeSession(m.session.ID))
+		}
 		m.session = msg.session
 		m.sessionFiles = msg.files
 		msgs, err := m.com.App.Messages.List(context.Background(), m.session.ID)
@@ -399,7 +431,7 @@ func (m *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateLayoutAndSize()
 
 	case sendMessageMsg:
-		cmds = append(cmds, m.sendMessage(msg.Content, msg.Attachments...))
+		cmds = append(cmds, m.sendMessageWithOptions(msg.Content, msg.Options, msg.Attachments...))
 
 	case userCommandsLoadedMsg:
 		m.customCommands = msg.Commands
@@ -498,10 +530,28 @@ func (m *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if initialized && m.mcpPrompts == nil {
 			cmds = append(cmds, m.loadMCPrompts())
 		}
+	case pubsub.Event[mcp.ElicitationRequest]:
+		switch msg.Type {
+		case pubsub.CreatedEvent:
+			m.dialog.OpenDialog(dialog.NewElicitation(m.com, msg.Payload))
+		case pubsub.DeletedEvent:
+			m.dialog.CloseDialog(dialog.ElicitationDialogID(msg.Payload.ID))
+		}
 	case pubsub.Event[permission.PermissionRequest]:
 		if cmd := m.openPermissionsDialog(msg.Payload); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+		if cmd := m.notifier.Notify(notify.PermissionRequested(msg.Payload)); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case pubsub.Event[agent.RunEvent]:
+		if cmd := m.notifyRunFinished(msg.Payload); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case tea.FocusMsg:
+		m.notifier.SetFocused(true)
+	case tea.BlurMsg:
+		m.notifier.SetFocused(false)
 	case pubsub.Event[permission.PermissionNotification]:
 		m.handlePermissionNotification(msg.Payload)
 	case cancelTimerExpiredMsg:
@@ -519,8 +569,14 @@ func (m *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyboardEnhancementsMsg:
 		m.keyenh = msg
 		if msg.SupportsKeyDisambiguation() {
-			m.keyMap.Models.SetHelp("ctrl+m", "models")
-			m.keyMap.Editor.Newline.SetHelp("shift+enter", "newline")
+			// Keep the help of remapped actions in sync with their keys.
+			bindings := m.com.Config().KeyBindings
+			if _, ok := bindings["models"]; !ok {
+				m.keyMap.Models.SetHelp("ctrl+m", "models")
+			}
+			if _, ok := bindings["editor.newline"]; !ok {
+				m.keyMap.Editor.Newline.SetHelp("shift+enter", "newline")
+			}
 		}
 	case copyChatHighlightMsg:
 		cmds = append(cmds, m.copyChatHighlight())
@@ -933,6 +989,9 @@ func (m *UI) updateSessionMessage(msg message.Message) tea.Cmd {
 		if assistantItem, ok := existingItem.(*chat.AssistantMessageItem); ok {
 			assistantItem.SetMessage(&msg)
 		}
+		if verificationItem, ok := existingItem.(*chat.VerificationMessageItem); ok {
+			verificationItem.SetMessage(&msg)
+		}
 	}
 
 	shouldRenderAssistant := chat.ShouldRenderAssistantMessage(&msg)
@@ -1290,6 +1349,12 @@ func (m *UI) handleDialogMsg(msg tea.Msg) tea.Cmd {
 			return uiutil.NewInfoMsg("Reasoning effort set to " + msg.Effort)
 		})
 		m.dialog.CloseDialog(dialog.ReasoningID)
+	case dialog.ActionSelectTheme:
+		m.dialog.CloseDialog(dialog.ThemesID)
+		if err := m.com.Config().SetTheme(msg.Theme.Name); err != nil {
+			cmds = append(cmds, uiutil.ReportError(err))
+		}
+		cmds = append(cmds, m.applyTheme(msg.Theme), uiutil.ReportInfo("Theme set to "+msg.Theme.Name))
 	case dialog.ActionPermissionResponse:
 		m.dialog.CloseDialog(dialog.PermissionsID)
 		switch msg.Action {
@@ -1301,6 +1366,19 @@ func (m *UI) handleDialogMsg(msg tea.Msg) tea.Cmd {
 			m.com.App.Permissions.Deny(msg.Permission)
 		}
 
+	case dialog.ActionElicitationResponse:
+		m.dialog.CloseDialog(dialog.ElicitationDialogID(msg.Request.ID))
+		mcp.RespondElicitation(msg.Request.ID, msg.Action, msg.Content)
+		if msg.Action == mcp.ElicitationAccept && msg.Request.URL != "" {
+			url := msg.Request.URL
+			cmds = append(cmds, func() tea.Msg {
+				if err := browser.OpenURL(url); err != nil {
+					return uiutil.ReportError(fmt.Errorf("failed to open browser: %w", err))()
+				}
+				return nil
+			})
+		}
+
 	case dialog.ActionFilePickerSelected:
 		cmds = append(cmds, tea.Sequence(
 			msg.Cmd(),
@@ -1316,19 +1394,14 @@ func (m *UI) handleDialogMsg(msg tea.Msg) tea.Cmd {
 			argsDialog := dialog.NewArguments(
 				m.com,
 				"Custom Command Arguments",
-				"",
+				msg.Command.Description,
 				msg.Arguments,
 				msg, // Pass the action as the result
 			)
 			m.dialog.OpenDialog(argsDialog)
 			break
 		}
-		content := msg.Content
-		if msg.Args != nil {
-			content = substituteArgs(content, msg.Args)
-		}
-		cmds = append(cmds, m.sendMessage(content))
-		m.dialog.CloseFrontDialog()
+		cmds = append(cmds, m.runCustomCommand(msg.Command, msg.Args))
 	case dialog.ActionRunMCPPrompt:
 		if len(msg.Arguments) > 0 && msg.Args == nil {
 			m.dialog.CloseFrontDialog()
@@ -1354,15 +1427,6 @@ func (m *UI) handleDialogMsg(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
-// substituteArgs replaces $ARG_NAME placeholders in content with actual values.
-func substituteArgs(content string, args map[string]string) string {
-	for name, value := range args {
-		placeholder := "$" + name
-		content = strings.ReplaceAll(content, placeholder, value)
-	}
-	return content
-}
-
 func (m *UI) openAuthenticationDialog(provider catwalk.Provider, model config.SelectedModel, modelType config.SelectedModelType) tea.Cmd {
 	var (
 		dlg dialog.Dialog
@@ -1879,6 +1943,7 @@ func (m *UI) View() tea.View {
 	v.AltScreen = true
 	v.BackgroundColor = m.com.Styles.Background
 	v.MouseMode = tea.MouseModeCellMotion
+	v.ReportFocus = m.notifier.ReportFocus()
 	v.WindowTitle = "crush " + home.Short(m.com.Config().WorkingDir())
 
 	canvas := uv.NewScreenBuffer(m.width, m.height)
@@ -2388,6 +2453,31 @@ func (m *UI) openEditor(value string) tea.Cmd {
 	})
 }
 
+// applyTheme restyles the UI with the given theme. The styles are shared by
+// all components, so they are replaced in place, and the styles components
+// copied are set again.
+func (m *UI) applyTheme(t *theme.Theme) tea.Cmd {
+	*m.com.Styles = styles.NewStyles(t)
+	m.textarea.SetStyles(m.com.Styles.TextArea)
+	m.todoSpinner.Style = m.com.Styles.Pills.TodoSpinner
+	m.status.help.Styles = m.com.Styles.Help
+	m.completions = completions.New(
+		m.com.Styles.Completions.Normal,
+		m.com.Styles.Completions.Focused,
+		m.com.Styles.Completions.Match,
+	)
+	m.updateLayoutAndSize()
+
+	if m.session == nil {
+		return nil
+	}
+	msgs, err := m.com.App.Messages.List(context.Background(), m.session.ID)
+	if err != nil {
+		return uiutil.ReportError(err)
+	}
+	return m.setSessionMessages(msgs)
+}
+
 // setEditorPrompt configures the textarea prompt function based on whether
 // yolo mode is enabled.
 func (m *UI) setEditorPrompt(yolo bool) {
@@ -2587,6 +2677,12 @@ func (m *UI) renderSidebarLogo(width int) {
 
 // sendMessage sends a message with the given content and attachments.
 func (m *UI) sendMessage(content string, attachments ...message.Attachment) tea.Cmd {
+	return m.sendMessageWithOptions(content, agent.RunOptions{}, attachments...)
+}
+
+// sendMessageWithOptions sends a message with the given content and
+// attachments, overriding the model and tools of the agent with opts.
+func (m *UI) sendMessageWithOptions(content string, opts agent.RunOptions, attachments ...message.Attachment) tea.Cmd {
 	if m.com.App.AgentCoordinator == nil {
 		return uiutil.ReportError(fmt.Errorf("coder agent is not initialized"))
 	}
@@ -2614,7 +2710,7 @@ func (m *UI) sendMessage(content string, attachments ...message.Attachment) tea.
 	// Capture session ID to avoid race with main goroutine updating m.session.
 	sessionID := m.session.ID
 	cmds = append(cmds, func() tea.Msg {
-		_, err := m.com.App.AgentCoordinator.Run(context.Background(), sessionID, content, attachments...)
+		_, err := m.com.App.AgentCoordinator.RunWithOptions(context.Background(), sessionID, content, opts, attachments...)
 		if err != nil {
 			isCancelErr := errors.Is(err, context.Canceled)
 			isPermissionErr := errors.Is(err, permission.ErrorPermissionDenied)
@@ -2694,6 +2790,10 @@ func (m *UI) openDialog(id string) tea.Cmd {
 		if cmd := m.openReasoningDialog(); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+	case dialog.ThemesID:
+		if cmd := m.openThemesDialog(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 	case dialog.QuitID:
 		if cmd := m.openQuitDialog(); cmd != nil {
 			cmds = append(cmds, cmd)
@@ -2776,6 +2876,17 @@ func (m *UI) openReasoningDialog() tea.Cmd {
 	return nil
 }
 
+// openThemesDialog opens the theme selection dialog.
+func (m *UI) openThemesDialog() tea.Cmd {
+	if m.dialog.ContainsDialog(dialog.ThemesID) {
+		m.dialog.BringToFront(dialog.ThemesID)
+		return nil
+	}
+
+	m.dialog.OpenDialog(dialog.NewThemes(m.com))
+	return nil
+}
+
 // openSessionsDialog opens the sessions dialog. If the dialog is already open,
 // it brings it to the front. Otherwise, it will list all the sessions and open
 // the dialog.
@@ -2831,6 +2942,21 @@ func (m *UI) openPermissionsDialog(perm permission.PermissionRequest) tea.Cmd {
 	return nil
 }
 
+// notifyRunFinished notifies the user that the agent finished, unless the
+// run was cancelled or denied by the user.
+func (m *UI) notifyRunFinished(ev agent.RunEvent) tea.Cmd {
+	if errors.Is(ev.Err, context.Canceled) || errors.Is(ev.Err, permission.ErrorPermissionDenied) {
+		return nil
+	}
+	var title string
+	if m.session != nil && m.session.ID == ev.SessionID {
+		title = m.session.Title
+	} else if sess, err := m.com.App.Sessions.Get(context.Background(), ev.SessionID); err == nil {
+		title = sess.Title
+	}
+	return m.notifier.Notify(notify.AgentFinished(title, ev.Err))
+}
+
 // handlePermissionNotification updates tool items when permission state changes.
 func (m *UI) handlePermissionNotification(notification permission.PermissionNotification) {
 	toolItem := m.chat.MessageItem(notification.ToolCallID)
@@ -2855,6 +2981,7 @@ func (m *UI) newSession() tea.Cmd {
 		return nil
 	}
 
+	closeCmd := m.closeSession(m.session.ID)
 	m.session = nil
 	m.sessionFiles = nil
 	m.sessionFileReads = nil
@@ -2866,7 +2993,15 @@ func (m *UI) newSession() tea.Cmd {
 	m.promptQueue = 0
 	m.pillsView = ""
 	m.historyReset()
-	return m.loadPromptHistory()
+	return tea.Batch(closeCmd, m.loadPromptHistory())
+}
+
+// closeSession terminates the background jobs of the session the user left.
+func (m *UI) closeSession(sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		m.com.App.CloseSession(sessionID)
+		return nil
+	}
 }
 
 // handlePasteMsg handles a paste message.
@@ -3056,6 +3191,32 @@ func (m *UI) runMCPPrompt(clientID, promptID string, arguments map[string]string
 	return tea.Sequence(cmds...)
 }
 
+// runCustomCommand expands a custom command with the given arguments and
+// sends the resulting prompt.
+func (m *UI) runCustomCommand(cmd commands.CustomCommand, arguments map[string]string) tea.Cmd {
+	workingDir := m.com.Config().WorkingDir()
+	load := func() tea.Msg {
+		prompt, err := cmd.Expand(context.Background(), workingDir, arguments)
+		if err != nil {
+			return uiutil.ReportError(err)()
+		}
+		return sendMessageMsg{
+			Content: prompt,
+			Options: cmd.RunOptions(),
+		}
+	}
+
+	var cmds []tea.Cmd
+	if c := m.dialog.StartLoading(); c != nil {
+		cmds = append(cmds, c)
+	}
+	cmds = append(cmds, load, func() tea.Msg {
+		return closeDialogMsg{}
+	})
+
+	return tea.Sequence(cmds...)
+}
+
 func (m *UI) copyChatHighlight() tea.Cmd {
 	text := m.chat.HighlightContent()
 	return common.CopyToClipboardWithCallback(
@@ -12,8 +12,8 @@ import (
 	"charm.land/glamour/v2/ansi"
 	"charm.land/lipgloss/v2"
 	"github.com/alecthomas/chroma/v2"
+	"github.com/charmbracelet/crush/internal/theme"
 	"github.com/charmbracelet/crush/internal/tui/exp/diffview"
-	"github.com/charmbracelet/x/exp/charmtone"
 )
 
 const (
@@ -186,6 +186,7 @@ type Styles struct {
 	GreenDark     color.Color
 	Red           color.Color
 	RedDark       color.Color
+	Cherry        color.Color
 	Yellow        color.Color
 
 	// Section Title
@@ -482,54 +483,56 @@ func (s *Styles) DialogHelpStyles() help.Styles {
 
 // DefaultStyles returns the default styles for the UI.
 func DefaultStyles() Styles {
+	return NewStyles(theme.Charmtone())
+}
+
+// NewStyles returns the styles for the UI with the colors of the given
+// theme.
+func NewStyles(t *theme.Theme) Styles {
 	var (
-		primary   = charmtone.Charple
-		secondary = charmtone.Dolly
-		tertiary  = charmtone.Bok
-		// accent    = charmtone.Zest
+		primary   = t.Primary
+		secondary = t.Secondary
+		tertiary  = t.Tertiary
+		accent    = t.Accent
 
 		// Backgrounds
-		bgBase        = charmtone.Pepper
-		bgBaseLighter = charmtone.BBQ
-		bgSubtle      = charmtone.Charcoal
-		bgOverlay     = charmtone.Iron
+		bgBase        = t.BgBase
+		bgBaseLighter = t.BgBaseLighter
+		bgSubtle      = t.BgSubtle
+		bgOverlay     = t.BgOverlay
 
 		// Foregrounds
-		fgBase      = charmtone.Ash
-		fgMuted     = charmtone.Squid
-		fgHalfMuted = charmtone.Smoke
-		fgSubtle    = charmtone.Oyster
-		// fgSelected  = charmtone.Salt
+		fgBase      = t.FgBase
+		fgMuted     = t.FgMuted
+		fgHalfMuted = t.FgHalfMuted
+		fgSubtle    = t.FgSubtle
+		fgSelected  = t.FgSelected
 
 		// Borders
-		border      = charmtone.Charcoal
-		borderFocus = charmtone.Charple
+		border      = t.Border
+		borderFocus = t.BorderFocus
 
 		// Status
-		error   = charmtone.Sriracha
-		warning = charmtone.Zest
-		info    = charmtone.Malibu
+		error   = t.Error
+		warning = t.Warning
+		info    = t.Info
 
 		// Colors
-		white = charmtone.Butter
+		white = t.White
 
-		blueLight = charmtone.Sardine
-		blue      = charmtone.Malibu
-		blueDark  = charmtone.Damson
+		blueLight = t.BlueLight
+		blue      = t.Blue
+		blueDark  = t.BlueDark
 
-		// yellow = charmtone.Mustard
-		yellow = charmtone.Mustard
-		// citron = charmtone.Citron
+		yellow = t.Yellow
+		citron = t.Citron
 
-		greenLight = charmtone.Bok
-		green      = charmtone.Julep
-		greenDark  = charmtone.Guac
-		// greenLight = charmtone.Bok
+		greenLight = t.GreenLight
+		green      = t.Green
+		greenDark  = t.GreenDark
 
-		red     = charmtone.Coral
-		redDark = charmtone.Sriracha
-		// redLight = charmtone.Salmon
-		// cherry   = charmtone.Cherry
+		red     = t.Red
+		redDark = t.RedDark
 	)
 
 	normalBorder := lipgloss.NormalBorder()
@@ -566,6 +569,7 @@ func DefaultStyles() Styles {
 	s.GreenDark = greenDark
 	s.Red = red
 	s.RedDark = redDark
+	s.Cherry = t.Cherry
 	s.Yellow = yellow
 
 	s.TextInput = textinput.Styles{
@@ -614,12 +618,13 @@ func DefaultStyles() Styles {
 		},
 	}
 
+	syntax := t.Syntax
 	s.Markdown = ansi.StyleConfig{
 		Document: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				// BlockPrefix: "\n",
 				// BlockSuffix: "\n",
-				Color: stringPtr(charmtone.Smoke.Hex()),
+				Color: stringPtr(theme.Hex(fgHalfMuted)),
 			},
 			// Margin: uintPtr(defaultMargin),
 		},
@@ -634,7 +639,7 @@ func DefaultStyles() Styles {
 		Heading: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				BlockSuffix: "\n",
-				Color:       stringPtr(charmtone.Malibu.Hex()),
+				Color:       stringPtr(theme.Hex(info)),
 				Bold:        boolPtr(true),
 			},
 		},
@@ -642,8 +647,8 @@ func DefaultStyles() Styles {
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix:          " ",
 				Suffix:          " ",
-				Color:           stringPtr(charmtone.Zest.Hex()),
-				BackgroundColor: stringPtr(charmtone.Charple.Hex()),
+				Color:           stringPtr(theme.Hex(accent)),
+				BackgroundColor: stringPtr(theme.Hex(primary)),
 				Bold:            boolPtr(true),
 			},
 		},
@@ -670,7 +675,7 @@ func DefaultStyles() Styles {
 		H6: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix: "###### ",
-				Color:  stringPtr(charmtone.Guac.Hex()),
+				Color:  stringPtr(theme.Hex(greenDark)),
 				Bold:   boolPtr(false),
 			},
 		},
@@ -684,7 +689,7 @@ func DefaultStyles() Styles {
 			Bold: boolPtr(true),
 		},
 		HorizontalRule: ansi.StylePrimitive{
-			Color:  stringPtr(charmtone.Charcoal.Hex()),
+			Color:  stringPtr(theme.Hex(border)),
 			Format: "\n--------\n",
 		},
 		Item: ansi.StylePrimitive{
@@ -699,119 +704,37 @@ func DefaultStyles() Styles {
 			Unticked:       "[ ] ",
 		},
 		Link: ansi.StylePrimitive{
-			Color:     stringPtr(charmtone.Zinc.Hex()),
+			Color:     stringPtr(theme.Hex(t.Link)),
 			Underline: boolPtr(true),
 		},
 		LinkText: ansi.StylePrimitive{
-			Color: stringPtr(charmtone.Guac.Hex()),
+			Color: stringPtr(theme.Hex(greenDark)),
 			Bold:  boolPtr(true),
 		},
 		Image: ansi.StylePrimitive{
-			Color:     stringPtr(charmtone.Cheeky.Hex()),
+			Color:     stringPtr(theme.Hex(t.Image)),
 			Underline: boolPtr(true),
 		},
 		ImageText: ansi.StylePrimitive{
-			Color:  stringPtr(charmtone.Squid.Hex()),
+			Color:  stringPtr(theme.Hex(fgMuted)),
 			Format: "Image: {{.text}} →",
 		},
 		Code: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
 				Prefix:          " ",
 				Suffix:          " ",
-				Color:           stringPtr(charmtone.Coral.Hex()),
-				BackgroundColor: stringPtr(charmtone.Charcoal.Hex()),
+				Color:           stringPtr(theme.Hex(red)),
+				BackgroundColor: stringPtr(theme.Hex(bgSubtle)),
 			},
 		},
 		CodeBlock: ansi.StyleCodeBlock{
 			StyleBlock: ansi.StyleBlock{
 				StylePrimitive: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Charcoal.Hex()),
+					Color: stringPtr(theme.Hex(border)),
 				},
 				Margin: uintPtr(defaultMargin),
 			},
-			Chroma: &ansi.Chroma{
-				Text: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Smoke.Hex()),
-				},
-				Error: ansi.StylePrimitive{
-					Color:           stringPtr(charmtone.Butter.Hex()),
-					BackgroundColor: stringPtr(charmtone.Sriracha.Hex()),
-				},
-				Comment: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Oyster.Hex()),
-				},
-				CommentPreproc: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Bengal.Hex()),
-				},
-				Keyword: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Malibu.Hex()),
-				},
-				KeywordReserved: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Pony.Hex()),
-				},
-				KeywordNamespace: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Pony.Hex()),
-				},
-				KeywordType: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Guppy.Hex()),
-				},
-				Operator: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Salmon.Hex()),
-				},
-				Punctuation: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Zest.Hex()),
-				},
-				Name: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Smoke.Hex()),
-				},
-				NameBuiltin: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Cheeky.Hex()),
-				},
-				NameTag: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Mauve.Hex()),
-				},
-				NameAttribute: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Hazy.Hex()),
-				},
-				NameClass: ansi.StylePrimitive{
-					Color:     stringPtr(charmtone.Salt.Hex()),
-					Underline: boolPtr(true),
-					Bold:      boolPtr(true),
-				},
-				NameDecorator: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Citron.Hex()),
-				},
-				NameFunction: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Guac.Hex()),
-				},
-				LiteralNumber: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Julep.Hex()),
-				},
-				LiteralString: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Cumin.Hex()),
-				},
-				LiteralStringEscape: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Bok.Hex()),
-				},
-				GenericDeleted: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Coral.Hex()),
-				},
-				GenericEmph: ansi.StylePrimitive{
-					Italic: boolPtr(true),
-				},
-				GenericInserted: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Guac.Hex()),
-				},
-				GenericStrong: ansi.StylePrimitive{
-					Bold: boolPtr(true),
-				},
-				GenericSubheading: ansi.StylePrimitive{
-					Color: stringPtr(charmtone.Squid.Hex()),
-				},
-				Background: ansi.StylePrimitive{
-					BackgroundColor: stringPtr(charmtone.Charcoal.Hex()),
-				},
-			},
+			Chroma: &syntax,
 		},
 		Table: ansi.StyleTable{
 			StyleBlock: ansi.StyleBlock{
@@ -824,8 +747,8 @@ func DefaultStyles() Styles {
 	}
 
 	// PlainMarkdown style - muted colors on subtle background for thinking content.
-	plainBg := stringPtr(bgBaseLighter.Hex())
-	plainFg := stringPtr(fgMuted.Hex())
+	plainBg := stringPtr(theme.Hex(bgBaseLighter))
+	plainFg := stringPtr(theme.Hex(fgMuted))
 	s.PlainMarkdown = ansi.StyleConfig{
 		Document: ansi.StyleBlock{
 			StylePrimitive: ansi.StylePrimitive{
@@ -1021,23 +944,23 @@ func DefaultStyles() Styles {
 		},
 		InsertLine: diffview.LineStyle{
 			LineNumber: lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#629657")).
-				Background(lipgloss.Color("#2b322a")),
+				Foreground(t.DiffInsert).
+				Background(t.DiffInsertLineNumBg),
 			Symbol: lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#629657")).
-				Background(lipgloss.Color("#323931")),
+				Foreground(t.DiffInsert).
+				Background(t.DiffInsertBg),
 			Code: lipgloss.NewStyle().
-				Background(lipgloss.Color("#323931")),
+				Background(t.DiffInsertBg),
 		},
 		DeleteLine: diffview.LineStyle{
 			LineNumber: lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#a45c59")).
-				Background(lipgloss.Color("#312929")),
+				Foreground(t.DiffDelete).
+				Background(t.DiffDeleteLineNumBg),
 			Symbol: lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#a45c59")).
-				Background(lipgloss.Color("#383030")),
+				Foreground(t.DiffDelete).
+				Background(t.DiffDeleteBg),
 			Code: lipgloss.NewStyle().
-				Background(lipgloss.Color("#383030")),
+				Background(t.DiffDeleteBg),
 		},
 	}
 
@@ -1172,10 +1095,10 @@ func DefaultStyles() Styles {
 	// Editor
 	s.EditorPromptNormalFocused = lipgloss.NewStyle().Foreground(greenDark).SetString("::: ")
 	s.EditorPromptNormalBlurred = s.EditorPromptNormalFocused.Foreground(fgMuted)
-	s.EditorPromptYoloIconFocused = lipgloss.NewStyle().MarginRight(1).Foreground(charmtone.Oyster).Background(charmtone.Citron).Bold(true).SetString(" ! ")
-	s.EditorPromptYoloIconBlurred = s.EditorPromptYoloIconFocused.Foreground(charmtone.Pepper).Background(charmtone.Squid)
-	s.EditorPromptYoloDotsFocused = lipgloss.NewStyle().MarginRight(1).Foreground(charmtone.Zest).SetString(":::")
-	s.EditorPromptYoloDotsBlurred = s.EditorPromptYoloDotsFocused.Foreground(charmtone.Squid)
+	s.EditorPromptYoloIconFocused = lipgloss.NewStyle().MarginRight(1).Foreground(fgSubtle).Background(citron).Bold(true).SetString(" ! ")
+	s.EditorPromptYoloIconBlurred = s.EditorPromptYoloIconFocused.Foreground(bgBase).Background(fgMuted)
+	s.EditorPromptYoloDotsFocused = lipgloss.NewStyle().MarginRight(1).Foreground(accent).SetString(":::")
+	s.EditorPromptYoloDotsBlurred = s.EditorPromptYoloDotsFocused.Foreground(fgMuted)
 
 	s.RadioOn = s.HalfMuted.SetString(RadioOn)
 	s.RadioOff = s.HalfMuted.SetString(RadioOff)
@@ -1189,7 +1112,7 @@ func DefaultStyles() Styles {
 
 	// Section
 	s.Section.Title = s.Subtle
-	s.Section.Line = s.Base.Foreground(charmtone.Charcoal)
+	s.Section.Line = s.Base.Foreground(border)
 
 	// Initialize
 	s.Initialize.Header = s.Base
@@ -1197,10 +1120,10 @@ func DefaultStyles() Styles {
 	s.Initialize.Accent = s.Base.Foreground(greenDark)
 
 	// LSP and MCP status.
-	s.ItemOfflineIcon = lipgloss.NewStyle().Foreground(charmtone.Squid).SetString("●")
-	s.ItemBusyIcon = s.ItemOfflineIcon.Foreground(charmtone.Citron)
-	s.ItemErrorIcon = s.ItemOfflineIcon.Foreground(charmtone.Coral)
-	s.ItemOnlineIcon = s.ItemOfflineIcon.Foreground(charmtone.Guac)
+	s.ItemOfflineIcon = lipgloss.NewStyle().Foreground(fgMuted).SetString("●")
+	s.ItemBusyIcon = s.ItemOfflineIcon.Foreground(citron)
+	s.ItemErrorIcon = s.ItemOfflineIcon.Foreground(red)
+	s.ItemOnlineIcon = s.ItemOfflineIcon.Foreground(greenDark)
 
 	// LSP
 	s.LSP.ErrorDiagnostic = s.Base.Foreground(redDark)
@@ -1253,7 +1176,7 @@ func DefaultStyles() Styles {
 	s.Chat.Message.ThinkingFooterDuration = s.Subtle
 
 	// Text selection.
-	s.TextSelection = lipgloss.NewStyle().Foreground(charmtone.Salt).Background(charmtone.Charple)
+	s.TextSelection = lipgloss.NewStyle().Foreground(fgSelected).Background(primary)
 
 	// Dialog styles
 	s.Dialog.Title = base.Padding(0, 1).Foreground(primary)
@@ -1298,11 +1221,11 @@ func DefaultStyles() Styles {
 	s.Dialog.Sessions.DeletingItemBlurred = s.Dialog.NormalItem.Foreground(fgSubtle)
 	s.Dialog.Sessions.DeletingItemFocused = s.Dialog.SelectedItem.Background(red)
 
-	s.Dialog.Sessions.UpdatingTitle = s.Dialog.Title.Foreground(charmtone.Zest)
-	s.Dialog.Sessions.UpdatingView = s.Dialog.View.BorderForeground(charmtone.Zest)
+	s.Dialog.Sessions.UpdatingTitle = s.Dialog.Title.Foreground(accent)
+	s.Dialog.Sessions.UpdatingView = s.Dialog.View.BorderForeground(accent)
 	s.Dialog.Sessions.UpdatingMessage = s.Base.Padding(1)
-	s.Dialog.Sessions.UpdatingTitleGradientFromColor = charmtone.Zest
-	s.Dialog.Sessions.UpdatingTitleGradientToColor = charmtone.Bok
+	s.Dialog.Sessions.UpdatingTitleGradientFromColor = accent
+	s.Dialog.Sessions.UpdatingTitleGradientToColor = tertiary
 	s.Dialog.Sessions.UpdatingItemBlurred = s.Dialog.NormalItem.Foreground(fgSubtle)
 	s.Dialog.Sessions.UpdatingItemFocused = s.Dialog.SelectedItem.UnsetBackground().UnsetForeground()
 